@@ -3,24 +3,49 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi"
 	authz "github.com/wurt83ow/gophkeeper-server/internal/authorization"
 	"github.com/wurt83ow/gophkeeper-server/internal/bdkeeper"
+	"github.com/wurt83ow/gophkeeper-server/internal/blobstore"
 	"github.com/wurt83ow/gophkeeper-server/internal/config"
 	"github.com/wurt83ow/gophkeeper-server/internal/controllers"
+	"github.com/wurt83ow/gophkeeper-server/internal/cryptokeeper"
+	"github.com/wurt83ow/gophkeeper-server/internal/events"
+	"github.com/wurt83ow/gophkeeper-server/internal/grpcserver"
 	"github.com/wurt83ow/gophkeeper-server/internal/logger"
+	"github.com/wurt83ow/gophkeeper-server/internal/memkeeper"
 	"github.com/wurt83ow/gophkeeper-server/internal/middleware"
+	"github.com/wurt83ow/gophkeeper-server/internal/sqlitekeeper"
 	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+	"github.com/wurt83ow/gophkeeper-server/internal/uploads"
+	"github.com/wurt83ow/gophkeeper-server/pkg/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// uploadCollectInterval is how often stale resumable uploads are looked for.
+	uploadCollectInterval = 10 * time.Minute
+	// blobCollectInterval is how often unreferenced blobs are looked for.
+	blobCollectInterval = 10 * time.Minute
+	// tokenPurgeInterval is how often expired refresh tokens and revoked token IDs are removed.
+	tokenPurgeInterval = time.Hour
+	// reencryptionBatchSize is the number of users the re-encryption job loads at a time.
+	reencryptionBatchSize = 100
 )
 
 // Server represents the application server.
 type Server struct {
-	srv *http.Server
-	ctx context.Context
+	srv  *http.Server
+	grpc *grpc.Server
+	ctx  context.Context
 }
 
 // NewServer creates a new Server instance.
@@ -50,18 +75,114 @@ func (server *Server) Serve() {
 	}
 	defer keeper.Close()
 
+	// Sensitive columns are encrypted before they reach the keeper
+	vault, err := initializeEncryption(keeper, option, nLogger)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Administrators can move the stored data to the current master key
+	var reencryption controllers.Reencryption
+	if cryptoKeeper, ok := vault.(*cryptokeeper.CryptoKeeper); ok {
+		job := cryptokeeper.NewReencryption(cryptoKeeper, reencryptionBatchSize, nLogger)
+		go job.Run(server.ctx)
+		reencryption = job
+	}
+
 	// Initialize the storage instance
-	memoryStorage := initializeStorage(keeper, nLogger)
+	memoryStorage := initializeStorage(vault, option, nLogger)
+
+	// Expired tokens are removed from the database
+	go memoryStorage.RunTokenPurge(server.ctx, tokenPurgeInterval)
 
-	authz := authz.NewJWTAuthz(option.JWTSigningKey(), nLogger)
+	// Passwords are hashed on the server with Argon2id
+	hasher := authz.NewArgon2idHasher(authz.Argon2idParams{
+		Memory:      option.Argon2Memory(),
+		Iterations:  option.Argon2Iterations(),
+		Parallelism: option.Argon2Parallelism(),
+	})
+
+	// Tokens are signed with the single signing key or with the current key of a key ring
+	signingKeys, err := authz.LoadSigningKeys(option.JWTSigningKey(), option.JWTSigningKeys())
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	authz := authz.NewJWTAuthzWithKeys(signingKeys, option.SRPSaltKey(), option.AccessTokenTTL(),
+		option.RefreshTokenTTL(), hasher, nLogger)
+
+	// Unfinished resumable uploads are kept next to the files and collected once inactive
+	uploadStore := uploads.NewStore(option.FileStoragePath())
+	go uploadStore.RunCollector(server.ctx, uploadCollectInterval, option.UploadTTL(), nLogger)
+
+	// File content is kept in the configured blob store
+	blobs, err := initializeBlobStore(option)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Blobs are shared by identical files and removed once no file refers to them
+	go blobstore.NewCollector(memoryStorage, blobs, nLogger).Run(server.ctx, blobCollectInterval)
+
+	// Files uploaded before content addressing are moved to their content keys
+	go blobstore.RunLegacyMigration(server.ctx, memoryStorage, blobs, nLogger)
+
+	// Changes are pushed to the other devices of a user
+	broker, err := initializeEvents(server.ctx, option, nLogger)
+	if err != nil {
+		log.Fatalln(err)
+	}
 
 	// Create a new controller to process incoming requests
-	baseController := initializeBaseController(memoryStorage, option, nLogger, authz)
+	baseController := initializeBaseController(memoryStorage, option, nLogger, authz, uploadStore, blobs, broker, reencryption)
+
+	// Files uploaded before per-user directories are moved into them in the background,
+	// downloads fall back to their old location until then
+	if err := baseController.LoadLegacyFiles(server.ctx); err != nil {
+		log.Fatalln(err)
+	}
+	go baseController.MigrateLegacyFiles(server.ctx)
+
+	// Create router and mount routes
+	r, err := NewRouter(baseController, authz, memoryStorage, option.MaxBodySize(), nLogger)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// The gRPC API runs on its own port over the same storage
+	if address := option.GRPCAddr(); address != "" {
+		var serverOptions []grpc.ServerOption
+		if option.EnableHTTPS() {
+			creds, err := credentials.NewServerTLSFromFile(option.HTTPSCertFile(), option.HTTPSKeyFile())
+			if err != nil {
+				log.Fatalln(err)
+			}
+			serverOptions = append(serverOptions, grpc.Creds(creds))
+		}
 
-	// Create an instance of ChiServerOptions with your middleware
+		keeperServer := grpcserver.NewServer(memoryStorage, option, nLogger, authz, blobs, broker)
+		server.grpc = NewGRPCServer(keeperServer, authz, memoryStorage, nLogger, serverOptions...)
+		go startGRPCServer(server, address)
+	}
+
+	// Configure and start the server
+	startServer(server, r, option.RunAddr(), option.EnableHTTPS(),
+		option.HTTPSCertFile(), option.HTTPSKeyFile(), option.ReadTimeout(), option.WriteTimeout())
+}
+
+// NewRouter mounts the routes of baseController behind the logging, compression,
+// request validation and authorization middlewares. JSON request bodies are limited
+// to maxBodySize bytes.
+func NewRouter(baseController controllers.ServerInterface, jwtAuthz *authz.JWTAuthz,
+	storage authz.Storage, maxBodySize int64, logger *logger.Logger,
+) (chi.Router, error) {
+	// Create an instance of ChiServerOptions with your middleware.
+	// Middlewares are applied in reverse order, so the JWT check runs before the ownership check.
+	// Both skip the operations that the spec marks as public.
 	options := controllers.ChiServerOptions{
 		Middlewares: []controllers.MiddlewareFunc{
-			authz.JWTAuthzMiddleware(memoryStorage, nLogger),
+			controllers.Secured(jwtAuthz.OwnershipMiddleware(logger)),
+			controllers.Secured(jwtAuthz.JWTAuthzMiddleware(storage, logger)),
 		},
 	}
 
@@ -69,49 +190,164 @@ func (server *Server) Serve() {
 	genHandler := controllers.HandlerWithOptions(baseController, options)
 
 	// Get a middleware for logging requests
-	reqLog := middleware.NewReqLog(nLogger)
+	reqLog := middleware.NewReqLog(logger)
 
-	// Create router and mount routes
+	// Get a middleware that rejects requests not matching the OpenAPI spec
+	spec, err := controllers.GetSwagger()
+	if err != nil {
+		return nil, err
+	}
+	validator, err := middleware.NewRequestValidator(spec, maxBodySize, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create router and mount routes.
+	// Compressed request bodies are unpacked before they are validated.
 	r := chi.NewRouter()
 	r.Use(reqLog.RequestLogger)
+	r.Use(middleware.GzipMiddleware)
+	r.Use(validator.Validate)
 	r.Mount("/", genHandler)
 
-	// Configure and start the server
-	startServer(server, r, option.RunAddr(), option.EnableHTTPS(),
-		option.HTTPSCertFile(), option.HTTPSKeyFile())
+	return r, nil
+}
+
+// NewGRPCServer registers keeperServer behind the JWT interceptors, which skip the
+// methods that are called before a login.
+func NewGRPCServer(keeperServer pb.KeeperServer, jwtAuthz *authz.JWTAuthz,
+	storage authz.Storage, logger *logger.Logger, opts ...grpc.ServerOption,
+) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(jwtAuthz.UnaryJWTInterceptor(storage, logger, grpcserver.PublicMethods...)),
+		grpc.ChainStreamInterceptor(jwtAuthz.StreamJWTInterceptor(storage, logger, grpcserver.PublicMethods...)),
+	)
+
+	s := grpc.NewServer(opts...)
+	pb.RegisterKeeperServer(s, keeperServer)
+
+	return s
+}
+
+// closableKeeper is a keeper that holds its resources until it is closed.
+type closableKeeper interface {
+	cryptokeeper.Keeper
+	Close() bool
 }
 
-func initializeKeeper(dataBaseDSN func() string, logger *logger.Logger) (*bdkeeper.BDKeeper, error) {
-	return bdkeeper.NewBDKeeper(dataBaseDSN, logger, nil)
+func initializeKeeper(dataBaseDSN func() string, logger *logger.Logger) (closableKeeper, error) {
+	// Without a database the data is kept in memory until the server stops
+	if dataBaseDSN() == "" {
+		logger.Info("database dsn is empty, data is kept in memory only")
+		return memkeeper.NewMemKeeper(), nil
+	}
+
+	// A single node can keep the data in a SQLite file instead of PostgreSQL
+	if strings.HasPrefix(dataBaseDSN(), sqlitekeeper.Scheme) {
+		keeper, err := sqlitekeeper.NewSQLiteKeeper(dataBaseDSN, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		return keeper, nil
+	}
+
+	keeper, err := bdkeeper.NewBDKeeper(dataBaseDSN, logger, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return keeper, nil
+}
+
+// initializeEncryption wraps keeper with encryption by the configured master keys.
+// Without a master key the records are stored as the clients send them.
+func initializeEncryption(keeper cryptokeeper.Keeper, options *config.Options, logger *logger.Logger) (storage.Keeper, error) {
+	masterKeys, err := cryptokeeper.LoadMasterKeys(options.MasterKey(), options.MasterKeyFile())
+	if err != nil {
+		return nil, err
+	}
+	if masterKeys == nil {
+		logger.Info("master key is empty, records are stored unencrypted")
+		return keeper, nil
+	}
+
+	return cryptokeeper.NewCryptoKeeper(keeper, masterKeys)
 }
 
-func initializeStorage(keeper storage.Keeper, logger *logger.Logger) *storage.MemoryStorage {
+func initializeStorage(keeper storage.Keeper, options *config.Options, logger *logger.Logger) *storage.MemoryStorage {
 	if keeper == nil {
 		return nil
 	}
 
-	return storage.NewMemoryStorage(keeper, logger)
+	return storage.NewMemoryStorage(keeper, logger, storage.CacheOptions{
+		Size: options.CacheSize(),
+		TTL:  options.CacheTTL(),
+	})
+}
+
+func initializeBlobStore(options *config.Options) (blobstore.BlobStore, error) {
+	switch options.BlobStore() {
+	case "fs":
+		return blobstore.NewFileStore(options.FileStoragePath()), nil
+	case "s3":
+		return blobstore.NewS3Store(blobstore.S3Config{
+			Endpoint:  options.S3Endpoint(),
+			Bucket:    options.S3Bucket(),
+			Region:    options.S3Region(),
+			AccessKey: options.S3AccessKey(),
+			SecretKey: options.S3SecretKey(),
+		}, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", options.BlobStore())
+	}
+}
+
+// initializeEvents returns the broker of change notifications. The postgres broker
+// listens for the notifications of all servers until ctx is done.
+func initializeEvents(ctx context.Context, options *config.Options, logger *logger.Logger) (controllers.Events, error) {
+	switch options.EventsBackend() {
+	case "memory":
+		return events.NewBroadcaster(), nil
+	case "postgres":
+		broker, err := events.NewPGBroker(options.DataBaseDSN(), logger)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			broker.Run(ctx)
+			broker.Close()
+		}()
+
+		return broker, nil
+	default:
+		return nil, fmt.Errorf("unknown events backend %q", options.EventsBackend())
+	}
 }
 
 func initializeBaseController(storage *storage.MemoryStorage, options *config.Options,
-	logger *logger.Logger, authz *authz.JWTAuthz,
+	logger *logger.Logger, authz *authz.JWTAuthz, uploads *uploads.Store, blobs blobstore.BlobStore,
+	events controllers.Events, reencryption controllers.Reencryption,
 ) *controllers.BaseController {
-	return controllers.NewBaseController(storage, options, logger, authz)
+	return controllers.NewBaseController(storage, options, logger, authz, uploads, blobs, events, reencryption)
 }
 
+// startServer starts the server with the given read and write timeouts. Requests that
+// transfer file content extend their own deadlines, so the timeouts fit ordinary API calls.
 func startServer(server *Server, router chi.Router, address string,
-	enableHTTPS bool, HTTPSCertFile, HTTPSKeyFile string) {
+	enableHTTPS bool, HTTPSCertFile, HTTPSKeyFile string, readTimeout, writeTimeout time.Duration) {
 	const (
-		oneMegabyte = 1 << 20
-		readTimeout = 3 * time.Second
+		oneMegabyte   = 1 << 20
+		headerTimeout = 3 * time.Second
 	)
 
 	server.srv = &http.Server{
 		Addr:              address,
 		Handler:           router,
-		ReadHeaderTimeout: readTimeout,
-		WriteTimeout:      readTimeout,
-		IdleTimeout:       readTimeout,
+		ReadHeaderTimeout: headerTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       headerTimeout,
 		MaxHeaderBytes:    oneMegabyte, // 1 MB
 	}
 
@@ -132,6 +368,19 @@ func startServer(server *Server, router chi.Router, address string,
 
 }
 
+func startGRPCServer(server *Server, address string) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	log.Printf("Starting gRPC server at %s\n", address)
+
+	if err := server.grpc.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		log.Fatalln(err)
+	}
+}
+
 // Shutdown gracefully shuts down the server.
 func (server *Server) Shutdown() {
 	log.Printf("server stopped")
@@ -141,6 +390,21 @@ func (server *Server) Shutdown() {
 
 	defer cancel()
 
+	// Streams that are still open when the timeout expires are cut off
+	if server.grpc != nil {
+		stopped := make(chan struct{})
+		go func() {
+			server.grpc.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-ctxShutDown.Done():
+			server.grpc.Stop()
+		}
+	}
+
 	if err := server.srv.Shutdown(ctxShutDown); err != nil {
 		if !errors.Is(err, http.ErrServerClosed) {
 			log.Fatalf("server Shutdown Failed:%s", err)
@@ -0,0 +1,97 @@
+package authz
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// authorizationMetadata is the gRPC metadata key that carries the access token.
+const authorizationMetadata = "authorization"
+
+// UnaryJWTInterceptor authenticates unary gRPC calls the same way JWTAuthzMiddleware
+// authenticates HTTP requests, by the access token in the "authorization" metadata.
+// Methods listed in public are called without a token.
+func (j *JWTAuthz) UnaryJWTInterceptor(storage Storage, log Log, public ...string) grpc.UnaryServerInterceptor {
+	publicMethods := methodSet(public)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ctx, err := j.authenticateCall(ctx, storage, log)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamJWTInterceptor authenticates streaming gRPC calls like UnaryJWTInterceptor.
+func (j *JWTAuthz) StreamJWTInterceptor(storage Storage, log Log, public ...string) grpc.StreamServerInterceptor {
+	publicMethods := methodSet(public)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if publicMethods[info.FullMethod] {
+			return handler(srv, ss)
+		}
+
+		ctx, err := j.authenticateCall(ss.Context(), storage, log)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticateCall verifies the access token of a call, marks its session as seen
+// and returns the context with its claims, or an Unauthenticated error.
+func (j *JWTAuthz) authenticateCall(ctx context.Context, storage Storage, log Log) (context.Context, error) {
+	var jwtToken string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(authorizationMetadata); len(values) > 0 {
+			jwtToken = values[0]
+		}
+	}
+
+	claims := j.verifyToken(ctx, jwtToken, storage, log)
+	if claims == nil {
+		return nil, status.Error(codes.Unauthenticated, "Authorization error")
+	}
+
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = remoteIP(p.Addr.String())
+	}
+	j.touchSession(ctx, storage, claims.SessionID, ip, log)
+
+	return withClaims(ctx, claims), nil
+}
+
+// methodSet collects full gRPC method names into a set.
+func methodSet(methods []string) map[string]bool {
+	set := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		set[method] = true
+	}
+
+	return set
+}
+
+// authenticatedStream is a server stream whose context carries the claims of its access token.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context with the claims.
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
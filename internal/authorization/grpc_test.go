@@ -0,0 +1,48 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestJWTAuthz_UnaryJWTInterceptor(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+	storage := &mockStorage{revoked: map[string]bool{}}
+	interceptor := jwtAuthz.UnaryJWTInterceptor(storage, &MockLogger{}, "/keeper/Login")
+
+	var keyUserID models.Key = "userID"
+	handler := func(ctx context.Context, req any) (any, error) {
+		return ctx.Value(keyUserID), nil
+	}
+
+	token := jwtAuthz.CreateJWTTokenForUser("user123", "session1")
+	withToken := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", token))
+
+	// The user of the token is passed to the handler
+	userID, err := interceptor(withToken, nil, &grpc.UnaryServerInfo{FullMethod: "/keeper/Get"}, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", userID)
+	assert.Equal(t, 1, storage.touched["session1"])
+
+	// Public methods are called without a token
+	userID, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/keeper/Login"}, handler)
+	assert.NoError(t, err)
+	assert.Nil(t, userID)
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/keeper/Get"}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	claims, err := jwtAuthz.DecodeJWTToClaims(token)
+	assert.NoError(t, err)
+	storage.revoked[claims.Id] = true
+
+	_, err = interceptor(withToken, nil, &grpc.UnaryServerInfo{FullMethod: "/keeper/Get"}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
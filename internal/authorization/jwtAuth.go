@@ -3,26 +3,52 @@ package authz
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"log"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/golang-jwt/jwt"
 	"github.com/wurt83ow/gophkeeper-server/internal/config"
+	"github.com/wurt83ow/gophkeeper-server/internal/keyring"
 	"github.com/wurt83ow/gophkeeper-server/internal/models"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"golang.org/x/crypto/bcrypt"
 )
 
-// Storage is an interface representing methods for inserting user data.
+// Default lifetimes of the issued tokens.
+const (
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// Storage is an interface representing methods for checking the state of issued tokens.
 type Storage interface {
+	// IsTokenRevoked reports whether the access token with the given ID has been revoked.
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	// IsSessionActive reports whether the session with the given ID exists and has not been revoked.
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+	// TouchSession updates the last-seen time and IP address of the session.
+	TouchSession(ctx context.Context, sessionID string, ip string) error
 }
 
+// sessionTouchInterval is how often the last-seen time of a session is updated at most,
+// so that a busy device does not write to the storage on every request.
+const sessionTouchInterval = time.Minute
+
 // CustomClaims represents custom claims for JWT token.
 type CustomClaims struct {
-	Email string `json:"email"`
+	Email     string `json:"email"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.StandardClaims
 }
 
@@ -33,18 +59,53 @@ type Log interface {
 
 // JWTAuthz provides JWT token creation, decoding, and middleware functionality for authentication and authorization.
 type JWTAuthz struct {
-	jwtSigningKey    []byte
+	jwtSigningKeys   *keyring.KeyRing
+	srpSaltKey       []byte
 	log              Log
 	jwtSigningMethod *jwt.SigningMethodHMAC
 	defaultCookie    http.Cookie
+	hasher           PasswordHasher
+	accessTTL        time.Duration
+	refreshTTL       time.Duration
+	touches          touchLimiter
+}
+
+// NewJWTAuthz creates a new JWTAuthz instance with the provided signing key, token lifetimes,
+// password hasher and logger. Zero lifetimes are replaced with the defaults.
+// If hasher is nil, an Argon2id hasher with the default parameters is used.
+// The signing key also derives the salts of unknown users at SRP login.
+func NewJWTAuthz(signingKey string, accessTTL, refreshTTL time.Duration, hasher PasswordHasher, log Log) *JWTAuthz {
+	signingKey = config.GetAsString("JWT_SIGNING_KEY", signingKey)
+	signingKeys := keyring.Single(keyring.Key{Secret: []byte(signingKey)})
+
+	return NewJWTAuthzWithKeys(signingKeys, signingKey, accessTTL, refreshTTL, hasher, log)
 }
 
-// NewJWTAuthz creates a new JWTAuthz instance with the provided signing key and logger.
-func NewJWTAuthz(signingKey string, log Log) *JWTAuthz {
+// NewJWTAuthzWithKeys is like NewJWTAuthz, but signs the tokens with a key ring and
+// derives the salts of unknown users at SRP login from srpSaltKey, which must stay
+// the same while the signing keys are rotated.
+// Tokens are signed with the current key and name it in their "kid" header; they are
+// verified with the key they name, so older keys can be kept until their tokens expire.
+func NewJWTAuthzWithKeys(signingKeys *keyring.KeyRing, srpSaltKey string, accessTTL, refreshTTL time.Duration, hasher PasswordHasher, log Log) *JWTAuthz {
+	if hasher == nil {
+		hasher = NewArgon2idHasher(DefaultArgon2idParams)
+	}
+	if accessTTL <= 0 {
+		accessTTL = DefaultAccessTokenTTL
+	}
+	if refreshTTL <= 0 {
+		refreshTTL = DefaultRefreshTokenTTL
+	}
+
 	return &JWTAuthz{
-		jwtSigningKey:    []byte(config.GetAsString("JWT_SIGNING_KEY", signingKey)),
+		jwtSigningKeys:   signingKeys,
+		srpSaltKey:       []byte(srpSaltKey),
 		log:              log,
 		jwtSigningMethod: jwt.SigningMethodHS256,
+		hasher:           hasher,
+		accessTTL:        accessTTL,
+		refreshTTL:       refreshTTL,
+		touches:          touchLimiter{last: make(map[string]time.Time)},
 
 		defaultCookie: http.Cookie{
 			HttpOnly: true,
@@ -52,49 +113,215 @@ func NewJWTAuthz(signingKey string, log Log) *JWTAuthz {
 	}
 }
 
+// LoadSigningKeys returns the JWT signing keys: the key ring given as a list of "[id:]key"
+// entries separated by commas, the current key first, if it is set, and the single
+// signing key with an empty ID otherwise.
+func LoadSigningKeys(signingKey string, signingKeys string) (*keyring.KeyRing, error) {
+	if strings.TrimSpace(signingKeys) == "" {
+		return keyring.Single(keyring.Key{Secret: []byte(signingKey)}), nil
+	}
+
+	return keyring.Parse(signingKeys, func(key string) ([]byte, error) {
+		return []byte(key), nil
+	})
+}
+
+// JWTAuthzMiddleware authenticates requests by the access token in the Authorization header.
+// Tokens revoked in the storage and tokens of revoked sessions are rejected. The user ID,
+// session ID, token ID and token expiration time are stored in the request context,
+// and the session is marked as seen from the client address.
 func (j *JWTAuthz) JWTAuthzMiddleware(storage Storage, log Log) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
-			var userID string
-			var err error
+			claims := j.verifyToken(r.Context(), r.Header.Get("Authorization"), storage, log)
 
-			jwtToken := r.Header.Get("Authorization")
+			// If there are no claims, return an authorization error
+			if claims == nil {
+				http.Error(w, "Authorization error", http.StatusUnauthorized)
+				return
+			}
+
+			j.touchSession(r.Context(), storage, claims.SessionID, remoteIP(r.RemoteAddr), log)
+
+			next.ServeHTTP(w, r.WithContext(withClaims(r.Context(), claims)))
+		}
 
-			if jwtToken != "" {
-				userID, err = j.DecodeJWTToUser(jwtToken)
+		return http.HandlerFunc(fn)
+	}
+}
+
+// touchSession updates the last-seen time and IP address of a session, at most once
+// per sessionTouchInterval. Failures are only logged, since the request is authenticated.
+func (j *JWTAuthz) touchSession(ctx context.Context, storage Storage, sessionID string, ip string, log Log) {
+	if storage == nil || !j.touches.allow(sessionID, time.Now()) {
+		return
+	}
+
+	if err := storage.TouchSession(ctx, sessionID, ip); err != nil {
+		log.Info("Error occurred updating session", zap.Error(err))
+	}
+}
+
+// touchLimiter remembers when the sessions were last touched.
+type touchLimiter struct {
+	mu    sync.Mutex
+	last  map[string]time.Time
+	swept time.Time
+}
 
-				if err != nil {
-					userID = ""
-					log.Info("Error occurred decoding JWT token", zap.Error(err))
-				}
+// allow reports whether a session is due for a touch and records the touch if it is.
+// Sessions that have not been touched for an interval are forgotten.
+func (l *touchLimiter) allow(sessionID string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.swept) >= sessionTouchInterval {
+		for id, touched := range l.last {
+			if now.Sub(touched) >= sessionTouchInterval {
+				delete(l.last, id)
 			}
+		}
+		l.swept = now
+	}
+
+	if touched, ok := l.last[sessionID]; ok && now.Sub(touched) < sessionTouchInterval {
+		return false
+	}
+	l.last[sessionID] = now
 
-			// If userID is still empty, return an authorization error
-			if userID == "" {
+	return true
+}
 
-				http.Error(w, "Authorization error", http.StatusUnauthorized)
+// remoteIP returns the IP address of a "host:port" client address.
+func remoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	return host
+}
+
+// verifyToken decodes an access token and checks that neither the token nor its session
+// has been revoked in the storage. It returns nil if the token does not authenticate a user.
+func (j *JWTAuthz) verifyToken(ctx context.Context, jwtToken string, storage Storage, log Log) *CustomClaims {
+	if jwtToken == "" {
+		return nil
+	}
+
+	claims, err := j.DecodeJWTToClaims(jwtToken)
+	if err != nil {
+		log.Info("Error occurred decoding JWT token", zap.Error(err))
+		return nil
+	}
+
+	if storage != nil {
+		// Reject tokens revoked on logout
+		revoked, err := storage.IsTokenRevoked(ctx, claims.Id)
+		if err != nil || revoked {
+			log.Info("Revoked or unverifiable JWT token", zap.Bool("revoked", revoked), zap.Error(err))
+			return nil
+		}
+
+		// Reject tokens of revoked sessions
+		active, err := storage.IsSessionActive(ctx, claims.SessionID)
+		if err != nil || !active {
+			log.Info("Revoked or unverifiable session", zap.Bool("active", active), zap.Error(err))
+			return nil
+		}
+	}
+
+	if claims.Email == "" {
+		return nil
+	}
+
+	return claims
+}
+
+// withClaims stores the user ID, session ID, token ID and token expiration time of an
+// authenticated token in the context.
+func withClaims(ctx context.Context, claims *CustomClaims) context.Context {
+	var (
+		keyUserID         models.Key = "userID"
+		keySessionID      models.Key = "sessionID"
+		keyTokenID        models.Key = "tokenID"
+		keyTokenExpiresAt models.Key = "tokenExpiresAt"
+	)
+	ctx = context.WithValue(ctx, keyUserID, claims.Email)
+	ctx = context.WithValue(ctx, keySessionID, claims.SessionID)
+	ctx = context.WithValue(ctx, keyTokenID, claims.Id)
+	ctx = context.WithValue(ctx, keyTokenExpiresAt, time.Unix(claims.ExpiresAt, 0))
+
+	return ctx
+}
+
+// OwnershipMiddleware rejects requests whose {userID} path parameter does not match
+// the user ID stored in the request context by JWTAuthzMiddleware, so it must run after it.
+// Routes without a {userID} parameter are passed through unchanged.
+func (j *JWTAuthz) OwnershipMiddleware(log Log) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			pathUserID := chi.URLParam(r, "userID")
+			if pathUserID == "" {
+				next.ServeHTTP(w, r)
 				return
 			}
 
 			var keyUserID models.Key = "userID"
-			ctx := r.Context()
-			ctx = context.WithValue(ctx, keyUserID, userID)
-			next.ServeHTTP(w, r.WithContext(ctx))
+			tokenUserID, _ := r.Context().Value(keyUserID).(string)
+
+			if !sameUserID(tokenUserID, pathUserID) {
+				log.Info("Access to another user's data denied",
+					zap.String("token_user_id", tokenUserID),
+					zap.String("path_user_id", pathUserID))
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
 		}
 
 		return http.HandlerFunc(fn)
 	}
 }
 
-// CreateJWTTokenForUser creates a JWT token for the specified user ID.
-func (j *JWTAuthz) CreateJWTTokenForUser(userid string) string {
+// sameUserID reports whether two string representations refer to the same numeric user ID.
+func sameUserID(tokenUserID, pathUserID string) bool {
+	tokenID, err := strconv.Atoi(tokenUserID)
+	if err != nil {
+		return false
+	}
+
+	pathID, err := strconv.Atoi(pathUserID)
+	if err != nil {
+		return false
+	}
+
+	return tokenID == pathID
+}
+
+// CreateJWTTokenForUser creates a short-lived JWT access token for the specified user ID and session.
+func (j *JWTAuthz) CreateJWTTokenForUser(userid string, sessionID string) string {
+	jti, err := randomToken(16)
+	if err != nil {
+		log.Println("Error occurred generating JWT ID", err)
+		return ""
+	}
+
+	now := time.Now()
 	claims := CustomClaims{
 		userid,
-		jwt.StandardClaims{},
+		sessionID,
+		jwt.StandardClaims{
+			Id:        jti,
+			Subject:   userid,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(j.accessTTL).Unix(),
+		},
 	}
 
 	// Encode to token string
-	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(j.jwtSigningKey)
+	tokenString, err := j.signToken(claims)
 	if err != nil {
 		log.Println("Error occurred generating JWT", err)
 		return ""
@@ -105,22 +332,102 @@ func (j *JWTAuthz) CreateJWTTokenForUser(userid string) string {
 
 // DecodeJWTToUser decodes a JWT token to retrieve the user ID.
 func (j *JWTAuthz) DecodeJWTToUser(token string) (string, error) {
+	claims, err := j.DecodeJWTToClaims(token)
+	if err != nil {
+		return "", err
+	}
+
+	return claims.Email, nil
+}
+
+// DecodeJWTToClaims decodes and validates a JWT token. Tokens without an expiration time,
+// an ID or a session ID are rejected.
+func (j *JWTAuthz) DecodeJWTToClaims(token string) (*CustomClaims, error) {
 	// Decode
-	decodeToken, err := jwt.ParseWithClaims(token, &CustomClaims{}, func(token *jwt.Token) (any, error) {
-		if !(j.jwtSigningMethod == token.Method) {
-			// Check our method hasn't changed since issuance
-			return nil, errors.New("signing method mismatch")
-		}
-		return j.jwtSigningKey, nil
-	})
+	decodeToken, err := jwt.ParseWithClaims(token, &CustomClaims{}, j.verificationKey)
 
 	// There's two parts. We might decode it successfully but it might
 	// be the case we aren't Valid so you must check both
-	if decClaims, ok := decodeToken.Claims.(*CustomClaims); ok && decodeToken.Valid {
-		return decClaims.Email, nil
+	if err != nil {
+		return nil, err
+	}
+
+	decClaims, ok := decodeToken.Claims.(*CustomClaims)
+	if !ok || !decodeToken.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	if decClaims.ExpiresAt == 0 || decClaims.Id == "" || decClaims.SessionID == "" {
+		return nil, errors.New("token has no expiration time, ID or session ID")
 	}
 
-	return "", err
+	return decClaims, nil
+}
+
+// signToken signs a token with the current signing key and names the key in the "kid" header.
+func (j *JWTAuthz) signToken(claims jwt.Claims) (string, error) {
+	key := j.jwtSigningKeys.Current()
+
+	token := jwt.NewWithClaims(j.jwtSigningMethod, claims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+
+	return token.SignedString(key.Secret)
+}
+
+// verificationKey returns the signing key named in the "kid" header of a token.
+// Tokens without the header were signed with the key that has no ID.
+func (j *JWTAuthz) verificationKey(token *jwt.Token) (any, error) {
+	if !(j.jwtSigningMethod == token.Method) {
+		// Check our method hasn't changed since issuance
+		return nil, errors.New("signing method mismatch")
+	}
+
+	id, _ := token.Header["kid"].(string)
+	key, err := j.jwtSigningKeys.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.Secret, nil
+}
+
+// CreateRefreshToken creates a new opaque refresh token. Only its hash should be stored
+// on the server, the token itself is handed to the client.
+func (j *JWTAuthz) CreateRefreshToken() (token string, tokenHash string, expiresAt time.Time, err error) {
+	token, err = randomToken(32)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+
+	return token, j.HashRefreshToken(token), time.Now().Add(j.refreshTTL), nil
+}
+
+// CreateSessionID creates a new random session ID.
+func (j *JWTAuthz) CreateSessionID() (string, error) {
+	return randomToken(16)
+}
+
+// HashRefreshToken computes the hash under which a refresh token is stored.
+func (j *JWTAuthz) HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// AccessTokenTTL returns the lifetime of the issued access tokens.
+func (j *JWTAuthz) AccessTokenTTL() time.Duration {
+	return j.accessTTL
+}
+
+// randomToken returns n random bytes encoded with URL-safe base64.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
 }
 
 // GetHash computes the SHA-256 hash of the concatenation of email and password.
@@ -146,13 +453,19 @@ func (j *JWTAuthz) AuthCookie(name string, token string) *http.Cookie {
 	return &d
 }
 
-// IsBcryptHash проверяет, является ли данная строка хешем bcrypt.
-func (j *JWTAuthz) IsBcryptHash(s string) bool {
-	_, err := bcrypt.Cost([]byte(s))
-	return err == nil
+// HashPassword derives the verifier that is stored for the password.
+func (j *JWTAuthz) HashPassword(password string) (string, error) {
+	return j.hasher.Hash(password)
 }
 
-func (j *JWTAuthz) CompareHashAndPassword(hashedPassword, password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	return err == nil
+// VerifyPassword checks the password against a stored verifier and reports
+// whether the verifier should be replaced with a fresh one.
+func (j *JWTAuthz) VerifyPassword(encoded, password string) (bool, bool) {
+	ok, needsRehash, err := j.hasher.Verify(encoded, password)
+	if err != nil {
+		j.log.Info("Error occurred verifying password", zap.Error(err))
+		return false, false
+	}
+
+	return ok, needsRehash
 }
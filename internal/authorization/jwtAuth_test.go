@@ -1,11 +1,17 @@
 package authz
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/gophkeeper-server/internal/keyring"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
 	"go.uber.org/zap/zapcore"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -15,17 +21,17 @@ type MockLogger struct{}
 func (m *MockLogger) Info(string, ...zapcore.Field) {}
 
 func TestJWTAuthz_CreateJWTTokenForUser(t *testing.T) {
-	jwtAuthz := NewJWTAuthz("secret", &MockLogger{})
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
 
-	token := jwtAuthz.CreateJWTTokenForUser("user123")
+	token := jwtAuthz.CreateJWTTokenForUser("user123", "session1")
 
 	assert.NotEmpty(t, token)
 }
 
 func TestJWTAuthz_DecodeJWTToUser(t *testing.T) {
-	jwtAuthz := NewJWTAuthz("secret", &MockLogger{})
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
 
-	token := jwtAuthz.CreateJWTTokenForUser("user123")
+	token := jwtAuthz.CreateJWTTokenForUser("user123", "session1")
 	userID, err := jwtAuthz.DecodeJWTToUser(token)
 
 	assert.NoError(t, err)
@@ -33,13 +39,13 @@ func TestJWTAuthz_DecodeJWTToUser(t *testing.T) {
 }
 
 func TestJWTAuthz_Middleware(t *testing.T) {
-	jwtAuthz := NewJWTAuthz("secret", &MockLogger{})
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
 
 	req := httptest.NewRequest("GET", "/", nil)
-	req.Header.Set("Authorization", jwtAuthz.CreateJWTTokenForUser("user123"))
+	req.Header.Set("Authorization", jwtAuthz.CreateJWTTokenForUser("user123", "session1"))
 	rr := httptest.NewRecorder()
 
 	middleware := jwtAuthz.JWTAuthzMiddleware(nil, &MockLogger{})(handler)
@@ -48,8 +54,254 @@ func TestJWTAuthz_Middleware(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
 
+type mockStorage struct {
+	revoked         map[string]bool
+	revokedSessions map[string]bool
+	touched         map[string]int
+	ip              string
+}
+
+func (m *mockStorage) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return m.revoked[jti], nil
+}
+
+func (m *mockStorage) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return !m.revokedSessions[sessionID], nil
+}
+
+func (m *mockStorage) TouchSession(ctx context.Context, sessionID string, ip string) error {
+	if m.touched == nil {
+		m.touched = make(map[string]int)
+	}
+	m.touched[sessionID]++
+	m.ip = ip
+	return nil
+}
+
+func TestJWTAuthz_TokenClaims(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", time.Minute, 0, nil, &MockLogger{})
+
+	claims, err := jwtAuthz.DecodeJWTToClaims(jwtAuthz.CreateJWTTokenForUser("user123", "session1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", claims.Email)
+	assert.Equal(t, "user123", claims.Subject)
+	assert.NotEmpty(t, claims.Id)
+	assert.NotZero(t, claims.IssuedAt)
+	assert.Equal(t, claims.IssuedAt+60, claims.ExpiresAt)
+
+	// Every token gets its own ID
+	other, err := jwtAuthz.DecodeJWTToClaims(jwtAuthz.CreateJWTTokenForUser("user123", "session1"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, claims.Id, other.Id)
+}
+
+func TestJWTAuthz_DecodeJWTToUser_Expired(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+
+	claims := CustomClaims{"user123", "session1", jwt.StandardClaims{Id: "jti", ExpiresAt: time.Now().Add(-time.Minute).Unix()}}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	assert.NoError(t, err)
+
+	_, err = jwtAuthz.DecodeJWTToUser(token)
+	assert.Error(t, err)
+}
+
+func TestJWTAuthz_DecodeJWTToUser_NoExpiration(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+
+	// Tokens issued before expiration was introduced are no longer accepted
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, CustomClaims{"user123", "session1", jwt.StandardClaims{}}).SignedString([]byte("secret"))
+	assert.NoError(t, err)
+
+	_, err = jwtAuthz.DecodeJWTToUser(token)
+	assert.Error(t, err)
+}
+
+func TestJWTAuthz_Middleware_Revoked(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	token := jwtAuthz.CreateJWTTokenForUser("user123", "session1")
+	claims, err := jwtAuthz.DecodeJWTToClaims(token)
+	assert.NoError(t, err)
+
+	storage := &mockStorage{revoked: map[string]bool{}}
+	middleware := jwtAuthz.JWTAuthzMiddleware(storage, &MockLogger{})(handler)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", token)
+	rr := httptest.NewRecorder()
+	middleware.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	storage.revoked[claims.Id] = true
+
+	rr = httptest.NewRecorder()
+	middleware.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestJWTAuthz_Middleware_RevokedSession(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+	var gotSessionID string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSessionID, _ = r.Context().Value(models.Key("sessionID")).(string)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	storage := &mockStorage{revokedSessions: map[string]bool{}}
+	middleware := jwtAuthz.JWTAuthzMiddleware(storage, &MockLogger{})(handler)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", jwtAuthz.CreateJWTTokenForUser("user123", "session1"))
+	rr := httptest.NewRecorder()
+	middleware.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "session1", gotSessionID)
+
+	storage.revokedSessions["session1"] = true
+
+	rr = httptest.NewRecorder()
+	middleware.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestJWTAuthz_Middleware_TouchSession(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	storage := &mockStorage{}
+	middleware := jwtAuthz.JWTAuthzMiddleware(storage, &MockLogger{})(handler)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("Authorization", jwtAuthz.CreateJWTTokenForUser("user123", "session1"))
+
+	// Requests of a session in quick succession touch it once
+	for i := 0; i < 3; i++ {
+		rr := httptest.NewRecorder()
+		middleware.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	}
+	assert.Equal(t, 1, storage.touched["session1"])
+	assert.Equal(t, "192.0.2.1", storage.ip)
+
+	req.Header.Set("Authorization", jwtAuthz.CreateJWTTokenForUser("user123", "session2"))
+	middleware.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, 1, storage.touched["session2"])
+}
+
+func TestTouchLimiter(t *testing.T) {
+	limiter := touchLimiter{last: make(map[string]time.Time)}
+	now := time.Now()
+
+	assert.True(t, limiter.allow("s1", now))
+	assert.False(t, limiter.allow("s1", now.Add(sessionTouchInterval/2)))
+	assert.True(t, limiter.allow("s2", now))
+	assert.True(t, limiter.allow("s1", now.Add(sessionTouchInterval)))
+
+	// Sessions that were not touched for an interval are forgotten
+	limiter.allow("s3", now.Add(3*sessionTouchInterval))
+	assert.Len(t, limiter.last, 1)
+}
+
+func TestJWTAuthz_DecodeJWTToUser_NoSession(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+
+	token := jwtAuthz.CreateJWTTokenForUser("user123", "")
+
+	_, err := jwtAuthz.DecodeJWTToUser(token)
+	assert.Error(t, err)
+}
+
+// newKeyRingAuthz returns a JWTAuthz that signs the tokens with the given key ring.
+func newKeyRingAuthz(t *testing.T, signingKeys string) *JWTAuthz {
+	keys, err := LoadSigningKeys("", signingKeys)
+	require.NoError(t, err)
+
+	return NewJWTAuthzWithKeys(keys, "secret", 0, 0, nil, &MockLogger{})
+}
+
+func TestLoadSigningKeys(t *testing.T) {
+	// A single key is used as a whole, whatever it contains
+	keys, err := LoadSigningKeys("2024:a,b", "")
+	require.NoError(t, err)
+	require.Len(t, keys.Keys(), 1)
+	assert.Equal(t, "", keys.Current().ID)
+	assert.Equal(t, []byte("2024:a,b"), keys.Current().Secret)
+
+	// A key ring replaces the single key
+	keys, err = LoadSigningKeys("single", "2024:fresh,secret")
+	require.NoError(t, err)
+	require.Len(t, keys.Keys(), 2)
+	assert.Equal(t, "2024", keys.Current().ID)
+	assert.Equal(t, []byte("secret"), keys.Keys()[1].Secret)
+
+	_, err = LoadSigningKeys("single", "2024:a,2024:b")
+	assert.ErrorIs(t, err, keyring.ErrDuplicateKey)
+}
+
+func TestJWTAuthz_KeyRotation(t *testing.T) {
+	old := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+	oldToken := old.CreateJWTTokenForUser("user123", "session1")
+
+	// A new current key signs new tokens, the old key still verifies the old ones
+	rotated := newKeyRingAuthz(t, "2024:fresh,secret")
+	newToken := rotated.CreateJWTTokenForUser("user123", "session1")
+
+	userID, err := rotated.DecodeJWTToUser(oldToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", userID)
+
+	userID, err = rotated.DecodeJWTToUser(newToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", userID)
+
+	// Once the old key is dropped its tokens are rejected
+	dropped := newKeyRingAuthz(t, "2024:fresh")
+	_, err = dropped.DecodeJWTToUser(oldToken)
+	assert.Error(t, err)
+	_, err = dropped.DecodeJWTToUser(newToken)
+	assert.NoError(t, err)
+
+	// The old server does not know the key of the new tokens
+	_, err = old.DecodeJWTToUser(newToken)
+	assert.Error(t, err)
+
+	// Fake SRP salts do not depend on the signing keys
+	assert.Equal(t, old.FakeSRPSalt("mallory"), rotated.FakeSRPSalt("mallory"))
+	assert.Equal(t, old.FakeSRPSalt("mallory"), dropped.FakeSRPSalt("mallory"))
+}
+
+func TestJWTAuthz_CreateSessionID(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+
+	first, err := jwtAuthz.CreateSessionID()
+	assert.NoError(t, err)
+	second, err := jwtAuthz.CreateSessionID()
+	assert.NoError(t, err)
+
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second)
+}
+
+func TestJWTAuthz_CreateRefreshToken(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, time.Hour, nil, &MockLogger{})
+
+	token, tokenHash, expiresAt, err := jwtAuthz.CreateRefreshToken()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.NotEqual(t, token, tokenHash)
+	assert.Equal(t, jwtAuthz.HashRefreshToken(token), tokenHash)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+}
+
 func TestJWTAuthz_Middleware_Unauthorized(t *testing.T) {
-	jwtAuthz := NewJWTAuthz("secret", &MockLogger{})
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("Handler should not have been called")
 	})
@@ -64,7 +316,7 @@ func TestJWTAuthz_Middleware_Unauthorized(t *testing.T) {
 }
 
 func TestJWTAuthz_GetHash(t *testing.T) {
-	jwtAuthz := NewJWTAuthz("secret", &MockLogger{})
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
 
 	email := "test@example.com"
 	password := "password123"
@@ -73,18 +325,8 @@ func TestJWTAuthz_GetHash(t *testing.T) {
 	assert.NotNil(t, expectedHash)
 }
 
-func TestJWTAuthz_IsBcryptHash(t *testing.T) {
-	jwtAuthz := NewJWTAuthz("secret", &MockLogger{})
-
-	password := "password123"
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	assert.NoError(t, err)
-
-	assert.True(t, jwtAuthz.IsBcryptHash(string(hashedPassword)))
-}
-
 func TestJWTAuthz_AuthCookie(t *testing.T) {
-	jwtAuthz := NewJWTAuthz("secret", &MockLogger{})
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
 
 	cookieName := "test_cookie"
 	tokenValue := "test_token"
@@ -96,12 +338,35 @@ func TestJWTAuthz_AuthCookie(t *testing.T) {
 	assert.True(t, cookie.HttpOnly)
 }
 
-func TestJWTAuthz_CompareHashAndPassword(t *testing.T) {
-	jwtAuthz := NewJWTAuthz("secret", &MockLogger{})
+func TestJWTAuthz_HashAndVerifyPassword(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, NewArgon2idHasher(testArgon2idParams), &MockLogger{})
+
+	hashedPassword, err := jwtAuthz.HashPassword("password123")
+	assert.NoError(t, err)
+
+	ok, needsRehash := jwtAuthz.VerifyPassword(hashedPassword, "password123")
+	assert.True(t, ok)
+	assert.False(t, needsRehash)
+
+	ok, _ = jwtAuthz.VerifyPassword(hashedPassword, "wrong")
+	assert.False(t, ok)
+
+	ok, _ = jwtAuthz.VerifyPassword("garbage", "password123")
+	assert.False(t, ok)
+}
+
+func TestJWTAuthz_VerifyPassword_LegacyBcrypt(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, NewArgon2idHasher(testArgon2idParams), &MockLogger{})
 
 	password := "password123"
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
 	assert.NoError(t, err)
 
-	assert.True(t, jwtAuthz.CompareHashAndPassword(string(hashedPassword), password))
+	ok, needsRehash := jwtAuthz.VerifyPassword(string(hashedPassword), password)
+	assert.True(t, ok)
+	assert.True(t, needsRehash)
+
+	// The hash itself is not accepted as a password any more
+	ok, _ = jwtAuthz.VerifyPassword(string(hashedPassword), string(hashedPassword))
+	assert.False(t, ok)
 }
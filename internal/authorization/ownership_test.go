@@ -0,0 +1,203 @@
+package authz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wurt83ow/gophkeeper-server/internal/controllers"
+)
+
+// stubServer answers 200 OK on every route that takes a userID.
+type stubServer struct {
+	controllers.ServerInterface
+}
+
+func (s *stubServer) PostAddDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table string, userID int, entryID string) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) DeleteDeleteDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table string, userID int, entryID string) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) GetGetAllDataTableUserID(w http.ResponseWriter, r *http.Request, table string, userID int, lastSyncStr string) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) GetGetDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table string, userID int, entryID string) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) PostBatchUserID(w http.ResponseWriter, r *http.Request, userID int) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) GetGetFileUserIDEntryID(w http.ResponseWriter, r *http.Request, userID int, entryID string) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) PostSendFileUserID(w http.ResponseWriter, r *http.Request, userID int, fileName string) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) GetSessionsUserID(w http.ResponseWriter, r *http.Request, userID int) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) DeleteSessionsUserID(w http.ResponseWriter, r *http.Request, userID int) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) DeleteSessionsUserIDSessionID(w http.ResponseWriter, r *http.Request, userID int, sessionID string) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) PostMfaUserIDEnroll(w http.ResponseWriter, r *http.Request, userID int) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) PostMfaUserIDVerify(w http.ResponseWriter, r *http.Request, userID int) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) GetSyncUserID(w http.ResponseWriter, r *http.Request, userID int, params controllers.GetSyncUserIDParams) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) GetEventsUserID(w http.ResponseWriter, r *http.Request, userID int) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) PutUpdateDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table string, userID int, entryID string) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) PostUploadsUserID(w http.ResponseWriter, r *http.Request, userID int) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) HeadUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request, userID int, uploadID string) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) PatchUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request, userID int, uploadID string, params controllers.PatchUploadsUserIDUploadIDParams) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) PostUploadsUserIDUploadIDFinish(w http.ResponseWriter, r *http.Request, userID int, uploadID string) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) DeleteUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request, userID int, uploadID string) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *stubServer) PostLogin(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func newOwnershipTestHandler(jwtAuthz *JWTAuthz) http.Handler {
+	return controllers.HandlerWithOptions(&stubServer{}, controllers.ChiServerOptions{
+		Middlewares: []controllers.MiddlewareFunc{
+			controllers.Secured(jwtAuthz.OwnershipMiddleware(&MockLogger{})),
+			controllers.Secured(jwtAuthz.JWTAuthzMiddleware(nil, &MockLogger{})),
+		},
+	})
+}
+
+func TestJWTAuthz_OwnershipMiddleware(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+	handler := newOwnershipTestHandler(jwtAuthz)
+	token := jwtAuthz.CreateJWTTokenForUser("1", "session1")
+
+	routes := []struct {
+		name   string
+		method string
+		own    string
+		other  string
+	}{
+		{"addData", http.MethodPost, "/addData/TextData/1/entry", "/addData/TextData/2/entry"},
+		{"deleteData", http.MethodDelete, "/deleteData/TextData/1/entry", "/deleteData/TextData/2/entry"},
+		{"getAllData", http.MethodGet, "/getAllData/TextData/1/2024-01-01T00:00:00Z", "/getAllData/TextData/2/2024-01-01T00:00:00Z"},
+		{"getData", http.MethodGet, "/getData/TextData/1/entry", "/getData/TextData/2/entry"},
+		{"batch", http.MethodPost, "/batch/1", "/batch/2"},
+		{"getFile", http.MethodGet, "/getFile/1/entry", "/getFile/2/entry"},
+		{"sendFile", http.MethodPost, "/sendFile/1/file.bin", "/sendFile/2/file.bin"},
+		{"updateData", http.MethodPut, "/updateData/TextData/1/entry", "/updateData/TextData/2/entry"},
+		{"listSessions", http.MethodGet, "/sessions/1", "/sessions/2"},
+		{"revokeSessions", http.MethodDelete, "/sessions/1", "/sessions/2"},
+		{"revokeSession", http.MethodDelete, "/sessions/1/session", "/sessions/2/session"},
+		{"mfaEnroll", http.MethodPost, "/mfa/1/enroll", "/mfa/2/enroll"},
+		{"mfaVerify", http.MethodPost, "/mfa/1/verify", "/mfa/2/verify"},
+		{"sync", http.MethodGet, "/sync/1", "/sync/2"},
+		{"events", http.MethodGet, "/events/1", "/events/2"},
+		{"createUpload", http.MethodPost, "/uploads/1", "/uploads/2"},
+		{"uploadOffset", http.MethodHead, "/uploads/1/upload", "/uploads/2/upload"},
+		{"uploadChunk", http.MethodPatch, "/uploads/1/upload", "/uploads/2/upload"},
+		{"finishUpload", http.MethodPost, "/uploads/1/upload/finish", "/uploads/2/upload/finish"},
+		{"abortUpload", http.MethodDelete, "/uploads/1/upload", "/uploads/2/upload"},
+	}
+
+	// The upload chunk route requires Upload-Offset before any middleware runs
+	for _, route := range routes {
+		t.Run(route.name+"_own", func(t *testing.T) {
+			req := httptest.NewRequest(route.method, route.own, nil)
+			req.Header.Set("Authorization", token)
+			req.Header.Set("Upload-Offset", "0")
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+		})
+
+		t.Run(route.name+"_other", func(t *testing.T) {
+			req := httptest.NewRequest(route.method, route.other, nil)
+			req.Header.Set("Authorization", token)
+			req.Header.Set("Upload-Offset", "0")
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusForbidden, rr.Code)
+		})
+
+		t.Run(route.name+"_no_token", func(t *testing.T) {
+			req := httptest.NewRequest(route.method, route.own, nil)
+			req.Header.Set("Upload-Offset", "0")
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		})
+	}
+}
+
+func TestJWTAuthz_OwnershipMiddleware_PublicRoutes(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+	handler := newOwnershipTestHandler(jwtAuthz)
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestJWTAuthz_OwnershipMiddleware_NoUserIDParam(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	rr := httptest.NewRecorder()
+
+	jwtAuthz.OwnershipMiddleware(&MockLogger{})(handler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
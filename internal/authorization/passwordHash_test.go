@@ -0,0 +1,63 @@
+package authz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testArgon2idParams keeps the tests fast.
+var testArgon2idParams = Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func TestArgon2idHasher_Hash(t *testing.T) {
+	hasher := NewArgon2idHasher(testArgon2idParams)
+
+	first, err := hasher.Hash("password123")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(first, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+	// A random salt makes every verifier unique
+	second, err := hasher.Hash("password123")
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second)
+}
+
+func TestArgon2idHasher_Verify(t *testing.T) {
+	hasher := NewArgon2idHasher(testArgon2idParams)
+
+	encoded, err := hasher.Hash("password123")
+	assert.NoError(t, err)
+
+	ok, needsRehash, err := hasher.Verify(encoded, "password123")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, needsRehash)
+
+	ok, _, err = hasher.Verify(encoded, "password124")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestArgon2idHasher_Verify_ChangedParams(t *testing.T) {
+	encoded, err := NewArgon2idHasher(testArgon2idParams).Hash("password123")
+	assert.NoError(t, err)
+
+	stronger := testArgon2idParams
+	stronger.Iterations = 2
+
+	ok, needsRehash, err := NewArgon2idHasher(stronger).Verify(encoded, "password123")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, needsRehash)
+}
+
+func TestArgon2idHasher_Verify_InvalidHash(t *testing.T) {
+	hasher := NewArgon2idHasher(testArgon2idParams)
+
+	for _, encoded := range []string{"", "plaintext", "$argon2id$v=19$m=1,t=1$salt", "$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$a2V5"} {
+		ok, _, err := hasher.Verify(encoded, "password123")
+		assert.ErrorIs(t, err, ErrInvalidHash, encoded)
+		assert.False(t, ok)
+	}
+}
@@ -0,0 +1,311 @@
+package authz
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+)
+
+// SRP-6a (RFC 5054) with the 2048-bit group and SHA-256 as the hash function.
+// All numbers travel as hex strings.
+const (
+	srpSaltSize      = 16 // bytes
+	srpEphemeralSize = 32 // bytes
+	srpChallengeTTL  = 2 * time.Minute
+
+	srpGroup2048 = "AC6BDB41324A9A9BF166DE5E1389582FAF72B6651987EE07FC3192943DB56050" +
+		"A37329CBB4A099ED8193E0757767A13DD52312AB4B03310DCD7F48A9DA04FD50" +
+		"E8083969EDB767B0CF6095179A163AB3661A05FBD5FAAAE82918A9962F0B93B8" +
+		"55F97993EC975EEAA80D740ADBF4FF747359D041D5C33EA71D281E446B14773B" +
+		"CA97B43A23FB801676BD207A436C6481F1D2B9078717461A5B9D32E688F87748" +
+		"544523B524B0D57D5EA77A2775D2ECFA032CFBDBF52FB3786160279004E57AE6" +
+		"AF874E7303CE53299CCC041C7BC308D82A5698F3A8D0C38271AE35F8E9DBFBB6" +
+		"94B5C803D89F7AE435DE236D525F54759B65E372FCD68EF20FA7111F9E4AFF73"
+)
+
+var (
+	srpN = func() *big.Int {
+		n, _ := new(big.Int).SetString(srpGroup2048, 16)
+		return n
+	}()
+	srpG = big.NewInt(2)
+	srpK = srpHash(srpPad(srpN), srpPad(srpG))
+)
+
+// ErrInvalidSRPValue is returned when a client sends a malformed or unsafe SRP value.
+var ErrInvalidSRPValue = errors.New("invalid SRP value")
+
+// srpServerEphemeral creates the server ephemeral values B = kv + g^b for a login attempt.
+// The private value b must stay on the server until the client proof arrives.
+func srpServerEphemeral(verifier string) (privateB string, publicB string, err error) {
+	v, err := srpParseHex(verifier)
+	if err != nil {
+		return "", "", err
+	}
+
+	b, err := srpRandomInt()
+	if err != nil {
+		return "", "", err
+	}
+
+	B := new(big.Int).Mul(srpK, v)
+	B.Add(B, new(big.Int).Exp(srpG, b, srpN))
+	B.Mod(B, srpN)
+
+	return hex.EncodeToString(b.Bytes()), hex.EncodeToString(B.Bytes()), nil
+}
+
+// ValidateSRPVerifier checks a registered salt and verifier before they are stored.
+// The salt must be hex and the verifier a hex number in (0, N), otherwise no login could ever succeed.
+func (j *JWTAuthz) ValidateSRPVerifier(verifier models.SRPVerifier) error {
+	if salt, err := hex.DecodeString(verifier.Salt); err != nil || len(salt) == 0 {
+		return ErrInvalidSRPValue
+	}
+	if _, err := srpParseHex(verifier.Verifier); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateSRPChallenge creates the server side of an SRP login attempt for the client ephemeral A.
+func (j *JWTAuthz) CreateSRPChallenge(username string, verifier models.SRPVerifier, publicA string) (models.SRPChallenge, error) {
+	// A = 0 (mod N) is rejected, otherwise S would be known to anyone
+	if _, err := srpParseHex(publicA); err != nil {
+		return models.SRPChallenge{}, err
+	}
+
+	privateB, publicB, err := srpServerEphemeral(verifier.Verifier)
+	if err != nil {
+		return models.SRPChallenge{}, err
+	}
+
+	id, err := randomToken(16)
+	if err != nil {
+		return models.SRPChallenge{}, err
+	}
+
+	return models.SRPChallenge{
+		ID:        id,
+		Username:  username,
+		A:         publicA,
+		B:         publicB,
+		PrivateB:  privateB,
+		ExpiresAt: time.Now().Add(srpChallengeTTL),
+	}, nil
+}
+
+// FakeSRPSalt returns a stable salt for unknown users, so that the login
+// does not reveal which usernames are registered. It is derived from the SRP salt
+// key, which does not change when the signing keys are rotated.
+func (j *JWTAuthz) FakeSRPSalt(username string) string {
+	mac := hmac.New(sha256.New, j.srpSaltKey)
+	mac.Write([]byte("srp-salt:" + username))
+
+	return hex.EncodeToString(mac.Sum(nil)[:srpSaltSize])
+}
+
+// VerifySRPProof checks the client proof M1 of a login attempt.
+// On success it returns the server proof M2, which lets the client authenticate the server.
+func (j *JWTAuthz) VerifySRPProof(verifier models.SRPVerifier, challenge models.SRPChallenge, clientProof string) (string, bool) {
+	salt, err1 := hex.DecodeString(verifier.Salt)
+	v, err2 := srpParseHex(verifier.Verifier)
+	A, err3 := srpParseHex(challenge.A)
+	B, err4 := srpParseHex(challenge.B)
+	b, err5 := srpParseHex(challenge.PrivateB)
+	M1, err6 := hex.DecodeString(clientProof)
+	if err := errors.Join(err1, err2, err3, err4, err5, err6); err != nil {
+		return "", false
+	}
+
+	u := srpScramble(A, B)
+	if u.Sign() == 0 {
+		return "", false
+	}
+
+	// S = (A * v^u) ^ b
+	S := new(big.Int).Exp(v, u, srpN)
+	S.Mul(S, A)
+	S.Exp(S, b, srpN)
+	K := srpHash(srpPad(S)).Bytes()
+
+	expected := srpClientProof(challenge.Username, salt, A, B, K)
+	if subtle.ConstantTimeCompare(expected, M1) != 1 {
+		return "", false
+	}
+
+	return hex.EncodeToString(srpServerProof(A, M1, K)), true
+}
+
+// NewSRPVerifier derives the salt and verifier that a client registers instead of the password.
+// It is the client side of the registration and never runs on the server.
+func NewSRPVerifier(username, password string) (models.SRPVerifier, error) {
+	salt := make([]byte, srpSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return models.SRPVerifier{}, err
+	}
+
+	v := new(big.Int).Exp(srpG, srpPrivateKey(username, password, salt), srpN)
+
+	return models.SRPVerifier{
+		Salt:     hex.EncodeToString(salt),
+		Verifier: hex.EncodeToString(v.Bytes()),
+	}, nil
+}
+
+// SRPClient is the client side of an SRP-6a login.
+type SRPClient struct {
+	username string
+	password string
+	a        *big.Int
+	publicA  *big.Int
+	proof    []byte
+	key      []byte
+}
+
+// NewSRPClient starts a login and creates the client ephemeral values.
+func NewSRPClient(username, password string) (*SRPClient, error) {
+	a, err := srpRandomInt()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SRPClient{
+		username: username,
+		password: password,
+		a:        a,
+		publicA:  new(big.Int).Exp(srpG, a, srpN),
+	}, nil
+}
+
+// PublicA returns the client ephemeral A that is sent to the server.
+func (c *SRPClient) PublicA() string {
+	return hex.EncodeToString(c.publicA.Bytes())
+}
+
+// Proof computes the client proof M1 from the salt and server ephemeral B.
+func (c *SRPClient) Proof(salt string, publicB string) (string, error) {
+	s, err := hex.DecodeString(salt)
+	if err != nil {
+		return "", err
+	}
+
+	B, err := srpParseHex(publicB)
+	if err != nil {
+		return "", err
+	}
+	u := srpScramble(c.publicA, B)
+	if u.Sign() == 0 {
+		return "", ErrInvalidSRPValue
+	}
+
+	// S = (B - k * g^x) ^ (a + u * x)
+	x := srpPrivateKey(c.username, c.password, s)
+	base := new(big.Int).Exp(srpG, x, srpN)
+	base.Mul(base, srpK)
+	base.Sub(B, base)
+	base.Mod(base, srpN)
+
+	exp := new(big.Int).Mul(u, x)
+	exp.Add(exp, c.a)
+
+	S := new(big.Int).Exp(base, exp, srpN)
+	c.key = srpHash(srpPad(S)).Bytes()
+	c.proof = srpClientProof(c.username, s, c.publicA, B, c.key)
+
+	return hex.EncodeToString(c.proof), nil
+}
+
+// VerifyServerProof checks the server proof M2, which proves that the server knows the verifier.
+func (c *SRPClient) VerifyServerProof(serverProof string) bool {
+	M2, err := hex.DecodeString(serverProof)
+	if err != nil || c.proof == nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(srpServerProof(c.publicA, c.proof, c.key), M2) == 1
+}
+
+// srpPrivateKey computes x = H(s | H(I | ":" | P)).
+func srpPrivateKey(username, password string, salt []byte) *big.Int {
+	inner := sha256.Sum256([]byte(username + ":" + password))
+	return srpHash(salt, inner[:])
+}
+
+// srpScramble computes u = H(PAD(A) | PAD(B)).
+func srpScramble(A, B *big.Int) *big.Int {
+	return srpHash(srpPad(A), srpPad(B))
+}
+
+// srpClientProof computes M1 = H(H(N) xor H(g) | H(I) | s | A | B | K) as in RFC 2945.
+func srpClientProof(username string, salt []byte, A, B *big.Int, K []byte) []byte {
+	hN := sha256.Sum256(srpN.Bytes())
+	hG := sha256.Sum256(srpG.Bytes())
+	for i := range hN {
+		hN[i] ^= hG[i]
+	}
+	hI := sha256.Sum256([]byte(username))
+
+	h := sha256.New()
+	h.Write(hN[:])
+	h.Write(hI[:])
+	h.Write(salt)
+	h.Write(A.Bytes())
+	h.Write(B.Bytes())
+	h.Write(K)
+
+	return h.Sum(nil)
+}
+
+// srpServerProof computes M2 = H(A | M1 | K).
+func srpServerProof(A *big.Int, M1, K []byte) []byte {
+	h := sha256.New()
+	h.Write(A.Bytes())
+	h.Write(M1)
+	h.Write(K)
+
+	return h.Sum(nil)
+}
+
+// srpHash hashes the concatenation of the values into a number.
+func srpHash(values ...[]byte) *big.Int {
+	h := sha256.New()
+	for _, value := range values {
+		h.Write(value)
+	}
+
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// srpPad left-pads a number with zeros to the length of N.
+func srpPad(n *big.Int) []byte {
+	padded := make([]byte, (srpN.BitLen()+7)/8)
+	return n.FillBytes(padded)
+}
+
+// srpParseHex parses a hex-encoded positive number that is smaller than N.
+func srpParseHex(value string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(value, 16)
+	if !ok || n.Sign() <= 0 || n.Cmp(srpN) >= 0 {
+		return nil, ErrInvalidSRPValue
+	}
+
+	return n, nil
+}
+
+// srpRandomInt creates a random private ephemeral value.
+func srpRandomInt() (*big.Int, error) {
+	buf := make([]byte, srpEphemeralSize)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(buf), nil
+}
@@ -0,0 +1,126 @@
+package authz
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+)
+
+// srpLogin runs both round trips of a login with the reference client.
+func srpLogin(t *testing.T, jwtAuthz *JWTAuthz, username, password, registeredAs string) (*SRPClient, string, bool) {
+	verifier, err := NewSRPVerifier(registeredAs, password)
+	require.NoError(t, err)
+
+	client, err := NewSRPClient(username, password)
+	require.NoError(t, err)
+
+	challenge, err := jwtAuthz.CreateSRPChallenge(registeredAs, verifier, client.PublicA())
+	require.NoError(t, err)
+
+	clientProof, err := client.Proof(verifier.Salt, challenge.B)
+	require.NoError(t, err)
+
+	serverProof, ok := jwtAuthz.VerifySRPProof(verifier, challenge, clientProof)
+	return client, serverProof, ok
+}
+
+func TestSRP_Login(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+
+	client, serverProof, ok := srpLogin(t, jwtAuthz, "user", "password", "user")
+	assert.True(t, ok)
+	assert.True(t, client.VerifyServerProof(serverProof))
+	assert.False(t, client.VerifyServerProof(hex.EncodeToString(make([]byte, 32))))
+}
+
+func TestSRP_WrongPassword(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+
+	verifier, err := NewSRPVerifier("user", "password")
+	require.NoError(t, err)
+
+	client, err := NewSRPClient("user", "wrong")
+	require.NoError(t, err)
+
+	challenge, err := jwtAuthz.CreateSRPChallenge("user", verifier, client.PublicA())
+	require.NoError(t, err)
+
+	clientProof, err := client.Proof(verifier.Salt, challenge.B)
+	require.NoError(t, err)
+
+	serverProof, ok := jwtAuthz.VerifySRPProof(verifier, challenge, clientProof)
+	assert.False(t, ok)
+	assert.Empty(t, serverProof)
+}
+
+func TestSRP_WrongUsername(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+
+	// The verifier is bound to the username
+	_, _, ok := srpLogin(t, jwtAuthz, "other", "password", "user")
+	assert.False(t, ok)
+}
+
+func TestSRP_RejectsUnsafeEphemeral(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+
+	verifier, err := NewSRPVerifier("user", "password")
+	require.NoError(t, err)
+
+	// A = 0 and A = N would make the session key independent of the password
+	for _, publicA := range []string{"0", srpGroup2048, "not-hex"} {
+		_, err := jwtAuthz.CreateSRPChallenge("user", verifier, publicA)
+		assert.ErrorIs(t, err, ErrInvalidSRPValue, publicA)
+	}
+}
+
+func TestJWTAuthz_ValidateSRPVerifier(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+
+	verifier, err := NewSRPVerifier("user", "password")
+	require.NoError(t, err)
+	assert.NoError(t, jwtAuthz.ValidateSRPVerifier(verifier))
+
+	// Malformed rows could never be used for a login
+	for _, bad := range []models.SRPVerifier{
+		{Salt: "not-hex", Verifier: verifier.Verifier},
+		{Salt: "", Verifier: verifier.Verifier},
+		{Salt: verifier.Salt, Verifier: "0"},
+		{Salt: verifier.Salt, Verifier: srpGroup2048},
+		{Salt: verifier.Salt, Verifier: "not-hex"},
+	} {
+		assert.ErrorIs(t, jwtAuthz.ValidateSRPVerifier(bad), ErrInvalidSRPValue, bad)
+	}
+}
+
+func TestSRP_ServerNeverSeesPassword(t *testing.T) {
+	verifier1, err := NewSRPVerifier("user", "password")
+	require.NoError(t, err)
+	verifier2, err := NewSRPVerifier("user", "password")
+	require.NoError(t, err)
+
+	// Every registration uses a new salt, so equal passwords give different verifiers
+	assert.NotEqual(t, verifier1.Salt, verifier2.Salt)
+	assert.NotEqual(t, verifier1.Verifier, verifier2.Verifier)
+}
+
+func TestJWTAuthz_FakeSRPSalt(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+
+	// Unknown users get a stable salt, so repeated logins do not reveal them
+	assert.Equal(t, jwtAuthz.FakeSRPSalt("ghost"), jwtAuthz.FakeSRPSalt("ghost"))
+	assert.NotEqual(t, jwtAuthz.FakeSRPSalt("ghost"), jwtAuthz.FakeSRPSalt("other"))
+	assert.Len(t, jwtAuthz.FakeSRPSalt("ghost"), srpSaltSize*2)
+
+	// The salts follow the SRP salt key, not the signing keys
+	keys, err := LoadSigningKeys("", "2024:fresh")
+	require.NoError(t, err)
+	rotated := NewJWTAuthzWithKeys(keys, "secret", 0, 0, nil, &MockLogger{})
+	assert.Equal(t, jwtAuthz.FakeSRPSalt("ghost"), rotated.FakeSRPSalt("ghost"))
+
+	other := NewJWTAuthzWithKeys(keys, "other", 0, 0, nil, &MockLogger{})
+	assert.NotEqual(t, jwtAuthz.FakeSRPSalt("ghost"), other.FakeSRPSalt("ghost"))
+}
@@ -0,0 +1,191 @@
+package authz
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+)
+
+// TOTP parameters as recommended by RFC 6238.
+const (
+	totpIssuer     = "GophKeeper"
+	totpDigits     = 6
+	totpPeriod     = 30 // seconds
+	totpSkewSteps  = 1  // accepted clock drift in steps
+	totpSecretSize = 20 // bytes, the size of an HMAC-SHA1 key
+
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 10 // bytes
+
+	mfaChallengeAudience = "mfa"
+	mfaChallengeTTL      = 5 * time.Minute
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MFAChallengeClaims represents the claims of the token issued between the password
+// and the second factor steps of the login.
+type MFAChallengeClaims struct {
+	UserID     int    `json:"uid"`
+	DeviceID   string `json:"did,omitempty"`
+	DeviceName string `json:"dnm,omitempty"`
+	jwt.StandardClaims
+}
+
+// CreateTOTPSecret creates a new random base32-encoded TOTP secret.
+func (j *JWTAuthz) CreateTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TOTPProvisioningURI returns the otpauth:// URI that authenticator apps import, usually as a QR code.
+func (j *JWTAuthz) TOTPProvisioningURI(account string, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", totpIssuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", strconv.Itoa(totpDigits))
+	params.Set("period", strconv.Itoa(totpPeriod))
+
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + totpIssuer + ":" + account,
+		RawQuery: params.Encode(),
+	}).String()
+}
+
+// ValidateTOTP checks a code against the secret at the current time and returns
+// the time step it belongs to. Callers should reject steps that were already used.
+func (j *JWTAuthz) ValidateTOTP(secret string, code string) (int64, bool) {
+	return validateTOTP(secret, code, time.Now())
+}
+
+// validateTOTP checks a code against the secret at the given time, allowing a small clock drift.
+func validateTOTP(secret string, code string, now time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkewSteps; step <= current+totpSkewSteps; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// totpCode computes the HOTP value (RFC 4226) of the key for the given counter.
+func totpCode(key []byte, counter int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, value%mod)
+}
+
+// CreateRecoveryCodes creates one-time recovery codes and the hashes under which they are stored.
+func (j *JWTAuthz) CreateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		b := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		code := totpEncoding.EncodeToString(b)
+		codes[i] = code[:8] + "-" + code[8:]
+		hashes[i] = j.HashRecoveryCode(codes[i])
+	}
+
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode computes the hash under which a recovery code is stored.
+// Case and dashes are ignored, so users may type the code either way.
+func (j *JWTAuthz) HashRecoveryCode(code string) string {
+	normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateMFAChallenge creates a short-lived token proving that the user has passed the password step.
+// It is not accepted as an access token.
+func (j *JWTAuthz) CreateMFAChallenge(userID int, deviceID, deviceName string) (string, error) {
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := MFAChallengeClaims{
+		UserID:     userID,
+		DeviceID:   deviceID,
+		DeviceName: deviceName,
+		StandardClaims: jwt.StandardClaims{
+			Audience:  mfaChallengeAudience,
+			Id:        jti,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(mfaChallengeTTL).Unix(),
+		},
+	}
+
+	return j.signToken(claims)
+}
+
+// DecodeMFAChallenge decodes and validates a token created by CreateMFAChallenge.
+func (j *JWTAuthz) DecodeMFAChallenge(token string) (models.MFAChallenge, error) {
+	decodeToken, err := jwt.ParseWithClaims(token, &MFAChallengeClaims{}, j.verificationKey)
+	if err != nil {
+		return models.MFAChallenge{}, err
+	}
+
+	claims, ok := decodeToken.Claims.(*MFAChallengeClaims)
+	if !ok || !decodeToken.Valid || !claims.VerifyAudience(mfaChallengeAudience, true) || claims.Id == "" {
+		return models.MFAChallenge{}, errors.New("invalid MFA challenge")
+	}
+
+	return models.MFAChallenge{
+		ID:         claims.Id,
+		UserID:     claims.UserID,
+		DeviceID:   claims.DeviceID,
+		DeviceName: claims.DeviceName,
+		ExpiresAt:  time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
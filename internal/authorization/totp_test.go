@@ -0,0 +1,111 @@
+package authz
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfc6238Secret is the SHA-1 seed of the RFC 6238 test vectors, base32-encoded.
+var rfc6238Secret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestValidateTOTP_RFC6238Vectors(t *testing.T) {
+	// The last six digits of the eight-digit values from RFC 6238, Appendix B
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, v := range vectors {
+		step, ok := validateTOTP(rfc6238Secret, v.code, time.Unix(v.unix, 0))
+		assert.True(t, ok, v.code)
+		assert.Equal(t, v.unix/totpPeriod, step)
+	}
+}
+
+func TestValidateTOTP_Window(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	// Codes of the neighbouring steps are accepted to tolerate clock drift
+	_, ok := validateTOTP(rfc6238Secret, "005924", now.Add(totpPeriod*time.Second))
+	assert.True(t, ok)
+	_, ok = validateTOTP(rfc6238Secret, "005924", now.Add(-totpPeriod*time.Second))
+	assert.True(t, ok)
+
+	// Older codes are rejected
+	_, ok = validateTOTP(rfc6238Secret, "005924", now.Add(3*totpPeriod*time.Second))
+	assert.False(t, ok)
+
+	_, ok = validateTOTP(rfc6238Secret, "000000", now)
+	assert.False(t, ok)
+	_, ok = validateTOTP(rfc6238Secret, "05924", now)
+	assert.False(t, ok)
+	_, ok = validateTOTP("not base32!", "005924", now)
+	assert.False(t, ok)
+}
+
+func TestJWTAuthz_TOTPSecretAndURI(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+
+	secret, err := jwtAuthz.CreateTOTPSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	uri, err := url.Parse(jwtAuthz.TOTPProvisioningURI("alice", secret))
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/GophKeeper:alice", uri.Path)
+	assert.Equal(t, secret, uri.Query().Get("secret"))
+	assert.Equal(t, "GophKeeper", uri.Query().Get("issuer"))
+
+	// A code computed from the secret is accepted
+	key, err := totpEncoding.DecodeString(secret)
+	assert.NoError(t, err)
+	_, ok := jwtAuthz.ValidateTOTP(secret, totpCode(key, time.Now().Unix()/totpPeriod))
+	assert.True(t, ok)
+}
+
+func TestJWTAuthz_CreateRecoveryCodes(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+
+	codes, hashes, err := jwtAuthz.CreateRecoveryCodes()
+	assert.NoError(t, err)
+	assert.Len(t, codes, recoveryCodeCount)
+	assert.Len(t, hashes, recoveryCodeCount)
+
+	for i, code := range codes {
+		assert.Equal(t, hashes[i], jwtAuthz.HashRecoveryCode(code))
+		// Case and dashes do not matter
+		assert.Equal(t, hashes[i], jwtAuthz.HashRecoveryCode(strings.ToLower(strings.ReplaceAll(code, "-", ""))))
+	}
+}
+
+func TestJWTAuthz_MFAChallenge(t *testing.T) {
+	jwtAuthz := NewJWTAuthz("secret", 0, 0, nil, &MockLogger{})
+
+	token, err := jwtAuthz.CreateMFAChallenge(7, "device", "laptop")
+	assert.NoError(t, err)
+
+	challenge, err := jwtAuthz.DecodeMFAChallenge(token)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, challenge.UserID)
+	assert.Equal(t, "device", challenge.DeviceID)
+	assert.Equal(t, "laptop", challenge.DeviceName)
+	assert.NotEmpty(t, challenge.ID)
+
+	// A challenge is not an access token and vice versa
+	_, err = jwtAuthz.DecodeJWTToClaims(token)
+	assert.Error(t, err)
+	_, err = jwtAuthz.DecodeMFAChallenge(jwtAuthz.CreateJWTTokenForUser("7", "session1"))
+	assert.Error(t, err)
+}
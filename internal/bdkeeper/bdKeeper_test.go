@@ -3,11 +3,21 @@ package bdkeeper
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"os"
+	"reflect"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/wurt83ow/gophkeeper-server/internal/config"
 	"github.com/wurt83ow/gophkeeper-server/internal/logger"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/schema"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage/storagetest"
 )
 
 // Функция для создания экземпляра BDKeeper с помощью NewBDKeeper
@@ -168,6 +178,239 @@ func TestBDKeeper_GetPassword(t *testing.T) {
 		t.Errorf("Unfulfilled expectations: %s", err)
 	}
 }
+func TestBDKeeper_UpdatePassword(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Ожидание замены хеша пароля и сдвига последовательности изменений пользователя
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE Users SET password = (.+) WHERE username = (.+) RETURNING id").
+		WithArgs("newHashedPassword", "testUser").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery("INSERT INTO SyncSequences").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(5))
+	mock.ExpectCommit()
+
+	// Замена хеша пароля
+	err = bdk.UpdatePassword(context.Background(), "testUser", "newHashedPassword")
+	if err != nil {
+		t.Fatalf("Error updating password: %v", err)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBDKeeper_LastChangeSeq(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Ожидание запросов счётчика изменений
+	mock.ExpectQuery("SELECT last_seq FROM SyncSequences WHERE user_id = (.+)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(7))
+	mock.ExpectQuery("SELECT last_seq FROM SyncSequences WHERE user_id = (.+)").
+		WithArgs(2).
+		WillReturnError(sql.ErrNoRows)
+
+	seq, err := bdk.LastChangeSeq(context.Background(), 1)
+	if err != nil || seq != 7 {
+		t.Errorf("Expected change seq 7, got %d (%v)", seq, err)
+	}
+
+	// Пользователь без изменений
+	seq, err = bdk.LastChangeSeq(context.Background(), 2)
+	if err != nil || seq != 0 {
+		t.Errorf("Expected change seq 0, got %d (%v)", seq, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBDKeeper_AddDataKey(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Другой сервер уже сохранил ключ, поэтому возвращается он
+	mock.ExpectExec("INSERT INTO DataKeys (.+) ON CONFLICT (.+) DO NOTHING").
+		WithArgs(1, "wrapped").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT wrapped_key FROM DataKeys WHERE user_id = (.+)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"wrapped_key"}).AddRow("stored"))
+	mock.ExpectQuery("SELECT wrapped_key FROM DataKeys WHERE user_id = (.+)").
+		WithArgs(2).
+		WillReturnError(sql.ErrNoRows)
+
+	wrappedKey, err := bdk.AddDataKey(context.Background(), 1, "wrapped")
+	if err != nil || wrappedKey != "stored" {
+		t.Errorf("Expected the stored key, got %q (%v)", wrappedKey, err)
+	}
+
+	// Пользователь без ключа
+	_, err = bdk.GetDataKey(context.Background(), 2)
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected storage.ErrNotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBDKeeper_ReplaceDataKey(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Первая замена удаётся, вторая находит уже другой ключ
+	mock.ExpectExec("UPDATE DataKeys SET wrapped_key = (.+) WHERE user_id = (.+) AND wrapped_key = (.+)").
+		WithArgs("new", 1, "old").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE DataKeys SET wrapped_key = (.+) WHERE user_id = (.+) AND wrapped_key = (.+)").
+		WithArgs("newer", 1, "old").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := bdk.ReplaceDataKey(context.Background(), 1, "old", "new"); err != nil {
+		t.Errorf("Error replacing data key: %v", err)
+	}
+	if err := bdk.ReplaceDataKey(context.Background(), 1, "old", "newer"); !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("Expected storage.ErrConflict, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBDKeeper_CountUsers(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Ожидание запроса числа пользователей
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM Users")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	count, err := bdk.CountUsers(context.Background())
+	if err != nil {
+		t.Errorf("Error counting users: %v", err)
+	}
+	if count != 42 {
+		t.Errorf("Expected 42 users, got %d", count)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBDKeeper_RewriteColumns(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Столбцы заменяются без изменения версии, вторая замена находит уже другие значения
+	query := regexp.QuoteMeta("UPDATE CreditCardData SET card_number = $1, cvv = $2 WHERE user_id = $3 AND id = $4 AND card_number = $5 AND cvv = $6")
+	mock.ExpectExec("^"+query+"$").
+		WithArgs("enc:number", "enc:cvv", 1, "card", "4111", "123").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^"+query+"$").
+		WithArgs("enc:number", "enc:cvv", 1, "card", "4111", "123").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	current := map[string]string{"card_number": "4111", "cvv": "123"}
+	replacement := map[string]string{"card_number": "enc:number", "cvv": "enc:cvv"}
+	if err := bdk.RewriteColumns(context.Background(), "creditcarddata", 1, "card", current, replacement); err != nil {
+		t.Errorf("Error rewriting columns: %v", err)
+	}
+	if err := bdk.RewriteColumns(context.Background(), "CreditCardData", 1, "card", current, replacement); !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("Expected storage.ErrConflict, got %v", err)
+	}
+
+	// Неизвестные столбцы не попадают в запрос
+	if err := bdk.RewriteColumns(context.Background(), "CreditCardData", 1, "card", nil, map[string]string{"version": "1"}); !errors.Is(err, schema.ErrUnknownColumn) {
+		t.Errorf("Expected schema.ErrUnknownColumn, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBDKeeper_ListUserIDs(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Ожидание запроса страницы пользователей
+	mock.ExpectQuery("SELECT id FROM Users WHERE id > (.+) ORDER BY id LIMIT (.+)").
+		WithArgs(10, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11).AddRow(14))
+
+	userIDs, err := bdk.ListUserIDs(context.Background(), 10, 2)
+	if err != nil {
+		t.Fatalf("Error listing users: %v", err)
+	}
+	if len(userIDs) != 2 || userIDs[0] != 11 || userIDs[1] != 14 {
+		t.Errorf("Expected users [11 14], got %v", userIDs)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
 func TestBDKeeper_GetUserID(t *testing.T) {
 	// Инициализация sqlmock
 	db, mock, err := sqlmock.New()
@@ -209,16 +452,21 @@ func TestBDKeeper_AddData(t *testing.T) {
 
 	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
 	bdk := newTestBDKeeper(t, db)
-	// Ожидание вызова Prepare
-	mock.ExpectPrepare("INSERT INTO testTable(.+) VALUES(.+)")
+
+	// Запись получает следующий номер изменения пользователя в той же транзакции
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO SyncSequences (.+) ON CONFLICT (.+) RETURNING last_seq").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(7))
 
 	// Ожидание вызова ExecContext для добавления данных
-	mock.ExpectExec("INSERT INTO testTable(.+) VALUES(.+)").
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+	mock.ExpectExec("INSERT INTO UserCredentials\\(user_id,id,change_seq,(.+)\\) VALUES(.+)").
+		WithArgs(1, "entry_id", int64(7), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	// Добавление новых данных
-	err = bdk.AddData(context.Background(), "testTable", 1, "entry_id", map[string]string{"key1": "value1", "key2": "value2"})
+	err = bdk.AddData(context.Background(), "UserCredentials", 1, "entry_id", map[string]string{"login": "value1", "password": "value2"})
 	if err != nil {
 		t.Fatalf("Ошибка при добавлении данных: %v", err)
 	}
@@ -229,7 +477,7 @@ func TestBDKeeper_AddData(t *testing.T) {
 	}
 }
 
-func TestBDKeeper_UpdateData(t *testing.T) {
+func TestBDKeeper_AddData_Duplicate(t *testing.T) {
 	// Инициализация sqlmock
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -240,18 +488,55 @@ func TestBDKeeper_UpdateData(t *testing.T) {
 	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
 	bdk := newTestBDKeeper(t, db)
 
-	// Ожидание вызова Prepare
-	mock.ExpectPrepare("UPDATE testTable SET(.+) WHERE user_id = (.+) AND id = (.+)")
+	// Запись с уже существующим идентификатором нарушает первичный ключ
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO SyncSequences (.+) ON CONFLICT (.+) RETURNING last_seq").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(7))
+	mock.ExpectExec("INSERT INTO TextData\\(user_id,id,change_seq,data\\) VALUES(.+)").
+		WithArgs(1, "entry_id", int64(7), "x").
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
 
-	// Ожидание вызова ExecContext для обновления данных
-	mock.ExpectExec("UPDATE testTable SET(.+) WHERE user_id = (.+) AND id = (.+)").
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	err = bdk.AddData(context.Background(), "TextData", 1, "entry_id", map[string]string{"data": "x"})
+	if !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("Expected storage.ErrConflict, got %v", err)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Не выполнены ожидания: %s", err)
+	}
+}
+
+func TestBDKeeper_UpdateData(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Ожидание обновления без проверки версии
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO SyncSequences (.+) ON CONFLICT (.+) RETURNING last_seq").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(7))
+	mock.ExpectQuery("UPDATE UserCredentials SET(.+),change_seq = \\$4,version = version \\+ 1 WHERE user_id = \\$5 AND id = \\$6 RETURNING version").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	mock.ExpectCommit()
 
 	// Обновление данных
-	err = bdk.UpdateData(context.Background(), "testTable", 1, "entryID", map[string]string{"key1": "value1", "key2": "value2"})
+	version, err := bdk.UpdateData(context.Background(), "UserCredentials", 1, "entryID", map[string]string{"login": "value1", "password": "value2"}, 0)
 	if err != nil {
 		t.Fatalf("Ошибка при обновлении данных: %v", err)
 	}
+	if version != 2 {
+		t.Errorf("Expected version %d, got %d", 2, version)
+	}
 
 	// Проверяем, что все ожидания выполнены
 	if err := mock.ExpectationsWereMet(); err != nil {
@@ -259,7 +544,7 @@ func TestBDKeeper_UpdateData(t *testing.T) {
 	}
 }
 
-func TestBDKeeper_DeleteData(t *testing.T) {
+func TestBDKeeper_UpdateData_Conflict(t *testing.T) {
 	// Инициализация sqlmock
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -269,24 +554,1116 @@ func TestBDKeeper_DeleteData(t *testing.T) {
 
 	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
 	bdk := newTestBDKeeper(t, db)
+	ctx := context.Background()
+	cols := []string{"id", "user_id", "deleted", "updated_at", "version", "data", "meta_info"}
 
-	// Не ожидаем вызов Query
-	mock.ExpectQuery("SELECT COUNT(.+) FROM testTable WHERE user_id = (.+) AND id = (.+)").
-		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	// Обновление устаревшей версии не затрагивает ни одной строки
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO SyncSequences (.+) ON CONFLICT (.+) RETURNING last_seq").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(7))
+	mock.ExpectQuery("UPDATE TextData SET (.+) WHERE user_id = \\$4 AND id = \\$5 AND version = \\$6 RETURNING version").
+		WithArgs("new", sqlmock.AnyArg(), int64(7), 1, "entryID", int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}))
 
-	// Ожидание вызова ExecContext для удаления данных
-	mock.ExpectExec("DELETE FROM testTable WHERE user_id = (.+) AND id = (.+)").
+	// Возвращается текущая копия записи
+	mock.ExpectQuery("SELECT id,user_id,deleted,updated_at,version,data,meta_info FROM TextData WHERE user_id = \\$1 AND id = \\$2").
 		WithArgs(1, "entryID").
+		WillReturnRows(sqlmock.NewRows(cols).AddRow("entryID", "1", "false", "2024-01-02T00:00:00Z", "2", "other", nil))
+	mock.ExpectRollback()
+
+	_, err = bdk.UpdateData(ctx, "TextData", 1, "entryID", map[string]string{"data": "new"}, 1)
+	var conflict *storage.ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Expected *storage.ConflictError, got %v", err)
+	}
+	if conflict.Current["version"] != "2" || conflict.Current["data"] != "other" {
+		t.Errorf("Unexpected current record: %v", conflict.Current)
+	}
+
+	// Несуществующая запись
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO SyncSequences (.+) ON CONFLICT (.+) RETURNING last_seq").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(7))
+	mock.ExpectQuery("UPDATE TextData SET (.+) RETURNING version").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}))
+	mock.ExpectQuery("SELECT (.+) FROM TextData WHERE user_id = \\$1 AND id = \\$2").
+		WithArgs(1, "missing").
+		WillReturnRows(sqlmock.NewRows(cols))
+	mock.ExpectRollback()
+
+	if _, err := bdk.UpdateData(ctx, "TextData", 1, "missing", map[string]string{"data": "new"}, 1); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected storage.ErrNotFound, got %v", err)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Не выполнены ожидания: %s", err)
+	}
+}
+
+func TestBDKeeper_DeleteData(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Ожидание вызова ExecContext для пометки данных как удаленных
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO SyncSequences (.+) ON CONFLICT (.+) RETURNING last_seq").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(7))
+	mock.ExpectExec("UPDATE TextData SET deleted = TRUE, updated_at = (.+), change_seq = (.+), version = version \\+ 1 WHERE user_id = (.+) AND id = (.+) AND deleted = FALSE").
+		WithArgs(sqlmock.AnyArg(), int64(7), 1, "entryID").
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	// Удаление данных
-	err = bdk.DeleteData(context.Background(), "testTable", 1, "entryID")
+	err = bdk.DeleteData(context.Background(), "TextData", 1, "entryID")
 	if err != nil {
 		t.Fatalf("Ошибка при удалении данных: %v", err)
 	}
 
+	// Повторное удаление не затрагивает ни одной записи и откатывает номер изменения
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO SyncSequences (.+) ON CONFLICT (.+) RETURNING last_seq").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(8))
+	mock.ExpectExec("UPDATE TextData SET deleted = TRUE, updated_at = (.+), change_seq = (.+), version = version \\+ 1 WHERE user_id = (.+) AND id = (.+) AND deleted = FALSE").
+		WithArgs(sqlmock.AnyArg(), int64(8), 1, "entryID").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = bdk.DeleteData(context.Background(), "TextData", 1, "entryID")
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected storage.ErrNotFound, got %v", err)
+	}
+
 	// Проверяем, что все ожидания выполнены
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Не выполнены ожидания: %s", err)
 	}
 }
+
+func TestBDKeeper_Batch(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+	ctx := context.Background()
+	ops := []models.BatchOperation{
+		{Op: models.BatchAdd, Table: "TextData", ID: "a", Data: map[string]string{"data": "x"}},
+		{Op: models.BatchDelete, Table: "TextData", ID: "b"},
+		{Op: "rename", Table: "TextData", ID: "c"},
+	}
+
+	// Обе операции выполняются в одной транзакции, ошибочная откатывает весь пакет
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO SyncSequences (.+) ON CONFLICT (.+) RETURNING last_seq").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(7))
+	mock.ExpectExec("INSERT INTO TextData\\(user_id,id,change_seq,data\\) VALUES(.+)").
+		WithArgs(1, "a", int64(7), "x").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("INSERT INTO SyncSequences (.+) ON CONFLICT (.+) RETURNING last_seq").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(8))
+	mock.ExpectExec("UPDATE TextData SET deleted = TRUE, (.+) WHERE user_id = (.+) AND id = (.+)").
+		WithArgs(sqlmock.AnyArg(), int64(8), 1, "b").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+
+	results, err := bdk.Batch(ctx, 1, ops, false)
+	var batchErr *storage.BatchError
+	if !errors.As(err, &batchErr) || batchErr.Index != 2 || !errors.Is(err, storage.ErrInvalidOperation) {
+		t.Fatalf("Expected *storage.BatchError for operation 2, got %v", err)
+	}
+	if len(results) != 3 || results[0].Version != 1 || results[2].Err == nil {
+		t.Errorf("Unexpected results: %v", results)
+	}
+
+	// В частичном режиме каждая операция выполняется в своей точке сохранения
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT batch_operation").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("INSERT INTO SyncSequences (.+) ON CONFLICT (.+) RETURNING last_seq").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(9))
+	mock.ExpectExec("INSERT INTO TextData\\(user_id,id,change_seq,data\\) VALUES(.+)").
+		WithArgs(1, "a", int64(9), "x").
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT batch_operation").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT batch_operation").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("INSERT INTO SyncSequences (.+) ON CONFLICT (.+) RETURNING last_seq").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(9))
+	mock.ExpectExec("UPDATE TextData SET deleted = TRUE, (.+) WHERE user_id = (.+) AND id = (.+)").
+		WithArgs(sqlmock.AnyArg(), int64(9), 1, "b").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("RELEASE SAVEPOINT batch_operation").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT batch_operation").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT batch_operation").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	results, err = bdk.Batch(ctx, 1, ops, true)
+	if err != nil {
+		t.Fatalf("Ошибка при выполнении пакета: %v", err)
+	}
+	if len(results) != 3 || !errors.Is(results[0].Err, storage.ErrConflict) || results[1].Err != nil || !errors.Is(results[2].Err, storage.ErrInvalidOperation) {
+		t.Errorf("Unexpected results: %v", results)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Не выполнены ожидания: %s", err)
+	}
+}
+
+func TestBDKeeper_SetFileContent(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	lock := "SELECT COALESCE\\(sha256, ''\\) FROM FilesData WHERE user_id = \\$1 AND id = \\$2 AND deleted = FALSE FOR UPDATE"
+	update := "UPDATE FilesData SET size = \\$1, sha256 = \\$2, updated_at = (.+) WHERE user_id = \\$5 AND id = \\$6 AND deleted = FALSE"
+
+	// Новое содержимое переносит ссылку со старого блоба на новый
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs(1, "entryID").
+		WillReturnRows(sqlmock.NewRows([]string{"sha256"}).AddRow("old"))
+	mock.ExpectQuery("INSERT INTO SyncSequences (.+) ON CONFLICT (.+) RETURNING last_seq").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(3))
+	mock.ExpectExec(update).
+		WithArgs(int64(5), "digest", sqlmock.AnyArg(), int64(3), 1, "entryID").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE Blobs SET ref_count = ref_count - 1 WHERE sha256 = \\$1").
+		WithArgs("old").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO Blobs (.+) ON CONFLICT \\(sha256\\) DO UPDATE SET ref_count = Blobs.ref_count \\+ 1").
+		WithArgs("digest", int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := bdk.SetFileContent(context.Background(), 1, "entryID", 5, "digest"); err != nil {
+		t.Fatalf("Ошибка при сохранении содержимого: %v", err)
+	}
+
+	// Повторная загрузка того же содержимого не меняет ссылки
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs(1, "entryID").
+		WillReturnRows(sqlmock.NewRows([]string{"sha256"}).AddRow("digest"))
+	mock.ExpectQuery("INSERT INTO SyncSequences (.+) ON CONFLICT (.+) RETURNING last_seq").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(4))
+	mock.ExpectExec(update).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := bdk.SetFileContent(context.Background(), 1, "entryID", 5, "digest"); err != nil {
+		t.Fatalf("Ошибка при сохранении содержимого: %v", err)
+	}
+
+	// Отсутствующая запись
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs(1, "missing").
+		WillReturnRows(sqlmock.NewRows([]string{"sha256"}))
+	mock.ExpectRollback()
+
+	if err := bdk.SetFileContent(context.Background(), 1, "missing", 5, "digest"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Не выполнены ожидания: %s", err)
+	}
+}
+
+func TestBDKeeper_LegacyFiles(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Выбираются только неудаленные записи без хеша
+	mock.ExpectQuery("SELECT user_id, id FROM FilesData WHERE sha256 IS NULL AND deleted = FALSE").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "id"}).AddRow(1, "f1").AddRow(2, "f2"))
+
+	files, err := bdk.LegacyFiles(context.Background())
+	if err != nil {
+		t.Fatalf("Ошибка при получении файлов: %v", err)
+	}
+
+	if len(files) != 2 || files[0] != (models.LegacyFile{UserID: 1, EntryID: "f1"}) || files[1].UserID != 2 {
+		t.Errorf("Unexpected files: %v", files)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Не выполнены ожидания: %s", err)
+	}
+}
+
+func TestBDKeeper_DeleteData_ReleasesBlob(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Удаление файла освобождает ссылку на его содержимое
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COALESCE(.+) FROM FilesData WHERE (.+) FOR UPDATE").
+		WithArgs(1, "entryID").
+		WillReturnRows(sqlmock.NewRows([]string{"sha256"}).AddRow("digest"))
+	mock.ExpectExec("UPDATE Blobs SET ref_count = ref_count - 1 WHERE sha256 = \\$1").
+		WithArgs("digest").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO SyncSequences (.+) ON CONFLICT (.+) RETURNING last_seq").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq"}).AddRow(7))
+	mock.ExpectExec("UPDATE FilesData SET deleted = TRUE, (.+)").
+		WithArgs(sqlmock.AnyArg(), int64(7), 1, "entryID").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := bdk.DeleteData(context.Background(), "FilesData", 1, "entryID"); err != nil {
+		t.Fatalf("Ошибка при удалении данных: %v", err)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Не выполнены ожидания: %s", err)
+	}
+}
+
+func TestBDKeeper_DeleteBlob(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Ожидание выборки блобов без ссылок
+	mock.ExpectQuery("SELECT sha256 FROM Blobs WHERE ref_count <= 0").
+		WillReturnRows(sqlmock.NewRows([]string{"sha256"}).AddRow("unused").AddRow("reused"))
+
+	digests, err := bdk.UnreferencedBlobs(context.Background())
+	if err != nil {
+		t.Fatalf("Ошибка при получении блобов: %v", err)
+	}
+	if len(digests) != 2 || digests[0] != "unused" {
+		t.Errorf("Unexpected digests: %v", digests)
+	}
+
+	// Содержимое удаляется, пока строка заблокирована
+	lock := "SELECT ref_count FROM Blobs WHERE sha256 = \\$1 FOR UPDATE"
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs("unused").
+		WillReturnRows(sqlmock.NewRows([]string{"ref_count"}).AddRow(0))
+	mock.ExpectExec("DELETE FROM Blobs WHERE sha256 = \\$1").
+		WithArgs("unused").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	removed := false
+	err = bdk.DeleteBlob(context.Background(), "unused", func() error {
+		removed = true
+		return nil
+	})
+	if err != nil || !removed {
+		t.Fatalf("Ошибка при удалении блоба: %v", err)
+	}
+
+	// Блоб, получивший новую ссылку, остается
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).
+		WithArgs("reused").
+		WillReturnRows(sqlmock.NewRows([]string{"ref_count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	err = bdk.DeleteBlob(context.Background(), "reused", func() error {
+		t.Errorf("Content of a referenced blob removed")
+		return nil
+	})
+	if !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("Expected ErrConflict, got %v", err)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Не выполнены ожидания: %s", err)
+	}
+}
+
+func TestBDKeeper_BlobStats(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Два блоба по 100 байт, на один из них ссылаются три записи
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\), (.+) FROM Blobs WHERE ref_count > 0").
+		WillReturnRows(sqlmock.NewRows([]string{"count", "refs", "stored", "logical"}).AddRow(2, 4, 200, 400))
+
+	stats, err := bdk.BlobStats(context.Background())
+	if err != nil {
+		t.Fatalf("Ошибка при получении статистики: %v", err)
+	}
+	if stats != (models.BlobStats{Blobs: 2, References: 4, StoredBytes: 200, LogicalBytes: 400, SavedBytes: 200}) {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Не выполнены ожидания: %s", err)
+	}
+}
+
+func TestBDKeeper_GetFileContent(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Удаленные записи и записи без содержимого не возвращаются
+	query := "SELECT size, sha256, updated_at FROM FilesData WHERE user_id = \\$1 AND id = \\$2 AND deleted = FALSE AND sha256 IS NOT NULL"
+	updatedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(query).
+		WithArgs(1, "entryID").
+		WillReturnRows(sqlmock.NewRows([]string{"size", "sha256", "updated_at"}).AddRow(5, "digest", updatedAt))
+	mock.ExpectQuery(query).
+		WithArgs(1, "deleted").
+		WillReturnRows(sqlmock.NewRows([]string{"size", "sha256", "updated_at"}))
+
+	content, err := bdk.GetFileContent(context.Background(), 1, "entryID")
+	if err != nil {
+		t.Fatalf("Ошибка при получении содержимого: %v", err)
+	}
+	if content != (models.FileContent{Size: 5, SHA256: "digest", UpdatedAt: updatedAt}) {
+		t.Errorf("Unexpected content: %+v", content)
+	}
+
+	if _, err := bdk.GetFileContent(context.Background(), 1, "deleted"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Не выполнены ожидания: %s", err)
+	}
+}
+
+func TestBDKeeper_GetAllData(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Ожидание запроса с колонками из реестра и меткой синхронизации в параметре
+	lastSync := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT id,user_id,deleted,updated_at,version,data,meta_info FROM TextData WHERE user_id = \\$1 AND updated_at > \\$2").
+		WithArgs(1, lastSync).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "deleted", "updated_at", "version", "data", "meta_info"}).
+			AddRow("entryID", "1", "false", "2024-01-02T00:00:00Z", "3", "secret", "note"))
+
+	// Получение данных
+	data, err := bdk.GetAllData(context.Background(), "textdata", 1, lastSync, true)
+	if err != nil {
+		t.Fatalf("Ошибка при получении данных: %v", err)
+	}
+
+	if len(data) != 1 || data[0]["data"] != "secret" {
+		t.Errorf("Unexpected data: %v", data)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Не выполнены ожидания: %s", err)
+	}
+}
+
+func TestBDKeeper_GetData(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+	ctx := context.Background()
+	cols := []string{"id", "user_id", "deleted", "updated_at", "version", "data", "meta_info"}
+	query := "SELECT id,user_id,deleted,updated_at,version,data,meta_info FROM TextData WHERE user_id = \\$1 AND id = \\$2"
+
+	// Существующая запись
+	mock.ExpectQuery(query).
+		WithArgs(1, "entryID").
+		WillReturnRows(sqlmock.NewRows(cols).AddRow("entryID", "1", "false", "2024-01-02T00:00:00Z", "3", "secret", nil))
+
+	record, err := bdk.GetData(ctx, "textdata", 1, "entryID")
+	if err != nil {
+		t.Fatalf("Ошибка при получении записи: %v", err)
+	}
+	if record["data"] != "secret" || record["version"] != "3" {
+		t.Errorf("Unexpected record: %v", record)
+	}
+
+	// Удалённая запись не возвращается
+	mock.ExpectQuery(query).
+		WithArgs(1, "deleted").
+		WillReturnRows(sqlmock.NewRows(cols).AddRow("deleted", "1", "true", "2024-01-02T00:00:00Z", "4", "", nil))
+
+	if _, err := bdk.GetData(ctx, "TextData", 1, "deleted"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	// Несуществующая запись
+	mock.ExpectQuery(query).
+		WithArgs(1, "missing").
+		WillReturnRows(sqlmock.NewRows(cols))
+
+	if _, err := bdk.GetData(ctx, "TextData", 1, "missing"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Не выполнены ожидания: %s", err)
+	}
+}
+
+func TestBDKeeper_RejectsUnknownTablesAndColumns(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+	ctx := context.Background()
+
+	// Ни один запрос не должен дойти до базы данных
+	if err := bdk.AddData(ctx, "Users", 1, "entryID", map[string]string{"username": "u"}); !errors.Is(err, schema.ErrUnknownTable) {
+		t.Errorf("Expected ErrUnknownTable, got %v", err)
+	}
+	if err := bdk.AddData(ctx, "TextData", 1, "entryID", map[string]string{"data": "d", "user_id": "2"}); !errors.Is(err, schema.ErrUnknownColumn) {
+		t.Errorf("Expected ErrUnknownColumn, got %v", err)
+	}
+	if _, err := bdk.UpdateData(ctx, "TextData; DROP TABLE Users", 1, "entryID", map[string]string{"data": "d"}, 0); !errors.Is(err, schema.ErrUnknownTable) {
+		t.Errorf("Expected ErrUnknownTable, got %v", err)
+	}
+	if err := bdk.DeleteData(ctx, "Users", 1, "entryID"); !errors.Is(err, schema.ErrUnknownTable) {
+		t.Errorf("Expected ErrUnknownTable, got %v", err)
+	}
+	if _, err := bdk.GetAllData(ctx, "Users", 1, time.Time{}, false); !errors.Is(err, schema.ErrUnknownTable) {
+		t.Errorf("Expected ErrUnknownTable, got %v", err)
+	}
+	if _, err := bdk.GetData(ctx, "Users", 1, "entryID"); !errors.Is(err, schema.ErrUnknownTable) {
+		t.Errorf("Expected ErrUnknownTable, got %v", err)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Не выполнены ожидания: %s", err)
+	}
+}
+
+func TestBDKeeper_RefreshTokens(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+	ctx := context.Background()
+
+	// Сохранение хеша refresh-токена
+	mock.ExpectExec("INSERT INTO RefreshTokens (.+) VALUES (.+)").
+		WithArgs("hash", 1, "session", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	if err := bdk.AddRefreshToken(ctx, "hash", 1, "session", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Error adding refresh token: %v", err)
+	}
+
+	// Первое использование токена возвращает владельца
+	mock.ExpectQuery("UPDATE RefreshTokens rt SET revoked = TRUE (.+) RETURNING rt.user_id, rt.session_id").
+		WithArgs("hash", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "session_id"}).AddRow(1, "session"))
+	userID, sessionID, err := bdk.ConsumeRefreshToken(ctx, "hash")
+	if err != nil {
+		t.Fatalf("Error consuming refresh token: %v", err)
+	}
+	if userID != 1 || sessionID != "session" {
+		t.Errorf("Expected user ID %d and session %s, got %d and %s", 1, "session", userID, sessionID)
+	}
+
+	// Повторное использование токена отклоняется
+	mock.ExpectQuery("UPDATE RefreshTokens rt SET revoked = TRUE (.+) RETURNING rt.user_id, rt.session_id").
+		WithArgs("hash", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "session_id"}))
+	if _, _, err := bdk.ConsumeRefreshToken(ctx, "hash"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows, got %v", err)
+	}
+
+	// Отзыв токена пользователем
+	mock.ExpectExec("UPDATE RefreshTokens SET revoked = TRUE WHERE token_hash = (.+) AND user_id = (.+)").
+		WithArgs("hash", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := bdk.RevokeRefreshToken(ctx, "hash", 1); err != nil {
+		t.Fatalf("Error revoking refresh token: %v", err)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBDKeeper_RevokeToken(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+	ctx := context.Background()
+
+	// Добавление идентификатора токена в список отозванных
+	mock.ExpectExec("INSERT INTO RevokedTokens (.+) VALUES (.+) ON CONFLICT (.+) DO NOTHING").
+		WithArgs("jti", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	if err := bdk.RevokeToken(ctx, "jti", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Error revoking token: %v", err)
+	}
+
+	// Проверка отзыва токена
+	mock.ExpectQuery("SELECT COUNT(.+) FROM RevokedTokens WHERE jti = (.+)").
+		WithArgs("jti").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	revoked, err := bdk.IsTokenRevoked(ctx, "jti")
+	if err != nil {
+		t.Fatalf("Error checking token: %v", err)
+	}
+	if !revoked {
+		t.Error("Expected token to be revoked")
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBDKeeper_PurgeExpiredTokens(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+	ctx := context.Background()
+
+	// Удаление истекших токенов в одной транзакции
+	before := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM RefreshTokens WHERE expires_at < (.+)").
+		WithArgs(before.UTC()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM RevokedTokens WHERE expires_at < (.+)").
+		WithArgs(before.UTC()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	purged, err := bdk.PurgeExpiredTokens(ctx, before)
+	if err != nil {
+		t.Fatalf("Error purging tokens: %v", err)
+	}
+	if purged != 5 {
+		t.Errorf("Expected 5 purged tokens, got %d", purged)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBDKeeper_LegacyBlobs(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+	ctx := context.Background()
+
+	// Записи, содержимое которых еще хранится по старому ключу, включая удаленные
+	rows := sqlmock.NewRows([]string{"user_id", "id", "sha256", "deleted"}).
+		AddRow(1, "f1", "d1", false).
+		AddRow(2, "f2", "d2", true)
+	mock.ExpectQuery("SELECT user_id, id, sha256, deleted FROM FilesData WHERE legacy_blob = TRUE (.+)").
+		WillReturnRows(rows)
+
+	blobs, err := bdk.LegacyBlobs(ctx)
+	if err != nil {
+		t.Fatalf("Error getting legacy blobs: %v", err)
+	}
+	expected := []models.LegacyBlob{
+		{UserID: 1, EntryID: "f1", SHA256: "d1"},
+		{UserID: 2, EntryID: "f2", SHA256: "d2", Deleted: true},
+	}
+	if !reflect.DeepEqual(blobs, expected) {
+		t.Errorf("Expected %v, got %v", expected, blobs)
+	}
+
+	// Отметка о переносе не меняет версию записи
+	mock.ExpectExec("UPDATE FilesData SET legacy_blob = FALSE WHERE user_id = (.+) AND id = (.+)").
+		WithArgs(1, "f1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := bdk.ForgetLegacyBlob(ctx, 1, "f1"); err != nil {
+		t.Fatalf("Error forgetting legacy blob: %v", err)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBDKeeper_Sessions(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+	ctx := context.Background()
+
+	// Новая сессия отзывает предыдущие сессии устройства в одной транзакции
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE Sessions SET revoked = TRUE WHERE user_id = (.+) AND device_id = (.+)").
+		WithArgs(1, "device").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO Sessions (.+) VALUES (.+)").
+		WithArgs("session", 1, "device", "laptop", "127.0.0.1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	session := models.Session{ID: "session", DeviceID: "device", DeviceName: "laptop", IP: "127.0.0.1"}
+	if err := bdk.AddSession(ctx, 1, session); err != nil {
+		t.Fatalf("Error adding session: %v", err)
+	}
+
+	// Получение активных сессий
+	now := time.Now().UTC()
+	mock.ExpectQuery("SELECT (.+) FROM Sessions WHERE user_id = (.+) AND revoked = FALSE").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "device_id", "device_name", "ip", "created_at", "last_seen"}).
+			AddRow("session", "device", "laptop", "127.0.0.1", now, now))
+	sessions, err := bdk.ListSessions(ctx, 1)
+	if err != nil {
+		t.Fatalf("Error listing sessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].DeviceName != "laptop" {
+		t.Errorf("Unexpected sessions: %v", sessions)
+	}
+
+	// Обновление времени последней активности
+	mock.ExpectExec("UPDATE Sessions SET last_seen = (.+), ip = (.+) WHERE id = (.+)").
+		WithArgs(sqlmock.AnyArg(), "10.0.0.1", "session").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := bdk.TouchSession(ctx, "session", "10.0.0.1"); err != nil {
+		t.Fatalf("Error touching session: %v", err)
+	}
+
+	// Проверка активности сессии
+	mock.ExpectQuery("SELECT COUNT(.+) FROM Sessions WHERE id = (.+) AND revoked = FALSE").
+		WithArgs("session").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	active, err := bdk.IsSessionActive(ctx, "session")
+	if err != nil {
+		t.Fatalf("Error checking session: %v", err)
+	}
+	if !active {
+		t.Error("Expected session to be active")
+	}
+
+	// Отзыв сессии
+	mock.ExpectExec("UPDATE Sessions SET revoked = TRUE WHERE id = (.+) AND user_id = (.+)").
+		WithArgs("session", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := bdk.RevokeSession(ctx, 1, "session"); err != nil {
+		t.Fatalf("Error revoking session: %v", err)
+	}
+
+	// Отзыв несуществующей сессии
+	mock.ExpectExec("UPDATE Sessions SET revoked = TRUE WHERE id = (.+) AND user_id = (.+)").
+		WithArgs("unknown", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	if err := bdk.RevokeSession(ctx, 1, "unknown"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected storage.ErrNotFound, got %v", err)
+	}
+
+	// Отзыв всех сессий
+	mock.ExpectExec("UPDATE Sessions SET revoked = TRUE WHERE user_id = (.+) AND revoked = FALSE").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	if err := bdk.RevokeAllSessions(ctx, 1); err != nil {
+		t.Fatalf("Error revoking sessions: %v", err)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBDKeeper_MFA(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+	ctx := context.Background()
+
+	// Пользователь без настроек 2FA
+	mock.ExpectQuery("SELECT (.+) FROM UserMFA WHERE user_id = (.+)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"secret", "pending_secret", "enabled", "last_step"}))
+	if _, err := bdk.GetMFA(ctx, 1); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected storage.ErrNotFound, got %v", err)
+	}
+
+	// Сохранение неподтвержденного секрета
+	mock.ExpectExec("INSERT INTO UserMFA (.+) VALUES (.+) ON CONFLICT (.+) DO UPDATE SET pending_secret = (.+)").
+		WithArgs(1, "SECRET").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	if err := bdk.SetPendingTOTPSecret(ctx, 1, "SECRET"); err != nil {
+		t.Fatalf("Error setting pending secret: %v", err)
+	}
+
+	// Подтверждение секрета и замена кодов восстановления в одной транзакции
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE UserMFA SET secret = pending_secret, (.+) WHERE user_id = (.+) AND pending_secret IS NOT NULL").
+		WithArgs(int64(100), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM RecoveryCodes WHERE user_id = (.+)").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO RecoveryCodes (.+) VALUES (.+)").
+		WithArgs(1, "hash1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO RecoveryCodes (.+) VALUES (.+)").
+		WithArgs(1, "hash2").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	if err := bdk.EnableMFA(ctx, 1, 100, []string{"hash1", "hash2"}); err != nil {
+		t.Fatalf("Error enabling MFA: %v", err)
+	}
+
+	// Получение настроек 2FA
+	mock.ExpectQuery("SELECT (.+) FROM UserMFA WHERE user_id = (.+)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"secret", "pending_secret", "enabled", "last_step"}).
+			AddRow("SECRET", "", true, 100))
+	mfa, err := bdk.GetMFA(ctx, 1)
+	if err != nil {
+		t.Fatalf("Error getting MFA: %v", err)
+	}
+	if !mfa.Enabled || mfa.Secret != "SECRET" || mfa.LastStep != 100 {
+		t.Errorf("Unexpected MFA settings: %+v", mfa)
+	}
+
+	// Повторное использование шага TOTP отклоняется
+	mock.ExpectExec("UPDATE UserMFA SET last_step = (.+) WHERE user_id = (.+) AND last_step < (.+)").
+		WithArgs(int64(100), 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	if err := bdk.UseTOTPStep(ctx, 1, 100); !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("Expected storage.ErrConflict, got %v", err)
+	}
+
+	// Использование кода восстановления
+	mock.ExpectExec("UPDATE RecoveryCodes SET used = TRUE WHERE user_id = (.+) AND code_hash = (.+) AND used = FALSE").
+		WithArgs(1, "hash1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := bdk.UseRecoveryCode(ctx, 1, "hash1"); err != nil {
+		t.Fatalf("Error using recovery code: %v", err)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBDKeeper_GetUsername(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Ожидание вызова QueryRowContext для получения имени пользователя
+	mock.ExpectQuery("SELECT username FROM Users WHERE id = (.+)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"username"}).AddRow("testUser"))
+
+	username, err := bdk.GetUsername(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Error getting username: %v", err)
+	}
+	if username != "testUser" {
+		t.Errorf("Expected username %s, got %s", "testUser", username)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBDKeeper_SRP(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+	ctx := context.Background()
+	verifier := models.SRPVerifier{Salt: "salt", Verifier: "verifier"}
+
+	// Пользователь и верификатор добавляются в одной транзакции
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO Users (.+) VALUES (.+) RETURNING id").
+		WithArgs("testUser").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec("INSERT INTO SRPVerifiers (.+) VALUES (.+)").
+		WithArgs(1, "salt", "verifier").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	if err := bdk.AddSRPUser(ctx, "testUser", verifier); err != nil {
+		t.Fatalf("Error adding SRP user: %v", err)
+	}
+
+	// Получение верификатора
+	mock.ExpectQuery("SELECT (.+) FROM Users u JOIN SRPVerifiers v (.+) WHERE u.username = (.+)").
+		WithArgs("testUser").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "salt", "verifier"}).AddRow(1, "salt", "verifier"))
+	userID, got, err := bdk.GetSRPVerifier(ctx, "testUser")
+	if err != nil {
+		t.Fatalf("Error getting SRP verifier: %v", err)
+	}
+	if userID != 1 || got != verifier {
+		t.Errorf("Unexpected verifier: %d %+v", userID, got)
+	}
+
+	// Пользователь без верификатора
+	mock.ExpectQuery("SELECT (.+) FROM Users u JOIN SRPVerifiers v (.+) WHERE u.username = (.+)").
+		WithArgs("passwordUser").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "salt", "verifier"}))
+	if _, _, err := bdk.GetSRPVerifier(ctx, "passwordUser"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected storage.ErrNotFound, got %v", err)
+	}
+
+	// Сохранение попытки входа
+	expiresAt := time.Now().Add(time.Minute).UTC()
+	challenge := models.SRPChallenge{ID: "c1", Username: "testUser", A: "a", B: "b", PrivateB: "pb", ExpiresAt: expiresAt}
+	mock.ExpectExec("DELETE FROM SRPChallenges WHERE expires_at < (.+) INSERT INTO SRPChallenges (.+) VALUES (.+)").
+		WithArgs("c1", "testUser", "a", "b", "pb", expiresAt, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	if err := bdk.AddSRPChallenge(ctx, challenge); err != nil {
+		t.Fatalf("Error adding SRP challenge: %v", err)
+	}
+
+	// Попытка входа используется один раз
+	mock.ExpectQuery("DELETE FROM SRPChallenges WHERE id = (.+) RETURNING (.+)").
+		WithArgs("c1").
+		WillReturnRows(sqlmock.NewRows([]string{"username", "public_a", "public_b", "private_b", "expires_at"}).
+			AddRow("testUser", "a", "b", "pb", expiresAt))
+	consumed, err := bdk.ConsumeSRPChallenge(ctx, "c1")
+	if err != nil {
+		t.Fatalf("Error consuming SRP challenge: %v", err)
+	}
+	if consumed != challenge {
+		t.Errorf("Expected challenge %+v, got %+v", challenge, consumed)
+	}
+
+	mock.ExpectQuery("DELETE FROM SRPChallenges WHERE id = (.+) RETURNING (.+)").
+		WithArgs("c1").
+		WillReturnRows(sqlmock.NewRows([]string{"username", "public_a", "public_b", "private_b", "expires_at"}))
+	if _, err := bdk.ConsumeSRPChallenge(ctx, "c1"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected storage.ErrNotFound, got %v", err)
+	}
+
+	// Просроченная попытка входа отклоняется
+	mock.ExpectQuery("DELETE FROM SRPChallenges WHERE id = (.+) RETURNING (.+)").
+		WithArgs("c2").
+		WillReturnRows(sqlmock.NewRows([]string{"username", "public_a", "public_b", "private_b", "expires_at"}).
+			AddRow("testUser", "a", "b", "pb", time.Now().Add(-time.Minute)))
+	if _, err := bdk.ConsumeSRPChallenge(ctx, "c2"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected storage.ErrNotFound, got %v", err)
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBDKeeper_Sync(t *testing.T) {
+	// Инициализация sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Создание экземпляра BDKeeper через функцию newTestBDKeeper
+	bdk := newTestBDKeeper(t, db)
+
+	// Каждая таблица запрашивается после курсора с лимитом на одну запись больше страницы
+	rowsByTable := map[schema.Table]*sqlmock.Rows{
+		schema.UserCredentials: sqlmock.NewRows([]string{"id", "user_id", "deleted", "updated_at", "version", "login", "password", "meta_info", "change_seq"}).
+			AddRow("c1", "1", "false", "2024-01-01T00:00:00Z", "1", "l", "p", nil, 12),
+		schema.CreditCardData: sqlmock.NewRows([]string{"id", "user_id", "deleted", "updated_at", "version", "card_number", "expiration_date", "cvv", "meta_info", "change_seq"}),
+		schema.TextData: sqlmock.NewRows([]string{"id", "user_id", "deleted", "updated_at", "version", "data", "meta_info", "change_seq"}).
+			AddRow("t1", "1", "true", "2024-01-01T00:00:00Z", "2", "d", nil, 11).
+			AddRow("t2", "1", "false", "2024-01-01T00:00:00Z", "1", "d", nil, 14),
+		schema.FilesData: sqlmock.NewRows([]string{"id", "user_id", "deleted", "updated_at", "version", "path", "extension", "meta_info", "size", "sha256", "change_seq"}),
+	}
+	for _, table := range schema.Tables() {
+		mock.ExpectQuery("SELECT (.+),change_seq FROM "+string(table)+" WHERE user_id = \\$1 AND change_seq > \\$2 ORDER BY change_seq LIMIT \\$3").
+			WithArgs(1, int64(10), 3).
+			WillReturnRows(rowsByTable[table])
+	}
+
+	changes, hasMore, err := bdk.Sync(context.Background(), 1, 10, 2)
+	if err != nil {
+		t.Fatalf("Error syncing: %v", err)
+	}
+
+	// Изменения всех таблиц упорядочены по номеру изменения
+	if !hasMore {
+		t.Errorf("Expected more changes")
+	}
+	if len(changes) != 2 || changes[0].Entry["id"] != "t1" || changes[1].Table != "UserCredentials" || changes[1].Seq != 12 {
+		t.Errorf("Unexpected changes: %+v", changes)
+	}
+	if changes[0].Entry["deleted"] != "true" {
+		t.Errorf("Expected deleted record in changes: %+v", changes[0])
+	}
+
+	// Проверяем, что все ожидания выполнены
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Не выполнены ожидания: %s", err)
+	}
+}
+
+// TestBDKeeper_Conformance runs the keeper conformance suite against the PostgreSQL
+// database in TEST_DATABASE_URI. All tables of that database are emptied before each test.
+func TestBDKeeper_Conformance(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URI")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URI is not set")
+	}
+
+	nLogger, err := logger.NewLogger("error")
+	if err != nil {
+		t.Fatalf("Error creating logger: %v", err)
+	}
+
+	storagetest.Run(t, func(t *testing.T) storage.Keeper {
+		bdk, err := NewBDKeeper(func() string { return dsn }, nLogger, nil)
+		if err != nil {
+			t.Fatalf("Error creating BDKeeper: %v", err)
+		}
+		t.Cleanup(func() { bdk.Close() })
+
+		_, err = bdk.conn.Exec(`TRUNCATE Users, UserCredentials, CreditCardData, TextData, FilesData,
+			RefreshTokens, RevokedTokens, Sessions, UserMFA, RecoveryCodes, SRPVerifiers, SRPChallenges,
+			SyncSequences, Blobs, DataKeys RESTART IDENTITY CASCADE`)
+		if err != nil {
+			t.Fatalf("Error emptying database: %v", err)
+		}
+
+		return bdk
+	})
+}
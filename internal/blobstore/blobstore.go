@@ -0,0 +1,49 @@
+// Package blobstore provides storage backends for the content of vault files.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// Errors returned by the blob stores.
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Info describes a stored blob.
+type Info struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// BlobStore stores blobs under slash-separated keys such as "<userID>/<entryID>".
+type BlobStore interface {
+	// Put stores size bytes read from r under key, replacing an existing blob at once.
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Get opens a blob for reading. The caller must close it.
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Stat returns the size and modification time of a blob.
+	Stat(ctx context.Context, key string) (Info, error)
+	// Delete removes a blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// checkKey rejects keys that are empty, absolute or contain relative segments,
+// so that a key cannot address anything outside the store.
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+
+	return nil
+}
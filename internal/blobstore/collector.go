@@ -0,0 +1,130 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Log is an interface for logging operations.
+type Log interface {
+	Info(string, ...zapcore.Field)
+}
+
+// Index keeps the reference counts of content-addressed blobs.
+type Index interface {
+	// UnreferencedBlobs returns the digests of blobs that nothing refers to.
+	UnreferencedBlobs(ctx context.Context) ([]string, error)
+	// DeleteBlob calls remove for a blob that is still unreferenced and forgets it.
+	DeleteBlob(ctx context.Context, digest string, remove func() error) error
+}
+
+// ContentKey returns the key of the blob with the given hex-encoded SHA-256 digest.
+// Blobs are spread over subdirectories by the first byte of the digest.
+func ContentKey(digest string) string {
+	if len(digest) < 2 {
+		return "sha256/" + digest
+	}
+
+	return "sha256/" + digest[:2] + "/" + digest
+}
+
+// StoreContent stores the file at path under the content key of digest, unless the store
+// already holds it, and then calls reference to record that an entry uses the blob.
+// The reference is recorded only after the content is stored. A collection that removed
+// the blob in the meantime is detected afterwards, and the content is stored again.
+// Errors of reference are returned unchanged.
+func StoreContent(ctx context.Context, store BlobStore, digest string, path string, size int64, reference func() error) error {
+	key := ContentKey(digest)
+	if err := ensureContent(ctx, store, key, path, size); err != nil {
+		return err
+	}
+
+	if err := reference(); err != nil {
+		return err
+	}
+
+	// From now on the reference keeps the blob from being collected
+	return ensureContent(ctx, store, key, path, size)
+}
+
+// ensureContent stores the file at path under key if the store does not hold it.
+func ensureContent(ctx context.Context, store BlobStore, key string, path string, size int64) error {
+	_, err := store.Stat(ctx, key)
+	if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return store.Put(ctx, key, file, size)
+}
+
+// Collector removes the content of blobs whose last reference was dropped.
+type Collector struct {
+	index Index
+	store BlobStore
+	log   Log
+}
+
+// NewCollector creates a Collector for the blobs of store counted in index.
+func NewCollector(index Index, store BlobStore, log Log) *Collector {
+	return &Collector{
+		index: index,
+		store: store,
+		log:   log,
+	}
+}
+
+// Collect removes all unreferenced blobs and returns how many were removed.
+// Blobs that got a new reference in the meantime are skipped.
+func (c *Collector) Collect(ctx context.Context) (int, error) {
+	digests, err := c.index.UnreferencedBlobs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, digest := range digests {
+		key := ContentKey(digest)
+		err := c.index.DeleteBlob(ctx, digest, func() error {
+			return c.store.Delete(ctx, key)
+		})
+		if err != nil {
+			c.log.Info("blob not collected: ", zap.String("sha256", digest), zap.Error(err))
+			continue
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// Run collects unreferenced blobs every interval until ctx is done.
+func (c *Collector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := c.Collect(ctx)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				c.log.Info("cannot collect unreferenced blobs: ", zap.Error(err))
+			}
+			if removed > 0 {
+				c.log.Info("unreferenced blobs removed", zap.Int("count", removed))
+			}
+		}
+	}
+}
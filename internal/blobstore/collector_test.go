@@ -0,0 +1,97 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(string, ...zapcore.Field) {}
+
+// mockIndex keeps reference counts in memory.
+type mockIndex struct {
+	refs map[string]int
+}
+
+func (m *mockIndex) UnreferencedBlobs(ctx context.Context) ([]string, error) {
+	var digests []string
+	for digest, refs := range m.refs {
+		if refs == 0 {
+			digests = append(digests, digest)
+		}
+	}
+	return digests, nil
+}
+
+func (m *mockIndex) DeleteBlob(ctx context.Context, digest string, remove func() error) error {
+	if m.refs[digest] > 0 {
+		return errors.New("conflict")
+	}
+	if err := remove(); err != nil {
+		return err
+	}
+	delete(m.refs, digest)
+	return nil
+}
+
+func TestContentKey(t *testing.T) {
+	assert.Equal(t, "sha256/ab/abcdef", ContentKey("abcdef"))
+	assert.NoError(t, checkKey(ContentKey("abcdef")))
+}
+
+func TestCollector(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, ContentKey("unused"), strings.NewReader("a"), 1))
+	require.NoError(t, store.Put(ctx, ContentKey("shared"), strings.NewReader("b"), 1))
+
+	index := &mockIndex{refs: map[string]int{"unused": 0, "shared": 2}}
+	removed, err := NewCollector(index, store, &mockLogger{}).Collect(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	_, err = store.Stat(ctx, ContentKey("unused"))
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Stat(ctx, ContentKey("shared"))
+	assert.NoError(t, err)
+	assert.NotContains(t, index.refs, "unused")
+}
+
+func TestStoreContent(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "upload")
+	require.NoError(t, os.WriteFile(path, []byte("content"), 0600))
+
+	// The reference is recorded only after the content is stored
+	err := StoreContent(ctx, store, "digest", path, 7, func() error {
+		_, err := store.Stat(ctx, ContentKey("digest"))
+		return err
+	})
+	require.NoError(t, err)
+
+	// A failed reference is returned unchanged
+	errRef := errors.New("entry not found")
+	err = StoreContent(ctx, store, "digest", path, 7, func() error { return errRef })
+	assert.ErrorIs(t, err, errRef)
+
+	// A collection that removed the blob before the reference was recorded is repaired
+	err = StoreContent(ctx, store, "digest", path, 7, func() error {
+		return store.Delete(ctx, ContentKey("digest"))
+	})
+	require.NoError(t, err)
+	info, err := store.Stat(ctx, ContentKey("digest"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), info.Size)
+}
@@ -0,0 +1,116 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// FileStore keeps blobs as files below a root directory.
+type FileStore struct {
+	root string
+}
+
+// NewFileStore creates a FileStore for the directory root.
+func NewFileStore(root string) *FileStore {
+	return &FileStore{root: root}
+}
+
+// Put streams the blob into a temporary file next to its place and renames it,
+// so readers never see a partially written blob.
+func (fs *FileStore) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	path, err := fs.path(key)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".blob-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	written, err := io.Copy(tmp, r)
+	if err != nil {
+		return err
+	}
+	if written != size {
+		return io.ErrUnexpectedEOF
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get opens the file of a blob.
+func (fs *FileStore) Get(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	path, err := fs.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Stat returns the size and modification time of the file of a blob.
+func (fs *FileStore) Stat(ctx context.Context, key string) (Info, error) {
+	path, err := fs.path(key)
+	if err != nil {
+		return Info{}, err
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Info{}, ErrNotFound
+	}
+	if err != nil {
+		return Info{}, err
+	}
+
+	return Info{Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// Delete removes the file of a blob.
+func (fs *FileStore) Delete(ctx context.Context, key string) error {
+	path, err := fs.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// path maps a key to a file below the root directory.
+func (fs *FileStore) path(key string) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(fs.root, filepath.FromSlash(key)), nil
+}
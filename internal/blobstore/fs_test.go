@@ -0,0 +1,64 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	root := t.TempDir()
+	store := NewFileStore(root)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "1/entry", strings.NewReader("hello world"), 11))
+
+	content, err := os.ReadFile(filepath.Join(root, "1", "entry"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(content))
+
+	info, err := store.Stat(ctx, "1/entry")
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), info.Size)
+
+	object, err := store.Get(ctx, "1/entry")
+	require.NoError(t, err)
+	_, err = object.Seek(6, io.SeekStart)
+	require.NoError(t, err)
+	rest, err := io.ReadAll(object)
+	require.NoError(t, err)
+	assert.Equal(t, "world", string(rest))
+	require.NoError(t, object.Close())
+
+	// A short body leaves the stored blob untouched
+	assert.Error(t, store.Put(ctx, "1/entry", strings.NewReader("bye"), 11))
+	content, err = os.ReadFile(filepath.Join(root, "1", "entry"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(content))
+
+	entries, err := os.ReadDir(filepath.Join(root, "1"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	require.NoError(t, store.Delete(ctx, "1/entry"))
+	require.NoError(t, store.Delete(ctx, "1/entry"))
+
+	_, err = store.Get(ctx, "1/entry")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Stat(ctx, "1/entry")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestCheckKey(t *testing.T) {
+	assert.NoError(t, checkKey("1/entry.txt"))
+
+	for _, key := range []string{"", "/etc/passwd", "1/../2/entry", "1//entry", "./entry", "1\\entry", "1/"} {
+		assert.ErrorIs(t, checkKey(key), ErrInvalidKey, key)
+	}
+}
@@ -0,0 +1,104 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"go.uber.org/zap"
+)
+
+// LegacyIndex lists the file entries whose content may still be stored under the legacy key.
+type LegacyIndex interface {
+	// LegacyBlobs returns a batch of file entries that were not moved to content keys yet.
+	LegacyBlobs(ctx context.Context) ([]models.LegacyBlob, error)
+	// ForgetLegacyBlob marks the content of a file entry as moved.
+	ForgetLegacyBlob(ctx context.Context, userID int, entryID string) error
+}
+
+// LegacyKey returns the key under which the content of a file entry was stored
+// before blobs were addressed by digest.
+func LegacyKey(userID int, entryID string) string {
+	return strconv.Itoa(userID) + "/" + entryID
+}
+
+// MigrateLegacy moves the content of file entries from their legacy keys to content keys
+// and returns how many entries were handled. Content that is already stored under its
+// digest is not copied again, and the content of deleted entries is only removed.
+// Entries that fail are kept for the next run.
+func MigrateLegacy(ctx context.Context, index LegacyIndex, store BlobStore, log Log) (int, error) {
+	migrated := 0
+	for {
+		blobs, err := index.LegacyBlobs(ctx)
+		if err != nil || len(blobs) == 0 {
+			return migrated, err
+		}
+
+		failed := 0
+		for _, blob := range blobs {
+			if err := migrateLegacy(ctx, index, store, blob); err != nil {
+				log.Info("legacy file not migrated: ", zap.Int("userID", blob.UserID),
+					zap.String("entryID", blob.EntryID), zap.Error(err))
+				failed++
+				continue
+			}
+			migrated++
+		}
+
+		// The failed entries would be returned again
+		if failed > 0 {
+			return migrated, nil
+		}
+	}
+}
+
+// migrateLegacy copies the content of one entry to its content key and removes the legacy blob.
+func migrateLegacy(ctx context.Context, index LegacyIndex, store BlobStore, blob models.LegacyBlob) error {
+	legacy := LegacyKey(blob.UserID, blob.EntryID)
+	if !blob.Deleted {
+		if err := copyBlob(ctx, store, legacy, ContentKey(blob.SHA256)); err != nil {
+			return err
+		}
+	}
+
+	if err := store.Delete(ctx, legacy); err != nil {
+		return err
+	}
+
+	return index.ForgetLegacyBlob(ctx, blob.UserID, blob.EntryID)
+}
+
+// copyBlob copies the blob at from to to, unless to exists.
+// It returns ErrNotFound if neither of them exists, so that the content is not considered moved.
+func copyBlob(ctx context.Context, store BlobStore, from string, to string) error {
+	_, err := store.Stat(ctx, to)
+	if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	info, err := store.Stat(ctx, from)
+	if err != nil {
+		return err
+	}
+
+	r, err := store.Get(ctx, from)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return store.Put(ctx, to, r, info.Size)
+}
+
+// RunLegacyMigration moves the legacy file content once and logs the result.
+// Downloads fall back to the legacy keys until it has finished.
+func RunLegacyMigration(ctx context.Context, index LegacyIndex, store BlobStore, log Log) {
+	migrated, err := MigrateLegacy(ctx, index, store, log)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Info("cannot migrate legacy files: ", zap.Error(err))
+	}
+	if migrated > 0 {
+		log.Info("legacy files migrated", zap.Int("count", migrated))
+	}
+}
@@ -0,0 +1,76 @@
+package blobstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+)
+
+// mockLegacyIndex hands out the entries in batches of two.
+type mockLegacyIndex struct {
+	blobs []models.LegacyBlob
+}
+
+func (m *mockLegacyIndex) LegacyBlobs(ctx context.Context) ([]models.LegacyBlob, error) {
+	return append([]models.LegacyBlob(nil), m.blobs[:min(len(m.blobs), 2)]...), nil
+}
+
+func (m *mockLegacyIndex) ForgetLegacyBlob(ctx context.Context, userID int, entryID string) error {
+	for i, blob := range m.blobs {
+		if blob.UserID == userID && blob.EntryID == entryID {
+			m.blobs = append(m.blobs[:i], m.blobs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func TestLegacyKey(t *testing.T) {
+	assert.Equal(t, "1/entry", LegacyKey(1, "entry"))
+	assert.NoError(t, checkKey(LegacyKey(1, "entry")))
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, LegacyKey(1, "live"), strings.NewReader("live"), 4))
+	require.NoError(t, store.Put(ctx, LegacyKey(1, "shared"), strings.NewReader("old"), 3))
+	require.NoError(t, store.Put(ctx, ContentKey("d2"), strings.NewReader("new"), 3))
+	require.NoError(t, store.Put(ctx, LegacyKey(2, "deleted"), strings.NewReader("gone"), 4))
+	require.NoError(t, store.Put(ctx, ContentKey("d4"), strings.NewReader("moved"), 5))
+
+	index := &mockLegacyIndex{blobs: []models.LegacyBlob{
+		{UserID: 1, EntryID: "live", SHA256: "d1"},
+		{UserID: 1, EntryID: "shared", SHA256: "d2"},
+		{UserID: 2, EntryID: "deleted", SHA256: "d3", Deleted: true},
+		{UserID: 2, EntryID: "moved", SHA256: "d4"},
+		{UserID: 2, EntryID: "lost", SHA256: "d5"},
+	}}
+	migrated, err := MigrateLegacy(ctx, index, store, &mockLogger{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, migrated)
+
+	// An entry whose content is found under neither key stays flagged
+	assert.Equal(t, []models.LegacyBlob{{UserID: 2, EntryID: "lost", SHA256: "d5"}}, index.blobs)
+
+	// The content is copied unless it is already stored under its digest
+	info, err := store.Stat(ctx, ContentKey("d1"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), info.Size)
+	info, err = store.Stat(ctx, ContentKey("d2"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), info.Size)
+
+	// Deleted entries lose their content, the legacy keys are removed
+	_, err = store.Stat(ctx, ContentKey("d3"))
+	assert.ErrorIs(t, err, ErrNotFound)
+	for _, key := range []string{LegacyKey(1, "live"), LegacyKey(1, "shared"), LegacyKey(2, "deleted")} {
+		_, err = store.Stat(ctx, key)
+		assert.ErrorIs(t, err, ErrNotFound, key)
+	}
+}
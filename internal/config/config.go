@@ -8,6 +8,8 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Options represents the configuration options.
@@ -15,6 +17,22 @@ type Options struct {
 	flagRunAddr, flagDataBaseDSN, flagLogLevel,
 	flagHTTPSCertFile, flagHTTPSKeyFile, flagJWTSigningKey, flagFileStoragePath string
 	flagEnableHTTPS bool
+	flagArgon2Memory, flagArgon2Iterations, flagArgon2Parallelism uint
+	flagMaxUploadSize, flagMaxBodySize                            uint
+	flagAccessTokenTTL, flagRefreshTokenTTL, flagUploadTTL        time.Duration
+	flagReadTimeout, flagWriteTimeout                             time.Duration
+
+	flagBlobStore, flagS3Endpoint, flagS3Bucket, flagS3Region, flagS3AccessKey, flagS3SecretKey string
+	flagAdminUsers                                                                              string
+	flagGRPCAddr                                                                                string
+	flagEventsBackend                                                                           string
+
+	flagCacheSize uint
+	flagCacheTTL  time.Duration
+
+	flagMasterKey, flagMasterKeyFile string
+
+	flagJWTSigningKeys, flagSRPSaltKey string
 }
 
 // NewOptions creates a new instance of Options.
@@ -32,6 +50,31 @@ func (o *Options) ParseFlags() {
 	regBoolVar(&o.flagEnableHTTPS, "s", false, "enable https")
 	regStringVar(&o.flagJWTSigningKey, "j", "test_key", "jwt signing key")
 	regStringVar(&o.flagFileStoragePath, "n", "", "file storage path")
+	regUintVar(&o.flagArgon2Memory, "argon2-memory", 64*1024, "argon2id memory cost in KiB")
+	regUintVar(&o.flagArgon2Iterations, "argon2-iterations", 3, "argon2id number of iterations")
+	regUintVar(&o.flagArgon2Parallelism, "argon2-parallelism", 2, "argon2id degree of parallelism")
+	regDurationVar(&o.flagAccessTokenTTL, "access-ttl", 15*time.Minute, "access token lifetime")
+	regDurationVar(&o.flagRefreshTokenTTL, "refresh-ttl", 30*24*time.Hour, "refresh token lifetime")
+	regUintVar(&o.flagMaxUploadSize, "max-upload-size", 100<<20, "maximum size of an uploaded file in bytes")
+	regUintVar(&o.flagMaxBodySize, "max-body-size", 1<<20, "maximum size of a JSON request body in bytes")
+	regDurationVar(&o.flagUploadTTL, "upload-ttl", 24*time.Hour, "lifetime of an inactive resumable upload")
+	regDurationVar(&o.flagReadTimeout, "read-timeout", 30*time.Second, "maximum duration for reading a request")
+	regDurationVar(&o.flagWriteTimeout, "write-timeout", 30*time.Second, "maximum duration for writing a response")
+	regStringVar(&o.flagBlobStore, "blob-store", "fs", "storage for file content: fs or s3")
+	regStringVar(&o.flagS3Endpoint, "s3-endpoint", "", "base URL of the S3-compatible service")
+	regStringVar(&o.flagS3Bucket, "s3-bucket", "", "S3 bucket for file content")
+	regStringVar(&o.flagS3Region, "s3-region", "us-east-1", "S3 region")
+	regStringVar(&o.flagS3AccessKey, "s3-access-key", "", "S3 access key")
+	regStringVar(&o.flagS3SecretKey, "s3-secret-key", "", "S3 secret key")
+	regStringVar(&o.flagAdminUsers, "admin-users", "", "comma-separated usernames allowed to use the admin endpoints")
+	regStringVar(&o.flagGRPCAddr, "grpc-address", "", "address and port to run the gRPC server, disabled if empty")
+	regStringVar(&o.flagEventsBackend, "events-backend", "memory", "delivery of change notifications: memory or postgres")
+	regUintVar(&o.flagCacheSize, "cache-size", 10000, "maximum number of cached record sets and credentials, 0 to disable the cache")
+	regDurationVar(&o.flagCacheTTL, "cache-ttl", 5*time.Minute, "maximum age of a cache entry")
+	regStringVar(&o.flagMasterKey, "master-key", "", "comma-separated base64-encoded 32-byte keys as [id:]key that encrypt the vault columns, the current key first, empty to store them as sent")
+	regStringVar(&o.flagMasterKeyFile, "master-key-file", "", "file holding the master keys, one per line")
+	regStringVar(&o.flagJWTSigningKeys, "jwt-keys", "", "comma-separated jwt signing keys as [id:]key, the current key first, used instead of -j")
+	regStringVar(&o.flagSRPSaltKey, "srp-salt-key", "", "secret that derives the salts of unknown users at srp login, -j if empty; keep it when rotating the jwt keys")
 
 	// parse the arguments passed to the server into registered variables
 	flag.Parse()
@@ -64,6 +107,32 @@ func (o *Options) ParseFlags() {
 		o.flagHTTPSKeyFile = envHTTPSKeyFile
 	}
 
+	setUintFromEnv(&o.flagArgon2Memory, "ARGON2_MEMORY")
+	setUintFromEnv(&o.flagArgon2Iterations, "ARGON2_ITERATIONS")
+	setUintFromEnv(&o.flagArgon2Parallelism, "ARGON2_PARALLELISM")
+	setDurationFromEnv(&o.flagAccessTokenTTL, "ACCESS_TOKEN_TTL")
+	setDurationFromEnv(&o.flagRefreshTokenTTL, "REFRESH_TOKEN_TTL")
+	setUintFromEnv(&o.flagMaxUploadSize, "MAX_UPLOAD_SIZE")
+	setUintFromEnv(&o.flagMaxBodySize, "MAX_BODY_SIZE")
+	setDurationFromEnv(&o.flagUploadTTL, "UPLOAD_TTL")
+	setDurationFromEnv(&o.flagReadTimeout, "READ_TIMEOUT")
+	setDurationFromEnv(&o.flagWriteTimeout, "WRITE_TIMEOUT")
+	setStringFromEnv(&o.flagBlobStore, "BLOB_STORE")
+	setStringFromEnv(&o.flagS3Endpoint, "S3_ENDPOINT")
+	setStringFromEnv(&o.flagS3Bucket, "S3_BUCKET")
+	setStringFromEnv(&o.flagS3Region, "S3_REGION")
+	setStringFromEnv(&o.flagS3AccessKey, "S3_ACCESS_KEY")
+	setStringFromEnv(&o.flagS3SecretKey, "S3_SECRET_KEY")
+	setStringFromEnv(&o.flagAdminUsers, "ADMIN_USERS")
+	setStringFromEnv(&o.flagGRPCAddr, "GRPC_ADDRESS")
+	setStringFromEnv(&o.flagEventsBackend, "EVENTS_BACKEND")
+	setUintFromEnv(&o.flagCacheSize, "CACHE_SIZE")
+	setDurationFromEnv(&o.flagCacheTTL, "CACHE_TTL")
+	setStringFromEnv(&o.flagMasterKey, "MASTER_KEY")
+	setStringFromEnv(&o.flagMasterKeyFile, "MASTER_KEY_FILE")
+	setStringFromEnv(&o.flagJWTSigningKeys, "JWT_SIGNING_KEYS")
+	setStringFromEnv(&o.flagSRPSaltKey, "SRP_SALT_KEY")
+
 	if envEnableHTTPS := os.Getenv("ENABLE_HTTPS"); envEnableHTTPS != "" {
 		// Assuming "ENABLE_HTTPS" should be a boolean value
 		enableHTTPS, err := strconv.ParseBool(envEnableHTTPS)
@@ -82,6 +151,18 @@ func (o *Options) RunAddr() string {
 	return getStringFlag("a")
 }
 
+// GRPCAddr returns the configured address and port to run the gRPC server.
+// An empty address disables the gRPC server.
+func (o *Options) GRPCAddr() string {
+	return getStringFlag("grpc-address")
+}
+
+// EventsBackend returns the delivery of change notifications: "memory" within a single
+// server or "postgres" across all servers that share the database.
+func (o *Options) EventsBackend() string {
+	return getStringFlag("events-backend")
+}
+
 // DataBaseDSN returns the configured DSN for the database.
 func (o *Options) DataBaseDSN() string {
 	return getStringFlag("d")
@@ -118,6 +199,22 @@ func (o *Options) JWTSigningKey() string {
 	return getStringFlag("j")
 }
 
+// JWTSigningKeys returns the configured JWT signing keys as "[id:]key" entries.
+// When they are set, they are used instead of the single signing key.
+func (o *Options) JWTSigningKeys() string {
+	return getStringFlag("jwt-keys")
+}
+
+// SRPSaltKey returns the secret that derives the salts of unknown users at SRP login.
+// Without one the single JWT signing key is used, as it was before key rings.
+func (o *Options) SRPSaltKey() string {
+	if key := getStringFlag("srp-salt-key"); key != "" {
+		return key
+	}
+
+	return o.JWTSigningKey()
+}
+
 // HTTPSCertFile returns the path to the HTTPS cert file.
 func (o *Options) HTTPSCertFile() string {
 	return getStringFlag("r")
@@ -133,6 +230,120 @@ func (o *Options) EnableHTTPS() bool {
 	return getBoolFlag("s")
 }
 
+// Argon2Memory returns the Argon2id memory cost in KiB.
+func (o *Options) Argon2Memory() uint32 {
+	return uint32(getUintFlag("argon2-memory"))
+}
+
+// Argon2Iterations returns the number of Argon2id iterations.
+func (o *Options) Argon2Iterations() uint32 {
+	return uint32(getUintFlag("argon2-iterations"))
+}
+
+// Argon2Parallelism returns the Argon2id degree of parallelism.
+func (o *Options) Argon2Parallelism() uint8 {
+	return uint8(getUintFlag("argon2-parallelism"))
+}
+
+// AccessTokenTTL returns the lifetime of access tokens.
+func (o *Options) AccessTokenTTL() time.Duration {
+	return getDurationFlag("access-ttl")
+}
+
+// RefreshTokenTTL returns the lifetime of refresh tokens.
+func (o *Options) RefreshTokenTTL() time.Duration {
+	return getDurationFlag("refresh-ttl")
+}
+
+// MaxUploadSize returns the maximum size of an uploaded file in bytes.
+func (o *Options) MaxUploadSize() int64 {
+	return int64(getUintFlag("max-upload-size"))
+}
+
+// MaxBodySize returns the maximum size of a JSON request body in bytes.
+func (o *Options) MaxBodySize() int64 {
+	return int64(getUintFlag("max-body-size"))
+}
+
+// UploadTTL returns how long an inactive resumable upload is kept.
+func (o *Options) UploadTTL() time.Duration {
+	return getDurationFlag("upload-ttl")
+}
+
+// ReadTimeout returns the maximum duration for reading a request, including its body.
+// Requests that transfer file content extend it.
+func (o *Options) ReadTimeout() time.Duration {
+	return getDurationFlag("read-timeout")
+}
+
+// WriteTimeout returns the maximum duration for writing a response.
+// Requests that transfer file content extend it.
+func (o *Options) WriteTimeout() time.Duration {
+	return getDurationFlag("write-timeout")
+}
+
+// CacheSize returns the maximum number of entries in the storage cache. Zero disables the cache.
+func (o *Options) CacheSize() int {
+	return int(getUintFlag("cache-size"))
+}
+
+// CacheTTL returns how long an entry of the storage cache is served at most.
+func (o *Options) CacheTTL() time.Duration {
+	return getDurationFlag("cache-ttl")
+}
+
+// MasterKey returns the master keys that encrypt the vault columns as "[id:]base64" entries.
+func (o *Options) MasterKey() string {
+	return getStringFlag("master-key")
+}
+
+// MasterKeyFile returns the path to the file holding the master keys.
+func (o *Options) MasterKeyFile() string {
+	return getStringFlag("master-key-file")
+}
+
+// BlobStore returns the kind of storage for file content: "fs" or "s3".
+func (o *Options) BlobStore() string {
+	return getStringFlag("blob-store")
+}
+
+// S3Endpoint returns the base URL of the S3-compatible service.
+func (o *Options) S3Endpoint() string {
+	return getStringFlag("s3-endpoint")
+}
+
+// S3Bucket returns the S3 bucket for file content.
+func (o *Options) S3Bucket() string {
+	return getStringFlag("s3-bucket")
+}
+
+// S3Region returns the S3 region.
+func (o *Options) S3Region() string {
+	return getStringFlag("s3-region")
+}
+
+// S3AccessKey returns the S3 access key.
+func (o *Options) S3AccessKey() string {
+	return getStringFlag("s3-access-key")
+}
+
+// S3SecretKey returns the S3 secret key.
+func (o *Options) S3SecretKey() string {
+	return getStringFlag("s3-secret-key")
+}
+
+// AdminUsers returns the usernames allowed to use the admin endpoints.
+func (o *Options) AdminUsers() []string {
+	var users []string
+	for _, user := range strings.Split(getStringFlag("admin-users"), ",") {
+		if user = strings.TrimSpace(user); user != "" {
+			users = append(users, user)
+		}
+	}
+
+	return users
+}
+
 // regStringVar registers a string flag with the specified name, default value, and usage string.
 func regStringVar(p *string, name string, value string, usage string) {
 	if flag.Lookup(name) == nil {
@@ -140,6 +351,13 @@ func regStringVar(p *string, name string, value string, usage string) {
 	}
 }
 
+// setStringFromEnv overrides a string flag value with the environment variable, if it is set.
+func setStringFromEnv(p *string, key string) {
+	if env := os.Getenv(key); env != "" {
+		*p = env
+	}
+}
+
 // regBoolVar registers a bool flag with the specified name, default value, and usage string.
 func regBoolVar(p *bool, name string, value bool, usage string) {
 	if flag.Lookup(name) == nil {
@@ -147,6 +365,50 @@ func regBoolVar(p *bool, name string, value bool, usage string) {
 	}
 }
 
+// regUintVar registers a uint flag with the specified name, default value, and usage string.
+func regUintVar(p *uint, name string, value uint, usage string) {
+	if flag.Lookup(name) == nil {
+		flag.UintVar(p, name, value, usage)
+	}
+}
+
+// setUintFromEnv overrides a uint flag value with the environment variable, if it is set.
+func setUintFromEnv(p *uint, key string) {
+	env := os.Getenv(key)
+	if env == "" {
+		return
+	}
+
+	value, err := strconv.ParseUint(env, 10, 0)
+	if err != nil {
+		fmt.Printf("Failed to parse %s as an unsigned integer: %v\n", key, err)
+		return
+	}
+	*p = uint(value)
+}
+
+// regDurationVar registers a duration flag with the specified name, default value, and usage string.
+func regDurationVar(p *time.Duration, name string, value time.Duration, usage string) {
+	if flag.Lookup(name) == nil {
+		flag.DurationVar(p, name, value, usage)
+	}
+}
+
+// setDurationFromEnv overrides a duration flag value with the environment variable, if it is set.
+func setDurationFromEnv(p *time.Duration, key string) {
+	env := os.Getenv(key)
+	if env == "" {
+		return
+	}
+
+	value, err := time.ParseDuration(env)
+	if err != nil {
+		fmt.Printf("Failed to parse %s as a duration: %v\n", key, err)
+		return
+	}
+	*p = value
+}
+
 // getStringFlag retrieves the string value of the specified flag.
 func getStringFlag(name string) string {
 	return flag.Lookup(name).Value.(flag.Getter).Get().(string)
@@ -157,6 +419,16 @@ func getBoolFlag(name string) bool {
 	return flag.Lookup(name).Value.(flag.Getter).Get().(bool)
 }
 
+// getUintFlag retrieves the uint value of the specified flag.
+func getUintFlag(name string) uint {
+	return flag.Lookup(name).Value.(flag.Getter).Get().(uint)
+}
+
+// getDurationFlag retrieves the duration value of the specified flag.
+func getDurationFlag(name string) time.Duration {
+	return flag.Lookup(name).Value.(flag.Getter).Get().(time.Duration)
+}
+
 // GetAsString reads an environment variable or returns a default value.
 func GetAsString(key string, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
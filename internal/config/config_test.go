@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -28,6 +29,25 @@ func TestOptions_ParseFlags(t *testing.T) {
 	os.Setenv("HTTPS_CERT_FILE", "/path/to/cert_env.pem")
 	os.Setenv("HTTPS_KEY_FILE", "/path/to/key_env.pem")
 	os.Setenv("ENABLE_HTTPS", "true")
+	os.Setenv("ARGON2_MEMORY", "2048")
+	os.Setenv("ARGON2_ITERATIONS", "4")
+	os.Setenv("ARGON2_PARALLELISM", "1")
+	os.Setenv("ACCESS_TOKEN_TTL", "5m")
+	os.Setenv("REFRESH_TOKEN_TTL", "24h")
+	os.Setenv("MAX_UPLOAD_SIZE", "1024")
+	os.Setenv("MAX_BODY_SIZE", "4096")
+	os.Setenv("UPLOAD_TTL", "2h")
+	os.Setenv("READ_TIMEOUT", "10s")
+	os.Setenv("WRITE_TIMEOUT", "20s")
+	os.Setenv("BLOB_STORE", "s3")
+	os.Setenv("S3_ENDPOINT", "http://minio:9000")
+	os.Setenv("S3_BUCKET", "vault")
+	os.Setenv("S3_REGION", "eu-central-1")
+	os.Setenv("S3_ACCESS_KEY", "access")
+	os.Setenv("S3_SECRET_KEY", "secret")
+	os.Setenv("ADMIN_USERS", "alice, bob")
+	os.Setenv("GRPC_ADDRESS", ":9090")
+	os.Setenv("MASTER_KEY_FILE", "/run/secrets/master.key")
 
 	// Create an instance of Options
 	options := NewOptions()
@@ -44,6 +64,25 @@ func TestOptions_ParseFlags(t *testing.T) {
 	assert.Equal(t, "/path/to/cert_env.pem", options.HTTPSCertFile())
 	assert.Equal(t, "/path/to/key_env.pem", options.HTTPSKeyFile())
 	assert.True(t, options.EnableHTTPS())
+	assert.Equal(t, uint32(2048), options.Argon2Memory())
+	assert.Equal(t, uint32(4), options.Argon2Iterations())
+	assert.Equal(t, uint8(1), options.Argon2Parallelism())
+	assert.Equal(t, 5*time.Minute, options.AccessTokenTTL())
+	assert.Equal(t, 24*time.Hour, options.RefreshTokenTTL())
+	assert.Equal(t, int64(1024), options.MaxUploadSize())
+	assert.Equal(t, int64(4096), options.MaxBodySize())
+	assert.Equal(t, 2*time.Hour, options.UploadTTL())
+	assert.Equal(t, 10*time.Second, options.ReadTimeout())
+	assert.Equal(t, 20*time.Second, options.WriteTimeout())
+	assert.Equal(t, "s3", options.BlobStore())
+	assert.Equal(t, "http://minio:9000", options.S3Endpoint())
+	assert.Equal(t, "vault", options.S3Bucket())
+	assert.Equal(t, "eu-central-1", options.S3Region())
+	assert.Equal(t, "access", options.S3AccessKey())
+	assert.Equal(t, "secret", options.S3SecretKey())
+	assert.Equal(t, []string{"alice", "bob"}, options.AdminUsers())
+	assert.Equal(t, ":9090", options.GRPCAddr())
+	assert.Equal(t, "/run/secrets/master.key", options.MasterKeyFile())
 
 	// Reset the environment variables
 	os.Unsetenv("RUN_ADDRESS")
@@ -54,6 +93,25 @@ func TestOptions_ParseFlags(t *testing.T) {
 	os.Unsetenv("HTTPS_CERT_FILE")
 	os.Unsetenv("HTTPS_KEY_FILE")
 	os.Unsetenv("ENABLE_HTTPS")
+	os.Unsetenv("ARGON2_MEMORY")
+	os.Unsetenv("ARGON2_ITERATIONS")
+	os.Unsetenv("ARGON2_PARALLELISM")
+	os.Unsetenv("ACCESS_TOKEN_TTL")
+	os.Unsetenv("REFRESH_TOKEN_TTL")
+	os.Unsetenv("MAX_UPLOAD_SIZE")
+	os.Unsetenv("MAX_BODY_SIZE")
+	os.Unsetenv("UPLOAD_TTL")
+	os.Unsetenv("READ_TIMEOUT")
+	os.Unsetenv("WRITE_TIMEOUT")
+	os.Unsetenv("BLOB_STORE")
+	os.Unsetenv("S3_ENDPOINT")
+	os.Unsetenv("S3_BUCKET")
+	os.Unsetenv("S3_REGION")
+	os.Unsetenv("S3_ACCESS_KEY")
+	os.Unsetenv("S3_SECRET_KEY")
+	os.Unsetenv("ADMIN_USERS")
+	os.Unsetenv("GRPC_ADDRESS")
+	os.Unsetenv("MASTER_KEY_FILE")
 }
 
 func TestOptions_DefaultValues(t *testing.T) {
@@ -0,0 +1,2196 @@
+// Package controllers provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.3.0 DO NOT EDIT.
+package controllers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/oapi-codegen/runtime"
+)
+
+const (
+	TokenAuthScopes = "tokenAuth.Scopes"
+)
+
+// Defines values for RecordDeleted.
+const (
+	False RecordDeleted = "false"
+	True  RecordDeleted = "true"
+)
+
+// BatchResponse defines model for BatchResponse.
+type BatchResponse struct {
+	Committed bool          `json:"committed"`
+	Results   []BatchResult `json:"results"`
+}
+
+// Binary A file; its content is uploaded separately with /sendFile or /uploads.
+type Binary struct {
+	Extension *string `json:"extension,omitempty"`
+	MetaInfo  *string `json:"meta_info,omitempty"`
+	Path      string  `json:"path"`
+}
+
+// BlobStats defines model for BlobStats.
+type BlobStats struct {
+	Blobs        int64 `json:"blobs"`
+	LogicalBytes int64 `json:"logicalBytes"`
+	References   int64 `json:"references"`
+	SavedBytes   int64 `json:"savedBytes"`
+	StoredBytes  int64 `json:"storedBytes"`
+}
+
+// CacheStats defines model for CacheStats.
+type CacheStats struct {
+	Entries       int64 `json:"entries"`
+	Evictions     int64 `json:"evictions"`
+	Hits          int64 `json:"hits"`
+	Invalidations int64 `json:"invalidations"`
+	Misses        int64 `json:"misses"`
+}
+
+// Card A bank card.
+type Card struct {
+	CardNumber     string  `json:"card_number"`
+	Cvv            string  `json:"cvv"`
+	ExpirationDate string  `json:"expiration_date"`
+	MetaInfo       *string `json:"meta_info,omitempty"`
+}
+
+// Credentials A login and password for a site or an application.
+type Credentials struct {
+	Login    string  `json:"login"`
+	MetaInfo *string `json:"meta_info,omitempty"`
+	Password string  `json:"password"`
+}
+
+// FileContentResponse defines model for FileContentResponse.
+type FileContentResponse struct {
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// Item The fields of a new vault record; the kind must match the {table} path parameter.
+type Item struct {
+	union json.RawMessage
+}
+
+// ItemPatch The changed fields of a vault record.
+type ItemPatch struct {
+	// BaseVersion The version the changes are based on, if it is not sent in If-Match.
+	BaseVersion    *string `json:"base_version,omitempty"`
+	CardNumber     *string `json:"card_number,omitempty"`
+	Cvv            *string `json:"cvv,omitempty"`
+	Data           *string `json:"data,omitempty"`
+	ExpirationDate *string `json:"expiration_date,omitempty"`
+	Extension      *string `json:"extension,omitempty"`
+	Login          *string `json:"login,omitempty"`
+	MetaInfo       *string `json:"meta_info,omitempty"`
+	Password       *string `json:"password,omitempty"`
+	Path           *string `json:"path,omitempty"`
+}
+
+// LoginResponse defines model for LoginResponse.
+type LoginResponse struct {
+	ChallengeToken *string `json:"challengeToken,omitempty"`
+	DeviceID       *string `json:"deviceID,omitempty"`
+	MfaRequired    *bool   `json:"mfa_required,omitempty"`
+	RefreshToken   *string `json:"refreshToken,omitempty"`
+
+	// ServerProof The SRP-6a server proof of logins made with /srp/login/verify.
+	ServerProof *string `json:"serverProof,omitempty"`
+	SessionID   *string `json:"sessionID,omitempty"`
+	Token       *string `json:"token,omitempty"`
+	UserID      *int    `json:"userID,omitempty"`
+}
+
+// MFAEnrollment defines model for MFAEnrollment.
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
+
+// Record A stored vault record with its service fields.
+type Record struct {
+	CardNumber     *string       `json:"card_number,omitempty"`
+	Cvv            *string       `json:"cvv,omitempty"`
+	Data           *string       `json:"data,omitempty"`
+	Deleted        RecordDeleted `json:"deleted"`
+	ExpirationDate *string       `json:"expiration_date,omitempty"`
+	Extension      *string       `json:"extension,omitempty"`
+	Id             string        `json:"id"`
+	Login          *string       `json:"login,omitempty"`
+	MetaInfo       *string       `json:"meta_info,omitempty"`
+	Password       *string       `json:"password,omitempty"`
+	Path           *string       `json:"path,omitempty"`
+
+	// Sha256 The SHA-256 digest of the uploaded content of a file.
+	Sha256 *string `json:"sha256,omitempty"`
+
+	// Size The size of the uploaded content of a file.
+	Size      *string `json:"size,omitempty"`
+	UpdatedAt string  `json:"updated_at"`
+	UserId    string  `json:"user_id"`
+	Version   string  `json:"version"`
+}
+
+// RecordDeleted defines model for Record.Deleted.
+type RecordDeleted string
+
+// RecoveryCodes defines model for RecoveryCodes.
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// ReencryptionProgress defines model for ReencryptionProgress.
+type ReencryptionProgress struct {
+	// DataKeys The number of data keys wrapped with the current master key.
+	DataKeys   int64      `json:"dataKeys"`
+	Error      *string    `json:"error,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	// KeyID The ID of the master key the run moves the data to.
+	KeyID string `json:"keyID"`
+
+	// Records The number of records that were encrypted.
+	Records   int64      `json:"records"`
+	Running   bool       `json:"running"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+
+	// Total The number of users when the run started. Users registered during the run are processed as well, so users may end up above total.
+	Total int64 `json:"total"`
+
+	// Users The number of users processed so far.
+	Users int64 `json:"users"`
+}
+
+// SRPChallenge defines model for SRPChallenge.
+type SRPChallenge struct {
+	ChallengeID string `json:"challengeID"`
+	PublicB     string `json:"publicB"`
+	Salt        string `json:"salt"`
+}
+
+// Session defines model for Session.
+type Session struct {
+	CreatedAt  time.Time `json:"createdAt"`
+	Current    bool      `json:"current"`
+	DeviceID   string    `json:"deviceID"`
+	DeviceName string    `json:"deviceName"`
+	Id         string    `json:"id"`
+	Ip         string    `json:"ip"`
+	LastSeen   time.Time `json:"lastSeen"`
+}
+
+// SyncResponse defines model for SyncResponse.
+type SyncResponse struct {
+	Changes []Change `json:"changes"`
+	Cursor  string   `json:"cursor"`
+	HasMore bool     `json:"hasMore"`
+}
+
+// Text An arbitrary text.
+type Text struct {
+	Data     string  `json:"data"`
+	MetaInfo *string `json:"meta_info,omitempty"`
+}
+
+// UploadSession defines model for UploadSession.
+type UploadSession struct {
+	CreatedAt time.Time `json:"createdAt"`
+	EntryID   string    `json:"entryID"`
+	Id        string    `json:"id"`
+	Length    int64     `json:"length"`
+	Offset    int64     `json:"offset"`
+}
+
+// Version defines model for Version.
+type Version struct {
+	Version int64 `json:"version"`
+}
+
+// EntryID defines model for EntryID.
+type EntryID = string
+
+// Table defines model for Table.
+type Table = string
+
+// UploadID defines model for UploadID.
+type UploadID = string
+
+// UserID defines model for UserID.
+type UserID = int
+
+// Login defines model for Login.
+type Login = LoginResponse
+
+// PostBatchUserIDJSONBody defines parameters for PostBatchUserID.
+type PostBatchUserIDJSONBody struct {
+	Operations []BatchOperation `json:"operations"`
+
+	// Partial Partial keeps the successful operations when others fail.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// PostLoginJSONBody defines parameters for PostLogin.
+type PostLoginJSONBody struct {
+	DeviceID   string `json:"deviceID,omitempty"`
+	DeviceName string `json:"deviceName,omitempty"`
+	Password   string `json:"password"`
+	Username   string `json:"username"`
+}
+
+// PostLoginMfaJSONBody defines parameters for PostLoginMfa.
+type PostLoginMfaJSONBody struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code,omitempty"`
+	RecoveryCode   string `json:"recoveryCode,omitempty"`
+}
+
+// PostLogoutJSONBody defines parameters for PostLogout.
+type PostLogoutJSONBody struct {
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+// PostMfaUserIDEnrollJSONBody defines parameters for PostMfaUserIDEnroll.
+type PostMfaUserIDEnrollJSONBody struct {
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recoveryCode,omitempty"`
+}
+
+// PostMfaUserIDVerifyJSONBody defines parameters for PostMfaUserIDVerify.
+type PostMfaUserIDVerifyJSONBody struct {
+	Code string `json:"code"`
+}
+
+// PostRegisterJSONBody defines parameters for PostRegister.
+type PostRegisterJSONBody struct {
+	Password string `json:"password"`
+	Username string `json:"username"`
+}
+
+// PostSrpLoginInitJSONBody defines parameters for PostSrpLoginInit.
+type PostSrpLoginInitJSONBody struct {
+	PublicA  string `json:"publicA"`
+	Username string `json:"username"`
+}
+
+// PostSrpLoginVerifyJSONBody defines parameters for PostSrpLoginVerify.
+type PostSrpLoginVerifyJSONBody struct {
+	ChallengeID string `json:"challengeID"`
+	ClientProof string `json:"clientProof"`
+	DeviceID    string `json:"deviceID,omitempty"`
+	DeviceName  string `json:"deviceName,omitempty"`
+}
+
+// PostSrpRegisterJSONBody defines parameters for PostSrpRegister.
+type PostSrpRegisterJSONBody struct {
+	Salt     string `json:"salt"`
+	Username string `json:"username"`
+	Verifier string `json:"verifier"`
+}
+
+// GetSyncUserIDParams defines parameters for GetSyncUserID.
+type GetSyncUserIDParams struct {
+	// Cursor Cursor is the opaque position returned by the previous call. Without it, all records are returned.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Limit Limit is the maximum number of changes in the response.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PostTokenRefreshJSONBody defines parameters for PostTokenRefresh.
+type PostTokenRefreshJSONBody struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// PostUploadsUserIDJSONBody defines parameters for PostUploadsUserID.
+type PostUploadsUserIDJSONBody struct {
+	EntryID string `json:"entryID"`
+	Length  int64  `json:"length"`
+}
+
+// PatchUploadsUserIDUploadIDParams defines parameters for PatchUploadsUserIDUploadID.
+type PatchUploadsUserIDUploadIDParams struct {
+	// UploadOffset The offset the chunk starts at, which must be the current offset of the upload.
+	UploadOffset int64 `json:"Upload-Offset"`
+}
+
+// PostAddDataTableUserIDEntryIDJSONRequestBody defines body for PostAddDataTableUserIDEntryID for application/json ContentType.
+type PostAddDataTableUserIDEntryIDJSONRequestBody = Item
+
+// PostBatchUserIDJSONRequestBody defines body for PostBatchUserID for application/json ContentType.
+type PostBatchUserIDJSONRequestBody PostBatchUserIDJSONBody
+
+// PostLoginJSONRequestBody defines body for PostLogin for application/json ContentType.
+type PostLoginJSONRequestBody PostLoginJSONBody
+
+// PostLoginMfaJSONRequestBody defines body for PostLoginMfa for application/json ContentType.
+type PostLoginMfaJSONRequestBody PostLoginMfaJSONBody
+
+// PostLogoutJSONRequestBody defines body for PostLogout for application/json ContentType.
+type PostLogoutJSONRequestBody PostLogoutJSONBody
+
+// PostMfaUserIDEnrollJSONRequestBody defines body for PostMfaUserIDEnroll for application/json ContentType.
+type PostMfaUserIDEnrollJSONRequestBody PostMfaUserIDEnrollJSONBody
+
+// PostMfaUserIDVerifyJSONRequestBody defines body for PostMfaUserIDVerify for application/json ContentType.
+type PostMfaUserIDVerifyJSONRequestBody PostMfaUserIDVerifyJSONBody
+
+// PostRegisterJSONRequestBody defines body for PostRegister for application/json ContentType.
+type PostRegisterJSONRequestBody PostRegisterJSONBody
+
+// PostSrpLoginInitJSONRequestBody defines body for PostSrpLoginInit for application/json ContentType.
+type PostSrpLoginInitJSONRequestBody PostSrpLoginInitJSONBody
+
+// PostSrpLoginVerifyJSONRequestBody defines body for PostSrpLoginVerify for application/json ContentType.
+type PostSrpLoginVerifyJSONRequestBody PostSrpLoginVerifyJSONBody
+
+// PostSrpRegisterJSONRequestBody defines body for PostSrpRegister for application/json ContentType.
+type PostSrpRegisterJSONRequestBody PostSrpRegisterJSONBody
+
+// PostTokenRefreshJSONRequestBody defines body for PostTokenRefresh for application/json ContentType.
+type PostTokenRefreshJSONRequestBody PostTokenRefreshJSONBody
+
+// PutUpdateDataTableUserIDEntryIDJSONRequestBody defines body for PutUpdateDataTableUserIDEntryID for application/json ContentType.
+type PutUpdateDataTableUserIDEntryIDJSONRequestBody = ItemPatch
+
+// PostUploadsUserIDJSONRequestBody defines body for PostUploadsUserID for application/json ContentType.
+type PostUploadsUserIDJSONRequestBody PostUploadsUserIDJSONBody
+
+// AsCredentials returns the union data inside the Item as a Credentials
+func (t Item) AsCredentials() (Credentials, error) {
+	var body Credentials
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromCredentials overwrites any union data inside the Item as the provided Credentials
+func (t *Item) FromCredentials(v Credentials) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeCredentials performs a merge with any union data inside the Item, using the provided Credentials
+func (t *Item) MergeCredentials(v Credentials) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsCard returns the union data inside the Item as a Card
+func (t Item) AsCard() (Card, error) {
+	var body Card
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromCard overwrites any union data inside the Item as the provided Card
+func (t *Item) FromCard(v Card) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeCard performs a merge with any union data inside the Item, using the provided Card
+func (t *Item) MergeCard(v Card) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsText returns the union data inside the Item as a Text
+func (t Item) AsText() (Text, error) {
+	var body Text
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromText overwrites any union data inside the Item as the provided Text
+func (t *Item) FromText(v Text) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeText performs a merge with any union data inside the Item, using the provided Text
+func (t *Item) MergeText(v Text) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsBinary returns the union data inside the Item as a Binary
+func (t Item) AsBinary() (Binary, error) {
+	var body Binary
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromBinary overwrites any union data inside the Item as the provided Binary
+func (t *Item) FromBinary(v Binary) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeBinary performs a merge with any union data inside the Item, using the provided Binary
+func (t *Item) MergeBinary(v Binary) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+func (t Item) MarshalJSON() ([]byte, error) {
+	b, err := t.union.MarshalJSON()
+	return b, err
+}
+
+func (t *Item) UnmarshalJSON(b []byte) error {
+	err := t.union.UnmarshalJSON(b)
+	return err
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Add a vault record.
+	// (POST /addData/{table}/{userID}/{entryID})
+	PostAddDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table Table, userID UserID, entryID EntryID)
+	// Get the hit and miss counters of the storage cache.
+	// (GET /admin/cache-stats)
+	GetAdminCacheStats(w http.ResponseWriter, r *http.Request)
+	// Get the progress of the latest re-encryption run.
+	// (GET /admin/reencryption)
+	GetAdminReencryption(w http.ResponseWriter, r *http.Request)
+	// Start moving the stored data to the current master key.
+	// (POST /admin/reencryption)
+	PostAdminReencryption(w http.ResponseWriter, r *http.Request)
+	// Get the storage statistics of the blobs.
+	// (GET /admin/stats)
+	GetAdminStats(w http.ResponseWriter, r *http.Request)
+	// Apply add, update and delete operations in one transaction.
+	// (POST /batch/{userID})
+	PostBatchUserID(w http.ResponseWriter, r *http.Request, userID UserID)
+	// Mark a vault record as deleted.
+	// (DELETE /deleteData/{table}/{userID}/{entryID})
+	DeleteDeleteDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table Table, userID UserID, entryID EntryID)
+	// Stream notifications about changed vault records.
+	// (GET /events/{userID})
+	GetEventsUserID(w http.ResponseWriter, r *http.Request, userID UserID)
+	// Get the vault records of a kind changed after a point in time.
+	// (GET /getAllData/{table}/{userID}/{lastSync})
+	GetGetAllDataTableUserID(w http.ResponseWriter, r *http.Request, table Table, userID UserID, lastSync string)
+	// Get a vault record.
+	// (GET /getData/{table}/{userID}/{entryID})
+	GetGetDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table Table, userID UserID, entryID EntryID)
+	// Download the content of a file record.
+	// (GET /getFile/{userID}/{entryID})
+	GetGetFileUserIDEntryID(w http.ResponseWriter, r *http.Request, userID UserID, entryID EntryID)
+	// Not implemented; passwords never leave the server.
+	// (GET /getPassword/{username})
+	GetGetPasswordUsername(w http.ResponseWriter, r *http.Request, username string)
+	// Look up the ID of a user.
+	// (GET /getUserID/{username})
+	GetGetUserIDUsername(w http.ResponseWriter, r *http.Request, username string)
+	// Log in with a password.
+	// (POST /login)
+	PostLogin(w http.ResponseWriter, r *http.Request)
+	// Complete a login with a one-time or recovery code.
+	// (POST /login/mfa)
+	PostLoginMfa(w http.ResponseWriter, r *http.Request)
+	// End the session of the access token.
+	// (POST /logout)
+	PostLogout(w http.ResponseWriter, r *http.Request)
+	// Start enrolling a TOTP authenticator.
+	// (POST /mfa/{userID}/enroll)
+	PostMfaUserIDEnroll(w http.ResponseWriter, r *http.Request, userID UserID)
+	// Confirm a TOTP enrollment with a code.
+	// (POST /mfa/{userID}/verify)
+	PostMfaUserIDVerify(w http.ResponseWriter, r *http.Request, userID UserID)
+	// Register a user with a password.
+	// (POST /register)
+	PostRegister(w http.ResponseWriter, r *http.Request)
+	// Upload the content of a file record in one request.
+	// (POST /sendFile/{userID}/{fileName})
+	PostSendFileUserID(w http.ResponseWriter, r *http.Request, userID UserID, fileName string)
+	// End all sessions of a user.
+	// (DELETE /sessions/{userID})
+	DeleteSessionsUserID(w http.ResponseWriter, r *http.Request, userID UserID)
+	// List the device sessions of a user.
+	// (GET /sessions/{userID})
+	GetSessionsUserID(w http.ResponseWriter, r *http.Request, userID UserID)
+	// End a session of a user.
+	// (DELETE /sessions/{userID}/{sessionID})
+	DeleteSessionsUserIDSessionID(w http.ResponseWriter, r *http.Request, userID UserID, sessionID string)
+	// Start an SRP-6a login.
+	// (POST /srp/login/init)
+	PostSrpLoginInit(w http.ResponseWriter, r *http.Request)
+	// Complete an SRP-6a login with the client proof.
+	// (POST /srp/login/verify)
+	PostSrpLoginVerify(w http.ResponseWriter, r *http.Request)
+	// Register a user with an SRP-6a salt and verifier.
+	// (POST /srp/register)
+	PostSrpRegister(w http.ResponseWriter, r *http.Request)
+	// Get the changes of all vault records after a cursor.
+	// (GET /sync/{userID})
+	GetSyncUserID(w http.ResponseWriter, r *http.Request, userID UserID, params GetSyncUserIDParams)
+	// Exchange a refresh token for new tokens.
+	// (POST /token/refresh)
+	PostTokenRefresh(w http.ResponseWriter, r *http.Request)
+	// Update a vault record.
+	// (PUT /updateData/{table}/{userID}/{entryID})
+	PutUpdateDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table Table, userID UserID, entryID EntryID)
+	// Start a resumable upload of the content of a file record.
+	// (POST /uploads/{userID})
+	PostUploadsUserID(w http.ResponseWriter, r *http.Request, userID UserID)
+	// Abort a resumable upload.
+	// (DELETE /uploads/{userID}/{uploadID})
+	DeleteUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request, userID UserID, uploadID UploadID)
+	// Get the offset of a resumable upload.
+	// (HEAD /uploads/{userID}/{uploadID})
+	HeadUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request, userID UserID, uploadID UploadID)
+	// Append a chunk to a resumable upload.
+	// (PATCH /uploads/{userID}/{uploadID})
+	PatchUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request, userID UserID, uploadID UploadID, params PatchUploadsUserIDUploadIDParams)
+	// Complete a resumable upload and store its content.
+	// (POST /uploads/{userID}/{uploadID}/finish)
+	PostUploadsUserIDUploadIDFinish(w http.ResponseWriter, r *http.Request, userID UserID, uploadID UploadID)
+}
+
+// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+
+type Unimplemented struct{}
+
+// Add a vault record.
+// (POST /addData/{table}/{userID}/{entryID})
+func (_ Unimplemented) PostAddDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table Table, userID UserID, entryID EntryID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get the hit and miss counters of the storage cache.
+// (GET /admin/cache-stats)
+func (_ Unimplemented) GetAdminCacheStats(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get the progress of the latest re-encryption run.
+// (GET /admin/reencryption)
+func (_ Unimplemented) GetAdminReencryption(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Start moving the stored data to the current master key.
+// (POST /admin/reencryption)
+func (_ Unimplemented) PostAdminReencryption(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get the storage statistics of the blobs.
+// (GET /admin/stats)
+func (_ Unimplemented) GetAdminStats(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Apply add, update and delete operations in one transaction.
+// (POST /batch/{userID})
+func (_ Unimplemented) PostBatchUserID(w http.ResponseWriter, r *http.Request, userID UserID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Mark a vault record as deleted.
+// (DELETE /deleteData/{table}/{userID}/{entryID})
+func (_ Unimplemented) DeleteDeleteDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table Table, userID UserID, entryID EntryID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Stream notifications about changed vault records.
+// (GET /events/{userID})
+func (_ Unimplemented) GetEventsUserID(w http.ResponseWriter, r *http.Request, userID UserID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get the vault records of a kind changed after a point in time.
+// (GET /getAllData/{table}/{userID}/{lastSync})
+func (_ Unimplemented) GetGetAllDataTableUserID(w http.ResponseWriter, r *http.Request, table Table, userID UserID, lastSync string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get a vault record.
+// (GET /getData/{table}/{userID}/{entryID})
+func (_ Unimplemented) GetGetDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table Table, userID UserID, entryID EntryID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Download the content of a file record.
+// (GET /getFile/{userID}/{entryID})
+func (_ Unimplemented) GetGetFileUserIDEntryID(w http.ResponseWriter, r *http.Request, userID UserID, entryID EntryID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Not implemented; passwords never leave the server.
+// (GET /getPassword/{username})
+func (_ Unimplemented) GetGetPasswordUsername(w http.ResponseWriter, r *http.Request, username string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Look up the ID of a user.
+// (GET /getUserID/{username})
+func (_ Unimplemented) GetGetUserIDUsername(w http.ResponseWriter, r *http.Request, username string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Log in with a password.
+// (POST /login)
+func (_ Unimplemented) PostLogin(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Complete a login with a one-time or recovery code.
+// (POST /login/mfa)
+func (_ Unimplemented) PostLoginMfa(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// End the session of the access token.
+// (POST /logout)
+func (_ Unimplemented) PostLogout(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Start enrolling a TOTP authenticator.
+// (POST /mfa/{userID}/enroll)
+func (_ Unimplemented) PostMfaUserIDEnroll(w http.ResponseWriter, r *http.Request, userID UserID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Confirm a TOTP enrollment with a code.
+// (POST /mfa/{userID}/verify)
+func (_ Unimplemented) PostMfaUserIDVerify(w http.ResponseWriter, r *http.Request, userID UserID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Register a user with a password.
+// (POST /register)
+func (_ Unimplemented) PostRegister(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Upload the content of a file record in one request.
+// (POST /sendFile/{userID}/{fileName})
+func (_ Unimplemented) PostSendFileUserID(w http.ResponseWriter, r *http.Request, userID UserID, fileName string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// End all sessions of a user.
+// (DELETE /sessions/{userID})
+func (_ Unimplemented) DeleteSessionsUserID(w http.ResponseWriter, r *http.Request, userID UserID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List the device sessions of a user.
+// (GET /sessions/{userID})
+func (_ Unimplemented) GetSessionsUserID(w http.ResponseWriter, r *http.Request, userID UserID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// End a session of a user.
+// (DELETE /sessions/{userID}/{sessionID})
+func (_ Unimplemented) DeleteSessionsUserIDSessionID(w http.ResponseWriter, r *http.Request, userID UserID, sessionID string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Start an SRP-6a login.
+// (POST /srp/login/init)
+func (_ Unimplemented) PostSrpLoginInit(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Complete an SRP-6a login with the client proof.
+// (POST /srp/login/verify)
+func (_ Unimplemented) PostSrpLoginVerify(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Register a user with an SRP-6a salt and verifier.
+// (POST /srp/register)
+func (_ Unimplemented) PostSrpRegister(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get the changes of all vault records after a cursor.
+// (GET /sync/{userID})
+func (_ Unimplemented) GetSyncUserID(w http.ResponseWriter, r *http.Request, userID UserID, params GetSyncUserIDParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Exchange a refresh token for new tokens.
+// (POST /token/refresh)
+func (_ Unimplemented) PostTokenRefresh(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Update a vault record.
+// (PUT /updateData/{table}/{userID}/{entryID})
+func (_ Unimplemented) PutUpdateDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table Table, userID UserID, entryID EntryID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Start a resumable upload of the content of a file record.
+// (POST /uploads/{userID})
+func (_ Unimplemented) PostUploadsUserID(w http.ResponseWriter, r *http.Request, userID UserID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Abort a resumable upload.
+// (DELETE /uploads/{userID}/{uploadID})
+func (_ Unimplemented) DeleteUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request, userID UserID, uploadID UploadID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get the offset of a resumable upload.
+// (HEAD /uploads/{userID}/{uploadID})
+func (_ Unimplemented) HeadUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request, userID UserID, uploadID UploadID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Append a chunk to a resumable upload.
+// (PATCH /uploads/{userID}/{uploadID})
+func (_ Unimplemented) PatchUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request, userID UserID, uploadID UploadID, params PatchUploadsUserIDUploadIDParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Complete a resumable upload and store its content.
+// (POST /uploads/{userID}/{uploadID}/finish)
+func (_ Unimplemented) PostUploadsUserIDUploadIDFinish(w http.ResponseWriter, r *http.Request, userID UserID, uploadID UploadID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// PostAddDataTableUserIDEntryID operation middleware
+func (siw *ServerInterfaceWrapper) PostAddDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "table" -------------
+	var table Table
+
+	err = runtime.BindStyledParameterWithOptions("simple", "table", chi.URLParam(r, "table"), &table, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "table", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "entryID" -------------
+	var entryID EntryID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "entryID", chi.URLParam(r, "entryID"), &entryID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "entryID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAddDataTableUserIDEntryID(w, r, table, userID, entryID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetAdminCacheStats operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminCacheStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminCacheStats(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetAdminReencryption operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminReencryption(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminReencryption(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostAdminReencryption operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminReencryption(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostAdminReencryption(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetAdminStats operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAdminStats(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostBatchUserID operation middleware
+func (siw *ServerInterfaceWrapper) PostBatchUserID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostBatchUserID(w, r, userID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteDeleteDataTableUserIDEntryID operation middleware
+func (siw *ServerInterfaceWrapper) DeleteDeleteDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "table" -------------
+	var table Table
+
+	err = runtime.BindStyledParameterWithOptions("simple", "table", chi.URLParam(r, "table"), &table, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "table", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "entryID" -------------
+	var entryID EntryID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "entryID", chi.URLParam(r, "entryID"), &entryID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "entryID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteDeleteDataTableUserIDEntryID(w, r, table, userID, entryID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetEventsUserID operation middleware
+func (siw *ServerInterfaceWrapper) GetEventsUserID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetEventsUserID(w, r, userID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetGetAllDataTableUserID operation middleware
+func (siw *ServerInterfaceWrapper) GetGetAllDataTableUserID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "table" -------------
+	var table Table
+
+	err = runtime.BindStyledParameterWithOptions("simple", "table", chi.URLParam(r, "table"), &table, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "table", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "lastSync" -------------
+	var lastSync string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "lastSync", chi.URLParam(r, "lastSync"), &lastSync, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "lastSync", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetGetAllDataTableUserID(w, r, table, userID, lastSync)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetGetDataTableUserIDEntryID operation middleware
+func (siw *ServerInterfaceWrapper) GetGetDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "table" -------------
+	var table Table
+
+	err = runtime.BindStyledParameterWithOptions("simple", "table", chi.URLParam(r, "table"), &table, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "table", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "entryID" -------------
+	var entryID EntryID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "entryID", chi.URLParam(r, "entryID"), &entryID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "entryID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetGetDataTableUserIDEntryID(w, r, table, userID, entryID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetGetFileUserIDEntryID operation middleware
+func (siw *ServerInterfaceWrapper) GetGetFileUserIDEntryID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "entryID" -------------
+	var entryID EntryID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "entryID", chi.URLParam(r, "entryID"), &entryID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "entryID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetGetFileUserIDEntryID(w, r, userID, entryID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetGetPasswordUsername operation middleware
+func (siw *ServerInterfaceWrapper) GetGetPasswordUsername(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "username" -------------
+	var username string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetGetPasswordUsername(w, r, username)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetGetUserIDUsername operation middleware
+func (siw *ServerInterfaceWrapper) GetGetUserIDUsername(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "username" -------------
+	var username string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetGetUserIDUsername(w, r, username)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostLogin operation middleware
+func (siw *ServerInterfaceWrapper) PostLogin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostLogin(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostLoginMfa operation middleware
+func (siw *ServerInterfaceWrapper) PostLoginMfa(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostLoginMfa(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostLogout operation middleware
+func (siw *ServerInterfaceWrapper) PostLogout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostLogout(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostMfaUserIDEnroll operation middleware
+func (siw *ServerInterfaceWrapper) PostMfaUserIDEnroll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostMfaUserIDEnroll(w, r, userID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostMfaUserIDVerify operation middleware
+func (siw *ServerInterfaceWrapper) PostMfaUserIDVerify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostMfaUserIDVerify(w, r, userID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostRegister operation middleware
+func (siw *ServerInterfaceWrapper) PostRegister(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostRegister(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostSendFileUserID operation middleware
+func (siw *ServerInterfaceWrapper) PostSendFileUserID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "fileName" -------------
+	var fileName string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "fileName", chi.URLParam(r, "fileName"), &fileName, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "fileName", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostSendFileUserID(w, r, userID, fileName)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteSessionsUserID operation middleware
+func (siw *ServerInterfaceWrapper) DeleteSessionsUserID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteSessionsUserID(w, r, userID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetSessionsUserID operation middleware
+func (siw *ServerInterfaceWrapper) GetSessionsUserID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSessionsUserID(w, r, userID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteSessionsUserIDSessionID operation middleware
+func (siw *ServerInterfaceWrapper) DeleteSessionsUserIDSessionID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "sessionID" -------------
+	var sessionID string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "sessionID", chi.URLParam(r, "sessionID"), &sessionID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sessionID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteSessionsUserIDSessionID(w, r, userID, sessionID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostSrpLoginInit operation middleware
+func (siw *ServerInterfaceWrapper) PostSrpLoginInit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostSrpLoginInit(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostSrpLoginVerify operation middleware
+func (siw *ServerInterfaceWrapper) PostSrpLoginVerify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostSrpLoginVerify(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostSrpRegister operation middleware
+func (siw *ServerInterfaceWrapper) PostSrpRegister(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostSrpRegister(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetSyncUserID operation middleware
+func (siw *ServerInterfaceWrapper) GetSyncUserID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSyncUserIDParams
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSyncUserID(w, r, userID, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostTokenRefresh operation middleware
+func (siw *ServerInterfaceWrapper) PostTokenRefresh(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostTokenRefresh(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PutUpdateDataTableUserIDEntryID operation middleware
+func (siw *ServerInterfaceWrapper) PutUpdateDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "table" -------------
+	var table Table
+
+	err = runtime.BindStyledParameterWithOptions("simple", "table", chi.URLParam(r, "table"), &table, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "table", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "entryID" -------------
+	var entryID EntryID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "entryID", chi.URLParam(r, "entryID"), &entryID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "entryID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutUpdateDataTableUserIDEntryID(w, r, table, userID, entryID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostUploadsUserID operation middleware
+func (siw *ServerInterfaceWrapper) PostUploadsUserID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUploadsUserID(w, r, userID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteUploadsUserIDUploadID operation middleware
+func (siw *ServerInterfaceWrapper) DeleteUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "uploadID" -------------
+	var uploadID UploadID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uploadID", chi.URLParam(r, "uploadID"), &uploadID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "uploadID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteUploadsUserIDUploadID(w, r, userID, uploadID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// HeadUploadsUserIDUploadID operation middleware
+func (siw *ServerInterfaceWrapper) HeadUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "uploadID" -------------
+	var uploadID UploadID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uploadID", chi.URLParam(r, "uploadID"), &uploadID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "uploadID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.HeadUploadsUserIDUploadID(w, r, userID, uploadID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PatchUploadsUserIDUploadID operation middleware
+func (siw *ServerInterfaceWrapper) PatchUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "uploadID" -------------
+	var uploadID UploadID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uploadID", chi.URLParam(r, "uploadID"), &uploadID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "uploadID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchUploadsUserIDUploadIDParams
+
+	headers := r.Header
+
+	// ------------- Required header parameter "Upload-Offset" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Upload-Offset")]; found {
+		var UploadOffset int64
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Upload-Offset", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Upload-Offset", valueList[0], &UploadOffset, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Upload-Offset", Err: err})
+			return
+		}
+
+		params.UploadOffset = UploadOffset
+
+	} else {
+		err := fmt.Errorf("Header parameter Upload-Offset is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "Upload-Offset", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchUploadsUserIDUploadID(w, r, userID, uploadID, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostUploadsUserIDUploadIDFinish operation middleware
+func (siw *ServerInterfaceWrapper) PostUploadsUserIDUploadIDFinish(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "userID" -------------
+	var userID UserID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "uploadID" -------------
+	var uploadID UploadID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "uploadID", chi.URLParam(r, "uploadID"), &uploadID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "uploadID", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, TokenAuthScopes, []string{})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostUploadsUserIDUploadIDFinish(w, r, userID, uploadID)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/addData/{table}/{userID}/{entryID}", wrapper.PostAddDataTableUserIDEntryID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/cache-stats", wrapper.GetAdminCacheStats)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/reencryption", wrapper.GetAdminReencryption)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/reencryption", wrapper.PostAdminReencryption)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/stats", wrapper.GetAdminStats)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/batch/{userID}", wrapper.PostBatchUserID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/deleteData/{table}/{userID}/{entryID}", wrapper.DeleteDeleteDataTableUserIDEntryID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/events/{userID}", wrapper.GetEventsUserID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/getAllData/{table}/{userID}/{lastSync}", wrapper.GetGetAllDataTableUserID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/getData/{table}/{userID}/{entryID}", wrapper.GetGetDataTableUserIDEntryID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/getFile/{userID}/{entryID}", wrapper.GetGetFileUserIDEntryID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/getPassword/{username}", wrapper.GetGetPasswordUsername)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/getUserID/{username}", wrapper.GetGetUserIDUsername)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/login", wrapper.PostLogin)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/login/mfa", wrapper.PostLoginMfa)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/logout", wrapper.PostLogout)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/mfa/{userID}/enroll", wrapper.PostMfaUserIDEnroll)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/mfa/{userID}/verify", wrapper.PostMfaUserIDVerify)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/register", wrapper.PostRegister)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sendFile/{userID}/{fileName}", wrapper.PostSendFileUserID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/sessions/{userID}", wrapper.DeleteSessionsUserID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sessions/{userID}", wrapper.GetSessionsUserID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/sessions/{userID}/{sessionID}", wrapper.DeleteSessionsUserIDSessionID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/srp/login/init", wrapper.PostSrpLoginInit)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/srp/login/verify", wrapper.PostSrpLoginVerify)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/srp/register", wrapper.PostSrpRegister)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sync/{userID}", wrapper.GetSyncUserID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/token/refresh", wrapper.PostTokenRefresh)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/updateData/{table}/{userID}/{entryID}", wrapper.PutUpdateDataTableUserIDEntryID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/uploads/{userID}", wrapper.PostUploadsUserID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/uploads/{userID}/{uploadID}", wrapper.DeleteUploadsUserIDUploadID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Head(options.BaseURL+"/uploads/{userID}/{uploadID}", wrapper.HeadUploadsUserIDUploadID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/uploads/{userID}/{uploadID}", wrapper.PatchUploadsUserIDUploadID)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/uploads/{userID}/{uploadID}/finish", wrapper.PostUploadsUserIDUploadIDFinish)
+	})
+
+	return r
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+RdW3Pbtrb+Kxie8yhLdtPdmbpPinOpT3MbO+l+iDMxRC5J2CYBFgBlqxn/9zMLF15E",
+	"kKJk2cmePiWWcFlY+LDugL5FschywYFrFZ1+i5ZAE5Dmvy8/0gX+m4CKJcs1Ezw6jT4ugSgtBV8Q4Jrp",
+	"NdF0QcSc6CUQCbqQHBIiIZeggGuK3cbRKFLxEjKK4+l1DtFppLRkfBHd34+iT3kqaPJ+PlegwzPyIpuB",
+	"xGlmaw3Kz1eYjkRpISEhSpA5lc3J5kJmVEenEeP6l5+jkZ+dcQ0LkNE9zp9TSTPQft1cy/X5C/wvw/lz",
+	"qpfRKOI0w47gvh1FEv4qmIQkOtWygP4VfqSzFMJLkxALmZAbxpMRERxwbZ8UyDMJCXKYpmpE8A+mz6hM",
+	"XlBNR+Qj3Gn8H6E8Ia9YCgr/wqUHSNZm8t0ItlvSyYXCf73jqApk95j2ywEjNjZPgsoFV2D27jlNLuCv",
+	"ApTuYrb5kjBFMpoiOCAZR/ej6I1YIEl4HLgGbrrTPE9ZbBA8+Y8S5uuKlv+VMI9Oo/+ZVAdoYr9VEzPa",
+	"hSPMkrlBi7gBbmBMCYdbokApJviICEkoiZc0TYEvgGhsR5hDuwJJllQRfSuO5jTWQl5xWuglwsTSafCQ",
+	"FUoTpCoFDaZnivQQqsnE/G+Szen4iuO63wn9ShQ86WKXEoWMgSQCFOFCE7hjShuOfTJTC8n+ho7ecYVg",
+	"QiWQW5QaYwMExyi7ZTpevs9BUtv1W5RLkYPUzG7pjCr4E6RyXzaneV59STJ6A4pQToo8oRpILHjCsB1N",
+	"ieCGD3EhJXBNVq5LKbbwCOLp2SYuRtHd0UIc4adH6oblRyK3MxzlAttIi1ncb6oNTGjiifjQWNbG4Shn",
+	"ErP/QKx3mIglweFEjh8DL7Lo9DNSEY0iy5gIwYjQiL6MAmR4SdU+vdW5/IzDj0rBwpLoy+YC7kd2Z8tT",
+	"0NrYWGQZ0xrq9M+ESIEaZEpQRWqVEtOQqW1nzs9WpLrGTiolXbeor6au5ulbAY7Zpt+CaRtdFwZcOBpI",
+	"KWRwszr2UGmqCxUSfKNoVR2J7TquuXqWROXYwVUzTuW6G7xzmirYlGhTMmcp/EaYVsTJUJSyVlGgcgbU",
+	"shrSNbllekkmCniCmgtF3sQ2U3gEm0yGOw3cL7TFoAw0/cr4XAS/NeplK5JNqyAbUjG71NRCcEMmpWKm",
+	"BrF+FKHEjWn6fK1haBcJc5DA48EdFF1BsssM1mIa3mODZ3b9DUKbY24su0FhiNdnNF5CB7OBa8kGrwxW",
+	"LEZIDm2/ZHpoU8ZXNGUJ3WX4jCm1H4/9sh2J5VD1JW7SFGatTHY+yzPKb0hMrVLcEHtUJl+tPR48dfFq",
+	"Ffwc7nJmdfxXo4V2Pc+bIrxGRntwS0aQHUvKFxBG2Xq4LB+oJr2GtKMH6amMpJ13yZl1PCE5VeoW3Yi5",
+	"MSAV00auUk5qRmx7L1Nv9e4sWe102xlgZ6j1CPEA1cCZ1Rnd5oJa0p/+9Ut7RmcpOSfi8vcptkIRx/6G",
+	"fc6d6Tfy04XIPdeQhS3eOYM0qZn1K1qk2pmXvxlT84Z5Az1D28J89s2g5J6gJiKlL4q7JTi8n0enn/tR",
+	"WYfQ/WhLWyqTrY3Qu9zayFkJ918cRz7genaEsPESzIFMGryr821sRB+vj3UyCvgIX1ddTgLO4r4kupzR",
+	"+iTYMyHoebE5YcZk4UITZcwXTs7nR29xXeMoYCrvKwm9a7CXiOw3iB7hQPfZUa2j0XR82yazd2yN/xtm",
+	"Dmo3OH8R/DKb06/VWQ17DXMJatk9vgK5AvlBCjEPI+Xy4sPRL5TYdiTHhghKw1mMGiTgDViZO3d6BZLN",
+	"10GMOMe+Yz26k8yijJQExFWL7W9fTV9yKdI0cw7JhuiEWNrIWnseybYLcdfftg5JRKcXW/yc+tBc/UBb",
+	"9qGfgDxmsRebhzM1Og+Y9XuTumesZQHo+KN4CjrEDz+UHd7dU57VUU19BiD/+/Top3/9QhK2AKWbwVVI",
+	"SnfOiGb08sJId/q2PTx+s+egNmKRfKW685R87WBvTR30o9u4w36kCiONyavhuuC/Ark+EwkEHBi5+XUZ",
+	"z+gKAoWjFs1xwoQAj+XaMP+DFAsJKkAPHo8/YK22hdqxHbmBtSK3kuY5uJNbD6RlVGmQ2GhI9Kw3BjJn",
+	"nKklJFPdsNpwC440yyAEjxtwgfr2Ms5feMhVNJo/ZcFJJlagzF9mjVoEwWfF1VY2uWZEL6kmtyCBuE2A",
+	"ZCBTZME5zhnUaEpTqXdjixaaptvIRsQrcrsEXrLFTTU2+QdFJCwYsg4SkhQ4dtmQSkDNGINC64kqcgtp",
+	"OiJKuFEzuibAE1LkhM7ECoihaCA3zBDDqK9oqHI/u9r7nvkeTZ4Az8ZRdV4qSIQO3+XFhzNv3/SYPh2m",
+	"QF7MUhY/D36naKoHeMS1GVyfatggwdY2CdAqge4IuVowtA3hXpvOfvnOuG/DlSfLgx+nVOlLAD6U8JAq",
+	"KKlt0GamHNVYU5usWn6QzWse95rEfGH/OyjM7eIXLV1haFAdsnVJ1VshIbQ7bRAZcsrhqs6htRlPccew",
+	"BYqPGdOSyjXRcKfbdl+n/bZDfMiMESLZZjYPiX2oEsaDbT/gC71sTNEtEEWZGN8r0l9lrN2s5Yh1OIdY",
+	"VUu7NZn0gORDtx1lvKW4kEyvLxHsdirjIk0Ly6y2RqAxKgCfJ1WqMN689dbKdLgtaqgyzVOXuLQZx+ok",
+	"5ewPWNt0rQdZc8YPPsyWUU4XIL2XaFT/DUCu6knPURVGVSMDdWWCdWjtmkAH+kBG2Yyv+BX/s+YjKaIl",
+	"XUGKuvX/Lt+/I5ZJqK+FArKiaeECGBZTakyMpd1wqK74NUuuR+TaGbf4X2fdmk9L8/bakHXttubajJtR",
+	"xjVlHBIys4aTXetvVxz/EHoJ0s1DEshR27vwSq2ggSzYCrgf4NoFuq43I124+gtRaFDWwqTk+pt1gFtt",
+	"ieDp2pJS5cSdoVfHAqa3R5FmOoXoNHot8uUfADlIMv1wXrPmT6OT8fH42CZMgdOcRafRs/Hx+FlkfSmD",
+	"wQlNTN3FxC1g4qmbfHOH696cEWHLDoRPZp8niBmh9NT2N1UgtgbiZXko68UnHYG+qsnEDNEZm6s1tNMM",
+	"aelJuf9iTyoo/Vwk64MVQ5hw6X1TDrjcdaN246fj494KGaYITRLvhfiA3gme8kDVUogi12xi2hiKfj4+",
+	"7mpc0japFZWYLr+GyTRQpKkEmqxNmQZtBDz0kily/sLVPxRZZnKs0TRJWgFPbDGhScb4JMbE2JHymbEF",
+	"BBD2GvQUG9eSaGHWHmQ7a7OEClvQORQF1yjVLLue9bDLhVsxT4ErYEpLqoXcZNJr0OaEL5m2FS5MqXIa",
+	"f/yVFpIugBiWNZgoa27xVi7WfejH5GPQV+/gaO6+fxBHsefP4Z5OjSFouWg49eF98PR41qdUg0L8HlVr",
+	"QkfRzOrFYnPaf0ua11xwE2bAWFCaes+0O9ZgMOBmUjWdo3y4seClCz4ilMxMqqX0GqkmlKAxNybTuQZ5",
+	"S637DkSkCcjaRIrElJMZkEQKDICYnExAum+FzU/fBTboquOeer/+6dHTIS6nJWk4m4/8kDXoTcRdIukY",
+	"r/HhB7fDLm7TGYyqnf5hwvPR5WZVTtKxW0gnU5rFjyA4vWys5vBH15RyOH6Zc1IaN3WTpknFJ57i4b/O",
+	"qURD9xqpUaBHZsA5k0qTOWUpblnJbILpCUVmNL4xzW6XIgV7Mq84nmd7jC3zyQK0sikCU6Y0JuecuNlI",
+	"JhKwZqCZjrIUkmoeaxUXPBEcrBHYPrCmrOuTLzfdzQBz3R5iLDV9qYry3UrdqpJJdIzp3bnteXJ8fGwS",
+	"pv7vdqDAMTLk3lgOW0/G4KYwNvW8SOscNkFD4wEow/9a9NTHFYbWL7bKCktmBBzErSZkAnPqavUOc2Yb",
+	"FYxdUtYWEJZeCM7WQGTL5MvzdI227MiXqiL+rWdWZzPjph5cS8oVjW0FhzmntukAj8Q2bAu9F3aAcpj/",
+	"BsdkN0/B+blOku5h5P+8vUtZO93c3rdU3myY9ITWKcIthJUZrC5rF6HLD1NyabTr0SVwTV6aXkRpCTQb",
+	"459y7coZbCLNTSdqBeNGNnONJFCOIQEN2ZHtk1wTIYn9xAcGiKHMxRmMlkW/i9upXRhiTM5ElhlSqIQr",
+	"bsbPQTKRYM0fSmZhhIhVz4JzMPBFcPMxmZI4ZdjFhE3m1OgFWGK8gKkrnjDlukCC9aRELUWRJmZt5mNz",
+	"XGJj0xW5T8aveRwS969BW6YdQtz3WgYY37HbemT3pylmNqOVQVFiee+2t2UJ4aeo9dncSTOF2ZVClyU0",
+	"dch5nb4APU3TDllhIthrHvfh72+QwljL7naPMjZ6I+mFGpcL7SH+G6F87SJEpiNNlSh7u0ZEcFDj0H69",
+	"LimuiaUnkUetQPXFqzPy7NmzX623EL5d43m401WYLw80NAeZCVW1Ii/S1PDDEbaRZu5Qax5Ge0jQoBHa",
+	"gKeVVyZI6OFL0RkjlBgLAbWf5bqH8QB912Xhv7a9/zvV3IHcRouFvr1+UCTtWZd36FSS0ixN7e2lWjme",
+	"jQ/bQrt3goOvtnt6rY0gDcbhFqCxPrUfcc1lXxZ5LqS9CFFeQUKlJY2mdn6DshWhyELUsbqzEMcBoEtY",
+	"InUPA/WjY1XEGsJqsUwfzWw56WiYoqyxZG/E/nT8SzAy4a2pBuv7EO4abUDc7WEvwveH6wtxy83d1xqV",
+	"VS1VC8E+cWVRjJqrid5cQkx1pblCOPNjfHIjtGHWcanTNd5fO/7r+KTN+XdCE4Z3HDPgpV1dMWjj69/K",
+	"EnlFOGC4KgXqckg2flUxyx6HMKtCjLHtn54tu+qH0O3ZjvBSmadwCdno9POXOnffCHGDxrcuK62o6eiY",
+	"WJY2dqfE7IXbQwVRuitMdrix2VOIMnyU3urMctu3li8UdTR1Xp0YnFTrlzF2L/bVuSfbuzTuCvfjaoGy",
+	"0mWB/crrqMK7ywOQ9XZODwauAVXrsUgehJp6aef+43QVhFm6/wHwOfOX3qm7G+WAhHrXeKJCEs9qgntW",
+	"IUsUeiussM2hQNV/UWGHHW/t6eC8urubgMYn8KQMlu21IeUWvHShfD94qDbDMj2b08qoBnN9oTvlcAF5",
+	"SmOfBKo9eiBkpX3sdlevI5CNxxEc1tUVr0EiNumEeSOTtDG+JHQDNh2Jhbdz6i1xs5zvnlz4YeTSUJQe",
+	"xNltXofpyqkDTxBPH99//EDsDZeevFsnpszhwaBAQnwWyyCKKVOlgDMI2Xj6YiO3aZGP7ailpQG+0Fmx",
+	"1436pVWJxD9t4x8UiS2Vha0eoKgOFiup7lmE4DMADWNyUZcYrlhvKW65zV0KHsMBYmtngs+ZzDx2oIS9",
+	"V301Lefr+fuRc+FbHWrn6xZxxvgbV/p6Muq3j3ubPq613J1zr65E7L93HbaL53tDl7VsYP9qRi0chQ7/",
+	"O++mdu/rpev5wETIqP+yTfkWlY/7mQSB7TFzd278ba+AX+zXsrtfPASpDwxDPZ0ECl2I74+EmTSfFnsD",
+	"c+do1Cj6+eRZ1y341Og/LQRJqVzApuKzJfi9oSuf/HYbW6LfGJXNvGl/ktsV+j9S/q/TqLby3pvVLQsZ",
+	"U2hly3r0ZNQZaHrchRw28+RoDdxoDGIYixtWFes2GfaGKZtAshGaMOOC8Jh8K+9f7wyVS9/zIaIyIOJU",
+	"bdwHx/4GO3X7R5oNXOvuXJPl5SV4xtkWD/pS5iZUcI4tD2ZdmAtu0wOG29yA39sKbVwn7Dg4rvJSscqF",
+	"xfJYuLMp1YPbKNZjodw/kuCu2WwgYYh/4rFQuicHDtV1XMeyhSfl6w87vD7xtHHk3qud9UX8o4J6TdzV",
+	"KsQNQ+xbHTU0DvN3LmV+cJen48Jur0Qyl5LYnAXfuugWV+6eb9n3h3R7ht6aMa+LqvEeflIJDeSHicR4",
+	"jnhArHkcKrdr21lrHh/aRzozd2l9ll/k9K8CSC4Uc2FJ92qyuyeXS1gxUeDlgzQdk38zvRSFJkyPGqVX",
+	"VFYPLpfu1F8FGPfFGRvlJd6eV3k3iX3DMvsQkn084Y5lRVa7ee9fT2K8UbLdRUGKo0W9j/g+Zt1L4/J1",
+	"QItOSU4XUFvXAeudPKfctZZm+ZMvdrI75EBqwuMTlxroF1smcXDhWj5NUqL9Hkmt9T9AEb10ZpXJBpil",
+	"u3vHcyHNc2/mL194aUu7h1wbLfR+b5aVpb3uKPrHyoitdSFCum+u+HX9hbRre2+3LlnqF3eZIhi3vJVM",
+	"a+Ck4LUqpnQdTH0U+lO52n/uJVf7At4Tuwn+jn5vZZ99iddc937667K7hpWOfz0Yc4ZUPZqCLV+CqhiP",
+	"bVEQHhh/Am2h3kwka68WfaLQOWCxyNcPYOxGcMxeCQnVIrpnkoNXtNoqwobZ1I9y0anvmYye9zDwdltW",
+	"ZNHp8aAncxsvXeynlE4Ohr/mWyNdBVemESLLvcZBqJXHb0T1bmsNWf7jLfcL9jyuewd1XWzAXIfKUID7",
+	"hTVLKbuKFDehPfnmf2NiQOSugfRP1W9TPFYZbDlFwHbtKBKtdlkCPj/28MDcdCaCDDcDI17a3PodaPIj",
+	"8arD7bTP0xgvzh7k5uuBG+fBTnD0phQhu/z6i38P6Kj69Zk+id34pZr7+wfuoHcU3HrFvGs3c/+27oaQ",
+	"x4+/336OevbO2qwFv7GXzxWhekRulyxe2tePZ9DQoxUHmtscfL6nuWN9UfTdlMmPkkfsKjM37Kzl+UKn",
+	"4Ilg3BPQcVtZ/mKMfedayNrO4iIYJ4WNGhxwESfP+lgHdzGAe+khLFda93TBJD9sdy3Cx3OL9prY5w12",
+	"MNX8CXtlO35H0fyjJLMPhc06/MofSBKyBONmbU1ZTdoyalA3GRLrP3gyju434geN98s+f7n/cv//AwD4",
+	"IcZYcW0AAA==",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
@@ -0,0 +1,33 @@
+package controllers
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.3.0 --config=../../api/server.cfg.yaml ../../api/openapi.yaml
+
+import (
+	"net/http"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+)
+
+// These schemas are the storage models themselves, so the spec reuses them
+// instead of generating copies.
+type (
+	BatchOperation = models.BatchOperation
+	BatchResult    = models.BatchResult
+	Change         = models.Change
+	Event          = models.Event
+)
+
+// Secured applies mw only to the operations the spec protects with tokenAuth,
+// so public routes such as /login and /register skip it.
+func Secured(mw MiddlewareFunc) MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		secured := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Context().Value(TokenAuthScopes) == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			secured.ServeHTTP(w, r)
+		})
+	}
+}
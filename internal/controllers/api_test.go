@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSwagger(t *testing.T) {
+	spec, err := GetSwagger()
+	require.NoError(t, err)
+
+	assert.NoError(t, spec.Validate(context.Background()))
+}
+
+func TestSecured(t *testing.T) {
+	deny := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+	}
+	handler := HandlerWithOptions(&BaseController{}, ChiServerOptions{
+		Middlewares: []MiddlewareFunc{Secured(deny)},
+	})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/sessions/1", nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/login", nil))
+	assert.NotEqual(t, http.StatusUnauthorized, rr.Code)
+}
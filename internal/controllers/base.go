@@ -1,103 +1,69 @@
-// Package server provides primitives to interact with the openapi HTTP API.
-//
-// Code generated by github.com/deepmap/oapi-codegen/v2 version v2.1.0 DO NOT _EDIT.
 package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/oapi-codegen/runtime"
+	"github.com/wurt83ow/gophkeeper-server/internal/blobstore"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/schema"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+	"github.com/wurt83ow/gophkeeper-server/internal/uploads"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-// PostAddDataTableUserIDEntryIDJSONBody defines parameters for PostAddDataTableUserIDEntryID.
-type PostAddDataTableUserIDEntryIDJSONBody map[string]string
-
-// PostLoginJSONBody defines parameters for PostLogin.
-type PostLoginJSONBody struct {
-	Password string `json:"password,omitempty"`
-	Username string `json:"username,omitempty"`
-}
-
-// PostRegisterJSONBody defines parameters for PostRegister.
-type PostRegisterJSONBody struct {
-	Password string `json:"password,omitempty"`
-	Username string `json:"username,omitempty"`
-}
-
-// PutUpdateDataTableUserIDEntryIDJSONBody defines parameters for PutUpdateDataTableUserIDEntryID.
-type PutUpdateDataTableUserIDEntryIDJSONBody map[string]string
-
-// PostAddDataTableUserIDEntryIDJSONRequestBody defines body for PostAddDataTableUserIDEntryID for application/json ContentType.
-type PostAddDataTableUserIDEntryIDJSONRequestBody PostAddDataTableUserIDEntryIDJSONBody
-
-// PostLoginJSONRequestBody defines body for PostLogin for application/json ContentType.
-type PostLoginJSONRequestBody PostLoginJSONBody
-
-// PostRegisterJSONRequestBody defines body for PostRegister for application/json ContentType.
-type PostRegisterJSONRequestBody PostRegisterJSONBody
-
-// PutUpdateDataTableUserIDEntryIDJSONRequestBody defines body for PutUpdateDataTableUserIDEntryID for application/json ContentType.
-type PutUpdateDataTableUserIDEntryIDJSONRequestBody PutUpdateDataTableUserIDEntryIDJSONBody
-
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-
-	// (POST /addData/{table}/{userID}/{entryID})
-	PostAddDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table string, userID int, entryID string)
-
-	// (DELETE /deleteData/{table}/{userID}/{entryID})
-	DeleteDeleteDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table string, userID int, entryID string)
-
-	// (GET /getAllData/{table}/{userID}/{lastSync})
-	GetGetAllDataTableUserID(w http.ResponseWriter, r *http.Request, table string, userID int, lastSyncStr string)
-
-	// (GET /getData/{table}/{userID}/{entryID})
-	GetGetDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table string, userID int, entryID string)
-
-	// (GET /getFile/{userID}/{entryID})
-	GetGetFileUserIDEntryID(w http.ResponseWriter, r *http.Request, userID int, entryID string)
-
-	// (GET /getPassword/{username})
-	GetGetPasswordUsername(w http.ResponseWriter, r *http.Request, username string)
-
-	// (GET /getUserID/{username})
-	GetGetUserIDUsername(w http.ResponseWriter, r *http.Request, username string)
-
-	// (POST /login)
-	PostLogin(w http.ResponseWriter, r *http.Request)
-
-	// (POST /register)
-	PostRegister(w http.ResponseWriter, r *http.Request)
-
-	// (POST /sendFile/{userID})
-	PostSendFileUserID(w http.ResponseWriter, r *http.Request, userID int, fileName string)
-
-	// (PUT /updateData/{table}/{userID}/{entryID})
-	PutUpdateDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table string, userID int, entryID string)
-}
-
-// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
-
-type Unimplemented struct{}
-
 type Storage interface {
 	UserExists(ctx context.Context, username string) (bool, error)
 	AddUser(ctx context.Context, username string, hashedPassword string) error
 	GetPassword(ctx context.Context, username string) (string, error)
+	UpdatePassword(ctx context.Context, username string, hashedPassword string) error
 	GetUserID(ctx context.Context, username string) (int, error)
+	AddSRPUser(ctx context.Context, username string, verifier models.SRPVerifier) error
+	GetSRPVerifier(ctx context.Context, username string) (int, models.SRPVerifier, error)
+	AddSRPChallenge(ctx context.Context, challenge models.SRPChallenge) error
+	ConsumeSRPChallenge(ctx context.Context, id string) (models.SRPChallenge, error)
+	GetUsername(ctx context.Context, userID int) (string, error)
+	GetMFA(ctx context.Context, userID int) (models.MFA, error)
+	SetPendingTOTPSecret(ctx context.Context, userID int, secret string) error
+	EnableMFA(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error
+	UseTOTPStep(ctx context.Context, userID int, step int64) error
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) error
+	AddSession(ctx context.Context, userID int, session models.Session) error
+	ListSessions(ctx context.Context, userID int) ([]models.Session, error)
+	TouchSession(ctx context.Context, sessionID string, ip string) error
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+	RevokeSession(ctx context.Context, userID int, sessionID string) error
+	RevokeAllSessions(ctx context.Context, userID int) error
+	AddRefreshToken(ctx context.Context, tokenHash string, userID int, sessionID string, expiresAt time.Time) error
+	ConsumeRefreshToken(ctx context.Context, tokenHash string) (int, string, error)
+	RevokeRefreshToken(ctx context.Context, tokenHash string, userID int) error
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
 	AddData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string) error
-	UpdateData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string) error
+	UpdateData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string, baseVersion int64) (int64, error)
+	Sync(ctx context.Context, userID int, afterSeq int64, limit int) ([]models.Change, bool, error)
+	GetFileContent(ctx context.Context, userID int, entryID string) (models.FileContent, error)
+	SetFileContent(ctx context.Context, userID int, entryID string, size int64, digest string) error
+	LegacyFiles(ctx context.Context) ([]models.LegacyFile, error)
+	BlobStats(ctx context.Context) (models.BlobStats, error)
+	CacheStats() models.CacheStats
 	DeleteData(ctx context.Context, table string, user_id int, entry_id string) error
+	GetData(ctx context.Context, table string, userID int, entryID string) (map[string]string, error)
+	Batch(ctx context.Context, userID int, ops []models.BatchOperation, partial bool) ([]models.BatchResult, error)
 	GetAllData(ctx context.Context, table string, user_id int, last_sync time.Time, incl_del bool) ([]map[string]string, error)
 }
 
@@ -109,7 +75,39 @@ type Options interface {
 	// RunAddr returns the address to run the application.
 	RunAddr() string
 
+	// FileStoragePath returns the directory for uploaded files.
 	FileStoragePath() string
+
+	// MaxUploadSize returns the maximum size of an uploaded file in bytes.
+	MaxUploadSize() int64
+
+	// AdminUsers returns the usernames allowed to use the admin endpoints.
+	AdminUsers() []string
+}
+
+// Uploads represents an interface for resumable uploads.
+type Uploads interface {
+	Create(userID int, entryID string, length int64) (uploads.Session, error)
+	Get(userID int, id string) (uploads.Session, error)
+	Append(userID int, id string, offset int64, r io.Reader) (int64, error)
+	Finish(userID int, id string) (uploads.Result, error)
+	Remove(userID int, id string) error
+}
+
+// Events represents an interface for notifying the devices of a user about changed records.
+type Events interface {
+	// Notify publishes an event to the subscribers of the user.
+	Notify(ctx context.Context, userID int, event models.Event) error
+	// Subscribe returns a channel with the events of the user and a function that ends the subscription.
+	Subscribe(userID int) (<-chan models.Event, func())
+}
+
+// Reencryption represents an interface for the job that moves the stored data to the current master key.
+type Reencryption interface {
+	// Start starts a run and returns its initial progress. It fails if a run has not finished yet.
+	Start() (models.ReencryptionProgress, error)
+	// Progress returns the progress of the current or the latest run.
+	Progress() models.ReencryptionProgress
 }
 
 // Log represents an interface for logging functionality.
@@ -118,12 +116,51 @@ type Log interface {
 	Info(string, ...zapcore.Field)
 }
 
+// PasswordHasher represents an interface for deriving and checking stored password verifiers.
+type PasswordHasher interface {
+	// HashPassword derives the verifier that is stored for the password.
+	HashPassword(password string) (string, error)
+	// VerifyPassword checks the password against a stored verifier and reports
+	// whether the verifier should be replaced with a fresh one.
+	VerifyPassword(hashedPassword, password string) (ok bool, needsRehash bool)
+}
+
 // Authz represents an interface for user authorization functionality.
 type Authz interface {
-	// CreateJWTTokenForUser creates a JWT token for a specified user ID.
-	CreateJWTTokenForUser(userID string) string
-	IsBcryptHash(s string) bool
-	CompareHashAndPassword(hashedPassword, password string) bool
+	PasswordHasher
+
+	// CreateJWTTokenForUser creates a JWT token for a specified user ID and session.
+	CreateJWTTokenForUser(userID string, sessionID string) string
+	// CreateSessionID creates a new random session ID.
+	CreateSessionID() (string, error)
+	// CreateRefreshToken creates a new refresh token and the hash under which it is stored.
+	CreateRefreshToken() (token string, tokenHash string, expiresAt time.Time, err error)
+	// HashRefreshToken computes the hash under which a refresh token is stored.
+	HashRefreshToken(token string) string
+
+	// CreateTOTPSecret creates a new random base32 TOTP secret.
+	CreateTOTPSecret() (string, error)
+	// TOTPProvisioningURI builds the otpauth:// URI for authenticator apps.
+	TOTPProvisioningURI(account string, secret string) string
+	// ValidateTOTP checks a TOTP code and returns the time step it belongs to.
+	ValidateTOTP(secret string, code string) (int64, bool)
+	// CreateRecoveryCodes creates one-time recovery codes and the hashes under which they are stored.
+	CreateRecoveryCodes() (codes []string, codeHashes []string, err error)
+	// HashRecoveryCode computes the hash under which a recovery code is stored.
+	HashRecoveryCode(code string) string
+	// CreateMFAChallenge creates a short-lived token for a login that awaits the second factor.
+	CreateMFAChallenge(userID int, deviceID, deviceName string) (string, error)
+	// DecodeMFAChallenge validates a challenge token and returns its details.
+	DecodeMFAChallenge(token string) (models.MFAChallenge, error)
+
+	// CreateSRPChallenge creates the server side of an SRP login attempt for the client ephemeral A.
+	CreateSRPChallenge(username string, verifier models.SRPVerifier, publicA string) (models.SRPChallenge, error)
+	// ValidateSRPVerifier checks a salt and verifier that a client registers.
+	ValidateSRPVerifier(verifier models.SRPVerifier) error
+	// FakeSRPSalt returns a stable salt for unknown users.
+	FakeSRPSalt(username string) string
+	// VerifySRPProof checks the client proof of an SRP login attempt and returns the server proof.
+	VerifySRPProof(verifier models.SRPVerifier, challenge models.SRPChallenge, clientProof string) (string, bool)
 }
 
 // BaseController represents a basic controller for handling user requests.
@@ -133,6 +170,12 @@ type BaseController struct {
 	options Options
 	log     Log
 	authz   Authz
+	uploads Uploads
+	blobs   blobstore.BlobStore
+	legacy  legacyFiles
+	events  Events
+
+	reencryption Reencryption
 }
 
 // Example usage:
@@ -141,19 +184,59 @@ type BaseController struct {
 //	r.Mount("/", controller.Route())
 //	flagRunAddr := option.RunAddr()
 //	http.ListenAndServe(flagRunAddr, r)
-func NewBaseController(storage Storage, options Options, log Log, authz Authz) *BaseController {
+func NewBaseController(storage Storage, options Options, log Log, authz Authz, uploads Uploads, blobs blobstore.BlobStore, events Events,
+	reencryption Reencryption,
+) *BaseController {
 	instance := &BaseController{
 		storage: storage,
 		options: options,
 		log:     log,
 		authz:   authz,
+		uploads: uploads,
+		blobs:   blobs,
+		events:  events,
+
+		reencryption: reencryption,
 	}
 
 	return instance
 }
 
+// transferTimeout limits requests that transfer file content.
+const transferTimeout = 30 * time.Minute
+
+// fileNamePattern matches the names under which files are stored. Separators and
+// leading dots are excluded, so a name cannot leave the user directory.
+var fileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]{0,127}$`)
+
+// validFileName reports whether name can be used as a stored file name.
+func validFileName(name string) bool {
+	return fileNamePattern.MatchString(name)
+}
+
+// userFileDir returns the local directory of a user, where uploads are staged.
+func (h *BaseController) userFileDir(userID int) string {
+	return filepath.Join(h.options.FileStoragePath(), strconv.Itoa(userID))
+}
+
+// lookupSchema resolves the {table} path parameter against the vault schema registry.
+// It responds with '400 Bad Request' and returns false if the table is unknown.
+func lookupSchema(w http.ResponseWriter, table string) (schema.Schema, bool) {
+	s, err := schema.Lookup(table)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return schema.Schema{}, false
+	}
+
+	return s, true
+}
+
 // (POST /addData/{table}/{userID}/{entryID})
 func (h *BaseController) PostAddDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table string, userID int, entryID string) {
+	s, ok := lookupSchema(w, table)
+	if !ok {
+		return
+	}
 
 	// Parse and decode the request body into a new 'map[string]string' value
 	var requestBody map[string]string
@@ -163,31 +246,60 @@ func (h *BaseController) PostAddDataTableUserIDEntryID(w http.ResponseWriter, r
 		return
 	}
 
+	// Reject unknown columns and missing required fields
+	if err := s.ValidateAdd(requestBody); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Call the 'AddData' method with the userID, table, and data from the request body
 	err = h.storage.AddData(r.Context(), table, userID, entryID, requestBody)
+	if errors.Is(err, storage.ErrConflict) {
+		// The ID is taken by another record of the user
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// If everything goes well, respond with a status of '200 OK'
+	h.notify(r.Context(), userID, models.Event{Type: models.EventItemChanged, Table: string(s.Table), ID: entryID, Version: 1})
+
+	// If everything goes well, respond with a status of '200 OK' and the first version of the record
+	w.Header().Set("ETag", versionETag(1))
 	w.WriteHeader(http.StatusOK)
 }
 
 // (DELETE /deleteData/{table}/{userID}/{entryID})
 func (h *BaseController) DeleteDeleteDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table string, userID int, entryID string) {
+	s, ok := lookupSchema(w, table)
+	if !ok {
+		return
+	}
+
 	// Call the 'DeleteData' method with the userID, table, and entryID
 	err := h.storage.DeleteData(r.Context(), table, userID, entryID)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	h.notify(r.Context(), userID, models.Event{Type: models.EventItemDeleted, Table: string(s.Table), ID: entryID})
+
 	// If everything goes well, respond with a status of '200 OK'
 	w.WriteHeader(http.StatusOK)
 }
 
 func (h *BaseController) GetGetAllDataTableUserID(w http.ResponseWriter, r *http.Request, table string, userID int, lastSyncStr string) {
+	if _, ok := lookupSchema(w, table); !ok {
+		return
+	}
+
 	// Преобразуйте lastSync обратно в time.Time
 	lastSync, err := time.Parse(time.RFC3339, lastSyncStr)
 	if err != nil {
@@ -216,695 +328,1423 @@ func (h *BaseController) GetGetAllDataTableUserID(w http.ResponseWriter, r *http
 
 // (GET /getData/{table}/{userID}/{entryID})
 func (h *BaseController) GetGetDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table string, userID int, entryID string) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
-
-// (GET /getFile/{userID}/{entryID})
-func (h *BaseController) GetGetFileUserIDEntryID(w http.ResponseWriter, r *http.Request, userID int, entryID string) {
-
-	// Путь к файлу
-	filePath := filepath.Join(h.options.FileStoragePath(), entryID)
-	// Проверка существования файла
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		http.Error(w, "Файл не найден", http.StatusNotFound)
+	if _, ok := lookupSchema(w, table); !ok {
 		return
 	}
 
-	// Отправка файла
-	http.ServeFile(w, r, filePath)
-}
-
-// (GET /getPassword/{username})
-func (h *BaseController) GetGetPasswordUsername(w http.ResponseWriter, r *http.Request, username string) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
-
-// (GET /getUserID/{username})
-func (h *BaseController) GetGetUserIDUsername(w http.ResponseWriter, r *http.Request, username string) {
-	// Получаем UserID из базы данных
-	userID, err := h.storage.GetUserID(r.Context(), username)
+	record, err := h.storage.GetData(r.Context(), table, userID, entryID)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		// Если произошла ошибка, отправляем статус 500 и сообщение об ошибке
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// Преобразуем UserID в JSON
-	userIDJSON, err := json.Marshal(userID)
-	if err != nil {
-		// Если произошла ошибка, отправляем статус 500 и сообщение об ошибке
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	// The version of the record is its entity tag, the same one updates expect in If-Match
+	etag := `"` + record["version"] + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
-	// Устанавливаем заголовок Content-Type и отправляем UserID в ответе
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(userIDJSON)
+	writeJSON(w, record)
 }
 
-// (POST /login)
-func (h *BaseController) PostLogin(w http.ResponseWriter, r *http.Request) {
-	// Parse and decode the request body into a new 'PostLoginJSONRequestBody' value
-	var requestBody PostLoginJSONRequestBody
-	err := json.NewDecoder(r.Body).Decode(&requestBody)
-	if err != nil {
+// maxBatchOperations limits the number of operations in a batch, which all run in one transaction.
+const maxBatchOperations = 1000
+
+// (POST /batch/{userID})
+func (h *BaseController) PostBatchUserID(w http.ResponseWriter, r *http.Request, userID int) {
+	var requestBody PostBatchUserIDJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if len(requestBody.Operations) == 0 || len(requestBody.Operations) > maxBatchOperations {
+		http.Error(w, fmt.Sprintf("a batch must have 1 to %d operations", maxBatchOperations), http.StatusBadRequest)
+		return
+	}
 
-	ctx := r.Context()
-
-	// Попытка получить хешированный пароль пользователя из локальной базы данных
-	hashedPassword, err := h.storage.GetPassword(ctx, requestBody.Username)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+	results, err := h.storage.Batch(r.Context(), userID, requestBody.Operations, requestBody.Partial)
+	var batchErr *storage.BatchError
+	if err != nil && !errors.As(err, &batchErr) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	if h.authz.IsBcryptHash(requestBody.Password) {
-		if hashedPassword != requestBody.Password {
-			err := fmt.Errorf("Unauthorized")
-			http.Error(w, err.Error(), http.StatusUnauthorized)
-			return
-		}
-	} else {
-		// Сравнение хешированного пароля с хешем введенного пароля
-		if !h.authz.CompareHashAndPassword(hashedPassword, requestBody.Password) {
-			err := fmt.Errorf("Unauthorized")
-			http.Error(w, err.Error(), http.StatusUnauthorized)
-			return
+	for i := range results {
+		results[i].Status = batchStatus(results[i].Err)
+		if results[i].Err != nil {
+			results[i].Error = results[i].Err.Error()
 		}
 	}
 
-	userID, err := h.storage.GetUserID(ctx, requestBody.Username)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
-		return
+	// A rolled back batch gets the status of the operation that failed it
+	status := http.StatusOK
+	if batchErr != nil {
+		status = batchStatus(batchErr.Err)
+	} else {
+		h.notifyBatch(r.Context(), userID, requestBody.Operations, results)
 	}
 
-	// Create a new JWT for the authenticated user
-	token := h.authz.CreateJWTTokenForUser(strconv.Itoa(userID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(BatchResponse{Committed: batchErr == nil, Results: results})
+}
 
-	// Prepare the response
-	response := map[string]interface{}{
-		"userID": userID,
-		"token":  token,
+// notifyBatch notifies about the records changed by the successful operations of a committed batch.
+func (h *BaseController) notifyBatch(ctx context.Context, userID int, ops []models.BatchOperation, results []models.BatchResult) {
+	for i, result := range results {
+		if result.Err != nil || i >= len(ops) {
+			continue
+		}
+
+		event := models.Event{Type: models.EventItemChanged, Table: ops[i].Table, ID: ops[i].ID, Version: result.Version}
+		if ops[i].Op == models.BatchDelete {
+			event.Type = models.EventItemDeleted
+			event.Version = 0
+		}
+		if s, err := schema.Lookup(ops[i].Table); err == nil {
+			event.Table = string(s.Table)
+		}
+
+		h.notify(ctx, userID, event)
 	}
+}
 
-	// Convert the response to JSON
-	responseBytes, err := json.Marshal(response)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+// notify tells the devices of the user about a changed record. Failures are only logged,
+// since the change itself has been stored.
+func (h *BaseController) notify(ctx context.Context, userID int, event models.Event) {
+	if h.events == nil {
 		return
 	}
 
-	// Send the JWT and userID to the client in the response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write(responseBytes)
+	if err := h.events.Notify(ctx, userID, event); err != nil {
+		h.log.Info("cannot publish event: ", zap.Error(err))
+	}
 }
 
-// (POST /register)
-func (h *BaseController) PostRegister(w http.ResponseWriter, r *http.Request) {
-	// Parse and decode the request body into a new 'PostRegisterJSONBody' value
-	var requestBody PostRegisterJSONBody
-	err := json.NewDecoder(r.Body).Decode(&requestBody)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+// batchStatus returns the HTTP status matching the outcome of a batch operation.
+func batchStatus(err error) int {
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, storage.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, storage.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, storage.ErrInvalidOperation), errors.Is(err, schema.ErrUnknownTable),
+		errors.Is(err, schema.ErrUnknownColumn), errors.Is(err, schema.ErrMissingField), errors.Is(err, schema.ErrEmptyData):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// (GET /admin/stats)
+func (h *BaseController) GetAdminStats(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
 
-	// Call the 'AddUser' method with the username and password from the request body
-	err = h.storage.AddUser(r.Context(), requestBody.Username, requestBody.Password)
+	stats, err := h.storage.BlobStats(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// If everything goes well, respond with a status of '200 OK'
-	w.WriteHeader(http.StatusOK)
+	writeJSON(w, stats)
 }
 
-// (POST /sendFile/{userID})
-// (POST /sendFile/{userID})
-func (h *BaseController) PostSendFileUserID(w http.ResponseWriter, r *http.Request, userID int, fileName string) {
-	// Чтение файла из тела запроса
-	file, err := io.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, "Ошибка при чтении файла", http.StatusInternalServerError)
+// (GET /admin/cache-stats)
+func (h *BaseController) GetAdminCacheStats(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
-	defer r.Body.Close()
 
-	// Сохранение файла на сервере
-	path := filepath.Join(h.options.FileStoragePath(), fileName)
-	err = os.WriteFile(path, file, 0644)
-	if err != nil {
-		http.Error(w, "Ошибка при сохранении файла", http.StatusInternalServerError)
+	writeJSON(w, h.storage.CacheStats())
+}
+
+// (GET /admin/reencryption)
+func (h *BaseController) GetAdminReencryption(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if h.reencryption == nil {
+		http.Error(w, "encryption is disabled", http.StatusNotFound)
 		return
 	}
 
-	// Отправка ответа
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintf(w, "Файл успешно сохранен")
+	writeJSON(w, h.reencryption.Progress())
 }
 
-// (PUT /updateData/{table}/{userID}/{entryID})
-func (h *BaseController) PutUpdateDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table string, userID int, entryID string) {
-	// Parse and decode the request body into a new 'map[string]string' value
-	var requestBody map[string]string
-	err := json.NewDecoder(r.Body).Decode(&requestBody)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+// (POST /admin/reencryption)
+func (h *BaseController) PostAdminReencryption(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if h.reencryption == nil {
+		http.Error(w, "encryption is disabled", http.StatusNotFound)
 		return
 	}
 
-	// Call the 'UpdateData' method with the userID, table, entryID, and data from the request body
-	err = h.storage.UpdateData(r.Context(), table, userID, entryID, requestBody)
+	progress, err := h.reencryption.Start()
+	if errors.Is(err, storage.ErrReencryptionRunning) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// If everything goes well, respond with a status of 'OK'
-	w.WriteHeader(http.StatusOK)
-}
+	h.log.Info("re-encryption started", zap.String("keyID", progress.KeyID))
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(progress)
 }
 
-type MiddlewareFunc func(http.Handler) http.Handler
+// isAdmin reports whether the authenticated user is listed in the admin users option.
+func (h *BaseController) isAdmin(r *http.Request) bool {
+	var keyUserID models.Key = "userID"
+	userID, err := strconv.Atoi(fmt.Sprint(r.Context().Value(keyUserID)))
+	if err != nil {
+		return false
+	}
 
-// PostAddDataTableUserIDEntryID operation middleware
-func (siw *ServerInterfaceWrapper) PostAddDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	username, err := h.storage.GetUsername(r.Context(), userID)
+	if err != nil {
+		return false
+	}
 
-	var err error
+	for _, admin := range h.options.AdminUsers() {
+		if admin == username {
+			return true
+		}
+	}
 
-	// ------------- Path parameter "table" -------------
-	var table string
+	return false
+}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "table", chi.URLParam(r, "table"), &table, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "table", Err: err})
+// (GET /getFile/{userID}/{entryID})
+func (h *BaseController) GetGetFileUserIDEntryID(w http.ResponseWriter, r *http.Request, userID int, entryID string) {
+	if !validFileName(entryID) {
+		http.Error(w, "invalid file name", http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Path parameter "userID" -------------
-	var userID int
-
-	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// The file is served only for an existing entry of the user that has uploaded content
+	content, err := h.storage.GetFileContent(r.Context(), userID, entryID)
+	if errors.Is(err, storage.ErrNotFound) && h.legacy.contains(models.LegacyFile{UserID: userID, EntryID: entryID}) {
+		// Files uploaded before per-user directories have no digest until they are migrated
+		h.serveLegacyFile(w, r, entryID)
+		return
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, "Файл не найден", http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// ------------- Path parameter "entryID" -------------
-	var entryID string
-
-	err = runtime.BindStyledParameterWithOptions("simple", "entryID", chi.URLParam(r, "entryID"), &entryID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	file, err := h.blobs.Get(r.Context(), blobstore.ContentKey(content.SHA256))
+	if errors.Is(err, blobstore.ErrNotFound) {
+		// Files uploaded before content addressing stay under their entry until they are migrated
+		file, err = h.blobs.Get(r.Context(), blobstore.LegacyKey(userID, entryID))
+	}
+	if errors.Is(err, blobstore.ErrNotFound) {
+		http.Error(w, "Файл не найден", http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "entryID", Err: err})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	defer file.Close()
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostAddDataTableUserIDEntryID(w, r, table, userID, entryID)
-	}))
-
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+	// The digest identifies the content, so it is a strong validator for If-None-Match and If-Range.
+	// ServeContent answers conditional and range requests with the headers set here.
+	w.Header().Set("ETag", `"`+content.SHA256+`"`)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Cache-Control", "private, no-cache")
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	extendTransferDeadline(w)
+	http.ServeContent(w, r, entryID, content.UpdatedAt, file)
 }
 
-// DeleteDeleteDataTableUserIDEntryID operation middleware
-func (siw *ServerInterfaceWrapper) DeleteDeleteDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	var err error
-
-	// ------------- Path parameter "table" -------------
-	var table string
+// (GET /getPassword/{username})
+func (h *BaseController) GetGetPasswordUsername(w http.ResponseWriter, r *http.Request, username string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "table", chi.URLParam(r, "table"), &table, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+// (GET /getUserID/{username})
+func (h *BaseController) GetGetUserIDUsername(w http.ResponseWriter, r *http.Request, username string) {
+	// Получаем UserID из базы данных
+	userID, err := h.storage.GetUserID(r.Context(), username)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "table", Err: err})
+		// Если произошла ошибка, отправляем статус 500 и сообщение об ошибке
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// ------------- Path parameter "userID" -------------
-	var userID int
-
-	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// Преобразуем UserID в JSON
+	userIDJSON, err := json.Marshal(userID)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		// Если произошла ошибка, отправляем статус 500 и сообщение об ошибке
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// ------------- Path parameter "entryID" -------------
-	var entryID string
+	// Устанавливаем заголовок Content-Type и отправляем UserID в ответе
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(userIDJSON)
+}
+
+// errInvalidCredentials is the answer to every failed password login, so it does not
+// reveal whether the username is registered.
+const errInvalidCredentials = "invalid credentials"
 
-	err = runtime.BindStyledParameterWithOptions("simple", "entryID", chi.URLParam(r, "entryID"), &entryID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+// (POST /login)
+func (h *BaseController) PostLogin(w http.ResponseWriter, r *http.Request) {
+	// Parse and decode the request body into a new 'PostLoginJSONRequestBody' value
+	var requestBody PostLoginJSONRequestBody
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "entryID", Err: err})
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteDeleteDataTableUserIDEntryID(w, r, table, userID, entryID)
-	}))
-
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
-
-	handler.ServeHTTP(w, r.WithContext(ctx))
-}
-
-// GetGetAllDataTableUserID operation middleware
-func (siw *ServerInterfaceWrapper) GetGetAllDataTableUserID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	var err error
+	// Попытка получить хешированный пароль пользователя из локальной базы данных
+	hashedPassword, err := h.storage.GetPassword(ctx, requestBody.Username)
+	if err != nil {
+		h.log.Info("Error occurred getting password", zap.Error(err))
 
-	// ------------- Path parameter "table" -------------
-	var table string
+		// Hash the password anyway, so the response time does not reveal registered usernames
+		_, _ = h.authz.HashPassword(requestBody.Password)
+		http.Error(w, errInvalidCredentials, http.StatusUnauthorized)
+		return
+	}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "table", chi.URLParam(r, "table"), &table, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "table", Err: err})
+	// Сравнение сохраненного хеша с введенным паролем
+	ok, needsRehash := h.authz.VerifyPassword(hashedPassword, requestBody.Password)
+	if !ok {
+		http.Error(w, errInvalidCredentials, http.StatusUnauthorized)
 		return
 	}
 
-	// ------------- Path parameter "userID" -------------
-	var userID int
+	// Upgrade verifiers made by an outdated algorithm or with outdated parameters
+	if needsRehash {
+		h.rehashPassword(ctx, requestBody.Username, requestBody.Password)
+	}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	userID, err := h.storage.GetUserID(ctx, requestBody.Username)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		h.log.Info("Error occurred getting user ID", zap.Error(err))
+		http.Error(w, errInvalidCredentials, http.StatusUnauthorized)
 		return
 	}
 
-	// ------------- Path parameter "lastSyncStr" -------------
-	var lastSyncStr string
+	h.completeLogin(w, r, userID, requestBody.DeviceID, requestBody.DeviceName, nil)
+}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "lastSyncStr", chi.URLParam(r, "lastSyncStr"), &lastSyncStr, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: false})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "lastSyncStr", Err: err})
+// completeLogin finishes the first factor of a login. Users with two-factor authentication
+// get a challenge with the extra fields, all others get a new session.
+func (h *BaseController) completeLogin(w http.ResponseWriter, r *http.Request, userID int, deviceID, deviceName string, extra map[string]interface{}) {
+	mfa, err := h.storage.GetMFA(r.Context(), userID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetGetAllDataTableUserID(w, r, table, userID, lastSyncStr)
-	}))
+	if !mfa.Enabled {
+		h.startSession(w, r, userID, deviceID, deviceName, extra)
+		return
+	}
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
+	challengeToken, err := h.authz.CreateMFAChallenge(userID, deviceID, deviceName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	response := map[string]interface{}{
+		"mfa_required":   true,
+		"challengeToken": challengeToken,
+	}
+	for key, value := range extra {
+		response[key] = value
+	}
+
+	writeJSON(w, response)
 }
 
-// GetGetDataTableUserIDEntryID operation middleware
-func (siw *ServerInterfaceWrapper) GetGetDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request) {
+// (POST /login/mfa)
+func (h *BaseController) PostLoginMfa(w http.ResponseWriter, r *http.Request) {
+	// Parse and decode the request body into a new 'PostLoginMfaJSONRequestBody' value
+	var requestBody PostLoginMfaJSONRequestBody
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.ChallengeToken == "" || (requestBody.Code == "" && requestBody.RecoveryCode == "") {
+		http.Error(w, "challengeToken and code or recoveryCode must be specified", http.StatusBadRequest)
+		return
+	}
+
 	ctx := r.Context()
 
-	var err error
+	challenge, err := h.authz.DecodeMFAChallenge(requestBody.ChallengeToken)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Each challenge allows a single attempt, so codes can not be guessed with one password check
+	revoked, err := h.storage.IsTokenRevoked(ctx, challenge.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if revoked {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err = h.storage.RevokeToken(ctx, challenge.ID, challenge.ExpiresAt)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	// ------------- Path parameter "table" -------------
-	var table string
+	mfa, err := h.storage.GetMFA(ctx, challenge.UserID)
+	if err != nil || !mfa.Enabled {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if requestBody.Code != "" {
+		step, ok := h.authz.ValidateTOTP(mfa.Secret, requestBody.Code)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "table", chi.URLParam(r, "table"), &table, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+		// A code can not be used twice
+		err = h.storage.UseTOTPStep(ctx, challenge.UserID, step)
+	} else {
+		err = h.storage.UseRecoveryCode(ctx, challenge.UserID, h.authz.HashRecoveryCode(requestBody.RecoveryCode))
+	}
+	if errors.Is(err, storage.ErrConflict) || errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "table", Err: err})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// ------------- Path parameter "userID" -------------
-	var userID int
+	h.startSession(w, r, challenge.UserID, challenge.DeviceID, challenge.DeviceName, nil)
+}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+// useSecondFactor checks a one-time code of the active authenticator or, if the code
+// is empty, a recovery code, and uses it up. It reports false for a wrong or used code.
+func (h *BaseController) useSecondFactor(ctx context.Context, userID int, mfa models.MFA, code, recoveryCode string) (bool, error) {
+	var err error
+	switch {
+	case code != "":
+		step, ok := h.authz.ValidateTOTP(mfa.Secret, code)
+		if !ok {
+			return false, nil
+		}
+
+		// A code can not be used twice
+		err = h.storage.UseTOTPStep(ctx, userID, step)
+	case recoveryCode != "":
+		err = h.storage.UseRecoveryCode(ctx, userID, h.authz.HashRecoveryCode(recoveryCode))
+	default:
+		return false, nil
+	}
+	if errors.Is(err, storage.ErrConflict) || errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		return false, err
+	}
+
+	return true, nil
+}
+
+// startSession registers a session for the device of an authenticated user and issues the tokens
+// together with the extra fields. Clients that do not send a device ID get a new one.
+func (h *BaseController) startSession(w http.ResponseWriter, r *http.Request, userID int, deviceID, deviceName string, extra map[string]interface{}) {
+	sessionID, err := h.authz.CreateSessionID()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// ------------- Path parameter "entryID" -------------
-	var entryID string
+	if deviceID == "" {
+		deviceID, err = h.authz.CreateSessionID()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "entryID", chi.URLParam(r, "entryID"), &entryID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = h.storage.AddSession(r.Context(), userID, models.Session{
+		ID:         sessionID,
+		DeviceID:   deviceID,
+		DeviceName: deviceName,
+		IP:         clientIP(r),
+	})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "entryID", Err: err})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetGetDataTableUserIDEntryID(w, r, table, userID, entryID)
-	}))
+	response := map[string]interface{}{"deviceID": deviceID}
+	for key, value := range extra {
+		response[key] = value
+	}
+
+	h.issueTokens(w, r, userID, sessionID, response)
+}
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
+// writeJSON sends the value to the client as a JSON response.
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
 }
 
-// GetGetFileUserIDEntryID operation middleware
-func (siw *ServerInterfaceWrapper) GetGetFileUserIDEntryID(w http.ResponseWriter, r *http.Request) {
+// (POST /mfa/{userID}/enroll)
+func (h *BaseController) PostMfaUserIDEnroll(w http.ResponseWriter, r *http.Request, userID int) {
+	// The code of the current authenticator in the body is needed only to replace it
+	var requestBody PostMfaUserIDEnrollJSONRequestBody
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	ctx := r.Context()
 
-	var err error
+	mfa, err := h.storage.GetMFA(ctx, userID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// An access token alone can not replace the second factor, or a stolen token would take it over
+	if mfa.Enabled {
+		ok, err := h.useSecondFactor(ctx, userID, mfa, requestBody.Code, requestBody.RecoveryCode)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			http.Error(w, "a valid code of the current authenticator or a recovery code is required", http.StatusForbidden)
+			return
+		}
+	}
+
+	username, err := h.storage.GetUsername(ctx, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	secret, err := h.authz.CreateTOTPSecret()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	// ------------- Path parameter "userID" -------------
-	var userID int
+	// The secret becomes active only after the user confirms it with a code
+	err = h.storage.SetPendingTOTPSecret(ctx, userID, secret)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	writeJSON(w, map[string]string{
+		"secret": secret,
+		"uri":    h.authz.TOTPProvisioningURI(username, secret),
+	})
+}
+
+// (POST /mfa/{userID}/verify)
+func (h *BaseController) PostMfaUserIDVerify(w http.ResponseWriter, r *http.Request, userID int) {
+	// Parse and decode the request body into a new 'PostMfaUserIDVerifyJSONRequestBody' value
+	var requestBody PostMfaUserIDVerifyJSONRequestBody
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Path parameter "entryID" -------------
-	var entryID string
+	ctx := r.Context()
 
-	err = runtime.BindStyledParameterWithOptions("simple", "entryID", chi.URLParam(r, "entryID"), &entryID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	mfa, err := h.storage.GetMFA(ctx, userID)
+	if errors.Is(err, storage.ErrNotFound) || (err == nil && mfa.PendingSecret == "") {
+		http.Error(w, "no pending enrollment", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "entryID", Err: err})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetGetFileUserIDEntryID(w, r, userID, entryID)
-	}))
+	step, ok := h.authz.ValidateTOTP(mfa.PendingSecret, requestBody.Code)
+	if !ok {
+		http.Error(w, "invalid code", http.StatusBadRequest)
+		return
+	}
+
+	codes, codeHashes, err := h.authz.CreateRecoveryCodes()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
+	err = h.storage.EnableMFA(ctx, userID, step, codeHashes)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, "no pending enrollment", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	// Recovery codes are shown only once
+	writeJSON(w, map[string]interface{}{
+		"recoveryCodes": codes,
+	})
 }
 
-// GetGetPasswordUsername operation middleware
-func (siw *ServerInterfaceWrapper) GetGetPasswordUsername(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// clientIP returns the IP address of the client that sent the request.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
 
-	var err error
+	return host
+}
 
-	// ------------- Path parameter "username" -------------
-	var username string
+// issueTokens creates an access token and a refresh token for the user session
+// and sends them to the client together with the user ID, session ID and extra fields.
+func (h *BaseController) issueTokens(w http.ResponseWriter, r *http.Request, userID int, sessionID string, extra map[string]interface{}) {
+	// Create a new JWT for the authenticated user
+	token := h.authz.CreateJWTTokenForUser(strconv.Itoa(userID), sessionID)
+	if token == "" {
+		http.Error(w, "failed to create token", http.StatusInternalServerError)
+		return
+	}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// Create a new refresh token and store only its hash
+	refreshToken, refreshTokenHash, expiresAt, err := h.authz.CreateRefreshToken()
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetGetPasswordUsername(w, r, username)
-	}))
+	err = h.storage.AddRefreshToken(r.Context(), refreshTokenHash, userID, sessionID, expiresAt)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
+	// Prepare the response
+	response := map[string]interface{}{
+		"userID":       userID,
+		"sessionID":    sessionID,
+		"token":        token,
+		"refreshToken": refreshToken,
+	}
+	for key, value := range extra {
+		response[key] = value
 	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	// Convert the response to JSON
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Send the JWT and userID to the client in the response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseBytes)
 }
 
-// GetGetUserIDUsername operation middleware
-func (siw *ServerInterfaceWrapper) GetGetUserIDUsername(w http.ResponseWriter, r *http.Request) {
+// (POST /logout)
+func (h *BaseController) PostLogout(w http.ResponseWriter, r *http.Request) {
+	// The refresh token in the body is optional
+	var requestBody PostLogoutJSONRequestBody
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var (
+		keyUserID         models.Key = "userID"
+		keySessionID      models.Key = "sessionID"
+		keyTokenID        models.Key = "tokenID"
+		keyTokenExpiresAt models.Key = "tokenExpiresAt"
+	)
 	ctx := r.Context()
+	userIDStr, _ := ctx.Value(keyUserID).(string)
+	sessionID, _ := ctx.Value(keySessionID).(string)
+	tokenID, _ := ctx.Value(keyTokenID).(string)
+	tokenExpiresAt, _ := ctx.Value(keyTokenExpiresAt).(time.Time)
+
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil || tokenID == "" {
+		http.Error(w, "Authorization error", http.StatusUnauthorized)
+		return
+	}
 
-	var err error
+	// Revoke the access token that authenticated this request
+	err = h.storage.RevokeToken(ctx, tokenID, tokenExpiresAt)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	// ------------- Path parameter "username" -------------
-	var username string
+	// End the session of this device, which also invalidates its refresh tokens
+	if sessionID != "" {
+		err = h.storage.RevokeSession(ctx, userID, sessionID)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "username", chi.URLParam(r, "username"), &username, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// Revoke the refresh token of this client, if it was provided
+	if requestBody.RefreshToken != "" {
+		err = h.storage.RevokeRefreshToken(ctx, h.authz.HashRefreshToken(requestBody.RefreshToken), userID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// (POST /token/refresh)
+func (h *BaseController) PostTokenRefresh(w http.ResponseWriter, r *http.Request) {
+	// Parse and decode the request body into a new 'PostTokenRefreshJSONRequestBody' value
+	var requestBody PostTokenRefreshJSONRequestBody
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.RefreshToken == "" {
+		http.Error(w, "refreshToken must be specified", http.StatusBadRequest)
+		return
+	}
+
+	// Each refresh token can be used only once, a new one is issued instead
+	userID, sessionID, err := h.storage.ConsumeRefreshToken(r.Context(), h.authz.HashRefreshToken(requestBody.RefreshToken))
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "username", Err: err})
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetGetUserIDUsername(w, r, username)
-	}))
+	// A refresh means the device is still in use
+	if err := h.storage.TouchSession(r.Context(), sessionID, clientIP(r)); err != nil {
+		h.log.Info("Error occurred updating session", zap.Error(err))
+	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	h.issueTokens(w, r, userID, sessionID, nil)
 }
 
-// PostLogin operation middleware
-func (siw *ServerInterfaceWrapper) PostLogin(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// (GET /sessions/{userID})
+func (h *BaseController) GetSessionsUserID(w http.ResponseWriter, r *http.Request, userID int) {
+	sessions, err := h.storage.ListSessions(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostLogin(w, r)
-	}))
+	// Mark the session that made this request
+	var keySessionID models.Key = "sessionID"
+	currentSessionID, _ := r.Context().Value(keySessionID).(string)
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	jsonData, err := json.Marshal(sessions)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
 }
 
-// PostRegister operation middleware
-func (siw *ServerInterfaceWrapper) PostRegister(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// (DELETE /sessions/{userID})
+func (h *BaseController) DeleteSessionsUserID(w http.ResponseWriter, r *http.Request, userID int) {
+	err := h.storage.RevokeAllSessions(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostRegister(w, r)
-	}))
+	w.WriteHeader(http.StatusOK)
+}
+
+// (DELETE /sessions/{userID}/{sessionID})
+func (h *BaseController) DeleteSessionsUserIDSessionID(w http.ResponseWriter, r *http.Request, userID int, sessionID string) {
+	err := h.storage.RevokeSession(r.Context(), userID, sessionID)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	w.WriteHeader(http.StatusOK)
 }
 
-// PostSendFileUserID operation middleware
-func (siw *ServerInterfaceWrapper) PostSendFileUserID(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// rehashPassword replaces the stored verifier of the user with a fresh one.
+// Failures are only logged, since the user has already been authenticated.
+func (h *BaseController) rehashPassword(ctx context.Context, username, password string) {
+	hashedPassword, err := h.authz.HashPassword(password)
+	if err != nil {
+		h.log.Info("Error occurred rehashing password", zap.Error(err))
+		return
+	}
 
-	var err error
+	if err := h.storage.UpdatePassword(ctx, username, hashedPassword); err != nil {
+		h.log.Info("Error occurred updating password hash", zap.Error(err))
+	}
+}
+
+// (POST /register)
+func (h *BaseController) PostRegister(w http.ResponseWriter, r *http.Request) {
+	// Parse and decode the request body into a new 'PostRegisterJSONBody' value
+	var requestBody PostRegisterJSONBody
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	// ------------- Path parameter "userID" -------------
-	var userID int
+	if requestBody.Username == "" || requestBody.Password == "" {
+		http.Error(w, "username and password must be specified", http.StatusBadRequest)
+		return
+	}
+
+	// The server always derives the stored verifier itself
+	hashedPassword, err := h.authz.HashPassword(requestBody.Password)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// Call the 'AddUser' method with the username and password hash
+	err = h.storage.AddUser(r.Context(), requestBody.Username, hashedPassword)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	// ------------- Path parameter "fileName" -------------
-	var fileName string
+	// If everything goes well, respond with a status of '200 OK'
+	w.WriteHeader(http.StatusOK)
+}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "fileName", chi.URLParam(r, "fileName"), &fileName, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+// Page sizes of GetSyncUserID.
+const (
+	defaultSyncLimit = 100
+	maxSyncLimit     = 1000
+)
+
+// (GET /sync/{userID})
+func (h *BaseController) GetSyncUserID(w http.ResponseWriter, r *http.Request, userID int, params GetSyncUserIDParams) {
+	var afterSeq int64
+	if params.Cursor != nil && *params.Cursor != "" {
+		seq, err := storage.DecodeSyncCursor(*params.Cursor)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		afterSeq = seq
+	}
+
+	limit := defaultSyncLimit
+	if params.Limit != nil {
+		if *params.Limit <= 0 {
+			http.Error(w, "limit must be positive", http.StatusBadRequest)
+			return
+		}
+		limit = min(*params.Limit, maxSyncLimit)
+	}
+
+	changes, hasMore, err := h.storage.Sync(r.Context(), userID, afterSeq, limit)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "fileName", Err: err})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The next call continues after the last returned change
+	if len(changes) > 0 {
+		afterSeq = changes[len(changes)-1].Seq
+	} else {
+		changes = []models.Change{}
+	}
+
+	writeJSON(w, SyncResponse{
+		Changes: changes,
+		Cursor:  storage.EncodeSyncCursor(afterSeq),
+		HasMore: hasMore,
+	})
+}
+
+// (POST /srp/register)
+func (h *BaseController) PostSrpRegister(w http.ResponseWriter, r *http.Request) {
+	// Parse and decode the request body into a new 'PostSrpRegisterJSONRequestBody' value
+	var requestBody PostSrpRegisterJSONRequestBody
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.Username == "" || requestBody.Salt == "" || requestBody.Verifier == "" {
+		http.Error(w, "username, salt and verifier must be specified", http.StatusBadRequest)
+		return
+	}
+
+	verifier := models.SRPVerifier{
+		Salt:     requestBody.Salt,
+		Verifier: requestBody.Verifier,
+	}
+	if err := h.authz.ValidateSRPVerifier(verifier); err != nil {
+		http.Error(w, "salt must be hex and verifier a hex number below the SRP group prime", http.StatusBadRequest)
 		return
 	}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostSendFileUserID(w, r, userID, fileName)
-	}))
+	ctx := r.Context()
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
+	exists, err := h.storage.UserExists(ctx, requestBody.Username)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if exists {
+		http.Error(w, "user already exists", http.StatusConflict)
+		return
 	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	// The server stores only the verifier, the password never leaves the client
+	err = h.storage.AddSRPUser(ctx, requestBody.Username, verifier)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
 }
 
-// PutUpdateDataTableUserIDEntryID operation middleware
-func (siw *ServerInterfaceWrapper) PutUpdateDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request) {
+// (POST /srp/login/init)
+func (h *BaseController) PostSrpLoginInit(w http.ResponseWriter, r *http.Request) {
+	// Parse and decode the request body into a new 'PostSrpLoginInitJSONRequestBody' value
+	var requestBody PostSrpLoginInitJSONRequestBody
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.Username == "" || requestBody.PublicA == "" {
+		http.Error(w, "username and publicA must be specified", http.StatusBadRequest)
+		return
+	}
+
 	ctx := r.Context()
 
-	var err error
+	_, verifier, err := h.storage.GetSRPVerifier(ctx, requestBody.Username)
+	known := err == nil
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-	// ------------- Path parameter "table" -------------
-	var table string
+	// Unknown users get a plausible answer and a decoy challenge that can not succeed,
+	// so neither the response nor its timing reveals registered usernames
+	if !known {
+		salt := h.authz.FakeSRPSalt(requestBody.Username)
+		verifier = models.SRPVerifier{Salt: salt, Verifier: salt}
+	}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "table", chi.URLParam(r, "table"), &table, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	challenge, err := h.authz.CreateSRPChallenge(requestBody.Username, verifier, requestBody.PublicA)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "table", Err: err})
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Path parameter "userID" -------------
-	var userID int
+	err = h.storage.AddSRPChallenge(ctx, challenge)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, map[string]string{
+		"challengeID": challenge.ID,
+		"salt":        verifier.Salt,
+		"publicB":     challenge.B,
+	})
+}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "userID", chi.URLParam(r, "userID"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+// (POST /srp/login/verify)
+func (h *BaseController) PostSrpLoginVerify(w http.ResponseWriter, r *http.Request) {
+	// Parse and decode the request body into a new 'PostSrpLoginVerifyJSONRequestBody' value
+	var requestBody PostSrpLoginVerifyJSONRequestBody
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userID", Err: err})
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Path parameter "entryID" -------------
-	var entryID string
+	if requestBody.ChallengeID == "" || requestBody.ClientProof == "" {
+		http.Error(w, "challengeID and clientProof must be specified", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
 
-	err = runtime.BindStyledParameterWithOptions("simple", "entryID", chi.URLParam(r, "entryID"), &entryID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// Each login attempt can be answered only once
+	challenge, err := h.storage.ConsumeSRPChallenge(ctx, requestBody.ChallengeID)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "entryID", Err: err})
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PutUpdateDataTableUserIDEntryID(w, r, table, userID, entryID)
-	}))
+	userID, verifier, err := h.storage.GetSRPVerifier(ctx, challenge.Username)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
+	serverProof, ok := h.authz.VerifySRPProof(verifier, challenge, requestBody.ClientProof)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
 	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
+	// The server proof lets the client check that it talks to the server holding its verifier
+	h.completeLogin(w, r, userID, requestBody.DeviceID, requestBody.DeviceName,
+		map[string]interface{}{"serverProof": serverProof})
 }
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
-}
+// eventsHeartbeat is how often an idle event stream gets a comment, so that proxies keep it open.
+// The session of the stream is checked at the same time.
+var eventsHeartbeat = 30 * time.Second
 
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
-}
+// (GET /events/{userID})
+func (h *BaseController) GetEventsUserID(w http.ResponseWriter, r *http.Request, userID int) {
+	if h.events == nil {
+		http.Error(w, "events are not available", http.StatusNotImplemented)
+		return
+	}
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
-}
+	var (
+		keySessionID      models.Key = "sessionID"
+		keyTokenExpiresAt models.Key = "tokenExpiresAt"
+	)
+	ctx := r.Context()
+	sessionID, _ := ctx.Value(keySessionID).(string)
+	tokenExpiresAt, _ := ctx.Value(keyTokenExpiresAt).(time.Time)
+
+	// The stream stays open much longer than the server write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	// The stream ends with the access token, the client reconnects with a fresh one
+	var expired <-chan time.Time
+	if !tokenExpiresAt.IsZero() {
+		timer := time.NewTimer(time.Until(tokenExpiresAt))
+		defer timer.Stop()
+		expired = timer.C
+	}
 
-type UnmarshalingParamError struct {
-	ParamName string
-	Err       error
-}
+	events, cancel := h.events.Subscribe(userID)
+	defer cancel()
 
-func (e *UnmarshalingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
-}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
 
-func (e *UnmarshalingParamError) Unwrap() error {
-	return e.Err
-}
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
 
-type RequiredParamError struct {
-	ParamName string
-}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-expired:
+			return
+		case event, ok := <-events:
+			// A client that fell behind is disconnected and catches up with a sync
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		case <-heartbeat.C:
+			// A revoked session ends the stream
+			if sessionID != "" {
+				active, err := h.storage.IsSessionActive(ctx, sessionID)
+				if err != nil || !active {
+					return
+				}
+			}
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
 
-func (e *RequiredParamError) Error() string {
-	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
 }
 
-type RequiredHeaderError struct {
-	ParamName string
-	Err       error
-}
+// (POST /sendFile/{userID}/{fileName})
+func (h *BaseController) PostSendFileUserID(w http.ResponseWriter, r *http.Request, userID int, fileName string) {
+	// The file name is the ID of the FilesData entry that describes the file
+	if !validFileName(fileName) {
+		http.Error(w, "invalid file name", http.StatusBadRequest)
+		return
+	}
 
-func (e *RequiredHeaderError) Error() string {
-	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
-}
+	dir := h.userFileDir(userID)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		h.log.Info("cannot create file directory: ", zap.Error(err))
+		http.Error(w, "Ошибка при сохранении файла", http.StatusInternalServerError)
+		return
+	}
 
-func (e *RequiredHeaderError) Unwrap() error {
-	return e.Err
-}
+	extendTransferDeadline(w)
+
+	// The body is streamed into a temporary file next to the target, so the rename below is atomic
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		h.log.Info("cannot create temporary file: ", zap.Error(err))
+		http.Error(w, "Ошибка при сохранении файла", http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	body := http.MaxBytesReader(w, r.Body, h.options.MaxUploadSize())
+	size, err := io.Copy(io.MultiWriter(tmp, hash), body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "file is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Ошибка при чтении файла", http.StatusBadRequest)
+		return
+	}
 
-type InvalidParamFormatError struct {
-	ParamName string
-	Err       error
+	if err := tmp.Sync(); err != nil {
+		http.Error(w, "Ошибка при сохранении файла", http.StatusInternalServerError)
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		http.Error(w, "Ошибка при сохранении файла", http.StatusInternalServerError)
+		return
+	}
+
+	content := FileContentResponse{Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}
+	if !h.commitFile(w, r, userID, fileName, tmp.Name(), content) {
+		return
+	}
+
+	writeJSON(w, content)
 }
 
-func (e *InvalidParamFormatError) Error() string {
-	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+// commitFile makes sure that the blob store holds the uploaded content and records it for
+// a FilesData entry. Blobs are addressed by digest, so identical content is stored only once.
+// On failure it responds with the error and returns false.
+func (h *BaseController) commitFile(w http.ResponseWriter, r *http.Request, userID int, entryID string, path string, content FileContentResponse) bool {
+	err := blobstore.StoreContent(r.Context(), h.blobs, content.SHA256, path, content.Size, func() error {
+		return h.storage.SetFileContent(r.Context(), userID, entryID, content.Size, content.SHA256)
+	})
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return false
+	}
+	if err != nil {
+		h.log.Info("cannot store uploaded file: ", zap.Error(err))
+		http.Error(w, "Ошибка при сохранении файла", http.StatusInternalServerError)
+		return false
+	}
+
+	// The other devices learn about the new content once the blob is available
+	h.notify(r.Context(), userID, models.Event{Type: models.EventItemChanged, Table: string(schema.FilesData), ID: entryID})
+	return true
 }
 
-func (e *InvalidParamFormatError) Unwrap() error {
-	return e.Err
+// extendTransferDeadline lifts the server write timeout for a request that transfers
+// file content, since such requests may take much longer than ordinary API calls.
+func extendTransferDeadline(w http.ResponseWriter) {
+	rc := http.NewResponseController(w)
+	deadline := time.Now().Add(transferTimeout)
+	_ = rc.SetReadDeadline(deadline)
+	_ = rc.SetWriteDeadline(deadline)
 }
 
-type TooManyValuesForParamError struct {
-	ParamName string
-	Count     int
+// (POST /uploads/{userID})
+func (h *BaseController) PostUploadsUserID(w http.ResponseWriter, r *http.Request, userID int) {
+	var requestBody PostUploadsUserIDJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The upload completes the FilesData entry with this ID
+	if !validFileName(requestBody.EntryID) || requestBody.Length < 0 {
+		http.Error(w, "invalid upload", http.StatusBadRequest)
+		return
+	}
+	if requestBody.Length > h.options.MaxUploadSize() {
+		http.Error(w, "file is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	session, err := h.uploads.Create(userID, requestBody.EntryID, requestBody.Length)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/uploads/%d/%s", userID, session.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(session)
 }
 
-func (e *TooManyValuesForParamError) Error() string {
-	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+// (HEAD /uploads/{userID}/{uploadID})
+func (h *BaseController) HeadUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request, userID int, uploadID string) {
+	session, err := h.uploads.Get(userID, uploadID)
+	if !h.checkUploadError(w, err) {
+		return
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(session.Length, 10))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
 }
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{})
+// (PATCH /uploads/{userID}/{uploadID})
+func (h *BaseController) PatchUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request, userID int, uploadID string, params PatchUploadsUserIDUploadIDParams) {
+	// The client states where the chunk starts, so a repeated chunk cannot be appended twice
+	if params.UploadOffset < 0 {
+		http.Error(w, "invalid Upload-Offset header", http.StatusBadRequest)
+		return
+	}
+
+	extendTransferDeadline(w)
+
+	offset, err := h.uploads.Append(userID, uploadID, params.UploadOffset, r.Body)
+	if !errors.Is(err, uploads.ErrNotFound) && !errors.Is(err, uploads.ErrBusy) {
+		w.Header().Set("Upload-Offset", strconv.FormatInt(offset, 10))
+	}
+	if !h.checkUploadError(w, err) {
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
-type ChiServerOptions struct {
-	BaseURL          string
-	BaseRouter       chi.Router
-	Middlewares      []MiddlewareFunc
-	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+// (POST /uploads/{userID}/{uploadID}/finish)
+func (h *BaseController) PostUploadsUserIDUploadIDFinish(w http.ResponseWriter, r *http.Request, userID int, uploadID string) {
+	session, err := h.uploads.Get(userID, uploadID)
+	if !h.checkUploadError(w, err) {
+		return
+	}
+
+	result, err := h.uploads.Finish(userID, uploadID)
+	if !h.checkUploadError(w, err) {
+		return
+	}
+
+	// The upload is kept if the entry is missing, so it can be finished once the entry exists
+	content := FileContentResponse{Size: result.Size, SHA256: result.SHA256}
+	if !h.commitFile(w, r, userID, session.EntryID, result.Path, content) {
+		return
+	}
+
+	if err := h.uploads.Remove(userID, uploadID); err != nil {
+		h.log.Info("cannot remove finished upload: ", zap.Error(err))
+	}
+
+	writeJSON(w, content)
 }
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
-func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{
-		BaseRouter: r,
-	})
+// (DELETE /uploads/{userID}/{uploadID})
+func (h *BaseController) DeleteUploadsUserIDUploadID(w http.ResponseWriter, r *http.Request, userID int, uploadID string) {
+	if !h.checkUploadError(w, h.uploads.Remove(userID, uploadID)) {
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{
-		BaseURL:    baseURL,
-		BaseRouter: r,
-	})
+// checkUploadError responds with the status that matches an upload error.
+// It returns true if there is no error.
+func (h *BaseController) checkUploadError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, uploads.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, uploads.ErrOffsetMismatch), errors.Is(err, uploads.ErrBusy), errors.Is(err, uploads.ErrIncomplete):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, uploads.ErrTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	default:
+		h.log.Info("upload failed: ", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+
+	return false
 }
 
-// HandlerWithOptions creates http.Handler with additional options
-func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
-	r := options.BaseRouter
+// (PUT /updateData/{table}/{userID}/{entryID})
+func (h *BaseController) PutUpdateDataTableUserIDEntryID(w http.ResponseWriter, r *http.Request, table string, userID int, entryID string) {
+	s, ok := lookupSchema(w, table)
+	if !ok {
+		return
+	}
 
-	if r == nil {
-		r = chi.NewRouter()
+	// Parse and decode the request body into a new 'map[string]string' value
+	var requestBody map[string]string
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	if options.ErrorHandlerFunc == nil {
-		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+
+	// The version the client has based its changes on comes in If-Match or in the body
+	baseVersion, err := parseBaseVersion(r, requestBody)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	delete(requestBody, baseVersionField)
+
+	// Reject unknown columns
+	if err := s.ValidateUpdate(requestBody); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Call the 'UpdateData' method with the userID, table, entryID, and data from the request body
+	version, err := h.storage.UpdateData(r.Context(), table, userID, entryID, requestBody, baseVersion)
+
+	// A stale update gets the current server copy, so the client can merge the changes
+	var conflict *storage.ConflictError
+	if errors.As(err, &conflict) {
+		w.Header().Set("ETag", `"`+conflict.Current["version"]+`"`)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(conflict.Current)
+		return
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.notify(r.Context(), userID, models.Event{Type: models.EventItemChanged, Table: string(s.Table), ID: entryID, Version: version})
+
+	// If everything goes well, respond with the new version of the record
+	w.Header().Set("ETag", versionETag(version))
+	writeJSON(w, map[string]int64{"version": version})
+}
+
+// baseVersionField is the body field that can carry the base version instead of If-Match.
+const baseVersionField = "base_version"
+
+// versionETag formats the version of a record as a strong entity tag.
+func versionETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseBaseVersion returns the version an update is based on. It is taken from the If-Match
+// header or the 'base_version' body field; zero means an unconditional update.
+func parseBaseVersion(r *http.Request, data map[string]string) (int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" {
+		value = data[baseVersionField]
+	} else if value == "*" {
+		return 0, nil
+	} else {
+		// Versions are strong validators, so weak tags never match
+		if !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) || len(value) < 2 {
+			return 0, fmt.Errorf("invalid If-Match header: %s", value)
 		}
+		value = value[1 : len(value)-1]
 	}
-	wrapper := ServerInterfaceWrapper{
-		Handler:            si,
-		HandlerMiddlewares: options.Middlewares,
-		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+
+	if value == "" {
+		return 0, nil
 	}
 
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/addData/{table}/{userID}/{entryID}", wrapper.PostAddDataTableUserIDEntryID)
-	})
-	r.Group(func(r chi.Router) {
-		r.Delete(options.BaseURL+"/deleteData/{table}/{userID}/{entryID}", wrapper.DeleteDeleteDataTableUserIDEntryID)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/getAllData/{table}/{userID}/{lastSyncStr}", wrapper.GetGetAllDataTableUserID)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/getData/{table}/{userID}/{entryID}", wrapper.GetGetDataTableUserIDEntryID)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/getFile/{userID}/{entryID}", wrapper.GetGetFileUserIDEntryID)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/getPassword/{username}", wrapper.GetGetPasswordUsername)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/getUserID/{username}", wrapper.GetGetUserIDUsername)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/login", wrapper.PostLogin)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/register", wrapper.PostRegister)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/sendFile/{userID}/{fileName}", wrapper.PostSendFileUserID)
-	})
-	r.Group(func(r chi.Router) {
-		r.Put(options.BaseURL+"/updateData/{table}/{userID}/{entryID}", wrapper.PutUpdateDataTableUserIDEntryID)
-	})
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid base version: %s", value)
+	}
 
-	return r
+	return version, nil
 }
@@ -0,0 +1,1566 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wurt83ow/gophkeeper-server/internal/blobstore"
+	"github.com/wurt83ow/gophkeeper-server/internal/events"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+	"github.com/wurt83ow/gophkeeper-server/internal/uploads"
+	"go.uber.org/zap/zapcore"
+)
+
+type mockStorage struct {
+	calls         int
+	passwords     map[string]string
+	refreshTokens map[string]mockRefreshToken
+	revokedTokens map[string]bool
+	sessions      map[string]models.Session
+	mfa           models.MFA
+	recoveryCodes map[string]bool
+	srpVerifiers  map[string]models.SRPVerifier
+	srpChallenges map[string]models.SRPChallenge
+	version       int64
+	changes       []models.Change
+	files         map[string]string
+	legacyFiles   []models.LegacyFile
+}
+
+type mockRefreshToken struct {
+	userID    int
+	sessionID string
+}
+
+func (m *mockStorage) UserExists(ctx context.Context, username string) (bool, error) {
+	if m.srpVerifiers != nil {
+		_, ok := m.srpVerifiers[username]
+		return ok, nil
+	}
+	return true, nil
+}
+
+func (m *mockStorage) AddUser(ctx context.Context, username string, hashedPassword string) error {
+	if m.passwords == nil {
+		m.passwords = make(map[string]string)
+	}
+	m.passwords[username] = hashedPassword
+	return nil
+}
+
+func (m *mockStorage) GetPassword(ctx context.Context, username string) (string, error) {
+	password, ok := m.passwords[username]
+	if !ok {
+		return "", errors.New("user not found")
+	}
+	return password, nil
+}
+
+func (m *mockStorage) UpdatePassword(ctx context.Context, username string, hashedPassword string) error {
+	m.passwords[username] = hashedPassword
+	return nil
+}
+
+func (m *mockStorage) GetUserID(ctx context.Context, username string) (int, error) {
+	return 1, nil
+}
+
+func (m *mockStorage) AddSRPUser(ctx context.Context, username string, verifier models.SRPVerifier) error {
+	m.srpVerifiers[username] = verifier
+	return nil
+}
+
+func (m *mockStorage) GetSRPVerifier(ctx context.Context, username string) (int, models.SRPVerifier, error) {
+	verifier, ok := m.srpVerifiers[username]
+	if !ok {
+		return 0, models.SRPVerifier{}, storage.ErrNotFound
+	}
+	return 1, verifier, nil
+}
+
+func (m *mockStorage) AddSRPChallenge(ctx context.Context, challenge models.SRPChallenge) error {
+	if m.srpChallenges == nil {
+		m.srpChallenges = make(map[string]models.SRPChallenge)
+	}
+	m.srpChallenges[challenge.ID] = challenge
+	return nil
+}
+
+func (m *mockStorage) ConsumeSRPChallenge(ctx context.Context, id string) (models.SRPChallenge, error) {
+	challenge, ok := m.srpChallenges[id]
+	if !ok {
+		return models.SRPChallenge{}, storage.ErrNotFound
+	}
+	delete(m.srpChallenges, id)
+	return challenge, nil
+}
+
+func (m *mockStorage) GetUsername(ctx context.Context, userID int) (string, error) {
+	return "u", nil
+}
+
+func (m *mockStorage) GetMFA(ctx context.Context, userID int) (models.MFA, error) {
+	if m.mfa == (models.MFA{}) {
+		return models.MFA{}, storage.ErrNotFound
+	}
+	return m.mfa, nil
+}
+
+func (m *mockStorage) SetPendingTOTPSecret(ctx context.Context, userID int, secret string) error {
+	m.mfa.PendingSecret = secret
+	return nil
+}
+
+func (m *mockStorage) EnableMFA(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error {
+	if m.mfa.PendingSecret == "" {
+		return storage.ErrNotFound
+	}
+	m.mfa = models.MFA{Secret: m.mfa.PendingSecret, Enabled: true, LastStep: step}
+	m.recoveryCodes = make(map[string]bool)
+	for _, codeHash := range recoveryCodeHashes {
+		m.recoveryCodes[codeHash] = true
+	}
+	return nil
+}
+
+func (m *mockStorage) UseTOTPStep(ctx context.Context, userID int, step int64) error {
+	if step <= m.mfa.LastStep {
+		return storage.ErrConflict
+	}
+	m.mfa.LastStep = step
+	return nil
+}
+
+func (m *mockStorage) UseRecoveryCode(ctx context.Context, userID int, codeHash string) error {
+	if !m.recoveryCodes[codeHash] {
+		return storage.ErrNotFound
+	}
+	delete(m.recoveryCodes, codeHash)
+	return nil
+}
+
+func (m *mockStorage) AddSession(ctx context.Context, userID int, session models.Session) error {
+	if m.sessions == nil {
+		m.sessions = make(map[string]models.Session)
+	}
+	m.sessions[session.ID] = session
+	return nil
+}
+
+func (m *mockStorage) ListSessions(ctx context.Context, userID int) ([]models.Session, error) {
+	sessions := make([]models.Session, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (m *mockStorage) TouchSession(ctx context.Context, sessionID string, ip string) error {
+	session := m.sessions[sessionID]
+	session.IP = ip
+	m.sessions[sessionID] = session
+	return nil
+}
+
+func (m *mockStorage) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	_, ok := m.sessions[sessionID]
+	return ok, nil
+}
+
+func (m *mockStorage) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	if _, ok := m.sessions[sessionID]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(m.sessions, sessionID)
+	for hash, token := range m.refreshTokens {
+		if token.sessionID == sessionID {
+			delete(m.refreshTokens, hash)
+		}
+	}
+	return nil
+}
+
+func (m *mockStorage) RevokeAllSessions(ctx context.Context, userID int) error {
+	m.sessions = nil
+	m.refreshTokens = nil
+	return nil
+}
+
+func (m *mockStorage) AddRefreshToken(ctx context.Context, tokenHash string, userID int, sessionID string, expiresAt time.Time) error {
+	if m.refreshTokens == nil {
+		m.refreshTokens = make(map[string]mockRefreshToken)
+	}
+	m.refreshTokens[tokenHash] = mockRefreshToken{userID: userID, sessionID: sessionID}
+	return nil
+}
+
+func (m *mockStorage) ConsumeRefreshToken(ctx context.Context, tokenHash string) (int, string, error) {
+	token, ok := m.refreshTokens[tokenHash]
+	if !ok {
+		return 0, "", errors.New("refresh token not found")
+	}
+	delete(m.refreshTokens, tokenHash)
+	return token.userID, token.sessionID, nil
+}
+
+func (m *mockStorage) RevokeRefreshToken(ctx context.Context, tokenHash string, userID int) error {
+	if m.refreshTokens[tokenHash].userID == userID {
+		delete(m.refreshTokens, tokenHash)
+	}
+	return nil
+}
+
+func (m *mockStorage) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	if m.revokedTokens == nil {
+		m.revokedTokens = make(map[string]bool)
+	}
+	m.revokedTokens[jti] = true
+	return nil
+}
+
+func (m *mockStorage) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return m.revokedTokens[jti], nil
+}
+
+// AddData rejects the ID of the existing record "entry".
+func (m *mockStorage) AddData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string) error {
+	m.calls++
+	if entry_id == "entry" {
+		return storage.ErrConflict
+	}
+	return nil
+}
+
+// UpdateData keeps a single record "entry" whose version starts at 1.
+func (m *mockStorage) UpdateData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string, baseVersion int64) (int64, error) {
+	m.calls++
+	if entry_id != "entry" {
+		return 0, storage.ErrNotFound
+	}
+	if m.version == 0 {
+		m.version = 1
+	}
+	if baseVersion != 0 && baseVersion != m.version {
+		return 0, &storage.ConflictError{Current: map[string]string{"id": entry_id, "version": strconv.FormatInt(m.version, 10)}}
+	}
+	m.version++
+	return m.version, nil
+}
+
+func (m *mockStorage) Sync(ctx context.Context, userID int, afterSeq int64, limit int) ([]models.Change, bool, error) {
+	var changes []models.Change
+	for _, change := range m.changes {
+		if change.Seq > afterSeq {
+			changes = append(changes, change)
+		}
+	}
+	if len(changes) > limit {
+		return changes[:limit], true, nil
+	}
+	return changes, false, nil
+}
+
+// SetFileContent keeps the digest of each uploaded entry; the entry "missing" does not exist.
+func (m *mockStorage) SetFileContent(ctx context.Context, userID int, entryID string, size int64, digest string) error {
+	if entryID == "missing" {
+		return storage.ErrNotFound
+	}
+	if m.files == nil {
+		m.files = make(map[string]string)
+	}
+	m.files[entryID] = digest
+	return nil
+}
+
+func (m *mockStorage) LegacyFiles(ctx context.Context) ([]models.LegacyFile, error) {
+	return m.legacyFiles, nil
+}
+
+// GetFileContent returns the digest kept by SetFileContent; deleted entries are dropped from it.
+// The entries belong to user 1, so other users do not find them.
+func (m *mockStorage) GetFileContent(ctx context.Context, userID int, entryID string) (models.FileContent, error) {
+	digest, ok := m.files[entryID]
+	if !ok || userID != 1 {
+		return models.FileContent{}, storage.ErrNotFound
+	}
+	return models.FileContent{SHA256: digest, UpdatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, nil
+}
+
+// BlobStats counts the distinct digests kept by SetFileContent.
+func (m *mockStorage) BlobStats(ctx context.Context) (models.BlobStats, error) {
+	digests := make(map[string]bool)
+	for _, digest := range m.files {
+		digests[digest] = true
+	}
+	return models.BlobStats{Blobs: int64(len(digests)), References: int64(len(m.files))}, nil
+}
+
+// CacheStats reports a fixed set of counters.
+func (m *mockStorage) CacheStats() models.CacheStats {
+	return models.CacheStats{Entries: 2, Hits: 5, Misses: 2}
+}
+
+func (m *mockStorage) DeleteData(ctx context.Context, table string, user_id int, entry_id string) error {
+	m.calls++
+	if entry_id == "missing" {
+		return storage.ErrNotFound
+	}
+	delete(m.files, entry_id)
+	return nil
+}
+
+// Batch fails operations on "missing" entries with ErrNotFound and on "stale" entries with a conflict.
+func (m *mockStorage) Batch(ctx context.Context, userID int, ops []models.BatchOperation, partial bool) ([]models.BatchResult, error) {
+	m.calls++
+	var results []models.BatchResult
+	for i, op := range ops {
+		result := models.BatchResult{ID: op.ID, Version: 1}
+		switch op.ID {
+		case "missing":
+			result = models.BatchResult{ID: op.ID, Err: storage.ErrNotFound}
+		case "duplicate":
+			result = models.BatchResult{ID: op.ID, Err: storage.ErrConflict}
+		case "stale":
+			current := map[string]string{"id": op.ID, "version": "2"}
+			result = models.BatchResult{ID: op.ID, Err: &storage.ConflictError{Current: current}, Current: current}
+		}
+		results = append(results, result)
+
+		if result.Err != nil && !partial {
+			return results, &storage.BatchError{Index: i, Err: result.Err}
+		}
+	}
+	return results, nil
+}
+
+// GetData returns a record with the current mock version; "missing" entries are not found.
+func (m *mockStorage) GetData(ctx context.Context, table string, userID int, entryID string) (map[string]string, error) {
+	m.calls++
+	if entryID == "missing" {
+		return nil, storage.ErrNotFound
+	}
+	return map[string]string{"id": entryID, "data": "secret", "version": strconv.FormatInt(m.version, 10)}, nil
+}
+
+func (m *mockStorage) GetAllData(ctx context.Context, table string, user_id int, last_sync time.Time, incl_del bool) ([]map[string]string, error) {
+	m.calls++
+	return nil, nil
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(string, ...zapcore.Field) {}
+
+// mockAuthz marks current verifiers with a "new:" prefix and legacy ones with "old:".
+type mockAuthz struct {
+	refreshCounter int
+	sessionCounter int
+}
+
+func (m *mockAuthz) HashPassword(password string) (string, error) {
+	return "new:" + password, nil
+}
+
+func (m *mockAuthz) VerifyPassword(hashedPassword, password string) (bool, bool) {
+	switch hashedPassword {
+	case "new:" + password:
+		return true, false
+	case "old:" + password:
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+func (m *mockAuthz) CreateJWTTokenForUser(userID string, sessionID string) string {
+	return "token-" + userID + "-" + sessionID
+}
+
+func (m *mockAuthz) CreateSessionID() (string, error) {
+	m.sessionCounter++
+	return "session-" + strconv.Itoa(m.sessionCounter), nil
+}
+
+func (m *mockAuthz) CreateRefreshToken() (string, string, time.Time, error) {
+	m.refreshCounter++
+	token := "refresh-" + strconv.Itoa(m.refreshCounter)
+	return token, m.HashRefreshToken(token), time.Now().Add(time.Hour), nil
+}
+
+func (m *mockAuthz) HashRefreshToken(token string) string {
+	return "hash:" + token
+}
+
+func (m *mockAuthz) CreateTOTPSecret() (string, error) {
+	return "SECRET", nil
+}
+
+func (m *mockAuthz) TOTPProvisioningURI(account string, secret string) string {
+	return "otpauth://totp/" + account + "?secret=" + secret
+}
+
+// ValidateTOTP accepts codes of the form "<secret>-<step>".
+func (m *mockAuthz) ValidateTOTP(secret string, code string) (int64, bool) {
+	stepStr, ok := strings.CutPrefix(code, secret+"-")
+	if !ok {
+		return 0, false
+	}
+	step, err := strconv.ParseInt(stepStr, 10, 64)
+	return step, err == nil
+}
+
+func (m *mockAuthz) CreateRecoveryCodes() ([]string, []string, error) {
+	codes := []string{"code-1", "code-2"}
+	return codes, []string{m.HashRecoveryCode(codes[0]), m.HashRecoveryCode(codes[1])}, nil
+}
+
+func (m *mockAuthz) HashRecoveryCode(code string) string {
+	return "hash:" + code
+}
+
+// mockAuthz challenges carry the user ID and device ID as "challenge-<uid>-<did>".
+func (m *mockAuthz) CreateMFAChallenge(userID int, deviceID, deviceName string) (string, error) {
+	return "challenge-" + strconv.Itoa(userID) + "-" + deviceID, nil
+}
+
+func (m *mockAuthz) DecodeMFAChallenge(token string) (models.MFAChallenge, error) {
+	parts := strings.SplitN(token, "-", 3)
+	if len(parts) != 3 || parts[0] != "challenge" {
+		return models.MFAChallenge{}, errors.New("invalid challenge")
+	}
+	userID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return models.MFAChallenge{}, err
+	}
+	return models.MFAChallenge{ID: token, UserID: userID, DeviceID: parts[2], ExpiresAt: time.Now().Add(time.Minute)}, nil
+}
+
+// CreateSRPChallenge answers the client ephemeral "<A>" with the server ephemeral "B-<A>".
+func (m *mockAuthz) CreateSRPChallenge(username string, verifier models.SRPVerifier, publicA string) (models.SRPChallenge, error) {
+	m.sessionCounter++
+	return models.SRPChallenge{
+		ID:       "srp-" + strconv.Itoa(m.sessionCounter),
+		Username: username,
+		A:        publicA,
+		B:        "B-" + publicA,
+	}, nil
+}
+
+// ValidateSRPVerifier rejects the verifier "0", which is outside (0, N).
+func (m *mockAuthz) ValidateSRPVerifier(verifier models.SRPVerifier) error {
+	if verifier.Verifier == "0" {
+		return errors.New("invalid SRP value")
+	}
+	return nil
+}
+
+func (m *mockAuthz) FakeSRPSalt(username string) string {
+	return "fake-salt"
+}
+
+// VerifySRPProof accepts the client proof "<verifier>:<B>".
+func (m *mockAuthz) VerifySRPProof(verifier models.SRPVerifier, challenge models.SRPChallenge, clientProof string) (string, bool) {
+	if clientProof != verifier.Verifier+":"+challenge.B {
+		return "", false
+	}
+	return "server-proof", true
+}
+
+type mockOptions struct {
+	fileStoragePath string
+	maxUploadSize   int64
+	adminUsers      []string
+}
+
+func (m *mockOptions) ParseFlags() {}
+
+func (m *mockOptions) RunAddr() string {
+	return ""
+}
+
+func (m *mockOptions) FileStoragePath() string {
+	return m.fileStoragePath
+}
+
+func (m *mockOptions) MaxUploadSize() int64 {
+	return m.maxUploadSize
+}
+
+func (m *mockOptions) AdminUsers() []string {
+	return m.adminUsers
+}
+
+// withToken imitates JWTAuthzMiddleware by putting the token details into the request context.
+func withToken(r *http.Request, userID, sessionID, tokenID string) *http.Request {
+	ctx := context.WithValue(r.Context(), models.Key("userID"), userID)
+	ctx = context.WithValue(ctx, models.Key("sessionID"), sessionID)
+	ctx = context.WithValue(ctx, models.Key("tokenID"), tokenID)
+	ctx = context.WithValue(ctx, models.Key("tokenExpiresAt"), time.Now().Add(time.Minute))
+	return r.WithContext(ctx)
+}
+
+func TestBaseController_PostTokenRefresh(t *testing.T) {
+	storage := &mockStorage{passwords: map[string]string{"u": "new:p"}}
+	handler := Handler(NewBaseController(storage, nil, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil))
+
+	// Log in to get the first refresh token
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"username":"u","password":"p","deviceID":"d1","deviceName":"laptop"}`))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var login map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &login))
+	assert.Equal(t, "refresh-1", login["refreshToken"])
+	assert.Equal(t, "session-1", login["sessionID"])
+	assert.Equal(t, "d1", login["deviceID"])
+	assert.Equal(t, "laptop", storage.sessions["session-1"].DeviceName)
+
+	// The refresh token is exchanged for a new pair
+	req = httptest.NewRequest(http.MethodPost, "/token/refresh", strings.NewReader(`{"refreshToken":"refresh-1"}`))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var refreshed map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &refreshed))
+	assert.Equal(t, "token-1-session-1", refreshed["token"])
+	assert.Equal(t, "refresh-2", refreshed["refreshToken"])
+	assert.Equal(t, "session-1", refreshed["sessionID"])
+
+	// The old refresh token can not be reused
+	req = httptest.NewRequest(http.MethodPost, "/token/refresh", strings.NewReader(`{"refreshToken":"refresh-1"}`))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/token/refresh", strings.NewReader(`{}`))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestBaseController_PostLogout(t *testing.T) {
+	storage := &mockStorage{
+		refreshTokens: map[string]mockRefreshToken{
+			"hash:refresh-1": {userID: 1, sessionID: "session-1"},
+			"hash:refresh-2": {userID: 1, sessionID: "session-2"},
+		},
+		sessions: map[string]models.Session{"session-1": {ID: "session-1"}, "session-2": {ID: "session-2"}},
+	}
+	controller := NewBaseController(storage, nil, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/logout", strings.NewReader(`{"refreshToken":"refresh-1"}`))
+	rr := httptest.NewRecorder()
+	controller.PostLogout(rr, withToken(req, "1", "session-1", "jti-1"))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, storage.revokedTokens["jti-1"])
+	assert.NotContains(t, storage.sessions, "session-1")
+	assert.NotContains(t, storage.refreshTokens, "hash:refresh-1")
+
+	// Without a body the session of the token is still ended
+	req = httptest.NewRequest(http.MethodPost, "/logout", nil)
+	rr = httptest.NewRecorder()
+	controller.PostLogout(rr, withToken(req, "1", "session-2", "jti-2"))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, storage.revokedTokens["jti-2"])
+	assert.Empty(t, storage.sessions)
+	assert.Empty(t, storage.refreshTokens)
+
+	// Unauthenticated requests are rejected
+	req = httptest.NewRequest(http.MethodPost, "/logout", nil)
+	rr = httptest.NewRecorder()
+	controller.PostLogout(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestBaseController_Sessions(t *testing.T) {
+	storage := &mockStorage{
+		sessions: map[string]models.Session{"session-1": {ID: "session-1"}, "session-2": {ID: "session-2"}},
+	}
+	controller := NewBaseController(storage, nil, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil)
+	handler := Handler(controller)
+
+	// The session of the request is marked as current
+	req := httptest.NewRequest(http.MethodGet, "/sessions/1", nil)
+	rr := httptest.NewRecorder()
+	controller.GetSessionsUserID(rr, withToken(req, "1", "session-1", "jti"), 1)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var sessions []models.Session
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &sessions))
+	assert.Len(t, sessions, 2)
+	for _, session := range sessions {
+		assert.Equal(t, session.ID == "session-1", session.Current)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/sessions/1/session-2", nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, storage.sessions, "session-2")
+
+	req = httptest.NewRequest(http.MethodDelete, "/sessions/1/session-2", nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/sessions/1", nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, storage.sessions)
+}
+
+func TestBaseController_PostRegister(t *testing.T) {
+	storage := &mockStorage{}
+	handler := Handler(NewBaseController(storage, nil, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil))
+
+	req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(`{"username":"u","password":"p"}`))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "new:p", storage.passwords["u"])
+
+	req = httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(`{"username":"u2"}`))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestBaseController_PostLogin(t *testing.T) {
+	tests := []struct {
+		name     string
+		stored   string
+		password string
+		status   int
+		after    string
+	}{
+		{"current_hash", "new:p", "p", http.StatusOK, "new:p"},
+		{"legacy_hash_is_upgraded", "old:p", "p", http.StatusOK, "new:p"},
+		{"wrong_password", "new:p", "x", http.StatusUnauthorized, "new:p"},
+		{"hash_as_password", "new:p", "new:p", http.StatusUnauthorized, "new:p"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &mockStorage{passwords: map[string]string{"u": tt.stored}}
+			handler := Handler(NewBaseController(storage, nil, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil))
+
+			body := `{"username":"u","password":"` + tt.password + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+			assert.Equal(t, tt.after, storage.passwords["u"])
+		})
+	}
+}
+
+func TestBaseController_PostLogin_UnknownUser(t *testing.T) {
+	storage := &mockStorage{passwords: map[string]string{"u": "new:p"}}
+	handler := Handler(NewBaseController(storage, nil, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil))
+
+	login := func(username, password string) *httptest.ResponseRecorder {
+		body := `{"username":"` + username + `","password":"` + password + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Unknown users and wrong passwords get the same answer
+	unknown := login("ghost", "p")
+	wrong := login("u", "x")
+	assert.Equal(t, http.StatusUnauthorized, unknown.Code)
+	assert.Equal(t, http.StatusUnauthorized, wrong.Code)
+	assert.Equal(t, wrong.Body.String(), unknown.Body.String())
+	assert.NotContains(t, unknown.Body.String(), "not found")
+}
+
+func TestBaseController_GenericRoutesValidateSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"add_ok", http.MethodPost, "/addData/TextData/1/e1", `{"data":"d"}`, http.StatusOK},
+		{"add_unknown_table", http.MethodPost, "/addData/Users/1/e1", `{"username":"u"}`, http.StatusBadRequest},
+		{"add_unknown_column", http.MethodPost, "/addData/TextData/1/e1", `{"data":"d","user_id":"2"}`, http.StatusBadRequest},
+		{"add_missing_field", http.MethodPost, "/addData/UserCredentials/1/e1", `{"login":"l"}`, http.StatusBadRequest},
+		{"update_ok", http.MethodPut, "/updateData/CreditCardData/1/entry", `{"cvv":"123"}`, http.StatusOK},
+		{"update_unknown_table", http.MethodPut, "/updateData/Users/1/e1", `{"password":"p"}`, http.StatusBadRequest},
+		{"update_unknown_column", http.MethodPut, "/updateData/TextData/1/e1", `{"deleted":"false"}`, http.StatusBadRequest},
+		{"delete_ok", http.MethodDelete, "/deleteData/FilesData/1/e1", "", http.StatusOK},
+		{"delete_unknown_table", http.MethodDelete, "/deleteData/Users/1/e1", "", http.StatusBadRequest},
+		{"get_all_ok", http.MethodGet, "/getAllData/TextData/1/0001-01-01T00:00:00Z", "", http.StatusOK},
+		{"get_all_unknown_table", http.MethodGet, "/getAllData/Users/1/0001-01-01T00:00:00Z", "", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &mockStorage{}
+			handler := Handler(NewBaseController(storage, nil, &mockLogger{}, nil, nil, nil, nil, nil))
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+			if tt.status != http.StatusOK {
+				assert.Zero(t, storage.calls, "storage must not be reached")
+			}
+		})
+	}
+}
+
+func TestBaseController_DeleteMissing(t *testing.T) {
+	handler := Handler(NewBaseController(&mockStorage{}, nil, &mockLogger{}, nil, nil, nil, nil, nil))
+
+	req := httptest.NewRequest(http.MethodDelete, "/deleteData/TextData/1/missing", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestBaseController_AddDuplicate(t *testing.T) {
+	handler := Handler(NewBaseController(&mockStorage{}, nil, &mockLogger{}, nil, nil, nil, nil, nil))
+
+	req := httptest.NewRequest(http.MethodPost, "/addData/TextData/1/entry", strings.NewReader(`{"data":"d"}`))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestBaseController_MFA(t *testing.T) {
+	storage := &mockStorage{passwords: map[string]string{"u": "new:p"}}
+	handler := Handler(NewBaseController(storage, nil, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil))
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Verification without enrollment is rejected
+	rr := post("/mfa/1/verify", `{"code":"SECRET-1"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// Enrollment returns the secret and the provisioning URI
+	rr = post("/mfa/1/enroll", ``)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var enroll map[string]string
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &enroll))
+	assert.Equal(t, "SECRET", enroll["secret"])
+	assert.Equal(t, "otpauth://totp/u?secret=SECRET", enroll["uri"])
+	assert.False(t, storage.mfa.Enabled)
+
+	rr = post("/mfa/1/verify", `{"code":"WRONG-1"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// The first valid code enables 2FA and returns the recovery codes
+	rr = post("/mfa/1/verify", `{"code":"SECRET-10"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var verify map[string][]string
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &verify))
+	assert.Equal(t, []string{"code-1", "code-2"}, verify["recoveryCodes"])
+	assert.True(t, storage.mfa.Enabled)
+
+	// The password step now returns a challenge instead of the tokens
+	rr = post("/login", `{"username":"u","password":"p","deviceID":"d1"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var login map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &login))
+	assert.Equal(t, true, login["mfa_required"])
+	assert.Equal(t, "challenge-1-d1", login["challengeToken"])
+	assert.NotContains(t, login, "token")
+
+	// The code used for the enrollment can not be replayed
+	rr = post("/login/mfa", `{"challengeToken":"challenge-1-d1","code":"SECRET-10"}`)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// A challenge allows a single attempt
+	rr = post("/login/mfa", `{"challengeToken":"challenge-1-d1","code":"SECRET-11"}`)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = post("/login/mfa", `{"challengeToken":"challenge-1-d2","code":"SECRET-11"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &login))
+	assert.Equal(t, "token-1-session-1", login["token"])
+	assert.Equal(t, "d2", login["deviceID"])
+
+	// Recovery codes work once
+	rr = post("/login/mfa", `{"challengeToken":"challenge-1-d3","recoveryCode":"code-1"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = post("/login/mfa", `{"challengeToken":"challenge-1-d4","recoveryCode":"code-1"}`)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = post("/login/mfa", `{"challengeToken":"invalid","code":"SECRET-12"}`)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = post("/login/mfa", `{"challengeToken":"challenge-1-d5"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestBaseController_MFA_Reenroll(t *testing.T) {
+	storage := &mockStorage{
+		mfa:           models.MFA{Secret: "OLD", Enabled: true, LastStep: 10},
+		recoveryCodes: map[string]bool{"hash:code-1": true},
+	}
+	handler := Handler(NewBaseController(storage, nil, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil))
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// An access token alone does not replace the active authenticator
+	rr := post("/mfa/1/enroll", ``)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Empty(t, storage.mfa.PendingSecret)
+
+	rr = post("/mfa/1/enroll", `{"code":"WRONG-11"}`)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	// Used codes do not count
+	rr = post("/mfa/1/enroll", `{"code":"OLD-10"}`)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Empty(t, storage.mfa.PendingSecret)
+
+	rr = post("/mfa/1/verify", `{"code":"SECRET-11"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "OLD", storage.mfa.Secret)
+
+	// A code of the current authenticator starts the enrollment
+	rr = post("/mfa/1/enroll", `{"code":"OLD-11"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "SECRET", storage.mfa.PendingSecret)
+	assert.Equal(t, "OLD", storage.mfa.Secret)
+
+	// So does a recovery code, once
+	rr = post("/mfa/1/enroll", `{"recoveryCode":"code-1"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = post("/mfa/1/enroll", `{"recoveryCode":"code-1"}`)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = post("/mfa/1/verify", `{"code":"SECRET-12"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "SECRET", storage.mfa.Secret)
+}
+
+func TestBaseController_SRP(t *testing.T) {
+	storage := &mockStorage{srpVerifiers: map[string]models.SRPVerifier{}}
+	handler := Handler(NewBaseController(storage, nil, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil))
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := post("/srp/register", `{"username":"u","salt":"s"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = post("/srp/register", `{"username":"u","salt":"s","verifier":"0"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, storage.srpVerifiers)
+
+	rr = post("/srp/register", `{"username":"u","salt":"s","verifier":"v"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, models.SRPVerifier{Salt: "s", Verifier: "v"}, storage.srpVerifiers["u"])
+
+	rr = post("/srp/register", `{"username":"u","salt":"s","verifier":"v"}`)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	// First round trip: the client sends A and gets the salt and B
+	rr = post("/srp/login/init", `{"username":"u","publicA":"A"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var init map[string]string
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &init))
+	assert.Equal(t, "s", init["salt"])
+	assert.Equal(t, "B-A", init["publicB"])
+
+	// Second round trip: the client proves the knowledge of the password
+	rr = post("/srp/login/verify", `{"challengeID":"`+init["challengeID"]+`","clientProof":"v:B-A","deviceID":"d1"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var login map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &login))
+	assert.Equal(t, "server-proof", login["serverProof"])
+	assert.Equal(t, "d1", login["deviceID"])
+	assert.NotEmpty(t, login["token"])
+
+	// A challenge can be answered only once
+	rr = post("/srp/login/verify", `{"challengeID":"`+init["challengeID"]+`","clientProof":"v:B-A"}`)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// A wrong proof is rejected and burns the challenge
+	rr = post("/srp/login/init", `{"username":"u","publicA":"A"}`)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &init))
+	rr = post("/srp/login/verify", `{"challengeID":"`+init["challengeID"]+`","clientProof":"wrong"}`)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Empty(t, storage.srpChallenges)
+
+	// Unknown users get a fake salt and a challenge that can not succeed
+	rr = post("/srp/login/init", `{"username":"ghost","publicA":"A"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &init))
+	assert.Equal(t, "fake-salt", init["salt"])
+	assert.Contains(t, storage.srpChallenges, init["challengeID"])
+	rr = post("/srp/login/verify", `{"challengeID":"`+init["challengeID"]+`","clientProof":"fake-salt:B-A"}`)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// With two-factor authentication the server proof comes with the challenge token
+	storage.mfa = models.MFA{Secret: "SECRET", Enabled: true}
+	rr = post("/srp/login/init", `{"username":"u","publicA":"A"}`)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &init))
+	rr = post("/srp/login/verify", `{"challengeID":"`+init["challengeID"]+`","clientProof":"v:B-A","deviceID":"d1"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	login = nil
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &login))
+	assert.Equal(t, true, login["mfa_required"])
+	assert.Equal(t, "server-proof", login["serverProof"])
+	assert.NotContains(t, login, "token")
+}
+
+func TestBaseController_PutUpdateData_Versions(t *testing.T) {
+	storage := &mockStorage{}
+	handler := Handler(NewBaseController(storage, nil, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil))
+
+	put := func(path, body, ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// An update based on the current version succeeds and returns the new one
+	rr := put("/updateData/TextData/1/entry", `{"data":"a"}`, `"1"`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
+	assert.JSONEq(t, `{"version":2}`, rr.Body.String())
+
+	// A stale update gets the current server copy
+	rr = put("/updateData/TextData/1/entry", `{"data":"b"}`, `"1"`)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
+	assert.JSONEq(t, `{"id":"entry","version":"2"}`, rr.Body.String())
+
+	// The base version can also be sent in the body
+	rr = put("/updateData/TextData/1/entry", `{"data":"b","base_version":"1"}`, "")
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = put("/updateData/TextData/1/entry", `{"data":"b","base_version":"2"}`, "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+
+	// Clients that send no version overwrite the record as before
+	rr = put("/updateData/TextData/1/entry", `{"data":"c"}`, "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = put("/updateData/TextData/1/entry", `{"data":"c"}`, "*")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = put("/updateData/TextData/1/entry", `{"data":"c"}`, `W/"5"`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = put("/updateData/TextData/1/entry", `{"data":"c","base_version":"x"}`, "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = put("/updateData/TextData/1/missing", `{"data":"c"}`, "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestBaseController_GetData(t *testing.T) {
+	storage := &mockStorage{version: 3}
+	handler := Handler(NewBaseController(storage, nil, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil))
+
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// A single record comes with its version as the entity tag
+	rr := get("/getData/TextData/1/entry", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"id":"entry","data":"secret","version":"3"}`, rr.Body.String())
+
+	// A client holding the current version gets no body
+	rr = get("/getData/TextData/1/entry", `"3"`)
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+
+	rr = get("/getData/TextData/1/entry", `"2"`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = get("/getData/TextData/1/missing", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = get("/getData/Users/1/entry", "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestBaseController_PostBatch(t *testing.T) {
+	handler := Handler(NewBaseController(&mockStorage{}, nil, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil))
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/batch/1", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// All operations succeed
+	rr := post(`{"operations":[{"op":"add","table":"TextData","id":"a","data":{"data":"x"}},{"op":"delete","table":"TextData","id":"b"}]}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"committed":true,"results":[{"id":"a","status":200,"version":1},{"id":"b","status":200,"version":1}]}`, rr.Body.String())
+
+	// A failing operation rolls back the whole batch and stops it
+	rr = post(`{"operations":[{"op":"add","table":"TextData","id":"a"},{"op":"update","table":"TextData","id":"stale"},{"op":"delete","table":"TextData","id":"c"}]}`)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.JSONEq(t, `{"committed":false,"results":[{"id":"a","status":200,"version":1},
+		{"id":"stale","status":409,"error":"data conflict","current":{"id":"stale","version":"2"}}]}`, rr.Body.String())
+
+	// An ID that is already taken fails an add
+	rr = post(`{"operations":[{"op":"add","table":"TextData","id":"duplicate","data":{"data":"x"}}]}`)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.JSONEq(t, `{"committed":false,"results":[{"id":"duplicate","status":409,"error":"data conflict"}]}`, rr.Body.String())
+
+	// In partial mode the other operations are kept
+	rr = post(`{"partial":true,"operations":[{"op":"delete","table":"TextData","id":"missing"},{"op":"delete","table":"TextData","id":"c"}]}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"committed":true,"results":[{"id":"missing","status":404,"error":"not found"},{"id":"c","status":200,"version":1}]}`, rr.Body.String())
+
+	rr = post(`{"operations":[]}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = post(`{"operations":`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestBaseController_GetSync(t *testing.T) {
+	storage := &mockStorage{changes: []models.Change{
+		{Table: "TextData", Seq: 1, Entry: map[string]string{"id": "a"}},
+		{Table: "UserCredentials", Seq: 2, Entry: map[string]string{"id": "b"}},
+		{Table: "TextData", Seq: 4, Entry: map[string]string{"id": "a", "deleted": "true"}},
+	}}
+	handler := Handler(NewBaseController(storage, nil, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil))
+
+	get := func(path string) (*httptest.ResponseRecorder, SyncResponse) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		var response SyncResponse
+		if rr.Code == http.StatusOK {
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		}
+		return rr, response
+	}
+
+	// The first page starts from the beginning
+	rr, page := get("/sync/1?limit=2")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, page.Changes, 2)
+	assert.Equal(t, "UserCredentials", page.Changes[1].Table)
+	assert.True(t, page.HasMore)
+
+	// The cursor continues across record kinds
+	rr, page = get("/sync/1?limit=2&cursor=" + page.Cursor)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, page.Changes, 1)
+	assert.Equal(t, "true", page.Changes[0].Entry["deleted"])
+	assert.False(t, page.HasMore)
+
+	// Without new changes the cursor stays the same
+	cursor := page.Cursor
+	rr, page = get("/sync/1?cursor=" + cursor)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, page.Changes)
+	assert.NotNil(t, page.Changes)
+	assert.Equal(t, cursor, page.Cursor)
+
+	rr, _ = get("/sync/1?cursor=garbage")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr, _ = get("/sync/1?limit=0")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr, _ = get("/sync/1?limit=x")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestBaseController_PostSendFile(t *testing.T) {
+	storage := &mockStorage{}
+	options := &mockOptions{fileStoragePath: t.TempDir(), maxUploadSize: 8}
+	handler := Handler(NewBaseController(storage, options, &mockLogger{}, &mockAuthz{}, nil, blobstore.NewFileStore(options.fileStoragePath), nil, nil))
+
+	send := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// The content is stored in the user directory together with its digest
+	rr := send("/sendFile/1/entry.txt", "hello")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"size":5,"sha256":"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"}`, rr.Body.String())
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", storage.files["entry.txt"])
+
+	content, err := os.ReadFile(filepath.Join(options.fileStoragePath, "sha256", "2c", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+
+	// Files of other users are not visible
+	req := httptest.NewRequest(http.MethodGet, "/getFile/2/entry.txt", nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/getFile/1/entry.txt", nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "hello", rr.Body.String())
+
+	rr = send("/sendFile/1/large", "more than eight bytes")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+
+	rr = send("/sendFile/1/..", "hello")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = send("/sendFile/1/.hidden", "hello")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = send("/sendFile/1/missing", "hello")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Rejected uploads leave nothing behind
+	entries, err := os.ReadDir(filepath.Join(options.fileStoragePath, "1"))
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	entries, err = os.ReadDir(filepath.Join(options.fileStoragePath, "sha256"))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestBaseController_Uploads(t *testing.T) {
+	storage := &mockStorage{}
+	options := &mockOptions{fileStoragePath: t.TempDir(), maxUploadSize: 64}
+	handler := Handler(NewBaseController(storage, options, &mockLogger{}, &mockAuthz{}, uploads.NewStore(options.fileStoragePath), blobstore.NewFileStore(options.fileStoragePath), nil, nil))
+
+	do := func(method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := do(http.MethodPost, "/uploads/1", `{"entryID":"entry","length":11}`, nil)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var session uploads.Session
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &session))
+	path := "/uploads/1/" + session.ID
+	assert.Equal(t, path, rr.Header().Get("Location"))
+
+	rr = do(http.MethodPatch, path, "hello", map[string]string{"Upload-Offset": "0"})
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "5", rr.Header().Get("Upload-Offset"))
+
+	// The client asks for the offset after a dropped connection
+	rr = do(http.MethodHead, path, "", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "5", rr.Header().Get("Upload-Offset"))
+	assert.Equal(t, "11", rr.Header().Get("Upload-Length"))
+
+	rr = do(http.MethodPatch, path, "hello", map[string]string{"Upload-Offset": "0"})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, "5", rr.Header().Get("Upload-Offset"))
+
+	rr = do(http.MethodPatch, path, " world", nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = do(http.MethodPost, path+"/finish", "", nil)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = do(http.MethodPatch, path, " world", map[string]string{"Upload-Offset": "5"})
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = do(http.MethodPost, path+"/finish", "", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"size":11,"sha256":"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"}`, rr.Body.String())
+	assert.Equal(t, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", storage.files["entry"])
+
+	content, err := os.ReadFile(filepath.Join(options.fileStoragePath, "sha256", "b9", "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(content))
+
+	// A finished upload is gone
+	rr = do(http.MethodHead, path, "", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = do(http.MethodPost, "/uploads/1", `{"entryID":"entry","length":65}`, nil)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+
+	rr = do(http.MethodPost, "/uploads/1", `{"entryID":"../entry","length":1}`, nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// An upload for a missing entry is kept until it is aborted
+	rr = do(http.MethodPost, "/uploads/1", `{"entryID":"missing","length":0}`, nil)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &session))
+	path = "/uploads/1/" + session.ID
+
+	rr = do(http.MethodPost, path+"/finish", "", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = do(http.MethodDelete, path, "", nil)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = do(http.MethodDelete, path, "", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestBaseController_GetFile(t *testing.T) {
+	storage := &mockStorage{}
+	options := &mockOptions{fileStoragePath: t.TempDir(), maxUploadSize: 64}
+	handler := Handler(NewBaseController(storage, options, &mockLogger{}, &mockAuthz{}, nil, blobstore.NewFileStore(options.fileStoragePath), nil, nil))
+
+	get := func(path string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/sendFile/1/entry", strings.NewReader("hello world"))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	etag := `"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"`
+
+	rr := get("/getFile/1/entry", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, etag, rr.Header().Get("ETag"))
+	assert.Equal(t, "bytes", rr.Header().Get("Accept-Ranges"))
+	assert.Equal(t, "hello world", rr.Body.String())
+
+	// A client with the current copy gets no content
+	rr = get("/getFile/1/entry", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+
+	rr = get("/getFile/1/entry", map[string]string{"If-None-Match": `"other"`})
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// An interrupted download continues from a byte offset
+	rr = get("/getFile/1/entry", map[string]string{"Range": "bytes=6-"})
+	assert.Equal(t, http.StatusPartialContent, rr.Code)
+	assert.Equal(t, "bytes 6-10/11", rr.Header().Get("Content-Range"))
+	assert.Equal(t, "world", rr.Body.String())
+
+	// A range for a changed file returns the whole new content
+	rr = get("/getFile/1/entry", map[string]string{"Range": "bytes=6-", "If-Range": `"other"`})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "hello world", rr.Body.String())
+
+	rr = get("/getFile/1/entry", map[string]string{"Range": "bytes=20-"})
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, rr.Code)
+
+	// Files of other users are not served
+	rr = get("/getFile/2/entry", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = get("/getFile/1/..", nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// A soft-deleted entry is not served although its file is still on disk
+	req = httptest.NewRequest(http.MethodDelete, "/deleteData/FilesData/1/entry", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	rr = get("/getFile/1/entry", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestBaseController_Dedup(t *testing.T) {
+	storage := &mockStorage{}
+	options := &mockOptions{fileStoragePath: t.TempDir(), maxUploadSize: 64, adminUsers: []string{"u"}}
+	blobs := blobstore.NewFileStore(options.fileStoragePath)
+	handler := Handler(NewBaseController(storage, options, &mockLogger{}, &mockAuthz{}, nil, blobs, nil, nil))
+
+	// Two entries with the same content share one blob
+	for _, entry := range []string{"first", "second"} {
+		req := httptest.NewRequest(http.MethodPost, "/sendFile/1/"+entry, strings.NewReader("same"))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(options.fileStoragePath, "sha256"))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// A blob lost from the store is written again by the next upload of its content
+	digest := storage.files["first"]
+	assert.NoError(t, blobs.Delete(context.Background(), blobstore.ContentKey(digest)))
+
+	req := httptest.NewRequest(http.MethodPost, "/sendFile/1/third", strings.NewReader("same"))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	_, err = blobs.Stat(context.Background(), blobstore.ContentKey(digest))
+	assert.NoError(t, err)
+
+	req = withToken(httptest.NewRequest(http.MethodGet, "/admin/stats", nil), "1", "session", "token")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"blobs":1,"references":3,"storedBytes":0,"logicalBytes":0,"savedBytes":0}`, rr.Body.String())
+
+	// Only admin users see the statistics
+	options.adminUsers = []string{"admin"}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestBaseController_CacheStats(t *testing.T) {
+	options := &mockOptions{adminUsers: []string{"u"}}
+	handler := Handler(NewBaseController(&mockStorage{}, options, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil))
+
+	req := withToken(httptest.NewRequest(http.MethodGet, "/admin/cache-stats", nil), "1", "session", "token")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"entries":2,"hits":5,"misses":2,"evictions":0,"invalidations":0}`, rr.Body.String())
+
+	// Only admin users see the statistics
+	options.adminUsers = []string{"admin"}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+// mockReencryption is a re-encryption job that never finishes its run.
+type mockReencryption struct {
+	progress models.ReencryptionProgress
+}
+
+func (m *mockReencryption) Start() (models.ReencryptionProgress, error) {
+	if m.progress.Running {
+		return m.progress, storage.ErrReencryptionRunning
+	}
+	m.progress = models.ReencryptionProgress{Running: true, KeyID: "2024"}
+	return m.progress, nil
+}
+
+func (m *mockReencryption) Progress() models.ReencryptionProgress {
+	return m.progress
+}
+
+func TestBaseController_Reencryption(t *testing.T) {
+	options := &mockOptions{adminUsers: []string{"u"}}
+	handler := Handler(NewBaseController(&mockStorage{}, options, &mockLogger{}, &mockAuthz{}, nil, nil, nil, &mockReencryption{}))
+
+	do := func(method string) *httptest.ResponseRecorder {
+		req := withToken(httptest.NewRequest(method, "/admin/reencryption", nil), "1", "session", "token")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := do(http.MethodGet)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"running":false,"keyID":"","users":0,"total":0,"dataKeys":0,"records":0}`, rr.Body.String())
+
+	rr = do(http.MethodPost)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.JSONEq(t, `{"running":true,"keyID":"2024","users":0,"total":0,"dataKeys":0,"records":0}`, rr.Body.String())
+
+	// A run that has not finished cannot be started again
+	rr = do(http.MethodPost)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	// Only admin users control the job
+	options.adminUsers = []string{"admin"}
+	assert.Equal(t, http.StatusForbidden, do(http.MethodGet).Code)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost).Code)
+
+	// Without a master key there is no job
+	options.adminUsers = []string{"u"}
+	handler = Handler(NewBaseController(&mockStorage{}, options, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil))
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost).Code)
+}
+
+func TestBaseController_Events(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	handler := Handler(NewBaseController(&mockStorage{}, nil, &mockLogger{}, &mockAuthz{}, nil, nil, broadcaster, nil))
+
+	received, cancel := broadcaster.Subscribe(1)
+	defer cancel()
+
+	do := func(method, path, body string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/addData/TextData/1/a", `{"data":"x"}`))
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, "/updateData/TextData/1/entry", `{"data":"y"}`))
+	assert.Equal(t, http.StatusOK, do(http.MethodDelete, "/deleteData/TextData/1/a", ""))
+
+	// Failed changes and rolled back batches are not announced
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPut, "/updateData/TextData/1/missing", `{"data":"y"}`))
+	assert.Equal(t, http.StatusConflict, do(http.MethodPost, "/batch/1",
+		`{"operations":[{"op":"add","table":"TextData","id":"b"},{"op":"update","table":"TextData","id":"stale"}]}`))
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/batch/1",
+		`{"partial":true,"operations":[{"op":"delete","table":"TextData","id":"missing"},{"op":"delete","table":"TextData","id":"c"}]}`))
+
+	want := []models.Event{
+		{Type: models.EventItemChanged, Table: "TextData", ID: "a", Version: 1},
+		{Type: models.EventItemChanged, Table: "TextData", ID: "entry", Version: 2},
+		{Type: models.EventItemDeleted, Table: "TextData", ID: "a"},
+		{Type: models.EventItemDeleted, Table: "TextData", ID: "c"},
+	}
+	for _, event := range want {
+		assert.Equal(t, event, <-received)
+	}
+	assert.Empty(t, received)
+
+	// Events of other users are not delivered
+	other, cancelOther := broadcaster.Subscribe(2)
+	defer cancelOther()
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/addData/TextData/1/d", `{"data":"x"}`))
+	assert.Empty(t, other)
+}
+
+func TestBaseController_GetEventsUserID(t *testing.T) {
+	broadcaster := events.NewBroadcaster()
+	srv := httptest.NewServer(Handler(NewBaseController(&mockStorage{}, nil, &mockLogger{}, &mockAuthz{}, nil, nil, broadcaster, nil)))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events/1", nil)
+	assert.NoError(t, err)
+	resp, err := srv.Client().Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// The subscription is in place once the headers have been sent
+	assert.NoError(t, broadcaster.Notify(context.Background(), 1,
+		models.Event{Type: models.EventItemChanged, Table: "TextData", ID: "a", Version: 3}))
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		if !assert.NoError(t, err) {
+			return
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	assert.Equal(t, "event: item-changed", lines[0])
+	assert.Equal(t, `data: {"type":"item-changed","table":"TextData","id":"a","version":3}`, lines[1])
+	assert.Empty(t, lines[2])
+
+	// Without a broadcaster the stream is not available
+	rr := httptest.NewRecorder()
+	Handler(NewBaseController(&mockStorage{}, nil, &mockLogger{}, &mockAuthz{}, nil, nil, nil, nil)).
+		ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/events/1", nil))
+	assert.Equal(t, http.StatusNotImplemented, rr.Code)
+}
+
+func TestBaseController_GetEventsUserID_Ends(t *testing.T) {
+	heartbeat := eventsHeartbeat
+	defer func() { eventsHeartbeat = heartbeat }()
+	eventsHeartbeat = 20 * time.Millisecond
+
+	tests := []struct {
+		name      string
+		sessions  map[string]models.Session
+		expiresIn time.Duration
+	}{
+		{"token expires", map[string]models.Session{"session-1": {ID: "session-1"}}, 50 * time.Millisecond},
+		{"session revoked", map[string]models.Session{}, time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &mockStorage{sessions: tt.sessions}
+			handler := Handler(NewBaseController(storage, nil, &mockLogger{}, &mockAuthz{}, nil, nil, events.NewBroadcaster(), nil))
+
+			// The authorization middleware puts the session and the token expiry into the context
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := context.WithValue(r.Context(), models.Key("sessionID"), "session-1")
+				ctx = context.WithValue(ctx, models.Key("tokenExpiresAt"), time.Now().Add(tt.expiresIn))
+				handler.ServeHTTP(w, r.WithContext(ctx))
+			}))
+			defer srv.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events/1", nil)
+			assert.NoError(t, err)
+			resp, err := srv.Client().Do(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			// The server closes the stream on its own
+			_, err = io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+		})
+	}
+}
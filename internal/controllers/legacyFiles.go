@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/blobstore"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+	"go.uber.org/zap"
+)
+
+// legacyFiles tracks the file entries whose content may still be stored directly in the
+// file storage directory, where files were kept before per-user directories.
+type legacyFiles struct {
+	mu      sync.Mutex
+	pending map[models.LegacyFile]bool
+}
+
+// add remembers files as not migrated.
+func (l *legacyFiles) add(files []models.LegacyFile) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.pending == nil {
+		l.pending = make(map[models.LegacyFile]bool)
+	}
+	for _, file := range files {
+		l.pending[file] = true
+	}
+}
+
+// remove forgets a migrated file.
+func (l *legacyFiles) remove(file models.LegacyFile) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.pending, file)
+}
+
+// contains reports whether a file is not migrated yet.
+func (l *legacyFiles) contains(file models.LegacyFile) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.pending[file]
+}
+
+// list returns the files that are not migrated yet.
+func (l *legacyFiles) list() []models.LegacyFile {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	files := make([]models.LegacyFile, 0, len(l.pending))
+	for file := range l.pending {
+		files = append(files, file)
+	}
+
+	return files
+}
+
+// LoadLegacyFiles remembers the file entries without a content digest, so that downloads
+// fall back to the old location of their content until MigrateLegacyFiles has moved it.
+func (h *BaseController) LoadLegacyFiles(ctx context.Context) error {
+	files, err := h.storage.LegacyFiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	h.legacy.add(files)
+
+	return nil
+}
+
+// MigrateLegacyFiles copies the content of the loaded legacy entries into the blob store
+// and records its size and digest. Entries that fail are logged and keep the download fallback.
+func (h *BaseController) MigrateLegacyFiles(ctx context.Context) {
+	migrated := 0
+	for _, file := range h.legacy.list() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err := h.migrateLegacyFile(ctx, file); err != nil {
+			h.log.Info("legacy file not migrated: ", zap.Int("userID", file.UserID),
+				zap.String("entryID", file.EntryID), zap.Error(err))
+			continue
+		}
+
+		h.legacy.remove(file)
+		migrated++
+	}
+
+	if migrated > 0 {
+		h.log.Info("legacy files migrated", zap.Int("count", migrated))
+	}
+}
+
+// migrateLegacyFile stores the content of one entry under its digest, records its size
+// and digest and removes the old copy. Entries without uploaded content are left as they are.
+func (h *BaseController) migrateLegacyFile(ctx context.Context, file models.LegacyFile) error {
+	if !validFileName(file.EntryID) {
+		return errors.New("invalid file name")
+	}
+
+	path := h.legacyFilePath(file.EntryID)
+	size, digest, err := hashFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// The old copy is removed only after the digest is recorded, so an interrupted
+	// migration is repeated on the next start
+	err = blobstore.StoreContent(ctx, h.blobs, digest, path, size, func() error {
+		return h.storage.SetFileContent(ctx, file.UserID, file.EntryID, size, digest)
+	})
+
+	// The entry may have been deleted meanwhile
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// hashFile returns the size and hex-encoded SHA-256 digest of the file at path.
+func hashFile(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// serveLegacyFile serves the content of an entry that is not migrated yet from its old location.
+func (h *BaseController) serveLegacyFile(w http.ResponseWriter, r *http.Request, entryID string) {
+	file, err := os.Open(h.legacyFilePath(entryID))
+	if errors.Is(err, fs.ErrNotExist) {
+		http.Error(w, "Файл не найден", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+
+	extendTransferDeadline(w)
+	http.ServeContent(w, r, entryID, time.Time{}, file)
+}
+
+// legacyFilePath returns the path at which the content of an entry was stored
+// before per-user directories.
+func (h *BaseController) legacyFilePath(entryID string) string {
+	return filepath.Join(h.options.FileStoragePath(), entryID)
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/gophkeeper-server/internal/blobstore"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+)
+
+func TestBaseController_MigrateLegacyFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "old"), []byte("hello"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other"), []byte("secret"), 0644))
+
+	storage := &mockStorage{legacyFiles: []models.LegacyFile{
+		{UserID: 1, EntryID: "old"},
+		{UserID: 1, EntryID: "never-uploaded"},
+		{UserID: 1, EntryID: "../escape"},
+	}}
+	controller := NewBaseController(storage, &mockOptions{fileStoragePath: dir}, &mockLogger{}, &mockAuthz{}, nil, blobstore.NewFileStore(dir), nil, nil)
+	handler := Handler(controller)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Before the migration the content is served from its old location, for its owner only
+	require.NoError(t, controller.LoadLegacyFiles(context.Background()))
+	rr := get("/getFile/1/old")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "hello", rr.Body.String())
+	assert.Equal(t, http.StatusNotFound, get("/getFile/2/old").Code)
+	assert.Equal(t, http.StatusNotFound, get("/getFile/1/other").Code)
+
+	controller.MigrateLegacyFiles(context.Background())
+
+	// The content is stored under its digest and the digest is recorded
+	digest := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	content, err := os.ReadFile(filepath.Join(dir, "sha256", "2c", digest))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+	assert.NoFileExists(t, filepath.Join(dir, "old"))
+	assert.Equal(t, digest, storage.files["old"])
+	assert.NotContains(t, storage.files, "never-uploaded")
+
+	rr = get("/getFile/1/old")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "hello", rr.Body.String())
+
+	// Only the entry with an invalid name is left for the next start
+	assert.Equal(t, []models.LegacyFile{{UserID: 1, EntryID: "../escape"}}, controller.legacy.list())
+}
@@ -0,0 +1,377 @@
+// Package cryptokeeper provides a keeper that encrypts the sensitive columns of vault
+// records before they reach another keeper and decrypts them on the way back, so that
+// a dump of the database does not reveal them.
+//
+// Every user has a random data key that encrypts the user's columns with AES-GCM.
+// The data key is stored wrapped by the current master key of the server together
+// with the ID of that key. The master keys themselves are never stored.
+package cryptokeeper
+
+import (
+	"context"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/keyring"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/schema"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+)
+
+// Keeper is a keeper that also stores the wrapped data keys of the users.
+type Keeper interface {
+	storage.Keeper
+	// GetDataKey returns the wrapped data key of a user, or storage.ErrNotFound if there is none.
+	GetDataKey(ctx context.Context, userID int) (string, error)
+	// AddDataKey stores the wrapped data key of a user unless the user already has one
+	// and returns the stored key.
+	AddDataKey(ctx context.Context, userID int, wrappedKey string) (string, error)
+	// ReplaceDataKey replaces the wrapped data key of a user if it is still oldKey
+	// and returns storage.ErrConflict otherwise.
+	ReplaceDataKey(ctx context.Context, userID int, oldKey, newKey string) error
+	// ListUserIDs returns the IDs of at most limit users greater than afterID in ascending order.
+	ListUserIDs(ctx context.Context, afterID int, limit int) ([]int, error)
+	// CountUsers returns the number of users.
+	CountUsers(ctx context.Context) (int64, error)
+	// RewriteColumns replaces the columns of a record, deleted or not, if they still hold
+	// the values in current, and returns storage.ErrConflict otherwise. It leaves the
+	// version, change sequence and update time of the record as they are.
+	RewriteColumns(ctx context.Context, table string, userID int, entryID string, current, replacement map[string]string) error
+}
+
+// CryptoKeeper encrypts the columns listed in schema.Schema.Encrypted and passes
+// everything else to the wrapped keeper unchanged.
+type CryptoKeeper struct {
+	Keeper
+
+	masterKeys *keyring.KeyRing
+	masters    map[string]cipher.AEAD
+
+	mu       sync.Mutex
+	dataKeys map[int]cipher.AEAD
+}
+
+// NewCryptoKeeper wraps keeper with encryption by the given master keys. New data keys
+// are wrapped by the current master key, the other keys unwrap the older data keys.
+func NewCryptoKeeper(keeper Keeper, masterKeys *keyring.KeyRing) (*CryptoKeeper, error) {
+	masters := make(map[string]cipher.AEAD)
+	for _, key := range masterKeys.Keys() {
+		if len(key.Secret) != KeySize {
+			return nil, fmt.Errorf("master key %q has %d bytes instead of %d", key.ID, len(key.Secret), KeySize)
+		}
+
+		master, err := newAEAD(key.Secret)
+		if err != nil {
+			return nil, err
+		}
+		masters[key.ID] = master
+	}
+
+	return &CryptoKeeper{
+		Keeper:     keeper,
+		masterKeys: masterKeys,
+		masters:    masters,
+		dataKeys:   make(map[int]cipher.AEAD),
+	}, nil
+}
+
+// CurrentKeyID returns the ID of the master key that wraps new data keys.
+func (ck *CryptoKeeper) CurrentKeyID() string {
+	return ck.masterKeys.Current().ID
+}
+
+// dataKey returns the data key of a user. Unless create is set, a user without
+// a data key is an error.
+func (ck *CryptoKeeper) dataKey(ctx context.Context, userID int, create bool) (cipher.AEAD, error) {
+	ck.mu.Lock()
+	aead, ok := ck.dataKeys[userID]
+	ck.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+
+	wrappedKey, err := ck.GetDataKey(ctx, userID)
+	if errors.Is(err, storage.ErrNotFound) && create {
+		wrappedKey, err = ck.addDataKey(ctx, userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("data key of user %d: %w", userID, err)
+	}
+
+	key, err := ck.unwrapDataKey(userID, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("data key of user %d: %w", userID, err)
+	}
+
+	aead, err = newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ck.mu.Lock()
+	ck.dataKeys[userID] = aead
+	ck.mu.Unlock()
+
+	return aead, nil
+}
+
+// addDataKey creates a data key for a user and returns the wrapped key that was
+// stored, which is another one if a concurrent request has stored its key first.
+func (ck *CryptoKeeper) addDataKey(ctx context.Context, userID int) (string, error) {
+	key, err := NewKey()
+	if err != nil {
+		return "", err
+	}
+
+	wrappedKey, err := ck.wrapDataKey(userID, key)
+	if err != nil {
+		return "", err
+	}
+
+	return ck.AddDataKey(ctx, userID, wrappedKey)
+}
+
+// wrapDataKey encrypts the data key of a user with the current master key. The wrapped
+// key is "<key ID>:<base64>", or only the base64 part if the key has no ID.
+func (ck *CryptoKeeper) wrapDataKey(userID int, key []byte) (string, error) {
+	id := ck.CurrentKeyID()
+
+	sealed, err := seal(ck.masters[id], key, dataKeyAD(userID))
+	if err != nil {
+		return "", err
+	}
+
+	wrappedKey := base64.StdEncoding.EncodeToString(sealed)
+	if id != "" {
+		wrappedKey = id + ":" + wrappedKey
+	}
+
+	return wrappedKey, nil
+}
+
+// unwrapDataKey decrypts a data key wrapped by wrapDataKey with the master key it names.
+func (ck *CryptoKeeper) unwrapDataKey(userID int, wrappedKey string) ([]byte, error) {
+	id, encoded := splitWrappedKey(wrappedKey)
+	master, ok := ck.masters[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", keyring.ErrUnknownKey, id)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	return open(master, sealed, dataKeyAD(userID))
+}
+
+// splitWrappedKey returns the ID of the master key that has wrapped a data key and
+// the base64 part. Base64 has no colons, so a key without one was wrapped by the key
+// without an ID.
+func splitWrappedKey(wrappedKey string) (string, string) {
+	id, encoded, found := strings.Cut(wrappedKey, ":")
+	if !found {
+		return "", wrappedKey
+	}
+
+	return id, encoded
+}
+
+// dataKeyAD is the additional data of a wrapped data key, so that a key cannot
+// be moved to another user.
+func dataKeyAD(userID int) []byte {
+	return []byte("DataKeys/" + strconv.Itoa(userID))
+}
+
+// columnAD is the additional data of an encrypted column, so that a value cannot
+// be moved to another column or record.
+func columnAD(table schema.Table, userID int, entryID string, column string) []byte {
+	return []byte(fmt.Sprintf("%s/%d/%s/%s", table, userID, entryID, column))
+}
+
+// encrypt returns a copy of data with the encrypted columns of table encrypted.
+// Data of unknown tables is returned as it is for the wrapped keeper to reject.
+func (ck *CryptoKeeper) encrypt(ctx context.Context, table string, userID int, entryID string, data map[string]string) (map[string]string, error) {
+	s, err := schema.Lookup(table)
+	if err != nil {
+		return data, nil
+	}
+
+	encrypted := make(map[string]string, len(data))
+	for column, value := range data {
+		encrypted[column] = value
+	}
+
+	for _, column := range s.Encrypted {
+		value, ok := data[column]
+		if !ok {
+			continue
+		}
+
+		aead, err := ck.dataKey(ctx, userID, true)
+		if err != nil {
+			return nil, err
+		}
+		encrypted[column], err = encryptValue(aead, value, columnAD(s.Table, userID, entryID, column))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return encrypted, nil
+}
+
+// decrypt decrypts the encrypted columns of a record of table in place.
+func (ck *CryptoKeeper) decrypt(ctx context.Context, table string, userID int, record map[string]string) error {
+	s, err := schema.Lookup(table)
+	if err != nil {
+		return nil
+	}
+
+	for _, column := range s.Encrypted {
+		value := record[column]
+		if !isEncrypted(value) {
+			continue
+		}
+
+		aead, err := ck.dataKey(ctx, userID, false)
+		if err != nil {
+			return err
+		}
+		record[column], err = decryptValue(aead, value, columnAD(s.Table, userID, record["id"], column))
+		if err != nil {
+			return fmt.Errorf("%s.%s of record %q: %w", s.Table, column, record["id"], err)
+		}
+	}
+
+	return nil
+}
+
+// decryptConflict decrypts the current record carried by a conflict error.
+// Other errors are returned as they are.
+func (ck *CryptoKeeper) decryptConflict(ctx context.Context, table string, userID int, err error) error {
+	var conflict *storage.ConflictError
+	if !errors.As(err, &conflict) || conflict.Current == nil {
+		return err
+	}
+
+	if decryptErr := ck.decrypt(ctx, table, userID, conflict.Current); decryptErr != nil {
+		return decryptErr
+	}
+
+	return err
+}
+
+// AddData encrypts the sensitive columns of a record and adds it.
+func (ck *CryptoKeeper) AddData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string) error {
+	encrypted, err := ck.encrypt(ctx, table, user_id, entry_id, data)
+	if err != nil {
+		return err
+	}
+
+	return ck.Keeper.AddData(ctx, table, user_id, entry_id, encrypted)
+}
+
+// UpdateData encrypts the sensitive columns of a record and updates it.
+// The current record of a conflict is returned decrypted.
+func (ck *CryptoKeeper) UpdateData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string, baseVersion int64) (int64, error) {
+	encrypted, err := ck.encrypt(ctx, table, user_id, entry_id, data)
+	if err != nil {
+		return 0, err
+	}
+
+	version, err := ck.Keeper.UpdateData(ctx, table, user_id, entry_id, encrypted, baseVersion)
+	if err != nil {
+		return 0, ck.decryptConflict(ctx, table, user_id, err)
+	}
+
+	return version, nil
+}
+
+// Batch encrypts the sensitive columns of the operations and applies them.
+// The current records of conflicts are returned decrypted.
+func (ck *CryptoKeeper) Batch(ctx context.Context, userID int, ops []models.BatchOperation, partial bool) ([]models.BatchResult, error) {
+	encrypted := make([]models.BatchOperation, len(ops))
+	for i, op := range ops {
+		encrypted[i] = op
+		if op.Data == nil {
+			continue
+		}
+
+		var err error
+		encrypted[i].Data, err = ck.encrypt(ctx, op.Table, userID, op.ID, op.Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results, err := ck.Keeper.Batch(ctx, userID, encrypted, partial)
+	for i := range results {
+		if results[i].Current == nil {
+			continue
+		}
+		if err := ck.decrypt(ctx, ops[i].Table, userID, results[i].Current); err != nil {
+			return nil, err
+		}
+	}
+
+	// The failed operation of an atomic batch carries the same record as its result
+	var batchErr *storage.BatchError
+	if errors.As(err, &batchErr) && batchErr.Index < len(ops) {
+		return results, ck.decryptConflict(ctx, ops[batchErr.Index].Table, userID, err)
+	}
+
+	return results, err
+}
+
+// GetData retrieves a record and decrypts its sensitive columns.
+func (ck *CryptoKeeper) GetData(ctx context.Context, table string, userID int, entryID string) (map[string]string, error) {
+	record, err := ck.Keeper.GetData(ctx, table, userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ck.decrypt(ctx, table, userID, record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// GetAllData retrieves records and decrypts their sensitive columns.
+func (ck *CryptoKeeper) GetAllData(ctx context.Context, table string, user_id int, last_sync time.Time, incl_del bool) ([]map[string]string, error) {
+	records, err := ck.Keeper.GetAllData(ctx, table, user_id, last_sync, incl_del)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if err := ck.decrypt(ctx, table, user_id, record); err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+// Sync retrieves changed records and decrypts their sensitive columns.
+func (ck *CryptoKeeper) Sync(ctx context.Context, userID int, afterSeq int64, limit int) ([]models.Change, bool, error) {
+	changes, more, err := ck.Keeper.Sync(ctx, userID, afterSeq, limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, change := range changes {
+		if err := ck.decrypt(ctx, change.Table, userID, change.Entry); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return changes, more, nil
+}
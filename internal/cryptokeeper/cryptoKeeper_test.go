@@ -0,0 +1,308 @@
+package cryptokeeper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/gophkeeper-server/internal/keyring"
+	"github.com/wurt83ow/gophkeeper-server/internal/memkeeper"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage/storagetest"
+	"go.uber.org/zap"
+)
+
+var masterKey = bytes.Repeat([]byte{7}, KeySize)
+
+// newKeyRing returns a key ring of the given keys, the current key first.
+func newKeyRing(t *testing.T, keys ...keyring.Key) *keyring.KeyRing {
+	ring, err := keyring.New(keys...)
+	require.NoError(t, err)
+
+	return ring
+}
+
+// newTestKeeper returns a CryptoKeeper together with the keeper it encrypts for.
+func newTestKeeper(t *testing.T) (*CryptoKeeper, *memkeeper.MemKeeper) {
+	inner := memkeeper.NewMemKeeper()
+	keeper, err := NewCryptoKeeper(inner, newKeyRing(t, keyring.Key{Secret: masterKey}))
+	require.NoError(t, err)
+
+	return keeper, inner
+}
+
+// addUser registers a user and returns its ID.
+func addUser(t *testing.T, k storage.Keeper, username string) int {
+	t.Helper()
+	ctx := context.Background()
+
+	require.NoError(t, k.AddUser(ctx, username, "hash"))
+	userID, err := k.GetUserID(ctx, username)
+	require.NoError(t, err)
+
+	return userID
+}
+
+func TestCryptoKeeper(t *testing.T) {
+	// Encryption is transparent to the storage layer
+	storagetest.Run(t, func(t *testing.T) storage.Keeper {
+		keeper, _ := newTestKeeper(t)
+		return keeper
+	})
+}
+
+func TestCryptoKeeper_EncryptsAtRest(t *testing.T) {
+	ctx := context.Background()
+	keeper, inner := newTestKeeper(t)
+	userID := addUser(t, keeper, "alice")
+
+	require.NoError(t, keeper.AddData(ctx, "UserCredentials", userID, "site",
+		map[string]string{"login": "alice", "password": "secret", "meta_info": "mail"}))
+
+	stored, err := inner.GetData(ctx, "UserCredentials", userID, "site")
+	require.NoError(t, err)
+	assert.True(t, isEncrypted(stored["login"]))
+	assert.True(t, isEncrypted(stored["password"]))
+	assert.NotContains(t, stored["password"], "secret")
+	assert.Equal(t, "mail", stored["meta_info"])
+
+	record, err := keeper.GetData(ctx, "UserCredentials", userID, "site")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", record["login"])
+	assert.Equal(t, "secret", record["password"])
+
+	// The data key is stored wrapped
+	wrappedKey, err := inner.GetDataKey(ctx, userID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, wrappedKey)
+
+	// Another server with the same master key reads the records
+	other, err := NewCryptoKeeper(inner, newKeyRing(t, keyring.Key{Secret: masterKey}))
+	require.NoError(t, err)
+	records, err := other.GetAllData(ctx, "UserCredentials", userID, time.Time{}, false)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "secret", records[0]["password"])
+}
+
+func TestCryptoKeeper_WrongMasterKey(t *testing.T) {
+	ctx := context.Background()
+	keeper, inner := newTestKeeper(t)
+	userID := addUser(t, keeper, "alice")
+	require.NoError(t, keeper.AddData(ctx, "TextData", userID, "note", map[string]string{"data": "text"}))
+
+	other, err := NewCryptoKeeper(inner, newKeyRing(t, keyring.Key{Secret: bytes.Repeat([]byte{8}, KeySize)}))
+	require.NoError(t, err)
+
+	_, err = other.GetData(ctx, "TextData", userID, "note")
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestCryptoKeeper_MovedValue(t *testing.T) {
+	ctx := context.Background()
+	keeper, inner := newTestKeeper(t)
+	userID := addUser(t, keeper, "alice")
+	require.NoError(t, keeper.AddData(ctx, "TextData", userID, "first", map[string]string{"data": "first"}))
+	require.NoError(t, keeper.AddData(ctx, "TextData", userID, "second", map[string]string{"data": "second"}))
+
+	// A value copied to another record in the database does not decrypt there
+	stored, err := inner.GetData(ctx, "TextData", userID, "first")
+	require.NoError(t, err)
+	_, err = inner.UpdateData(ctx, "TextData", userID, "second", map[string]string{"data": stored["data"]}, 0)
+	require.NoError(t, err)
+
+	_, err = keeper.GetData(ctx, "TextData", userID, "second")
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestCryptoKeeper_PlaintextRecords(t *testing.T) {
+	ctx := context.Background()
+	keeper, inner := newTestKeeper(t)
+	userID := addUser(t, keeper, "alice")
+
+	// Records stored before encryption was enabled are read as they are
+	require.NoError(t, inner.AddData(ctx, "TextData", userID, "old", map[string]string{"data": "plain"}))
+
+	record, err := keeper.GetData(ctx, "TextData", userID, "old")
+	require.NoError(t, err)
+	assert.Equal(t, "plain", record["data"])
+
+	_, err = inner.GetDataKey(ctx, userID)
+	assert.True(t, errors.Is(err, storage.ErrNotFound))
+}
+
+func TestCryptoKeeper_Conflict(t *testing.T) {
+	ctx := context.Background()
+	keeper, _ := newTestKeeper(t)
+	userID := addUser(t, keeper, "alice")
+	require.NoError(t, keeper.AddData(ctx, "TextData", userID, "note", map[string]string{"data": "text"}))
+
+	_, err := keeper.UpdateData(ctx, "TextData", userID, "note", map[string]string{"data": "stale"}, 5)
+	var conflict *storage.ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, "text", conflict.Current["data"])
+
+	results, err := keeper.Batch(ctx, userID, []models.BatchOperation{
+		{Op: models.BatchUpdate, Table: "TextData", ID: "note", Data: map[string]string{"data": "stale"}, BaseVersion: 5},
+	}, true)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "text", results[0].Current["data"])
+}
+
+func TestCryptoKeeper_KeyRotation(t *testing.T) {
+	ctx := context.Background()
+	keeper, inner := newTestKeeper(t)
+	userID := addUser(t, keeper, "alice")
+	require.NoError(t, keeper.AddData(ctx, "TextData", userID, "note", map[string]string{"data": "text"}))
+
+	// A new current key reads the data keys wrapped by the old key, which it still has
+	newKey := keyring.Key{ID: "2024", Secret: bytes.Repeat([]byte{9}, KeySize)}
+	rotated, err := NewCryptoKeeper(inner, newKeyRing(t, newKey, keyring.Key{Secret: masterKey}))
+	require.NoError(t, err)
+
+	record, err := rotated.GetData(ctx, "TextData", userID, "note")
+	require.NoError(t, err)
+	assert.Equal(t, "text", record["data"])
+
+	// New data keys are wrapped by the new key and name it
+	bob := addUser(t, rotated, "bob")
+	require.NoError(t, rotated.AddData(ctx, "TextData", bob, "memo", map[string]string{"data": "text"}))
+	wrappedKey, err := inner.GetDataKey(ctx, bob)
+	require.NoError(t, err)
+	assert.Regexp(t, "^2024:", wrappedKey)
+
+	// Without the old key the old data keys cannot be unwrapped
+	dropped, err := NewCryptoKeeper(inner, newKeyRing(t, newKey))
+	require.NoError(t, err)
+	_, err = dropped.GetData(ctx, "TextData", userID, "note")
+	assert.ErrorIs(t, err, keyring.ErrUnknownKey)
+
+	_, err = NewCryptoKeeper(inner, newKeyRing(t, keyring.Key{Secret: []byte("short")}))
+	assert.Error(t, err)
+}
+
+func TestReencryption(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	keeper, inner := newTestKeeper(t)
+	alice := addUser(t, keeper, "alice")
+	bob := addUser(t, keeper, "bob")
+	addUser(t, keeper, "carol")
+	require.NoError(t, keeper.AddData(ctx, "TextData", alice, "note", map[string]string{"data": "text"}))
+	require.NoError(t, keeper.AddData(ctx, "UserCredentials", bob, "site", map[string]string{"login": "bob", "password": "secret"}))
+
+	// A record stored before encryption was enabled
+	require.NoError(t, inner.AddData(ctx, "CreditCardData", bob, "card",
+		map[string]string{"card_number": "4111", "expiration_date": "12/30", "cvv": "123"}))
+	require.NoError(t, inner.AddData(ctx, "TextData", bob, "old", map[string]string{"data": "plain"}))
+	require.NoError(t, inner.DeleteData(ctx, "TextData", bob, "old"))
+	before, err := inner.GetData(ctx, "CreditCardData", bob, "card")
+	require.NoError(t, err)
+	seq, err := inner.LastChangeSeq(ctx, bob)
+	require.NoError(t, err)
+
+	newKey := keyring.Key{ID: "2024", Secret: bytes.Repeat([]byte{9}, KeySize)}
+	rotated, err := NewCryptoKeeper(inner, newKeyRing(t, newKey, keyring.Key{Secret: masterKey}))
+	require.NoError(t, err)
+
+	job := NewReencryption(rotated, 2, zap.NewNop())
+	go job.Run(ctx)
+
+	progress, err := job.Start()
+	require.NoError(t, err)
+	assert.True(t, progress.Running)
+	assert.Equal(t, "2024", progress.KeyID)
+
+	require.Eventually(t, func() bool { return !job.Progress().Running }, 5*time.Second, 10*time.Millisecond)
+	progress = job.Progress()
+	assert.Empty(t, progress.Error)
+	assert.Equal(t, int64(3), progress.Users)
+	assert.Equal(t, int64(3), progress.Total)
+	assert.Equal(t, int64(2), progress.DataKeys)
+	assert.Equal(t, int64(2), progress.Records)
+	assert.NotNil(t, progress.FinishedAt)
+
+	// The old master key is no longer needed
+	dropped, err := NewCryptoKeeper(inner, newKeyRing(t, newKey))
+	require.NoError(t, err)
+
+	record, err := dropped.GetData(ctx, "TextData", alice, "note")
+	require.NoError(t, err)
+	assert.Equal(t, "text", record["data"])
+
+	stored, err := inner.GetData(ctx, "CreditCardData", bob, "card")
+	require.NoError(t, err)
+	assert.True(t, isEncrypted(stored["card_number"]))
+	assert.Equal(t, "12/30", stored["expiration_date"])
+
+	// The rewrite is not a change the devices have to sync
+	assert.Equal(t, before["version"], stored["version"])
+	assert.Equal(t, before["updated_at"], stored["updated_at"])
+	lastSeq, err := inner.LastChangeSeq(ctx, bob)
+	require.NoError(t, err)
+	assert.Equal(t, seq, lastSeq)
+
+	// Deleted records are encrypted as well
+	deleted, err := inner.GetAllData(ctx, "TextData", bob, time.Time{}, true)
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.True(t, isEncrypted(deleted[0]["data"]))
+
+	record, err = dropped.GetData(ctx, "CreditCardData", bob, "card")
+	require.NoError(t, err)
+	assert.Equal(t, "4111", record["card_number"])
+	assert.Equal(t, "123", record["cvv"])
+
+	// A second run finds nothing to do
+	_, err = job.Start()
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return !job.Progress().Running }, 5*time.Second, 10*time.Millisecond)
+	progress = job.Progress()
+	assert.Equal(t, int64(3), progress.Users)
+	assert.Zero(t, progress.DataKeys)
+	assert.Zero(t, progress.Records)
+}
+
+func TestReencryption_Running(t *testing.T) {
+	keeper, _ := newTestKeeper(t)
+
+	// Without Run the first run never finishes
+	job := NewReencryption(keeper, 10, zap.NewNop())
+	_, err := job.Start()
+	require.NoError(t, err)
+
+	_, err = job.Start()
+	assert.ErrorIs(t, err, storage.ErrReencryptionRunning)
+}
+
+func TestLoadMasterKeys(t *testing.T) {
+	encoded := "BwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwc="
+
+	ring, err := LoadMasterKeys(encoded, "")
+	require.NoError(t, err)
+	assert.Equal(t, keyring.Key{Secret: masterKey}, ring.Current())
+
+	// The key file takes precedence and holds a key per line
+	file := filepath.Join(t.TempDir(), "master.key")
+	require.NoError(t, os.WriteFile(file, []byte("2024:"+encoded+"\n"+encoded+"\n"), 0o600))
+	ring, err = LoadMasterKeys("ignored", file)
+	require.NoError(t, err)
+	assert.Equal(t, "2024", ring.Current().ID)
+	assert.Len(t, ring.Keys(), 2)
+
+	ring, err = LoadMasterKeys("", "")
+	require.NoError(t, err)
+	assert.Nil(t, ring)
+
+	_, err = LoadMasterKeys("c2hvcnQ=", "")
+	assert.Error(t, err)
+}
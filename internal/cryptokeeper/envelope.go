@@ -0,0 +1,132 @@
+package cryptokeeper
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/keyring"
+)
+
+// KeySize is the size of master and data keys in bytes, which selects AES-256.
+const KeySize = 32
+
+// prefix marks an encrypted column value. Values without it were stored before
+// encryption was enabled and are returned as they are.
+const prefix = "enc:v1:"
+
+// ErrDecrypt indicates a value that cannot be decrypted, because it was altered,
+// moved to another record or encrypted with another key.
+var ErrDecrypt = errors.New("cannot decrypt value")
+
+// ParseMasterKey decodes a base64-encoded master key.
+func ParseMasterKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("master key is not base64: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("master key has %d bytes instead of %d", len(key), KeySize)
+	}
+
+	return key, nil
+}
+
+// LoadMasterKeys returns the master keys given directly or read from a file, the file
+// taking precedence. The keys are a list of "[id:]base64" entries separated by commas
+// or line breaks, the current key first. It returns nil if neither is set.
+func LoadMasterKeys(spec string, file string) (*keyring.KeyRing, error) {
+	if file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		spec = string(content)
+	}
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	return keyring.Parse(spec, ParseMasterKey)
+}
+
+// NewKey returns a random key for AES-256.
+func NewKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// newAEAD returns AES-GCM with the given key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext with a random nonce and returns the nonce followed by
+// the ciphertext. The additional data binds the ciphertext to its place.
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts the output of seal.
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	return plaintext, nil
+}
+
+// isEncrypted reports whether a column value was written by encryptValue.
+func isEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// encryptValue encrypts a column value into printable text.
+func encryptValue(aead cipher.AEAD, value string, additionalData []byte) (string, error) {
+	sealed, err := seal(aead, []byte(value), additionalData)
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptValue decrypts the output of encryptValue.
+func decryptValue(aead cipher.AEAD, value string, additionalData []byte) (string, error) {
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(value, prefix))
+	if err != nil {
+		return "", ErrDecrypt
+	}
+
+	plaintext, err := open(aead, sealed, additionalData)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
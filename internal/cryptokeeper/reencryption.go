@@ -0,0 +1,233 @@
+package cryptokeeper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/schema"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Log is an interface for logging operations.
+type Log interface {
+	Info(string, ...zapcore.Field)
+}
+
+// Reencryption moves the stored data of all users to the current master key, so that
+// older master keys can be dropped. The data keys wrapped by older master keys are
+// wrapped again, which leaves the records as they are, and records stored before
+// encryption was enabled are encrypted. The users are processed in batches while the
+// server keeps serving them.
+type Reencryption struct {
+	keeper    *CryptoKeeper
+	batchSize int
+	log       Log
+	start     chan struct{}
+
+	mu       sync.Mutex
+	progress models.ReencryptionProgress
+}
+
+// NewReencryption creates a re-encryption job for the users of keeper that processes
+// batchSize users at a time.
+func NewReencryption(keeper *CryptoKeeper, batchSize int, log Log) *Reencryption {
+	return &Reencryption{
+		keeper:    keeper,
+		batchSize: batchSize,
+		log:       log,
+		start:     make(chan struct{}, 1),
+	}
+}
+
+// Start requests a re-encryption run from Run and returns its initial progress.
+// It returns storage.ErrReencryptionRunning if a run has not finished yet.
+func (r *Reencryption) Start() (models.ReencryptionProgress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.progress.Running {
+		return r.progress, storage.ErrReencryptionRunning
+	}
+
+	now := time.Now().UTC()
+	r.progress = models.ReencryptionProgress{
+		Running:   true,
+		KeyID:     r.keeper.CurrentKeyID(),
+		StartedAt: &now,
+	}
+	r.start <- struct{}{}
+
+	return r.progress, nil
+}
+
+// Progress returns the progress of the current or the latest run.
+func (r *Reencryption) Progress() models.ReencryptionProgress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.progress
+}
+
+// Run performs the requested re-encryption runs until ctx is done.
+func (r *Reencryption) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.start:
+			err := r.reencrypt(ctx)
+			if err != nil {
+				r.log.Info("re-encryption failed: ", zap.Error(err))
+			}
+			r.finish(err)
+		}
+	}
+}
+
+// reencrypt processes all users in batches.
+func (r *Reencryption) reencrypt(ctx context.Context) error {
+	total, err := r.keeper.CountUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.progress.Total = total
+	r.mu.Unlock()
+
+	afterID := 0
+	for {
+		userIDs, err := r.keeper.ListUserIDs(ctx, afterID, r.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+
+		for _, userID := range userIDs {
+			rewrapped, err := r.keeper.rewrapDataKey(ctx, userID)
+			if err != nil {
+				return err
+			}
+			records, err := r.keeper.encryptRecords(ctx, userID)
+			if err != nil {
+				return err
+			}
+
+			r.mu.Lock()
+			r.progress.Users++
+			if rewrapped {
+				r.progress.DataKeys++
+			}
+			r.progress.Records += int64(records)
+			r.mu.Unlock()
+		}
+
+		afterID = userIDs[len(userIDs)-1]
+	}
+}
+
+// finish records the end of a run.
+func (r *Reencryption) finish(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	r.progress.Running = false
+	r.progress.FinishedAt = &now
+	if err != nil {
+		r.progress.Error = err.Error()
+	}
+}
+
+// rewrapDataKey wraps the data key of a user with the current master key, unless it
+// already is or the user has none. It reports whether the key was wrapped again.
+func (ck *CryptoKeeper) rewrapDataKey(ctx context.Context, userID int) (bool, error) {
+	wrappedKey, err := ck.GetDataKey(ctx, userID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if id, _ := splitWrappedKey(wrappedKey); id == ck.CurrentKeyID() {
+		return false, nil
+	}
+
+	key, err := ck.unwrapDataKey(userID, wrappedKey)
+	if err != nil {
+		return false, err
+	}
+	rewrappedKey, err := ck.wrapDataKey(userID, key)
+	if err != nil {
+		return false, err
+	}
+
+	// Another server has wrapped the key in the meantime
+	err = ck.ReplaceDataKey(ctx, userID, wrappedKey, rewrappedKey)
+	if errors.Is(err, storage.ErrConflict) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// encryptRecords encrypts the columns of a user's records, deleted ones included, that
+// were stored unencrypted and returns the number of records that were rewritten. The
+// records keep their versions, since their data does not change. Records changed by
+// their owner in the meantime are skipped, since the change has encrypted them.
+func (ck *CryptoKeeper) encryptRecords(ctx context.Context, userID int) (int, error) {
+	rewritten := 0
+	for _, table := range schema.Tables() {
+		s, err := schema.Lookup(string(table))
+		if err != nil {
+			return rewritten, err
+		}
+		if len(s.Encrypted) == 0 {
+			continue
+		}
+
+		records, err := ck.Keeper.GetAllData(ctx, string(table), userID, time.Time{}, true)
+		if err != nil {
+			return rewritten, err
+		}
+
+		for _, record := range records {
+			plain := make(map[string]string)
+			for _, column := range s.Encrypted {
+				if value := record[column]; value != "" && !isEncrypted(value) {
+					plain[column] = value
+				}
+			}
+			if len(plain) == 0 {
+				continue
+			}
+
+			encrypted, err := ck.encrypt(ctx, string(table), userID, record["id"], plain)
+			if err != nil {
+				return rewritten, err
+			}
+
+			err = ck.Keeper.RewriteColumns(ctx, string(table), userID, record["id"], plain, encrypted)
+			if errors.Is(err, storage.ErrConflict) {
+				continue
+			}
+			if err != nil {
+				return rewritten, err
+			}
+			rewritten++
+		}
+	}
+
+	return rewritten, nil
+}
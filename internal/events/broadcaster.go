@@ -0,0 +1,87 @@
+// Package events delivers notifications about changed vault records to the connected
+// devices of their users.
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"go.uber.org/zap/zapcore"
+)
+
+// subscriberBuffer is the number of events a subscriber may fall behind before it is dropped.
+const subscriberBuffer = 64
+
+// Log is an interface for logging operations.
+type Log interface {
+	Info(string, ...zapcore.Field)
+}
+
+// Broadcaster delivers events to the subscribers of the same process.
+type Broadcaster struct {
+	mu          sync.Mutex
+	subscribers map[int]map[chan models.Event]struct{}
+}
+
+// NewBroadcaster creates a Broadcaster without subscribers.
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{
+		subscribers: make(map[int]map[chan models.Event]struct{}),
+	}
+}
+
+// Subscribe returns a channel with the events of the user and a function that ends the subscription.
+// The channel is closed when the subscription ends. A subscriber that does not keep up
+// is dropped, since it has to resynchronize anyway after missing events.
+func (b *Broadcaster) Subscribe(userID int) (<-chan models.Event, func()) {
+	ch := make(chan models.Event, subscriberBuffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan models.Event]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subscribers[userID][ch]; ok {
+			b.remove(userID, ch)
+		}
+	}
+
+	return ch, cancel
+}
+
+// Notify delivers the event to the subscribers of the user.
+func (b *Broadcaster) Notify(ctx context.Context, userID int, event models.Event) error {
+	b.Deliver(userID, event)
+	return nil
+}
+
+// Deliver sends the event to the subscribers of the user without waiting for them.
+func (b *Broadcaster) Deliver(userID int, event models.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[userID] {
+		select {
+		case ch <- event:
+		default:
+			b.remove(userID, ch)
+		}
+	}
+}
+
+// remove ends a subscription. The caller must hold the lock.
+func (b *Broadcaster) remove(userID int, ch chan models.Event) {
+	delete(b.subscribers[userID], ch)
+	if len(b.subscribers[userID]) == 0 {
+		delete(b.subscribers, userID)
+	}
+	close(ch)
+}
@@ -0,0 +1,55 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+)
+
+func TestBroadcaster(t *testing.T) {
+	b := NewBroadcaster()
+
+	first, cancelFirst := b.Subscribe(1)
+	second, cancelSecond := b.Subscribe(1)
+	other, cancelOther := b.Subscribe(2)
+	defer cancelSecond()
+	defer cancelOther()
+
+	event := models.Event{Type: models.EventItemChanged, Table: "TextData", ID: "note", Version: 2}
+	require.NoError(t, b.Notify(context.Background(), 1, event))
+
+	// Every subscriber of the user gets the event, other users get nothing
+	assert.Equal(t, event, <-first)
+	assert.Equal(t, event, <-second)
+	assert.Empty(t, other)
+
+	// A cancelled subscription is closed and gets no more events
+	cancelFirst()
+	cancelFirst()
+	b.Deliver(1, event)
+
+	_, ok := <-first
+	assert.False(t, ok)
+	assert.Equal(t, event, <-second)
+}
+
+func TestBroadcaster_SlowSubscriber(t *testing.T) {
+	b := NewBroadcaster()
+
+	events, cancel := b.Subscribe(1)
+	defer cancel()
+
+	// The subscriber is dropped once its buffer is full
+	for i := 0; i <= subscriberBuffer; i++ {
+		b.Deliver(1, models.Event{Type: models.EventItemDeleted, ID: "note"})
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+}
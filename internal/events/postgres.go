@@ -0,0 +1,121 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	_ "github.com/jackc/pgx/v5/stdlib" // registers a pgx driver.
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"go.uber.org/zap"
+)
+
+// Channel is the Postgres notification channel the events are published on.
+const Channel = "gophkeeper_events"
+
+// reconnectInterval is how long PGBroker waits before it listens again after a failure.
+const reconnectInterval = 5 * time.Second
+
+// notification is the payload of a Postgres notification.
+type notification struct {
+	UserID int          `json:"user_id"`
+	Event  models.Event `json:"event"`
+}
+
+// PGBroker publishes events with Postgres NOTIFY and delivers the notifications it listens to
+// to its local subscribers, so that events reach the devices connected to any server instance.
+// Events published while the listener reconnects are lost.
+type PGBroker struct {
+	*Broadcaster
+
+	db  *sql.DB
+	dsn string
+	log Log
+}
+
+// NewPGBroker creates a PGBroker for the database at dsn. The notifications are received
+// only while Run is running.
+func NewPGBroker(dsn string, log Log) (*PGBroker, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPGBroker(db, dsn, log), nil
+}
+
+func newPGBroker(db *sql.DB, dsn string, log Log) *PGBroker {
+	return &PGBroker{
+		Broadcaster: NewBroadcaster(),
+		db:          db,
+		dsn:         dsn,
+		log:         log,
+	}
+}
+
+// Notify publishes the event to the listeners of all server instances.
+func (p *PGBroker) Notify(ctx context.Context, userID int, event models.Event) error {
+	payload, err := json.Marshal(notification{UserID: userID, Event: event})
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", Channel, string(payload))
+	return err
+}
+
+// Run listens to the notifications until ctx is done, reconnecting after failures.
+func (p *PGBroker) Run(ctx context.Context) {
+	for {
+		err := p.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		p.log.Info("event listener failed: ", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectInterval):
+		}
+	}
+}
+
+// listen delivers notifications on a dedicated connection until it fails.
+func (p *PGBroker) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, p.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		p.deliverPayload(n.Payload)
+	}
+}
+
+// deliverPayload sends the event of a notification to the local subscribers.
+func (p *PGBroker) deliverPayload(payload string) {
+	var n notification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		p.log.Info("invalid event notification: ", zap.Error(err))
+		return
+	}
+
+	p.Deliver(n.UserID, n.Event)
+}
+
+// Close closes the connections used to publish events.
+func (p *PGBroker) Close() error {
+	return p.db.Close()
+}
@@ -0,0 +1,42 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"go.uber.org/zap/zapcore"
+)
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(string, ...zapcore.Field) {}
+
+func TestPGBroker_Notify(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	broker := newPGBroker(db, "", &mockLogger{})
+	events, cancel := broker.Subscribe(7)
+	defer cancel()
+
+	mock.ExpectExec(`SELECT pg_notify\(\$1, \$2\)`).
+		WithArgs(Channel, `{"user_id":7,"event":{"type":"item-changed","table":"TextData","id":"note","version":3}}`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	event := models.Event{Type: models.EventItemChanged, Table: "TextData", ID: "note", Version: 3}
+	require.NoError(t, broker.Notify(context.Background(), 7, event))
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// Events reach the subscribers only through the listener
+	assert.Empty(t, events)
+
+	broker.deliverPayload(`{"user_id":7,"event":{"type":"item-changed","table":"TextData","id":"note","version":3}}`)
+	broker.deliverPayload(`invalid`)
+	assert.Equal(t, event, <-events)
+	assert.Empty(t, events)
+}
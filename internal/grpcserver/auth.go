@@ -0,0 +1,209 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+	"github.com/wurt83ow/gophkeeper-server/pkg/pb"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errUnauthenticated is returned for failed logins without telling the reason.
+var errUnauthenticated = status.Error(codes.Unauthenticated, "Unauthorized")
+
+// Register registers a user with a password.
+func (s *Server) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	if req.GetUsername() == "" || req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "username and password must be specified")
+	}
+
+	// The server always derives the stored verifier itself
+	hashedPassword, err := s.authz.HashPassword(req.GetPassword())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := s.storage.AddUser(ctx, req.GetUsername(), hashedPassword); err != nil {
+		return nil, storageError(err)
+	}
+
+	return &pb.RegisterResponse{}, nil
+}
+
+// Login logs a user in with a password.
+func (s *Server) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	hashedPassword, err := s.storage.GetPassword(ctx, req.GetUsername())
+	if err != nil {
+		return nil, errUnauthenticated
+	}
+
+	ok, needsRehash := s.authz.VerifyPassword(hashedPassword, req.GetPassword())
+	if !ok {
+		return nil, errUnauthenticated
+	}
+
+	// Upgrade verifiers made by an outdated algorithm or with outdated parameters
+	if needsRehash {
+		s.rehashPassword(ctx, req.GetUsername(), req.GetPassword())
+	}
+
+	userID, err := s.storage.GetUserID(ctx, req.GetUsername())
+	if err != nil {
+		return nil, errUnauthenticated
+	}
+
+	mfa, err := s.storage.GetMFA(ctx, userID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if !mfa.Enabled {
+		return s.startSession(ctx, userID, req.GetDeviceId(), req.GetDeviceName())
+	}
+
+	challengeToken, err := s.authz.CreateMFAChallenge(userID, req.GetDeviceId(), req.GetDeviceName())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.LoginResponse{MfaRequired: true, ChallengeToken: challengeToken}, nil
+}
+
+// LoginMFA completes a login with a one-time or recovery code.
+func (s *Server) LoginMFA(ctx context.Context, req *pb.LoginMFARequest) (*pb.LoginResponse, error) {
+	if req.GetChallengeToken() == "" || (req.GetCode() == "" && req.GetRecoveryCode() == "") {
+		return nil, status.Error(codes.InvalidArgument, "challenge_token and code or recovery_code must be specified")
+	}
+
+	challenge, err := s.authz.DecodeMFAChallenge(req.GetChallengeToken())
+	if err != nil {
+		return nil, errUnauthenticated
+	}
+
+	// Each challenge allows a single attempt, so codes can not be guessed with one password check
+	revoked, err := s.storage.IsTokenRevoked(ctx, challenge.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if revoked {
+		return nil, errUnauthenticated
+	}
+
+	if err := s.storage.RevokeToken(ctx, challenge.ID, challenge.ExpiresAt); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	mfa, err := s.storage.GetMFA(ctx, challenge.UserID)
+	if err != nil || !mfa.Enabled {
+		return nil, errUnauthenticated
+	}
+
+	if req.GetCode() != "" {
+		step, ok := s.authz.ValidateTOTP(mfa.Secret, req.GetCode())
+		if !ok {
+			return nil, errUnauthenticated
+		}
+
+		// A code can not be used twice
+		err = s.storage.UseTOTPStep(ctx, challenge.UserID, step)
+	} else {
+		err = s.storage.UseRecoveryCode(ctx, challenge.UserID, s.authz.HashRecoveryCode(req.GetRecoveryCode()))
+	}
+	if errors.Is(err, storage.ErrConflict) || errors.Is(err, storage.ErrNotFound) {
+		return nil, errUnauthenticated
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return s.startSession(ctx, challenge.UserID, challenge.DeviceID, challenge.DeviceName)
+}
+
+// RefreshToken exchanges a refresh token for new tokens.
+func (s *Server) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.LoginResponse, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token must be specified")
+	}
+
+	// Each refresh token can be used only once, a new one is issued instead
+	userID, sessionID, err := s.storage.ConsumeRefreshToken(ctx, s.authz.HashRefreshToken(req.GetRefreshToken()))
+	if err != nil {
+		return nil, errUnauthenticated
+	}
+
+	// A refresh means the device is still in use
+	if err := s.storage.TouchSession(ctx, sessionID, clientIP(ctx)); err != nil {
+		s.log.Info("Error occurred updating session", zap.Error(err))
+	}
+
+	return s.issueTokens(ctx, &pb.LoginResponse{UserId: int64(userID), SessionId: sessionID})
+}
+
+// startSession registers a session for the device of an authenticated user and issues the tokens.
+// Clients that do not send a device ID get a new one.
+func (s *Server) startSession(ctx context.Context, userID int, deviceID, deviceName string) (*pb.LoginResponse, error) {
+	sessionID, err := s.authz.CreateSessionID()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if deviceID == "" {
+		deviceID, err = s.authz.CreateSessionID()
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	err = s.storage.AddSession(ctx, userID, models.Session{
+		ID:         sessionID,
+		DeviceID:   deviceID,
+		DeviceName: deviceName,
+		IP:         clientIP(ctx),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return s.issueTokens(ctx, &pb.LoginResponse{UserId: int64(userID), SessionId: sessionID, DeviceId: deviceID})
+}
+
+// issueTokens creates an access token and a refresh token for the user session of the response.
+func (s *Server) issueTokens(ctx context.Context, resp *pb.LoginResponse) (*pb.LoginResponse, error) {
+	resp.Token = s.authz.CreateJWTTokenForUser(strconv.FormatInt(resp.UserId, 10), resp.SessionId)
+	if resp.Token == "" {
+		return nil, status.Error(codes.Internal, "failed to create token")
+	}
+
+	// Only the hash of the refresh token is stored
+	refreshToken, refreshTokenHash, expiresAt, err := s.authz.CreateRefreshToken()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	err = s.storage.AddRefreshToken(ctx, refreshTokenHash, int(resp.UserId), resp.SessionId, expiresAt)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp.RefreshToken = refreshToken
+
+	return resp, nil
+}
+
+// rehashPassword replaces the stored verifier of the user with a fresh one.
+// Failures are only logged, since the user has already been authenticated.
+func (s *Server) rehashPassword(ctx context.Context, username, password string) {
+	hashedPassword, err := s.authz.HashPassword(password)
+	if err != nil {
+		s.log.Info("Error occurred rehashing password", zap.Error(err))
+		return
+	}
+
+	if err := s.storage.UpdatePassword(ctx, username, hashedPassword); err != nil {
+		s.log.Info("Error occurred updating password hash", zap.Error(err))
+	}
+}
@@ -0,0 +1,390 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/schema"
+	"github.com/wurt83ow/gophkeeper-server/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The fields of the Credentials, Card, Text and Binary messages are named after
+// the columns of their tables, so records are converted by reflection.
+
+// recordData returns the fields of a record kind message keyed by their columns.
+// Fields that are not set are stored empty.
+func recordData(m proto.Message) map[string]string {
+	msg := m.ProtoReflect()
+	fields := msg.Descriptor().Fields()
+
+	data := make(map[string]string, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		data[string(field.Name())] = msg.Get(field).String()
+	}
+
+	return data
+}
+
+// recordPatch returns only the set fields of a record kind message keyed by their columns,
+// so that an update keeps the stored values of the others.
+func recordPatch(m proto.Message) map[string]string {
+	msg := m.ProtoReflect()
+	fields := msg.Descriptor().Fields()
+
+	data := make(map[string]string, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if msg.Has(field) {
+			data[string(field.Name())] = msg.Get(field).String()
+		}
+	}
+
+	return data
+}
+
+// setRecordFields copies the columns of a stored record into a record kind message.
+func setRecordFields(m proto.Message, record map[string]string) {
+	msg := m.ProtoReflect()
+	fields := msg.Descriptor().Fields()
+
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if value, ok := record[string(field.Name())]; ok {
+			msg.Set(field, protoreflect.ValueOfString(value))
+		}
+	}
+}
+
+// itemMeta returns the service fields of a stored record.
+func itemMeta(record map[string]string) *pb.ItemMeta {
+	meta := &pb.ItemMeta{Id: record["id"]}
+	meta.Version, _ = strconv.ParseInt(record["version"], 10, 64)
+	meta.Deleted, _ = strconv.ParseBool(record["deleted"])
+	if updatedAt, err := time.Parse(time.RFC3339Nano, record["updated_at"]); err == nil {
+		meta.UpdatedAt = timestamppb.New(updatedAt)
+	}
+
+	return meta
+}
+
+func credentialsItem(record map[string]string) *pb.CredentialsItem {
+	item := &pb.CredentialsItem{Meta: itemMeta(record), Credentials: &pb.Credentials{}}
+	setRecordFields(item.Credentials, record)
+
+	return item
+}
+
+func cardItem(record map[string]string) *pb.CardItem {
+	item := &pb.CardItem{Meta: itemMeta(record), Card: &pb.Card{}}
+	setRecordFields(item.Card, record)
+
+	return item
+}
+
+func textItem(record map[string]string) *pb.TextItem {
+	item := &pb.TextItem{Meta: itemMeta(record), Text: &pb.Text{}}
+	setRecordFields(item.Text, record)
+
+	return item
+}
+
+func binaryItem(record map[string]string) *pb.BinaryItem {
+	item := &pb.BinaryItem{Meta: itemMeta(record), Binary: &pb.Binary{}, Sha256: record["sha256"]}
+	item.Size, _ = strconv.ParseInt(record["size"], 10, 64)
+	setRecordFields(item.Binary, record)
+
+	return item
+}
+
+// addItem stores a new record of the table with the fields of m.
+func (s *Server) addItem(ctx context.Context, table schema.Table, entryID string, m proto.Message) (*pb.ItemVersion, error) {
+	userID, err := s.itemRequest(ctx, entryID, m)
+	if err != nil {
+		return nil, err
+	}
+	data := recordData(m)
+
+	sch, err := schema.Lookup(string(table))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := sch.ValidateAdd(data); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.storage.AddData(ctx, string(table), userID, entryID, data); err != nil {
+		return nil, storageError(err)
+	}
+	s.notify(ctx, userID, models.Event{Type: models.EventItemChanged, Table: string(table), ID: entryID, Version: 1})
+
+	return &pb.ItemVersion{Version: 1}, nil
+}
+
+// updateItem replaces the fields of a record of the table with the set fields of m.
+func (s *Server) updateItem(ctx context.Context, table schema.Table, entryID string, m proto.Message, baseVersion int64) (*pb.ItemVersion, error) {
+	userID, err := s.itemRequest(ctx, entryID, m)
+	if err != nil {
+		return nil, err
+	}
+	data := recordPatch(m)
+	if baseVersion < 0 {
+		return nil, status.Error(codes.InvalidArgument, "base_version must not be negative")
+	}
+
+	sch, err := schema.Lookup(string(table))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := sch.ValidateUpdate(data); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	version, err := s.storage.UpdateData(ctx, string(table), userID, entryID, data, baseVersion)
+	if err != nil {
+		return nil, storageError(err)
+	}
+	s.notify(ctx, userID, models.Event{Type: models.EventItemChanged, Table: string(table), ID: entryID, Version: version})
+
+	return &pb.ItemVersion{Version: version}, nil
+}
+
+// itemRequest checks the record of an add or update call and returns the user.
+func (s *Server) itemRequest(ctx context.Context, entryID string, m proto.Message) (int, error) {
+	userID, err := userID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if entryID == "" {
+		return 0, status.Error(codes.InvalidArgument, "id must be specified")
+	}
+	if !m.ProtoReflect().IsValid() {
+		name := m.ProtoReflect().Descriptor().Name()
+		return 0, status.Error(codes.InvalidArgument, fmt.Sprintf("%s must be specified", name))
+	}
+
+	return userID, nil
+}
+
+// deleteItem marks a record of the table as deleted.
+func (s *Server) deleteItem(ctx context.Context, table schema.Table, entryID string) (*pb.DeleteItemResponse, error) {
+	userID, err := userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.DeleteData(ctx, string(table), userID, entryID); err != nil {
+		return nil, storageError(err)
+	}
+	s.notify(ctx, userID, models.Event{Type: models.EventItemDeleted, Table: string(table), ID: entryID})
+
+	return &pb.DeleteItemResponse{}, nil
+}
+
+// getItem returns a record of the table that is not deleted.
+func (s *Server) getItem(ctx context.Context, table schema.Table, entryID string) (map[string]string, error) {
+	userID, err := userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.storage.GetData(ctx, string(table), userID, entryID)
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	return record, nil
+}
+
+// listItems returns the records of the table changed after since. Without since all records
+// that are not deleted are returned; otherwise deleted ones are returned too.
+func (s *Server) listItems(ctx context.Context, table schema.Table, since *timestamppb.Timestamp) ([]map[string]string, error) {
+	userID, err := userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastSync time.Time
+	if since != nil {
+		if err := since.CheckValid(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		lastSync = since.AsTime()
+	}
+
+	records, err := s.storage.GetAllData(ctx, string(table), userID, lastSync, !lastSync.IsZero())
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	return records, nil
+}
+
+// AddCredentials adds a login and password.
+func (s *Server) AddCredentials(ctx context.Context, req *pb.AddCredentialsRequest) (*pb.ItemVersion, error) {
+	return s.addItem(ctx, schema.UserCredentials, req.GetId(), req.GetCredentials())
+}
+
+// GetCredentials returns a login and password.
+func (s *Server) GetCredentials(ctx context.Context, req *pb.GetItemRequest) (*pb.CredentialsItem, error) {
+	record, err := s.getItem(ctx, schema.UserCredentials, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return credentialsItem(record), nil
+}
+
+// ListCredentials lists the logins and passwords.
+func (s *Server) ListCredentials(ctx context.Context, req *pb.ListItemsRequest) (*pb.ListCredentialsResponse, error) {
+	records, err := s.listItems(ctx, schema.UserCredentials, req.GetSince())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListCredentialsResponse{Items: make([]*pb.CredentialsItem, 0, len(records))}
+	for _, record := range records {
+		resp.Items = append(resp.Items, credentialsItem(record))
+	}
+
+	return resp, nil
+}
+
+// UpdateCredentials changes the set fields of a login and password.
+func (s *Server) UpdateCredentials(ctx context.Context, req *pb.UpdateCredentialsRequest) (*pb.ItemVersion, error) {
+	return s.updateItem(ctx, schema.UserCredentials, req.GetId(), req.GetCredentials(), req.GetBaseVersion())
+}
+
+// DeleteCredentials deletes a login and password.
+func (s *Server) DeleteCredentials(ctx context.Context, req *pb.DeleteItemRequest) (*pb.DeleteItemResponse, error) {
+	return s.deleteItem(ctx, schema.UserCredentials, req.GetId())
+}
+
+// AddCard adds a bank card.
+func (s *Server) AddCard(ctx context.Context, req *pb.AddCardRequest) (*pb.ItemVersion, error) {
+	return s.addItem(ctx, schema.CreditCardData, req.GetId(), req.GetCard())
+}
+
+// GetCard returns a bank card.
+func (s *Server) GetCard(ctx context.Context, req *pb.GetItemRequest) (*pb.CardItem, error) {
+	record, err := s.getItem(ctx, schema.CreditCardData, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return cardItem(record), nil
+}
+
+// ListCards lists the bank cards.
+func (s *Server) ListCards(ctx context.Context, req *pb.ListItemsRequest) (*pb.ListCardsResponse, error) {
+	records, err := s.listItems(ctx, schema.CreditCardData, req.GetSince())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListCardsResponse{Items: make([]*pb.CardItem, 0, len(records))}
+	for _, record := range records {
+		resp.Items = append(resp.Items, cardItem(record))
+	}
+
+	return resp, nil
+}
+
+// UpdateCard changes the set fields of a bank card.
+func (s *Server) UpdateCard(ctx context.Context, req *pb.UpdateCardRequest) (*pb.ItemVersion, error) {
+	return s.updateItem(ctx, schema.CreditCardData, req.GetId(), req.GetCard(), req.GetBaseVersion())
+}
+
+// DeleteCard deletes a bank card.
+func (s *Server) DeleteCard(ctx context.Context, req *pb.DeleteItemRequest) (*pb.DeleteItemResponse, error) {
+	return s.deleteItem(ctx, schema.CreditCardData, req.GetId())
+}
+
+// AddText adds a text.
+func (s *Server) AddText(ctx context.Context, req *pb.AddTextRequest) (*pb.ItemVersion, error) {
+	return s.addItem(ctx, schema.TextData, req.GetId(), req.GetText())
+}
+
+// GetText returns a text.
+func (s *Server) GetText(ctx context.Context, req *pb.GetItemRequest) (*pb.TextItem, error) {
+	record, err := s.getItem(ctx, schema.TextData, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return textItem(record), nil
+}
+
+// ListTexts lists the texts.
+func (s *Server) ListTexts(ctx context.Context, req *pb.ListItemsRequest) (*pb.ListTextsResponse, error) {
+	records, err := s.listItems(ctx, schema.TextData, req.GetSince())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListTextsResponse{Items: make([]*pb.TextItem, 0, len(records))}
+	for _, record := range records {
+		resp.Items = append(resp.Items, textItem(record))
+	}
+
+	return resp, nil
+}
+
+// UpdateText changes the set fields of a text.
+func (s *Server) UpdateText(ctx context.Context, req *pb.UpdateTextRequest) (*pb.ItemVersion, error) {
+	return s.updateItem(ctx, schema.TextData, req.GetId(), req.GetText(), req.GetBaseVersion())
+}
+
+// DeleteText deletes a text.
+func (s *Server) DeleteText(ctx context.Context, req *pb.DeleteItemRequest) (*pb.DeleteItemResponse, error) {
+	return s.deleteItem(ctx, schema.TextData, req.GetId())
+}
+
+// AddBinary adds the description of a file.
+func (s *Server) AddBinary(ctx context.Context, req *pb.AddBinaryRequest) (*pb.ItemVersion, error) {
+	return s.addItem(ctx, schema.FilesData, req.GetId(), req.GetBinary())
+}
+
+// GetBinary returns the description of a file.
+func (s *Server) GetBinary(ctx context.Context, req *pb.GetItemRequest) (*pb.BinaryItem, error) {
+	record, err := s.getItem(ctx, schema.FilesData, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return binaryItem(record), nil
+}
+
+// ListBinaries lists the descriptions of files.
+func (s *Server) ListBinaries(ctx context.Context, req *pb.ListItemsRequest) (*pb.ListBinariesResponse, error) {
+	records, err := s.listItems(ctx, schema.FilesData, req.GetSince())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListBinariesResponse{Items: make([]*pb.BinaryItem, 0, len(records))}
+	for _, record := range records {
+		resp.Items = append(resp.Items, binaryItem(record))
+	}
+
+	return resp, nil
+}
+
+// UpdateBinary changes the set fields of the description of a file.
+func (s *Server) UpdateBinary(ctx context.Context, req *pb.UpdateBinaryRequest) (*pb.ItemVersion, error) {
+	return s.updateItem(ctx, schema.FilesData, req.GetId(), req.GetBinary(), req.GetBaseVersion())
+}
+
+// DeleteBinary deletes the description and content of a file.
+func (s *Server) DeleteBinary(ctx context.Context, req *pb.DeleteItemRequest) (*pb.DeleteItemResponse, error) {
+	return s.deleteItem(ctx, schema.FilesData, req.GetId())
+}
@@ -0,0 +1,181 @@
+// Package grpcserver implements the gRPC API of the server. It works on the same
+// storage, authorization and blob store as the HTTP controllers.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/blobstore"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+	"github.com/wurt83ow/gophkeeper-server/pkg/pb"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// PublicMethods are the methods that are called without an access token.
+var PublicMethods = []string{
+	pb.Keeper_Register_FullMethodName,
+	pb.Keeper_Login_FullMethodName,
+	pb.Keeper_LoginMFA_FullMethodName,
+	pb.Keeper_RefreshToken_FullMethodName,
+}
+
+// Storage represents an interface for the users, sessions and vault records the service works on.
+type Storage interface {
+	AddUser(ctx context.Context, username string, hashedPassword string) error
+	GetPassword(ctx context.Context, username string) (string, error)
+	UpdatePassword(ctx context.Context, username string, hashedPassword string) error
+	GetUserID(ctx context.Context, username string) (int, error)
+	GetMFA(ctx context.Context, userID int) (models.MFA, error)
+	UseTOTPStep(ctx context.Context, userID int, step int64) error
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) error
+	AddSession(ctx context.Context, userID int, session models.Session) error
+	TouchSession(ctx context.Context, sessionID string, ip string) error
+	AddRefreshToken(ctx context.Context, tokenHash string, userID int, sessionID string, expiresAt time.Time) error
+	ConsumeRefreshToken(ctx context.Context, tokenHash string) (int, string, error)
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	AddData(ctx context.Context, table string, userID int, entryID string, data map[string]string) error
+	UpdateData(ctx context.Context, table string, userID int, entryID string, data map[string]string, baseVersion int64) (int64, error)
+	DeleteData(ctx context.Context, table string, userID int, entryID string) error
+	GetData(ctx context.Context, table string, userID int, entryID string) (map[string]string, error)
+	GetAllData(ctx context.Context, table string, userID int, lastSync time.Time, inclDel bool) ([]map[string]string, error)
+	Sync(ctx context.Context, userID int, afterSeq int64, limit int) ([]models.Change, bool, error)
+	SetFileContent(ctx context.Context, userID int, entryID string, size int64, digest string) error
+}
+
+// Options represents an interface for the configuration the service needs.
+type Options interface {
+	// FileStoragePath returns the directory where uploads are staged.
+	FileStoragePath() string
+
+	// MaxUploadSize returns the maximum size of an uploaded file in bytes.
+	MaxUploadSize() int64
+}
+
+// Events represents an interface for notifying the devices of a user about changed records.
+type Events interface {
+	// Notify publishes an event to the subscribers of the user.
+	Notify(ctx context.Context, userID int, event models.Event) error
+}
+
+// Log represents an interface for logging functionality.
+type Log interface {
+	// Info logs an informational message with optional fields.
+	Info(string, ...zapcore.Field)
+}
+
+// Authz represents an interface for the password, token and second factor handling of logins.
+type Authz interface {
+	// HashPassword derives the verifier that is stored for the password.
+	HashPassword(password string) (string, error)
+	// VerifyPassword checks the password against a stored verifier and reports
+	// whether the verifier should be replaced with a fresh one.
+	VerifyPassword(hashedPassword, password string) (ok bool, needsRehash bool)
+
+	// CreateJWTTokenForUser creates a JWT token for a specified user ID and session.
+	CreateJWTTokenForUser(userID string, sessionID string) string
+	// CreateSessionID creates a new random session ID.
+	CreateSessionID() (string, error)
+	// CreateRefreshToken creates a new refresh token and the hash under which it is stored.
+	CreateRefreshToken() (token string, tokenHash string, expiresAt time.Time, err error)
+	// HashRefreshToken computes the hash under which a refresh token is stored.
+	HashRefreshToken(token string) string
+
+	// ValidateTOTP checks a TOTP code and returns the time step it belongs to.
+	ValidateTOTP(secret string, code string) (int64, bool)
+	// HashRecoveryCode computes the hash under which a recovery code is stored.
+	HashRecoveryCode(code string) string
+	// CreateMFAChallenge creates a short-lived token for a login that awaits the second factor.
+	CreateMFAChallenge(userID int, deviceID, deviceName string) (string, error)
+	// DecodeMFAChallenge validates a challenge token and returns its details.
+	DecodeMFAChallenge(token string) (models.MFAChallenge, error)
+}
+
+// Server implements the Keeper gRPC service.
+type Server struct {
+	pb.UnimplementedKeeperServer
+
+	storage Storage
+	options Options
+	log     Log
+	authz   Authz
+	blobs   blobstore.BlobStore
+	events  Events
+}
+
+// NewServer creates a Server. Its methods other than PublicMethods expect the user
+// of the call in the context, so it must be registered behind the JWT interceptors.
+func NewServer(storage Storage, options Options, log Log, authz Authz, blobs blobstore.BlobStore, events Events) *Server {
+	return &Server{
+		storage: storage,
+		options: options,
+		log:     log,
+		authz:   authz,
+		blobs:   blobs,
+		events:  events,
+	}
+}
+
+// notify tells the devices of the user about a changed record. Failures are only logged,
+// since the change itself has been stored.
+func (s *Server) notify(ctx context.Context, userID int, event models.Event) {
+	if s.events == nil {
+		return
+	}
+
+	if err := s.events.Notify(ctx, userID, event); err != nil {
+		s.log.Info("cannot publish event: ", zap.Error(err))
+	}
+}
+
+// userID returns the user authenticated for the call.
+func userID(ctx context.Context) (int, error) {
+	var keyUserID models.Key = "userID"
+	userIDStr, _ := ctx.Value(keyUserID).(string)
+
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		return 0, status.Error(codes.Unauthenticated, "Authorization error")
+	}
+
+	return userID, nil
+}
+
+// clientIP returns the IP address of the client that made the call.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+// storageError converts an error of the storage into a gRPC status.
+func storageError(err error) error {
+	var conflict *storage.ConflictError
+	switch {
+	case errors.As(err, &conflict):
+		return status.Errorf(codes.Aborted, "record has changed, current version is %s", conflict.Current["version"])
+	case errors.Is(err, storage.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, storage.ErrConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,391 @@
+package grpcserver_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/gophkeeper-server/internal/app"
+	authz "github.com/wurt83ow/gophkeeper-server/internal/authorization"
+	"github.com/wurt83ow/gophkeeper-server/internal/blobstore"
+	"github.com/wurt83ow/gophkeeper-server/internal/events"
+	"github.com/wurt83ow/gophkeeper-server/internal/grpcserver"
+	"github.com/wurt83ow/gophkeeper-server/internal/logger"
+	"github.com/wurt83ow/gophkeeper-server/internal/memkeeper"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/pkg/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type testOptions struct {
+	dir string
+}
+
+func (o *testOptions) FileStoragePath() string { return o.dir }
+func (o *testOptions) MaxUploadSize() int64    { return 1 << 10 }
+
+type testServer struct {
+	client  pb.KeeperClient
+	storage *memkeeper.MemKeeper
+	blobs   blobstore.BlobStore
+	events  *events.Broadcaster
+}
+
+// newTestServer serves the service behind the JWT interceptors over an in-memory connection.
+func newTestServer(t *testing.T) *testServer {
+	log, err := logger.NewLogger("error")
+	require.NoError(t, err)
+
+	keeper := memkeeper.NewMemKeeper()
+	hasher := authz.NewArgon2idHasher(authz.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1})
+	jwtAuthz := authz.NewJWTAuthz("secret", time.Minute, time.Hour, hasher, log)
+
+	dir := t.TempDir()
+	blobs := blobstore.NewFileStore(filepath.Join(dir, "blobs"))
+	broadcaster := events.NewBroadcaster()
+	keeperServer := grpcserver.NewServer(keeper, &testOptions{dir: dir}, log, jwtAuthz, blobs, broadcaster)
+
+	listener := bufconn.Listen(1 << 20)
+	srv := app.NewGRPCServer(keeperServer, jwtAuthz, keeper, log)
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return &testServer{client: pb.NewKeeperClient(conn), storage: keeper, blobs: blobs, events: broadcaster}
+}
+
+// login registers a user and returns a context that carries its access token.
+func (ts *testServer) login(t *testing.T, username string) (context.Context, *pb.LoginResponse) {
+	ctx := context.Background()
+	_, err := ts.client.Register(ctx, &pb.RegisterRequest{Username: username, Password: "password"})
+	require.NoError(t, err)
+
+	login, err := ts.client.Login(ctx, &pb.LoginRequest{Username: username, Password: "password", DeviceName: "test"})
+	require.NoError(t, err)
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", login.GetToken()), login
+}
+
+func requireCode(t *testing.T, code codes.Code, err error) {
+	t.Helper()
+	require.Error(t, err)
+	assert.Equal(t, code, status.Code(err), err.Error())
+}
+
+func TestServer_Auth(t *testing.T) {
+	ts := newTestServer(t)
+	ctx, login := ts.login(t, "user")
+
+	assert.NotZero(t, login.GetUserId())
+	assert.NotEmpty(t, login.GetSessionId())
+	assert.NotEmpty(t, login.GetDeviceId())
+	assert.NotEmpty(t, login.GetRefreshToken())
+	assert.False(t, login.GetMfaRequired())
+
+	_, err := ts.client.Register(context.Background(), &pb.RegisterRequest{Username: "user", Password: "password"})
+	requireCode(t, codes.AlreadyExists, err)
+
+	_, err = ts.client.Register(context.Background(), &pb.RegisterRequest{Username: "empty"})
+	requireCode(t, codes.InvalidArgument, err)
+
+	_, err = ts.client.Login(context.Background(), &pb.LoginRequest{Username: "user", Password: "wrong"})
+	requireCode(t, codes.Unauthenticated, err)
+
+	// Calls and streams without a valid token are rejected
+	_, err = ts.client.ListTexts(context.Background(), &pb.ListItemsRequest{})
+	requireCode(t, codes.Unauthenticated, err)
+
+	invalid := metadata.AppendToOutgoingContext(context.Background(), "authorization", "invalid")
+	_, err = ts.client.ListTexts(invalid, &pb.ListItemsRequest{})
+	requireCode(t, codes.Unauthenticated, err)
+
+	stream, err := ts.client.Sync(context.Background(), &pb.SyncRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	requireCode(t, codes.Unauthenticated, err)
+
+	_, err = ts.client.ListTexts(ctx, &pb.ListItemsRequest{})
+	require.NoError(t, err)
+
+	// A refresh token is exchanged once for new tokens of the same session
+	refreshed, err := ts.client.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: login.GetRefreshToken()})
+	require.NoError(t, err)
+	assert.Equal(t, login.GetSessionId(), refreshed.GetSessionId())
+	assert.NotEqual(t, login.GetRefreshToken(), refreshed.GetRefreshToken())
+
+	_, err = ts.client.RefreshToken(context.Background(), &pb.RefreshTokenRequest{RefreshToken: login.GetRefreshToken()})
+	requireCode(t, codes.Unauthenticated, err)
+
+	// Tokens of revoked sessions are rejected
+	require.NoError(t, ts.storage.RevokeSession(context.Background(), int(login.GetUserId()), login.GetSessionId()))
+
+	_, err = ts.client.ListTexts(ctx, &pb.ListItemsRequest{})
+	requireCode(t, codes.Unauthenticated, err)
+}
+
+func TestServer_Items(t *testing.T) {
+	ts := newTestServer(t)
+	ctx, _ := ts.login(t, "user")
+
+	version, err := ts.client.AddCredentials(ctx, &pb.AddCredentialsRequest{
+		Id:          "site",
+		Credentials: &pb.Credentials{Login: proto.String("alice"), Password: proto.String("secret"), MetaInfo: proto.String("meta")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version.GetVersion())
+
+	item, err := ts.client.GetCredentials(ctx, &pb.GetItemRequest{Id: "site"})
+	require.NoError(t, err)
+	assert.Equal(t, "site", item.GetMeta().GetId())
+	assert.Equal(t, int64(1), item.GetMeta().GetVersion())
+	assert.NotNil(t, item.GetMeta().GetUpdatedAt())
+	assert.Equal(t, "alice", item.GetCredentials().GetLogin())
+	assert.Equal(t, "secret", item.GetCredentials().GetPassword())
+	assert.Equal(t, "meta", item.GetCredentials().GetMetaInfo())
+
+	version, err = ts.client.UpdateCredentials(ctx, &pb.UpdateCredentialsRequest{
+		Id:          "site",
+		Credentials: &pb.Credentials{Login: proto.String("alice"), Password: proto.String("changed")},
+		BaseVersion: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version.GetVersion())
+
+	// Fields that are not set keep their values
+	item, err = ts.client.GetCredentials(ctx, &pb.GetItemRequest{Id: "site"})
+	require.NoError(t, err)
+	assert.Equal(t, "changed", item.GetCredentials().GetPassword())
+	assert.Equal(t, "meta", item.GetCredentials().GetMetaInfo())
+
+	// A field set to an empty value is cleared, an update without fields is rejected
+	_, err = ts.client.UpdateCredentials(ctx, &pb.UpdateCredentialsRequest{
+		Id:          "site",
+		Credentials: &pb.Credentials{MetaInfo: proto.String("")},
+	})
+	require.NoError(t, err)
+	item, err = ts.client.GetCredentials(ctx, &pb.GetItemRequest{Id: "site"})
+	require.NoError(t, err)
+	assert.Empty(t, item.GetCredentials().GetMetaInfo())
+	assert.Equal(t, "changed", item.GetCredentials().GetPassword())
+
+	_, err = ts.client.UpdateCredentials(ctx, &pb.UpdateCredentialsRequest{Id: "site", Credentials: &pb.Credentials{}})
+	requireCode(t, codes.InvalidArgument, err)
+
+	// An update based on an outdated version is aborted
+	_, err = ts.client.UpdateCredentials(ctx, &pb.UpdateCredentialsRequest{
+		Id:          "site",
+		Credentials: &pb.Credentials{Login: proto.String("alice"), Password: proto.String("stale")},
+		BaseVersion: 1,
+	})
+	requireCode(t, codes.Aborted, err)
+
+	_, err = ts.client.AddCard(ctx, &pb.AddCardRequest{
+		Id:   "card",
+		Card: &pb.Card{CardNumber: proto.String("4111111111111111"), ExpirationDate: proto.String("12/30"), Cvv: proto.String("123")},
+	})
+	require.NoError(t, err)
+
+	card, err := ts.client.GetCard(ctx, &pb.GetItemRequest{Id: "card"})
+	require.NoError(t, err)
+	assert.Equal(t, "4111111111111111", card.GetCard().GetCardNumber())
+	assert.Equal(t, "12/30", card.GetCard().GetExpirationDate())
+
+	_, err = ts.client.AddText(ctx, &pb.AddTextRequest{Id: "note", Text: &pb.Text{Data: proto.String("text")}})
+	require.NoError(t, err)
+
+	texts, err := ts.client.ListTexts(ctx, &pb.ListItemsRequest{})
+	require.NoError(t, err)
+	require.Len(t, texts.GetItems(), 1)
+	assert.Equal(t, "text", texts.GetItems()[0].GetText().GetData())
+
+	// Deleted records are gone, but listed as deleted since a point in time
+	_, err = ts.client.DeleteText(ctx, &pb.DeleteItemRequest{Id: "note"})
+	require.NoError(t, err)
+
+	_, err = ts.client.GetText(ctx, &pb.GetItemRequest{Id: "note"})
+	requireCode(t, codes.NotFound, err)
+
+	texts, err = ts.client.ListTexts(ctx, &pb.ListItemsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, texts.GetItems())
+
+	texts, err = ts.client.ListTexts(ctx, &pb.ListItemsRequest{Since: timestamppb.New(time.Unix(1, 0))})
+	require.NoError(t, err)
+	require.Len(t, texts.GetItems(), 1)
+	assert.True(t, texts.GetItems()[0].GetMeta().GetDeleted())
+
+	// Records of other users are not visible
+	other, _ := ts.login(t, "other")
+	_, err = ts.client.GetCredentials(other, &pb.GetItemRequest{Id: "site"})
+	requireCode(t, codes.NotFound, err)
+
+	_, err = ts.client.AddText(ctx, &pb.AddTextRequest{Id: "empty"})
+	requireCode(t, codes.InvalidArgument, err)
+
+	_, err = ts.client.AddText(ctx, &pb.AddTextRequest{Text: &pb.Text{Data: proto.String("text")}})
+	requireCode(t, codes.InvalidArgument, err)
+
+	_, err = ts.client.UpdateText(ctx, &pb.UpdateTextRequest{Id: "missing", Text: &pb.Text{Data: proto.String("text")}})
+	requireCode(t, codes.NotFound, err)
+}
+
+func TestServer_Events(t *testing.T) {
+	ts := newTestServer(t)
+	ctx, login := ts.login(t, "user")
+
+	received, cancel := ts.events.Subscribe(int(login.GetUserId()))
+	defer cancel()
+
+	_, err := ts.client.AddText(ctx, &pb.AddTextRequest{Id: "note", Text: &pb.Text{Data: proto.String("text")}})
+	require.NoError(t, err)
+	_, err = ts.client.UpdateText(ctx, &pb.UpdateTextRequest{Id: "note", Text: &pb.Text{Data: proto.String("changed")}, BaseVersion: 1})
+	require.NoError(t, err)
+	_, err = ts.client.DeleteText(ctx, &pb.DeleteItemRequest{Id: "note"})
+	require.NoError(t, err)
+
+	// Failed changes are not announced
+	_, err = ts.client.UpdateText(ctx, &pb.UpdateTextRequest{Id: "missing", Text: &pb.Text{Data: proto.String("text")}})
+	requireCode(t, codes.NotFound, err)
+
+	want := []models.Event{
+		{Type: models.EventItemChanged, Table: "TextData", ID: "note", Version: 1},
+		{Type: models.EventItemChanged, Table: "TextData", ID: "note", Version: 2},
+		{Type: models.EventItemDeleted, Table: "TextData", ID: "note"},
+	}
+	for _, event := range want {
+		select {
+		case got := <-received:
+			assert.Equal(t, event, got)
+		case <-time.After(time.Second):
+			t.Fatalf("event %v was not published", event)
+		}
+	}
+	assert.Empty(t, received)
+}
+
+func TestServer_Sync(t *testing.T) {
+	ts := newTestServer(t)
+	ctx, _ := ts.login(t, "user")
+
+	_, err := ts.client.AddCredentials(ctx, &pb.AddCredentialsRequest{Id: "site", Credentials: &pb.Credentials{Login: proto.String("alice")}})
+	require.NoError(t, err)
+	_, err = ts.client.AddText(ctx, &pb.AddTextRequest{Id: "note", Text: &pb.Text{Data: proto.String("text")}})
+	require.NoError(t, err)
+	_, err = ts.client.AddBinary(ctx, &pb.AddBinaryRequest{Id: "file", Binary: &pb.Binary{Path: proto.String("a.txt")}})
+	require.NoError(t, err)
+
+	changes := syncAll(t, ts.client, ctx, "")
+	require.Len(t, changes, 3)
+	assert.Equal(t, "alice", changes[0].GetCredentials().GetCredentials().GetLogin())
+	assert.Equal(t, "text", changes[1].GetText().GetText().GetData())
+	assert.Equal(t, "a.txt", changes[2].GetBinary().GetBinary().GetPath())
+
+	// A sync resumed from a cursor gets only the later changes
+	_, err = ts.client.DeleteText(ctx, &pb.DeleteItemRequest{Id: "note"})
+	require.NoError(t, err)
+
+	later := syncAll(t, ts.client, ctx, changes[2].GetCursor())
+	require.Len(t, later, 1)
+	assert.Equal(t, "note", later[0].GetText().GetMeta().GetId())
+	assert.True(t, later[0].GetText().GetMeta().GetDeleted())
+
+	stream, err := ts.client.Sync(ctx, &pb.SyncRequest{Cursor: "invalid"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	requireCode(t, codes.InvalidArgument, err)
+}
+
+// syncAll reads the whole sync stream after the cursor.
+func syncAll(t *testing.T, client pb.KeeperClient, ctx context.Context, cursor string) []*pb.Change {
+	stream, err := client.Sync(ctx, &pb.SyncRequest{Cursor: cursor})
+	require.NoError(t, err)
+
+	var changes []*pb.Change
+	for {
+		change, err := stream.Recv()
+		if err == io.EOF {
+			return changes
+		}
+		require.NoError(t, err)
+		changes = append(changes, change)
+	}
+}
+
+func TestServer_UploadFile(t *testing.T) {
+	ts := newTestServer(t)
+	ctx, _ := ts.login(t, "user")
+
+	_, err := ts.client.AddBinary(ctx, &pb.AddBinaryRequest{Id: "file", Binary: &pb.Binary{Path: proto.String("a.txt")}})
+	require.NoError(t, err)
+
+	content := []byte("hello, world")
+	sum := sha256.Sum256(content)
+
+	result, err := upload(ctx, ts.client, &pb.UploadFileRequest{Data: &pb.UploadFileRequest_Id{Id: "file"}},
+		&pb.UploadFileRequest{Data: &pb.UploadFileRequest_Chunk{Chunk: content[:5]}},
+		&pb.UploadFileRequest{Data: &pb.UploadFileRequest_Chunk{Chunk: content[5:]}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), result.GetSize())
+	assert.Equal(t, hex.EncodeToString(sum[:]), result.GetSha256())
+
+	// The content is stored by digest and recorded on the entry
+	_, err = ts.blobs.Stat(ctx, blobstore.ContentKey(result.GetSha256()))
+	require.NoError(t, err)
+
+	item, err := ts.client.GetBinary(ctx, &pb.GetItemRequest{Id: "file"})
+	require.NoError(t, err)
+	assert.Equal(t, result.GetSize(), item.GetSize())
+	assert.Equal(t, result.GetSha256(), item.GetSha256())
+
+	_, err = upload(ctx, ts.client, &pb.UploadFileRequest{Data: &pb.UploadFileRequest_Id{Id: "missing"}},
+		&pb.UploadFileRequest{Data: &pb.UploadFileRequest_Chunk{Chunk: content}})
+	requireCode(t, codes.NotFound, err)
+
+	_, err = upload(ctx, ts.client, &pb.UploadFileRequest{Data: &pb.UploadFileRequest_Chunk{Chunk: content}})
+	requireCode(t, codes.InvalidArgument, err)
+
+	// Files over the size limit are rejected
+	large := make([]byte, 600)
+	_, err = upload(ctx, ts.client, &pb.UploadFileRequest{Data: &pb.UploadFileRequest_Id{Id: "file"}},
+		&pb.UploadFileRequest{Data: &pb.UploadFileRequest_Chunk{Chunk: large}},
+		&pb.UploadFileRequest{Data: &pb.UploadFileRequest_Chunk{Chunk: large}})
+	requireCode(t, codes.ResourceExhausted, err)
+}
+
+// upload sends the messages of a file upload and returns the result.
+func upload(ctx context.Context, client pb.KeeperClient, msgs ...*pb.UploadFileRequest) (*pb.UploadFileResponse, error) {
+	stream, err := client.UploadFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, msg := range msgs {
+		// A rejected upload is reported by CloseAndRecv
+		if err := stream.Send(msg); err != nil {
+			break
+		}
+	}
+
+	return stream.CloseAndRecv()
+}
@@ -0,0 +1,178 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/blobstore"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/schema"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+	"github.com/wurt83ow/gophkeeper-server/pkg/pb"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// syncPageSize is the number of changes Sync reads from the storage at once.
+const syncPageSize = 100
+
+// Sync streams the changes of all records after the cursor, oldest first.
+// The cursors are the same as those of the HTTP sync endpoint.
+func (s *Server) Sync(req *pb.SyncRequest, stream pb.Keeper_SyncServer) error {
+	ctx := stream.Context()
+	userID, err := userID(ctx)
+	if err != nil {
+		return err
+	}
+
+	var afterSeq int64
+	if req.GetCursor() != "" {
+		afterSeq, err = storage.DecodeSyncCursor(req.GetCursor())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	for {
+		changes, hasMore, err := s.storage.Sync(ctx, userID, afterSeq, syncPageSize)
+		if err != nil {
+			return storageError(err)
+		}
+
+		for _, change := range changes {
+			afterSeq = change.Seq
+
+			msg, err := syncChange(change)
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+
+		if !hasMore {
+			return nil
+		}
+	}
+}
+
+// syncChange converts a changed record into a message of its kind.
+func syncChange(change models.Change) (*pb.Change, error) {
+	sch, err := schema.Lookup(change.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &pb.Change{Cursor: storage.EncodeSyncCursor(change.Seq)}
+	switch sch.Table {
+	case schema.UserCredentials:
+		msg.Item = &pb.Change_Credentials{Credentials: credentialsItem(change.Entry)}
+	case schema.CreditCardData:
+		msg.Item = &pb.Change_Card{Card: cardItem(change.Entry)}
+	case schema.TextData:
+		msg.Item = &pb.Change_Text{Text: textItem(change.Entry)}
+	case schema.FilesData:
+		msg.Item = &pb.Change_Binary{Binary: binaryItem(change.Entry)}
+	}
+
+	return msg, nil
+}
+
+// UploadFile stores the content of a binary record. The content is staged in a temporary file
+// and then stored in the blob store the same way as the HTTP uploads.
+func (s *Server) UploadFile(stream pb.Keeper_UploadFileServer) error {
+	ctx := stream.Context()
+	userID, err := userID(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The first message names the record
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) || (err == nil && first.GetId() == "") {
+		return status.Error(codes.InvalidArgument, "the first message must carry the record ID")
+	}
+	if err != nil {
+		return err
+	}
+	entryID := first.GetId()
+
+	dir := filepath.Join(s.options.FileStoragePath(), strconv.Itoa(userID))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		s.log.Info("cannot create file directory: ", zap.Error(err))
+		return status.Error(codes.Internal, "cannot store file")
+	}
+
+	tmp, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		s.log.Info("cannot create temporary file: ", zap.Error(err))
+		return status.Error(codes.Internal, "cannot store file")
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	w := io.MultiWriter(tmp, hash)
+
+	var size int64
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := req.GetData().(*pb.UploadFileRequest_Chunk); !ok {
+			return status.Error(codes.InvalidArgument, "only the first message may carry the record ID")
+		}
+
+		size += int64(len(req.GetChunk()))
+		if size > s.options.MaxUploadSize() {
+			return status.Error(codes.ResourceExhausted, "file is too large")
+		}
+		if _, err := w.Write(req.GetChunk()); err != nil {
+			s.log.Info("cannot write temporary file: ", zap.Error(err))
+			return status.Error(codes.Internal, "cannot store file")
+		}
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return status.Error(codes.Internal, "cannot store file")
+	}
+	if err := tmp.Close(); err != nil {
+		return status.Error(codes.Internal, "cannot store file")
+	}
+
+	content := &pb.UploadFileResponse{Size: size, Sha256: hex.EncodeToString(hash.Sum(nil))}
+	if err := s.commitFile(ctx, userID, entryID, tmp.Name(), content); err != nil {
+		return err
+	}
+	s.notify(ctx, userID, models.Event{Type: models.EventItemChanged, Table: string(schema.FilesData), ID: entryID})
+
+	return stream.SendAndClose(content)
+}
+
+// commitFile makes sure that the blob store holds the uploaded content and records it for a FilesData entry.
+func (s *Server) commitFile(ctx context.Context, userID int, entryID string, path string, content *pb.UploadFileResponse) error {
+	err := blobstore.StoreContent(ctx, s.blobs, content.Sha256, path, content.Size, func() error {
+		return s.storage.SetFileContent(ctx, userID, entryID, content.Size, content.Sha256)
+	})
+	if errors.Is(err, storage.ErrNotFound) {
+		return storageError(err)
+	}
+	if err != nil {
+		s.log.Info("cannot store uploaded file: ", zap.Error(err))
+		return status.Error(codes.Internal, "cannot store file")
+	}
+
+	return nil
+}
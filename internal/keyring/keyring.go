@@ -0,0 +1,100 @@
+// Package keyring holds the versions of a secret key, so that the key can be replaced
+// while data protected by older versions is still in use. Every version has an ID that
+// is stored next to the ciphertexts and tokens it protects.
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors returned by the key rings.
+var (
+	ErrNoKeys       = errors.New("key ring has no keys")
+	ErrDuplicateKey = errors.New("duplicate key ID")
+	ErrUnknownKey   = errors.New("unknown key ID")
+)
+
+// Key is a version of a secret key. The ID of a key given without one is empty.
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// KeyRing is an immutable list of key versions, the current one first.
+type KeyRing struct {
+	keys []Key
+}
+
+// Single creates a key ring that holds only the given key.
+func Single(key Key) *KeyRing {
+	return &KeyRing{keys: []Key{key}}
+}
+
+// New creates a key ring of the given keys. The first key is the current one.
+func New(keys ...Key) (*KeyRing, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if seen[key.ID] {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateKey, key.ID)
+		}
+		seen[key.ID] = true
+	}
+
+	return &KeyRing{keys: append([]Key(nil), keys...)}, nil
+}
+
+// Parse creates a key ring from a list of "[id:]secret" entries separated by commas or
+// line breaks, the current key first. Every secret is converted by decode.
+func Parse(spec string, decode func(string) ([]byte, error)) (*KeyRing, error) {
+	fields := strings.FieldsFunc(spec, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	})
+
+	keys := make([]Key, 0, len(fields))
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		var id string
+		if i := strings.Index(field, ":"); i >= 0 {
+			id, field = field[:i], field[i+1:]
+		}
+
+		secret, err := decode(field)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		keys = append(keys, Key{ID: id, Secret: secret})
+	}
+
+	return New(keys...)
+}
+
+// Current returns the key that protects new data.
+func (kr *KeyRing) Current() Key {
+	return kr.keys[0]
+}
+
+// Get returns the key with the given ID.
+func (kr *KeyRing) Get(id string) (Key, error) {
+	for _, key := range kr.keys {
+		if key.ID == id {
+			return key, nil
+		}
+	}
+
+	return Key{}, fmt.Errorf("%w: %q", ErrUnknownKey, id)
+}
+
+// Keys returns all keys, the current one first.
+func (kr *KeyRing) Keys() []Key {
+	return append([]Key(nil), kr.keys...)
+}
@@ -0,0 +1,54 @@
+package keyring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func raw(s string) ([]byte, error) {
+	return []byte(s), nil
+}
+
+func TestParse(t *testing.T) {
+	ring, err := Parse("2024:new, 2023:old\nlegacy", raw)
+	require.NoError(t, err)
+
+	assert.Equal(t, Key{ID: "2024", Secret: []byte("new")}, ring.Current())
+	assert.Len(t, ring.Keys(), 3)
+
+	key, err := ring.Get("2023")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("old"), key.Secret)
+
+	// A key without an ID has the empty one
+	key, err = ring.Get("")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("legacy"), key.Secret)
+
+	_, err = ring.Get("2022")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestSingle(t *testing.T) {
+	ring := Single(Key{Secret: []byte("secret")})
+
+	assert.Equal(t, Key{Secret: []byte("secret")}, ring.Current())
+	assert.Len(t, ring.Keys(), 1)
+
+	key, err := ring.Get("")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), key.Secret)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse("", raw)
+	assert.ErrorIs(t, err, ErrNoKeys)
+
+	_, err = Parse("a:one,a:two", raw)
+	assert.ErrorIs(t, err, ErrDuplicateKey)
+
+	_, err = Parse("a:one", func(string) ([]byte, error) { return nil, assert.AnError })
+	assert.ErrorIs(t, err, assert.AnError)
+}
@@ -0,0 +1,1162 @@
+// Package memkeeper provides a keeper that holds all data in memory. It behaves like
+// bdkeeper.BDKeeper, but nothing is kept once the server stops, so it suits tests,
+// demos and a local single-user server.
+package memkeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/schema"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+)
+
+// maxUnreferencedBlobs is the number of digests UnreferencedBlobs returns at most.
+const maxUnreferencedBlobs = 1000
+
+type user struct {
+	id       int
+	password string
+	srp      bool
+	verifier models.SRPVerifier
+}
+
+type session struct {
+	models.Session
+	userID  int
+	revoked bool
+}
+
+type refreshToken struct {
+	userID    int
+	sessionID string
+	expiresAt time.Time
+	revoked   bool
+}
+
+// recordKey identifies a vault record. As in the database, record IDs are unique
+// within a table, not within the records of a user.
+type recordKey struct {
+	table schema.Table
+	id    string
+}
+
+// record is a vault record. Records are replaced on every change and never modified
+// in place, so copies kept for a rollback stay intact.
+type record struct {
+	userID    int
+	deleted   bool
+	updatedAt time.Time
+	version   int64
+	changeSeq int64
+	columns   map[string]string
+}
+
+// fields returns the columns of the record the way the database keeper reads them.
+func (r record) fields(s schema.Schema, id string) map[string]string {
+	values := make(map[string]string, len(s.AllColumns()))
+	for _, column := range s.AllColumns() {
+		values[column] = r.columns[column]
+	}
+	values["id"] = id
+	values["user_id"] = strconv.Itoa(r.userID)
+	values["deleted"] = strconv.FormatBool(r.deleted)
+	values["updated_at"] = r.updatedAt.Format(time.RFC3339Nano)
+	values["version"] = strconv.FormatInt(r.version, 10)
+
+	return values
+}
+
+type blob struct {
+	size     int64
+	refCount int64
+}
+
+// MemKeeper represents an in-memory keeper. It is safe for concurrent use.
+type MemKeeper struct {
+	mu sync.Mutex
+
+	users         map[string]user
+	lastUserID    int
+	srpChallenges map[string]models.SRPChallenge
+	mfa           map[int]models.MFA
+	recoveryCodes map[int]map[string]bool
+	sessions      map[string]session
+	refreshTokens map[string]refreshToken
+	revokedTokens map[string]time.Time
+	records       map[recordKey]record
+	changeSeqs    map[int]int64
+	blobs         map[string]blob
+	dataKeys      map[int]string
+}
+
+// NewMemKeeper creates a new empty MemKeeper instance.
+func NewMemKeeper() *MemKeeper {
+	return &MemKeeper{
+		users:         make(map[string]user),
+		srpChallenges: make(map[string]models.SRPChallenge),
+		mfa:           make(map[int]models.MFA),
+		recoveryCodes: make(map[int]map[string]bool),
+		sessions:      make(map[string]session),
+		refreshTokens: make(map[string]refreshToken),
+		revokedTokens: make(map[string]time.Time),
+		records:       make(map[recordKey]record),
+		changeSeqs:    make(map[int]int64),
+		blobs:         make(map[string]blob),
+		dataKeys:      make(map[int]string),
+	}
+}
+
+// now returns the current time with the precision of PostgreSQL timestamps.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// Ping always succeeds, since there is no connection to check.
+func (mk *MemKeeper) Ping() bool {
+	return true
+}
+
+// Close always succeeds. The data is dropped together with the keeper.
+func (mk *MemKeeper) Close() bool {
+	return true
+}
+
+// UserExists checks if a user exists.
+func (mk *MemKeeper) UserExists(ctx context.Context, username string) (bool, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	_, ok := mk.users[username]
+
+	return ok, nil
+}
+
+// AddUser adds a new user. It returns storage.ErrConflict if the username is taken.
+func (mk *MemKeeper) AddUser(ctx context.Context, username string, hashedPassword string) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	return mk.addUser(user{password: hashedPassword}, username)
+}
+
+// AddSRPUser adds a new user that logs in with SRP. Such users have no password verifier,
+// so the password login always fails for them.
+func (mk *MemKeeper) AddSRPUser(ctx context.Context, username string, verifier models.SRPVerifier) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	return mk.addUser(user{srp: true, verifier: verifier}, username)
+}
+
+// addUser assigns the next user ID to u and stores it under username.
+func (mk *MemKeeper) addUser(u user, username string) error {
+	if _, ok := mk.users[username]; ok {
+		return fmt.Errorf("%w: user %q already exists", storage.ErrConflict, username)
+	}
+
+	mk.lastUserID++
+	u.id = mk.lastUserID
+	mk.users[username] = u
+
+	return nil
+}
+
+// GetSRPVerifier retrieves the user ID and SRP verifier of a user.
+// It returns storage.ErrNotFound if the user does not log in with SRP.
+func (mk *MemKeeper) GetSRPVerifier(ctx context.Context, username string) (int, models.SRPVerifier, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	u, ok := mk.users[username]
+	if !ok || !u.srp {
+		return 0, models.SRPVerifier{}, storage.ErrNotFound
+	}
+
+	return u.id, u.verifier, nil
+}
+
+// AddSRPChallenge stores the server side of an SRP login attempt.
+// Expired attempts that were never answered are removed first.
+func (mk *MemKeeper) AddSRPChallenge(ctx context.Context, challenge models.SRPChallenge) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	current := now()
+	for id, stored := range mk.srpChallenges {
+		if stored.ExpiresAt.Before(current) {
+			delete(mk.srpChallenges, id)
+		}
+	}
+
+	if _, ok := mk.srpChallenges[challenge.ID]; ok {
+		return fmt.Errorf("%w: SRP challenge already exists", storage.ErrConflict)
+	}
+	challenge.ExpiresAt = challenge.ExpiresAt.UTC()
+	mk.srpChallenges[challenge.ID] = challenge
+
+	return nil
+}
+
+// ConsumeSRPChallenge removes an SRP login attempt and returns it, so that every attempt
+// can be answered only once. It returns storage.ErrNotFound if the challenge is unknown or expired.
+func (mk *MemKeeper) ConsumeSRPChallenge(ctx context.Context, id string) (models.SRPChallenge, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	challenge, ok := mk.srpChallenges[id]
+	if !ok {
+		return models.SRPChallenge{}, storage.ErrNotFound
+	}
+	delete(mk.srpChallenges, id)
+
+	if !challenge.ExpiresAt.After(now()) {
+		return models.SRPChallenge{}, storage.ErrNotFound
+	}
+
+	return challenge, nil
+}
+
+// GetPassword retrieves the hashed password of a user.
+func (mk *MemKeeper) GetPassword(ctx context.Context, username string) (string, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	u, ok := mk.users[username]
+	if !ok {
+		return "", storage.ErrNotFound
+	}
+
+	return u.password, nil
+}
+
+// UpdatePassword replaces the hashed password of a user.
+func (mk *MemKeeper) UpdatePassword(ctx context.Context, username string, hashedPassword string) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	if u, ok := mk.users[username]; ok {
+		u.password = hashedPassword
+		mk.users[username] = u
+
+		// A new password outdates the credentials other servers have cached
+		mk.nextChangeSeq(nil, u.id)
+	}
+
+	return nil
+}
+
+// GetUserID retrieves the user ID of a user.
+func (mk *MemKeeper) GetUserID(ctx context.Context, username string) (int, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	u, ok := mk.users[username]
+	if !ok {
+		return 0, storage.ErrNotFound
+	}
+
+	return u.id, nil
+}
+
+// GetUsername retrieves the username of a user.
+func (mk *MemKeeper) GetUsername(ctx context.Context, userID int) (string, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	for username, u := range mk.users {
+		if u.id == userID {
+			return username, nil
+		}
+	}
+
+	return "", storage.ErrNotFound
+}
+
+// GetMFA retrieves the two-factor authentication settings of a user.
+// It returns storage.ErrNotFound if the user has never enrolled.
+func (mk *MemKeeper) GetMFA(ctx context.Context, userID int) (models.MFA, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	mfa, ok := mk.mfa[userID]
+	if !ok {
+		return models.MFA{}, storage.ErrNotFound
+	}
+
+	return mfa, nil
+}
+
+// SetPendingTOTPSecret stores a TOTP secret that awaits confirmation by the user.
+// An already active secret stays in use until the new one is confirmed.
+func (mk *MemKeeper) SetPendingTOTPSecret(ctx context.Context, userID int, secret string) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	mfa := mk.mfa[userID]
+	mfa.PendingSecret = secret
+	mk.mfa[userID] = mfa
+
+	return nil
+}
+
+// EnableMFA activates the pending TOTP secret of a user and replaces the recovery codes.
+// The TOTP step used for the confirmation is recorded, so that the code can not be replayed.
+// It returns storage.ErrNotFound if there is no pending secret.
+func (mk *MemKeeper) EnableMFA(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	mfa, ok := mk.mfa[userID]
+	if !ok || mfa.PendingSecret == "" {
+		return storage.ErrNotFound
+	}
+	mk.mfa[userID] = models.MFA{Secret: mfa.PendingSecret, Enabled: true, LastStep: step}
+
+	codes := make(map[string]bool, len(recoveryCodeHashes))
+	for _, codeHash := range recoveryCodeHashes {
+		codes[codeHash] = false
+	}
+	mk.recoveryCodes[userID] = codes
+
+	return nil
+}
+
+// UseTOTPStep records a used TOTP time step of a user. It returns storage.ErrConflict
+// if the step is not newer than the last used one, which prevents code replay.
+func (mk *MemKeeper) UseTOTPStep(ctx context.Context, userID int, step int64) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	mfa, ok := mk.mfa[userID]
+	if !ok || mfa.LastStep >= step {
+		return storage.ErrConflict
+	}
+	mfa.LastStep = step
+	mk.mfa[userID] = mfa
+
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code of a user as used.
+// It returns storage.ErrNotFound if the code is unknown or already used.
+func (mk *MemKeeper) UseRecoveryCode(ctx context.Context, userID int, codeHash string) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	used, ok := mk.recoveryCodes[userID][codeHash]
+	if !ok || used {
+		return storage.ErrNotFound
+	}
+	mk.recoveryCodes[userID][codeHash] = true
+
+	return nil
+}
+
+// AddSession registers a new device session. Active sessions of the same device
+// are revoked, so each device has at most one active session.
+func (mk *MemKeeper) AddSession(ctx context.Context, userID int, s models.Session) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	if _, ok := mk.sessions[s.ID]; ok {
+		return fmt.Errorf("%w: session already exists", storage.ErrConflict)
+	}
+
+	for id, other := range mk.sessions {
+		if other.userID == userID && other.DeviceID == s.DeviceID && !other.revoked {
+			other.revoked = true
+			mk.sessions[id] = other
+		}
+	}
+
+	s.CreatedAt = now()
+	s.LastSeen = s.CreatedAt
+	s.Current = false
+	mk.sessions[s.ID] = session{Session: s, userID: userID}
+
+	return nil
+}
+
+// ListSessions retrieves the active sessions of a user, most recently seen first.
+func (mk *MemKeeper) ListSessions(ctx context.Context, userID int) ([]models.Session, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	sessions := make([]models.Session, 0)
+	for _, s := range mk.sessions {
+		if s.userID == userID && !s.revoked {
+			sessions = append(sessions, s.Session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LastSeen.After(sessions[j].LastSeen) })
+
+	return sessions, nil
+}
+
+// TouchSession updates the last-seen time and IP address of a session.
+func (mk *MemKeeper) TouchSession(ctx context.Context, sessionID string, ip string) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	if s, ok := mk.sessions[sessionID]; ok {
+		s.LastSeen = now()
+		s.IP = ip
+		mk.sessions[sessionID] = s
+	}
+
+	return nil
+}
+
+// IsSessionActive checks whether a session exists and has not been revoked.
+func (mk *MemKeeper) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	s, ok := mk.sessions[sessionID]
+
+	return ok && !s.revoked, nil
+}
+
+// RevokeSession revokes an active session of a user.
+// It returns storage.ErrNotFound if the user has no such active session.
+func (mk *MemKeeper) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	s, ok := mk.sessions[sessionID]
+	if !ok || s.userID != userID || s.revoked {
+		return storage.ErrNotFound
+	}
+	s.revoked = true
+	mk.sessions[sessionID] = s
+
+	return nil
+}
+
+// RevokeAllSessions revokes all active sessions of a user.
+func (mk *MemKeeper) RevokeAllSessions(ctx context.Context, userID int) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	for id, s := range mk.sessions {
+		if s.userID == userID && !s.revoked {
+			s.revoked = true
+			mk.sessions[id] = s
+		}
+	}
+
+	return nil
+}
+
+// AddRefreshToken stores the hash of a refresh token issued to a user session.
+func (mk *MemKeeper) AddRefreshToken(ctx context.Context, tokenHash string, userID int, sessionID string, expiresAt time.Time) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	if _, ok := mk.refreshTokens[tokenHash]; ok {
+		return fmt.Errorf("%w: refresh token already exists", storage.ErrConflict)
+	}
+	mk.refreshTokens[tokenHash] = refreshToken{userID: userID, sessionID: sessionID, expiresAt: expiresAt.UTC()}
+
+	return nil
+}
+
+// ConsumeRefreshToken revokes a valid refresh token and returns the IDs of its owner and session.
+// Revoked, expired and unknown tokens as well as tokens of revoked sessions yield
+// storage.ErrNotFound, so each token can be used only once.
+func (mk *MemKeeper) ConsumeRefreshToken(ctx context.Context, tokenHash string) (int, string, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	token, ok := mk.refreshTokens[tokenHash]
+	if !ok || token.revoked || !token.expiresAt.After(now()) {
+		return 0, "", storage.ErrNotFound
+	}
+	if s, ok := mk.sessions[token.sessionID]; !ok || s.revoked {
+		return 0, "", storage.ErrNotFound
+	}
+
+	token.revoked = true
+	mk.refreshTokens[tokenHash] = token
+
+	return token.userID, token.sessionID, nil
+}
+
+// RevokeRefreshToken revokes a refresh token of the given user.
+func (mk *MemKeeper) RevokeRefreshToken(ctx context.Context, tokenHash string, userID int) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	if token, ok := mk.refreshTokens[tokenHash]; ok && token.userID == userID {
+		token.revoked = true
+		mk.refreshTokens[tokenHash] = token
+	}
+
+	return nil
+}
+
+// RevokeToken adds the ID of an access token to the revocation list.
+func (mk *MemKeeper) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	if _, ok := mk.revokedTokens[jti]; !ok {
+		mk.revokedTokens[jti] = expiresAt.UTC()
+	}
+
+	return nil
+}
+
+// IsTokenRevoked checks whether the access token with the given ID has been revoked.
+func (mk *MemKeeper) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	_, ok := mk.revokedTokens[jti]
+
+	return ok, nil
+}
+
+// PurgeExpiredTokens removes the refresh tokens and the revoked access token IDs that
+// expired before the given time and returns how many were removed.
+func (mk *MemKeeper) PurgeExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	var purged int64
+	for tokenHash, token := range mk.refreshTokens {
+		if token.expiresAt.Before(before) {
+			delete(mk.refreshTokens, tokenHash)
+			purged++
+		}
+	}
+	for jti, expiresAt := range mk.revokedTokens {
+		if expiresAt.Before(before) {
+			delete(mk.revokedTokens, jti)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// journal collects the actions that undo the changes of a batch.
+type journal []func()
+
+// rollback undoes the recorded changes, latest first.
+func (j *journal) rollback() {
+	for i := len(*j) - 1; i >= 0; i-- {
+		(*j)[i]()
+	}
+	*j = nil
+}
+
+// remember records the current entry of m under key, so that a rollback of j restores it.
+// A nil journal records nothing.
+func remember[K comparable, V any](j *journal, m map[K]V, key K) {
+	if j == nil {
+		return
+	}
+
+	previous, ok := m[key]
+	*j = append(*j, func() {
+		if ok {
+			m[key] = previous
+		} else {
+			delete(m, key)
+		}
+	})
+}
+
+// LastChangeSeq returns the change sequence number of the latest change of the user's
+// records or password, which is zero if there was none.
+func (mk *MemKeeper) LastChangeSeq(ctx context.Context, userID int) (int64, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	return mk.changeSeqs[userID], nil
+}
+
+// GetDataKey returns the wrapped data key of a user.
+// It returns storage.ErrNotFound if the user has no data key yet.
+func (mk *MemKeeper) GetDataKey(ctx context.Context, userID int) (string, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	wrappedKey, ok := mk.dataKeys[userID]
+	if !ok {
+		return "", storage.ErrNotFound
+	}
+
+	return wrappedKey, nil
+}
+
+// AddDataKey stores the wrapped data key of a user unless the user already has one
+// and returns the stored key.
+func (mk *MemKeeper) AddDataKey(ctx context.Context, userID int, wrappedKey string) (string, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	if stored, ok := mk.dataKeys[userID]; ok {
+		return stored, nil
+	}
+	mk.dataKeys[userID] = wrappedKey
+
+	return wrappedKey, nil
+}
+
+// ReplaceDataKey replaces the wrapped data key of a user, provided that it is still oldKey.
+// Otherwise it returns storage.ErrConflict.
+func (mk *MemKeeper) ReplaceDataKey(ctx context.Context, userID int, oldKey, newKey string) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	if stored, ok := mk.dataKeys[userID]; !ok || stored != oldKey {
+		return storage.ErrConflict
+	}
+	mk.dataKeys[userID] = newKey
+
+	return nil
+}
+
+// ListUserIDs returns the IDs of at most limit users that are greater than afterID,
+// in ascending order.
+func (mk *MemKeeper) ListUserIDs(ctx context.Context, afterID int, limit int) ([]int, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	var userIDs []int
+	for _, u := range mk.users {
+		if u.id > afterID {
+			userIDs = append(userIDs, u.id)
+		}
+	}
+	sort.Ints(userIDs)
+	if len(userIDs) > limit {
+		userIDs = userIDs[:limit]
+	}
+
+	return userIDs, nil
+}
+
+// CountUsers returns the number of users.
+func (mk *MemKeeper) CountUsers(ctx context.Context) (int64, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	return int64(len(mk.users)), nil
+}
+
+// RewriteColumns replaces the columns of a record, deleted or not, provided that they
+// still hold the values in current. Otherwise it returns storage.ErrConflict. The version,
+// change sequence and update time stay as they are.
+func (mk *MemKeeper) RewriteColumns(ctx context.Context, table string, userID int, entryID string, current, replacement map[string]string) error {
+	// Only vault tables and their columns are kept
+	s, err := schema.Lookup(table)
+	if err != nil {
+		return err
+	}
+	if err := s.ValidateUpdate(replacement); err != nil {
+		return err
+	}
+
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	r, ok := mk.userRecord(s.Table, userID, entryID)
+	if !ok {
+		return storage.ErrConflict
+	}
+	for column := range replacement {
+		if r.columns[column] != current[column] {
+			return storage.ErrConflict
+		}
+	}
+
+	columns := make(map[string]string, len(r.columns))
+	for column, value := range r.columns {
+		columns[column] = value
+	}
+	for column, value := range replacement {
+		columns[column] = value
+	}
+
+	r.columns = columns
+	mk.putRecord(nil, recordKey{table: s.Table, id: entryID}, r)
+
+	return nil
+}
+
+// nextChangeSeq advances the change sequence of a user and returns the new value.
+func (mk *MemKeeper) nextChangeSeq(j *journal, userID int) int64 {
+	remember(j, mk.changeSeqs, userID)
+	mk.changeSeqs[userID]++
+
+	return mk.changeSeqs[userID]
+}
+
+// putRecord stores a new state of a record.
+func (mk *MemKeeper) putRecord(j *journal, key recordKey, r record) {
+	remember(j, mk.records, key)
+	mk.records[key] = r
+}
+
+// userRecord returns a record of a user, including deleted ones.
+func (mk *MemKeeper) userRecord(table schema.Table, userID int, entryID string) (record, bool) {
+	r, ok := mk.records[recordKey{table: table, id: entryID}]
+	if !ok || r.userID != userID {
+		return record{}, false
+	}
+
+	return r, true
+}
+
+// AddData adds a record to a table. It returns storage.ErrConflict if the table
+// already holds a record with the same ID.
+func (mk *MemKeeper) AddData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string) error {
+	// Only vault tables and their columns are kept
+	s, err := schema.Lookup(table)
+	if err != nil {
+		return err
+	}
+	if err := s.ValidateAdd(data); err != nil {
+		return err
+	}
+
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	return mk.addData(nil, s, user_id, entry_id, data)
+}
+
+// addData inserts a record, recording the changes in j.
+func (mk *MemKeeper) addData(j *journal, s schema.Schema, user_id int, entry_id string, data map[string]string) error {
+	key := recordKey{table: s.Table, id: entry_id}
+	if _, ok := mk.records[key]; ok {
+		return fmt.Errorf("%w: %s %q already exists", storage.ErrConflict, s.Table, entry_id)
+	}
+
+	columns := make(map[string]string, len(data))
+	for column, value := range data {
+		columns[column] = value
+	}
+
+	mk.putRecord(j, key, record{
+		userID:    user_id,
+		updatedAt: now(),
+		version:   1,
+		changeSeq: mk.nextChangeSeq(j, user_id),
+		columns:   columns,
+	})
+
+	return nil
+}
+
+// UpdateData updates a record, refreshes its 'updated_at' field and increments its version.
+// If baseVersion is not zero, the record is updated only if its version still equals
+// baseVersion; otherwise a *storage.ConflictError with the current record is returned.
+// It returns the new version of the record.
+func (mk *MemKeeper) UpdateData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string, baseVersion int64) (int64, error) {
+	// Only vault tables and their columns are kept
+	s, err := schema.Lookup(table)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.ValidateUpdate(data); err != nil {
+		return 0, err
+	}
+
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	return mk.updateData(nil, s, user_id, entry_id, data, baseVersion)
+}
+
+// updateData updates a record, recording the changes in j, and returns its new version.
+func (mk *MemKeeper) updateData(j *journal, s schema.Schema, user_id int, entry_id string, data map[string]string, baseVersion int64) (int64, error) {
+	r, ok := mk.userRecord(s.Table, user_id, entry_id)
+	if !ok {
+		return 0, storage.ErrNotFound
+	}
+
+	// Update only the version the client has seen
+	if baseVersion != 0 && r.version != baseVersion {
+		return 0, &storage.ConflictError{Current: r.fields(s, entry_id)}
+	}
+
+	columns := make(map[string]string, len(r.columns)+len(data))
+	for column, value := range r.columns {
+		columns[column] = value
+	}
+	for column, value := range data {
+		columns[column] = value
+	}
+
+	r.columns = columns
+	r.updatedAt = now()
+	r.version++
+	r.changeSeq = mk.nextChangeSeq(j, user_id)
+	mk.putRecord(j, recordKey{table: s.Table, id: entry_id}, r)
+
+	return r.version, nil
+}
+
+// DeleteData marks a record as deleted and updates its 'updated_at' field.
+// It returns storage.ErrNotFound if there is no such record or it is already deleted.
+func (mk *MemKeeper) DeleteData(ctx context.Context, table string, user_id int, entry_id string) error {
+	// Check user_id and table
+	if user_id == 0 || table == "" {
+		return errors.New("user_id and table must be specified")
+	}
+
+	// Check entry_id
+	if entry_id == "" {
+		return errors.New("entry_id must be specified")
+	}
+
+	s, err := schema.Lookup(table)
+	if err != nil {
+		return err
+	}
+
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	return mk.deleteData(nil, s, user_id, entry_id)
+}
+
+// deleteData marks a record as deleted, recording the changes in j.
+func (mk *MemKeeper) deleteData(j *journal, s schema.Schema, user_id int, entry_id string) error {
+	r, ok := mk.userRecord(s.Table, user_id, entry_id)
+	if !ok || r.deleted {
+		return storage.ErrNotFound
+	}
+
+	// A deleted file entry no longer refers to its content
+	if s.Table == schema.FilesData {
+		mk.releaseBlob(j, r.columns["sha256"])
+	}
+
+	r.deleted = true
+	r.updatedAt = now()
+	r.version++
+	r.changeSeq = mk.nextChangeSeq(j, user_id)
+	mk.putRecord(j, recordKey{table: s.Table, id: entry_id}, r)
+
+	return nil
+}
+
+// Batch applies the operations of a user in order and returns a result for each applied
+// operation. Unless partial is set, the first failing operation undoes the whole batch and
+// is returned as a *storage.BatchError. In partial mode a failure undoes only that operation
+// and is reported in its result.
+func (mk *MemKeeper) Batch(ctx context.Context, userID int, ops []models.BatchOperation, partial bool) ([]models.BatchResult, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	var batch journal
+	results := make([]models.BatchResult, 0, len(ops))
+	for i, op := range ops {
+		var j journal
+
+		result := models.BatchResult{ID: op.ID}
+		result.Version, result.Err = mk.applyOperation(&j, userID, op)
+
+		var conflict *storage.ConflictError
+		if errors.As(result.Err, &conflict) {
+			result.Current = conflict.Current
+		}
+		results = append(results, result)
+
+		if result.Err == nil {
+			batch = append(batch, j...)
+			continue
+		}
+
+		j.rollback()
+		if !partial {
+			batch.rollback()
+			return results, &storage.BatchError{Index: i, Err: result.Err}
+		}
+	}
+
+	return results, nil
+}
+
+// applyOperation applies a single batch operation, recording the changes in j, and returns
+// the new version of the record, which is zero for deletions.
+func (mk *MemKeeper) applyOperation(j *journal, userID int, op models.BatchOperation) (int64, error) {
+	// Only vault tables and their columns are kept
+	s, err := schema.Lookup(op.Table)
+	if err != nil {
+		return 0, err
+	}
+	if op.ID == "" {
+		return 0, fmt.Errorf("%w: missing id", storage.ErrInvalidOperation)
+	}
+
+	switch op.Op {
+	case models.BatchAdd:
+		if err := s.ValidateAdd(op.Data); err != nil {
+			return 0, err
+		}
+		if err := mk.addData(j, s, userID, op.ID, op.Data); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	case models.BatchUpdate:
+		if err := s.ValidateUpdate(op.Data); err != nil {
+			return 0, err
+		}
+		return mk.updateData(j, s, userID, op.ID, op.Data, op.BaseVersion)
+	case models.BatchDelete:
+		return 0, mk.deleteData(j, s, userID, op.ID)
+	default:
+		return 0, fmt.Errorf("%w: %q", storage.ErrInvalidOperation, op.Op)
+	}
+}
+
+// SetFileContent records the size and SHA-256 digest of the uploaded content of a file entry
+// and moves the entry's blob reference to the new digest.
+// It returns storage.ErrNotFound if the entry does not exist or is deleted.
+func (mk *MemKeeper) SetFileContent(ctx context.Context, userID int, entryID string, size int64, digest string) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	r, ok := mk.userRecord(schema.FilesData, userID, entryID)
+	if !ok || r.deleted {
+		return storage.ErrNotFound
+	}
+	previous := r.columns["sha256"]
+
+	columns := make(map[string]string, len(r.columns)+2)
+	for column, value := range r.columns {
+		columns[column] = value
+	}
+	columns["size"] = strconv.FormatInt(size, 10)
+	columns["sha256"] = digest
+
+	r.columns = columns
+	r.updatedAt = now()
+	r.version++
+	r.changeSeq = mk.nextChangeSeq(nil, userID)
+	mk.putRecord(nil, recordKey{table: schema.FilesData, id: entryID}, r)
+
+	if previous != digest {
+		mk.releaseBlob(nil, previous)
+
+		b, ok := mk.blobs[digest]
+		if !ok {
+			b = blob{size: size}
+		}
+		b.refCount++
+		mk.blobs[digest] = b
+	}
+
+	return nil
+}
+
+// releaseBlob removes a reference to a blob, recording the change in j. Blobs without
+// references are left for DeleteBlob, which removes them together with their content.
+func (mk *MemKeeper) releaseBlob(j *journal, digest string) {
+	b, ok := mk.blobs[digest]
+	if digest == "" || !ok {
+		return
+	}
+
+	remember(j, mk.blobs, digest)
+	b.refCount--
+	mk.blobs[digest] = b
+}
+
+// UnreferencedBlobs returns the digests of blobs that no file entry refers to.
+func (mk *MemKeeper) UnreferencedBlobs(ctx context.Context) ([]string, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	var digests []string
+	for digest, b := range mk.blobs {
+		if b.refCount <= 0 {
+			digests = append(digests, digest)
+		}
+	}
+	sort.Strings(digests)
+
+	if len(digests) > maxUnreferencedBlobs {
+		digests = digests[:maxUnreferencedBlobs]
+	}
+
+	return digests, nil
+}
+
+// DeleteBlob removes a blob without references. The keeper stays locked while remove deletes
+// the content, so an upload of the same content waits and then stores it again.
+// It returns storage.ErrConflict if the blob got a new reference in the meantime.
+func (mk *MemKeeper) DeleteBlob(ctx context.Context, digest string, remove func() error) error {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	b, ok := mk.blobs[digest]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	if b.refCount > 0 {
+		return storage.ErrConflict
+	}
+
+	if err := remove(); err != nil {
+		return err
+	}
+	delete(mk.blobs, digest)
+
+	return nil
+}
+
+// LegacyBlobs returns nothing: the data lives only as long as the process,
+// so no content was stored before blobs were addressed by digest.
+func (mk *MemKeeper) LegacyBlobs(ctx context.Context) ([]models.LegacyBlob, error) {
+	return nil, nil
+}
+
+// ForgetLegacyBlob does nothing, see LegacyBlobs.
+func (mk *MemKeeper) ForgetLegacyBlob(ctx context.Context, userID int, entryID string) error {
+	return nil
+}
+
+// BlobStats summarizes the stored blobs and the space saved by sharing them.
+func (mk *MemKeeper) BlobStats(ctx context.Context) (models.BlobStats, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	var stats models.BlobStats
+	for _, b := range mk.blobs {
+		if b.refCount <= 0 {
+			continue
+		}
+		stats.Blobs++
+		stats.References += b.refCount
+		stats.StoredBytes += b.size
+		stats.LogicalBytes += b.size * b.refCount
+	}
+	stats.SavedBytes = stats.LogicalBytes - stats.StoredBytes
+
+	return stats, nil
+}
+
+// GetFileContent retrieves the content attributes of a file entry.
+// It returns storage.ErrNotFound if the entry does not exist, is deleted or has no uploaded content.
+func (mk *MemKeeper) GetFileContent(ctx context.Context, userID int, entryID string) (models.FileContent, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	r, ok := mk.userRecord(schema.FilesData, userID, entryID)
+	if !ok || r.deleted {
+		return models.FileContent{}, storage.ErrNotFound
+	}
+
+	digest, ok := r.columns["sha256"]
+	if !ok {
+		return models.FileContent{}, storage.ErrNotFound
+	}
+
+	size, err := strconv.ParseInt(r.columns["size"], 10, 64)
+	if err != nil {
+		return models.FileContent{}, err
+	}
+
+	return models.FileContent{Size: size, SHA256: digest, UpdatedAt: r.updatedAt}, nil
+}
+
+// LegacyFiles returns nothing: the data lives only as long as the process,
+// so no file was uploaded before the per-user directories.
+func (mk *MemKeeper) LegacyFiles(ctx context.Context) ([]models.LegacyFile, error) {
+	return nil, nil
+}
+
+// Sync retrieves the records of all vault tables that a user changed after the given
+// change sequence number, ordered by the change. At most limit changes are returned;
+// hasMore reports whether further changes remain.
+func (mk *MemKeeper) Sync(ctx context.Context, userID int, afterSeq int64, limit int) ([]models.Change, bool, error) {
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	var changes []models.Change
+	for key, r := range mk.records {
+		if r.userID != userID || r.changeSeq <= afterSeq {
+			continue
+		}
+
+		s, err := schema.Lookup(string(key.table))
+		if err != nil {
+			return nil, false, err
+		}
+		changes = append(changes, models.Change{Table: string(s.Table), Seq: r.changeSeq, Entry: r.fields(s, key.id)})
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Seq < changes[j].Seq })
+
+	hasMore := len(changes) > limit
+	if hasMore {
+		changes = changes[:limit]
+	}
+
+	return changes, hasMore, nil
+}
+
+// GetData retrieves a single record of a user from a table.
+// It returns storage.ErrNotFound if there is no such record or it is deleted.
+func (mk *MemKeeper) GetData(ctx context.Context, table string, userID int, entryID string) (map[string]string, error) {
+	s, err := schema.Lookup(table)
+	if err != nil {
+		return nil, err
+	}
+
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	// Deleted records are kept only for sync, so they are not served
+	r, ok := mk.userRecord(s.Table, userID, entryID)
+	if !ok || r.deleted {
+		return nil, storage.ErrNotFound
+	}
+
+	return r.fields(s, entryID), nil
+}
+
+// GetAllData retrieves the records of a user from a table, ordered by ID. Deleted records
+// are included only if inclDel is set; a non-zero lastSync limits the result to records
+// changed after it.
+func (mk *MemKeeper) GetAllData(ctx context.Context, table string, userID int, lastSync time.Time, inclDel bool) ([]map[string]string, error) {
+	s, err := schema.Lookup(table)
+	if err != nil {
+		return nil, err
+	}
+
+	mk.mu.Lock()
+	defer mk.mu.Unlock()
+
+	var data []map[string]string
+	for key, r := range mk.records {
+		if key.table != s.Table || r.userID != userID {
+			continue
+		}
+		if !inclDel && r.deleted {
+			continue
+		}
+		if !lastSync.IsZero() && !r.updatedAt.After(lastSync) {
+			continue
+		}
+		data = append(data, r.fields(s, key.id))
+	}
+
+	sort.Slice(data, func(i, j int) bool { return data[i]["id"] < data[j]["id"] })
+
+	return data, nil
+}
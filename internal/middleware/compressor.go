@@ -21,12 +21,12 @@ func GzipMiddleware(h http.Handler) http.Handler {
 		acceptEncoding := r.Header.Get("Accept-Encoding")
 		supportsGzip := strings.Contains(acceptEncoding, "gzip")
 		if supportsGzip {
-			// wrap the original http.ResponseWriter with a new one with compression support
-			cw := compress.NewCompressWriter(w)
+			// wrap the original http.ResponseWriter with one that compresses JSON and text responses
+			gw := &gzipResponseWriter{ResponseWriter: w}
 			// change the original http.ResponseWriter to a new one
-			ow = cw
+			ow = gw
 			// do not forget to send all compressed data to the client after the middleware is completed
-			defer cw.Close()
+			defer gw.Close()
 		}
 
 		// check that the client sent compressed data to the server in gzip format
@@ -48,3 +48,70 @@ func GzipMiddleware(h http.Handler) http.Handler {
 		h.ServeHTTP(ow, r)
 	})
 }
+
+// gzipResponseWriter compresses the response once its status and content type are known.
+// File content keeps its length and byte ranges, so it is written as is.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	cw          *compress.CompressWriter
+	wroteHeader bool
+}
+
+// WriteHeader decides whether the response is compressed and sends the status code.
+func (g *gzipResponseWriter) WriteHeader(statusCode int) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+
+	g.Header().Add("Vary", "Accept-Encoding")
+	if compressible(statusCode, g.Header()) {
+		g.Header().Del("Content-Length")
+		g.cw = compress.NewCompressWriter(g.ResponseWriter)
+		g.cw.WriteHeader(statusCode)
+		return
+	}
+	g.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write sends the response body, compressing it if it was chosen to.
+func (g *gzipResponseWriter) Write(p []byte) (int, error) {
+	if !g.wroteHeader {
+		g.WriteHeader(http.StatusOK)
+	}
+	if g.cw != nil {
+		return g.cw.Write(p)
+	}
+	return g.ResponseWriter.Write(p)
+}
+
+// Close flushes the compressed data.
+func (g *gzipResponseWriter) Close() error {
+	if g.cw == nil {
+		return nil
+	}
+	return g.cw.Close()
+}
+
+// Unwrap returns the original http.ResponseWriter for http.ResponseController.
+func (g *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
+
+// compressible reports whether a response is worth compressing. The status codes
+// are those for which CompressWriter sets the Content-Encoding header.
+func compressible(statusCode int, header http.Header) bool {
+	if statusCode == http.StatusNoContent || statusCode >= 300 && statusCode != http.StatusConflict {
+		return false
+	}
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+
+	// Event streams are flushed event by event, so they are sent as is
+	contentType := header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "text/event-stream") {
+		return false
+	}
+	return strings.HasPrefix(contentType, "application/json") || strings.HasPrefix(contentType, "text/")
+}
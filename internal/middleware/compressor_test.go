@@ -85,3 +85,22 @@ func TestGzipMiddleware(t *testing.T) {
 
 	})
 }
+
+func TestGzipMiddleware_FileContent(t *testing.T) {
+	handler := GzipMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", "7")
+		w.Write([]byte("content"))
+	}))
+
+	r := httptest.NewRequest("GET", "/getFile/1/entry", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, r)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Empty(t, rr.Header().Get("Content-Encoding"))
+	require.Equal(t, "7", rr.Header().Get("Content-Length"))
+	require.Equal(t, "content", rr.Body.String())
+}
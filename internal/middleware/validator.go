@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"go.uber.org/zap"
+)
+
+// RequestValidator is a middleware that checks incoming requests against the OpenAPI spec.
+type RequestValidator struct {
+	router      routers.Router
+	maxBodySize int64
+	log         Log
+}
+
+// NewRequestValidator creates a new instance of RequestValidator for the specified spec.
+// JSON request bodies larger than maxBodySize bytes are rejected.
+func NewRequestValidator(spec *openapi3.T, maxBodySize int64, log Log) (*RequestValidator, error) {
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestValidator{
+		router:      router,
+		maxBodySize: maxBodySize,
+		log:         log,
+	}, nil
+}
+
+// Validate is an HTTP middleware that rejects requests that do not match the spec
+// with 400 Bad Request. A body of an operation that takes JSON is always validated,
+// so a body with another or no media type is rejected with 415 Unsupported Media Type
+// and a body that exceeds the size limit with 413 Request Entity Too Large.
+// Requests to paths that the spec does not describe are passed on.
+func (rv *RequestValidator) Validate(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := rv.router.FindRoute(r)
+		if err != nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		jsonBody := takesJSON(route.Operation)
+		if jsonBody && r.ContentLength != 0 && !isJSON(r) {
+			http.Error(w, "request body must be application/json", http.StatusUnsupportedMediaType)
+			return
+		}
+		if jsonBody {
+			// The body is read whole to be validated, so its size is limited
+			r.Body = http.MaxBytesReader(w, r.Body, rv.maxBodySize)
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				// Tokens are checked by the authorization middleware
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				// File content is streamed to the handler as is
+				ExcludeRequestBody: !jsonBody,
+			},
+		}
+		input.Options.WithCustomSchemaErrorFunc(func(err *openapi3.SchemaError) string {
+			return err.Reason
+		})
+
+		err = openapi3filter.ValidateRequest(r.Context(), input)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			rv.log.Info("request does not match the spec", zap.String("path", r.URL.Path), zap.Error(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// takesJSON reports whether the spec declares a JSON request body for the operation.
+func takesJSON(operation *openapi3.Operation) bool {
+	if operation == nil || operation.RequestBody == nil || operation.RequestBody.Value == nil {
+		return false
+	}
+
+	return operation.RequestBody.Value.Content.Get("application/json") != nil
+}
+
+// isJSON reports whether the request declares a JSON body.
+func isJSON(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/gophkeeper-server/internal/controllers"
+	"go.uber.org/zap/zapcore"
+)
+
+type mockLog struct{}
+
+func (l *mockLog) Info(string, ...zapcore.Field) {}
+
+func TestRequestValidator_Validate(t *testing.T) {
+	spec, err := controllers.GetSwagger()
+	require.NoError(t, err)
+
+	validator, err := NewRequestValidator(spec, 64, &mockLog{})
+	require.NoError(t, err)
+
+	handler := validator.Validate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		code        int
+	}{
+		{"valid item", http.MethodPost, "/addData/TextData/1/entry", "application/json", `{"data":"text","meta_info":"note"}`, http.StatusOK},
+		{"unknown field", http.MethodPost, "/addData/TextData/1/entry", "application/json", `{"data":"text","color":"red"}`, http.StatusBadRequest},
+		{"missing field", http.MethodPost, "/addData/CreditCardData/1/entry", "application/json", `{"card_number":"4111111111111111"}`, http.StatusBadRequest},
+		{"empty patch", http.MethodPut, "/updateData/TextData/1/entry", "application/json", `{}`, http.StatusBadRequest},
+		{"invalid userID", http.MethodGet, "/getData/TextData/user/entry", "", "", http.StatusBadRequest},
+		{"missing media type", http.MethodPost, "/addData/TextData/1/entry", "", `{"data":"text","color":"red"}`, http.StatusUnsupportedMediaType},
+		{"wrong media type", http.MethodPost, "/addData/TextData/1/entry", "text/plain", `{"data":"text"}`, http.StatusUnsupportedMediaType},
+		{"json with charset", http.MethodPost, "/addData/TextData/1/entry", "application/json; charset=utf-8", `{"data":"text","color":"red"}`, http.StatusBadRequest},
+		{"missing body", http.MethodPost, "/addData/TextData/1/entry", "", "", http.StatusBadRequest},
+		{"body too large", http.MethodPost, "/addData/TextData/1/entry", "application/json", `{"data":"` + strings.Repeat("x", 64) + `"}`, http.StatusRequestEntityTooLarge},
+		{"file content", http.MethodPost, "/sendFile/1/file.bin", "application/octet-stream", "content", http.StatusOK},
+		{"unknown path", http.MethodGet, "/unknown", "", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.code, rr.Code, rr.Body.String())
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Key is an alias for string and represents a key used in various contexts.
 type Key string
 
@@ -7,3 +9,156 @@ type Key string
 type Response struct {
 	Result string `json:"result"`
 }
+
+// Session describes a device session of a user.
+type Session struct {
+	ID         string    `json:"id"`
+	DeviceID   string    `json:"deviceID"`
+	DeviceName string    `json:"deviceName"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeen   time.Time `json:"lastSeen"`
+	Current    bool      `json:"current"`
+}
+
+// MFA describes the two-factor authentication settings of a user.
+type MFA struct {
+	Secret        string
+	PendingSecret string
+	Enabled       bool
+	LastStep      int64
+}
+
+// MFAChallenge describes a login that has passed the password step and awaits the second factor.
+type MFAChallenge struct {
+	ID         string
+	UserID     int
+	DeviceID   string
+	DeviceName string
+	ExpiresAt  time.Time
+}
+
+// SRPVerifier describes the SRP-6a salt and verifier that a user registers instead of a password.
+type SRPVerifier struct {
+	Salt     string
+	Verifier string
+}
+
+// SRPChallenge describes an SRP-6a login that awaits the client proof.
+type SRPChallenge struct {
+	ID        string
+	Username  string
+	A         string
+	B         string
+	PrivateB  string
+	ExpiresAt time.Time
+}
+
+// LegacyFile identifies a file entry whose content was uploaded before the files were kept
+// in per-user directories and has no recorded size and digest.
+type LegacyFile struct {
+	UserID  int
+	EntryID string
+}
+
+// FileContent describes the uploaded content of a FilesData entry.
+type FileContent struct {
+	Size      int64
+	SHA256    string
+	UpdatedAt time.Time
+}
+
+// BlobStats summarizes the content-addressed blobs.
+// LogicalBytes is what the file entries would occupy without sharing blobs.
+type BlobStats struct {
+	Blobs        int64 `json:"blobs"`
+	References   int64 `json:"references"`
+	StoredBytes  int64 `json:"storedBytes"`
+	LogicalBytes int64 `json:"logicalBytes"`
+	SavedBytes   int64 `json:"savedBytes"`
+}
+
+// LegacyBlob is a file entry whose content may still be stored under the entry
+// instead of its digest, as it was before blobs were shared.
+type LegacyBlob struct {
+	UserID  int
+	EntryID string
+	SHA256  string
+	Deleted bool
+}
+
+// CacheStats counts the lookups of the storage cache. A lookup misses if the entry
+// is absent, expired or outdated by a change.
+type CacheStats struct {
+	Entries       int64 `json:"entries"`
+	Hits          int64 `json:"hits"`
+	Misses        int64 `json:"misses"`
+	Evictions     int64 `json:"evictions"`
+	Invalidations int64 `json:"invalidations"`
+}
+
+// ReencryptionProgress describes the latest run of the re-encryption job, which wraps
+// the data keys with the current master key and encrypts records stored unencrypted.
+// Users counts the users that have been processed so far, out of Total users when the
+// run started; users registered during the run are processed as well.
+type ReencryptionProgress struct {
+	Running    bool       `json:"running"`
+	KeyID      string     `json:"keyID"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Users      int64      `json:"users"`
+	Total      int64      `json:"total"`
+	DataKeys   int64      `json:"dataKeys"`
+	Records    int64      `json:"records"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// Change describes a vault record changed after a sync cursor.
+type Change struct {
+	Table string            `json:"table"`
+	Seq   int64             `json:"-"`
+	Entry map[string]string `json:"entry"`
+}
+
+// Batch operation kinds.
+const (
+	BatchAdd    = "add"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// BatchOperation is a single mutation of a batch.
+// BaseVersion makes an update conditional on the current version of the record.
+type BatchOperation struct {
+	Op          string            `json:"op"`
+	Table       string            `json:"table"`
+	ID          string            `json:"id"`
+	Data        map[string]string `json:"data,omitempty"`
+	BaseVersion int64             `json:"baseVersion,omitempty"`
+}
+
+// BatchResult is the outcome of a batch operation. Current is the server copy
+// of a record an update conflicted with.
+type BatchResult struct {
+	ID      string            `json:"id"`
+	Status  int               `json:"status"`
+	Version int64             `json:"version,omitempty"`
+	Error   string            `json:"error,omitempty"`
+	Current map[string]string `json:"current,omitempty"`
+	Err     error             `json:"-"`
+}
+
+// Event types.
+const (
+	EventItemChanged = "item-changed"
+	EventItemDeleted = "item-deleted"
+)
+
+// Event notifies the devices of a user that a vault record has been changed or deleted.
+// Version is the version of the record after the change, if it is known.
+type Event struct {
+	Type    string `json:"type"`
+	Table   string `json:"table"`
+	ID      string `json:"id"`
+	Version int64  `json:"version,omitempty"`
+}
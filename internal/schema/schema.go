@@ -0,0 +1,144 @@
+// Package schema describes the vault record kinds accepted by the generic data routes.
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrUnknownTable indicates that the requested table is not a vault record kind.
+	ErrUnknownTable = errors.New("unknown table")
+	// ErrUnknownColumn indicates that the request body contains a column the table does not allow.
+	ErrUnknownColumn = errors.New("unknown column")
+	// ErrMissingField indicates that a required column is absent from the request body.
+	ErrMissingField = errors.New("missing required field")
+	// ErrEmptyData indicates that the request body contains no columns at all.
+	ErrEmptyData = errors.New("no data provided")
+)
+
+// Table is the name of a table holding one kind of vault record.
+type Table string
+
+// Vault record kinds.
+const (
+	UserCredentials Table = "UserCredentials"
+	CreditCardData  Table = "CreditCardData"
+	TextData        Table = "TextData"
+	FilesData       Table = "FilesData"
+)
+
+// Schema lists the columns a client may write for a vault record kind.
+type Schema struct {
+	// Table is the canonical table name used in SQL statements.
+	Table Table
+	// Columns are the columns a client may set.
+	Columns []string
+	// Required are the columns that must be present when a record is added.
+	Required []string
+	// Server are the columns of this kind that only the server writes.
+	Server []string
+	// Encrypted are the client columns that are encrypted at rest when the server has a master key.
+	Encrypted []string
+}
+
+// Service columns maintained by the server for every vault record kind.
+var serviceColumns = []string{"id", "user_id", "deleted", "updated_at", "version"}
+
+var registry = map[Table]Schema{
+	UserCredentials: {
+		Table:     UserCredentials,
+		Columns:   []string{"login", "password", "meta_info"},
+		Required:  []string{"login", "password"},
+		Encrypted: []string{"login", "password"},
+	},
+	CreditCardData: {
+		Table:     CreditCardData,
+		Columns:   []string{"card_number", "expiration_date", "cvv", "meta_info"},
+		Required:  []string{"card_number", "expiration_date", "cvv"},
+		Encrypted: []string{"card_number", "cvv"},
+	},
+	TextData: {
+		Table:     TextData,
+		Columns:   []string{"data", "meta_info"},
+		Required:  []string{"data"},
+		Encrypted: []string{"data"},
+	},
+	FilesData: {
+		Table:    FilesData,
+		Columns:  []string{"path", "extension", "meta_info"},
+		Required: []string{"path"},
+		Server:   []string{"size", "sha256"},
+	},
+}
+
+// Lookup returns the schema of the given table. The name is matched case-insensitively,
+// the same way PostgreSQL treats unquoted identifiers.
+func Lookup(name string) (Schema, error) {
+	for table, s := range registry {
+		if strings.EqualFold(string(table), name) {
+			return s, nil
+		}
+	}
+
+	return Schema{}, fmt.Errorf("%w: %q", ErrUnknownTable, name)
+}
+
+// Tables returns the names of all vault record kinds.
+func Tables() []Table {
+	return []Table{UserCredentials, CreditCardData, TextData, FilesData}
+}
+
+// AllColumns returns the service columns followed by the client and server columns of the table.
+func (s Schema) AllColumns() []string {
+	cols := make([]string, 0, len(serviceColumns)+len(s.Columns)+len(s.Server))
+	cols = append(cols, serviceColumns...)
+	cols = append(cols, s.Columns...)
+	cols = append(cols, s.Server...)
+
+	return cols
+}
+
+// HasColumn reports whether a client may write the given column.
+func (s Schema) HasColumn(column string) bool {
+	for _, c := range s.Columns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateAdd checks the body of an add request: every column must be known
+// and every required column must be present.
+func (s Schema) ValidateAdd(data map[string]string) error {
+	if err := s.ValidateUpdate(data); err != nil {
+		return err
+	}
+
+	for _, column := range s.Required {
+		if _, ok := data[column]; !ok {
+			return fmt.Errorf("%w: %s.%s", ErrMissingField, s.Table, column)
+		}
+	}
+
+	return nil
+}
+
+// ValidateUpdate checks the body of an update request: it must not be empty
+// and every column must be known.
+func (s Schema) ValidateUpdate(data map[string]string) error {
+	if len(data) == 0 {
+		return ErrEmptyData
+	}
+
+	for column := range data {
+		if !s.HasColumn(column) {
+			return fmt.Errorf("%w: %s.%s", ErrUnknownColumn, s.Table, column)
+		}
+	}
+
+	return nil
+}
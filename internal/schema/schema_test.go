@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	s, err := Lookup("textdata")
+	assert.NoError(t, err)
+	assert.Equal(t, TextData, s.Table)
+
+	_, err = Lookup("Users")
+	assert.ErrorIs(t, err, ErrUnknownTable)
+
+	_, err = Lookup("TextData; DROP TABLE Users")
+	assert.ErrorIs(t, err, ErrUnknownTable)
+}
+
+func TestTables(t *testing.T) {
+	for _, table := range Tables() {
+		s, err := Lookup(string(table))
+		assert.NoError(t, err)
+		assert.Equal(t, table, s.Table)
+	}
+}
+
+func TestSchema_ValidateAdd(t *testing.T) {
+	s, err := Lookup(string(UserCredentials))
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.ValidateAdd(map[string]string{"login": "l", "password": "p"}))
+	assert.NoError(t, s.ValidateAdd(map[string]string{"login": "l", "password": "p", "meta_info": "m"}))
+	assert.ErrorIs(t, s.ValidateAdd(map[string]string{"login": "l"}), ErrMissingField)
+	assert.ErrorIs(t, s.ValidateAdd(map[string]string{"login": "l", "password": "p", "user_id": "2"}), ErrUnknownColumn)
+	assert.ErrorIs(t, s.ValidateAdd(nil), ErrEmptyData)
+}
+
+func TestSchema_ValidateUpdate(t *testing.T) {
+	s, err := Lookup(string(CreditCardData))
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.ValidateUpdate(map[string]string{"cvv": "123"}))
+	assert.ErrorIs(t, s.ValidateUpdate(map[string]string{"cvv = cvv, user_id": "1"}), ErrUnknownColumn)
+	assert.ErrorIs(t, s.ValidateUpdate(map[string]string{}), ErrEmptyData)
+}
+
+func TestSchema_AllColumns(t *testing.T) {
+	s, err := Lookup(string(TextData))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"id", "user_id", "deleted", "updated_at", "version", "data", "meta_info"}, s.AllColumns())
+}
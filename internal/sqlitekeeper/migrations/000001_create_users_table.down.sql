@@ -0,0 +1 @@
+DROP TABLE IF EXISTS Users;
\ No newline at end of file
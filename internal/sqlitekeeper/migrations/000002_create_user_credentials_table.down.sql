@@ -0,0 +1 @@
+DROP TABLE IF EXISTS UserCredentials;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS TextData;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS TextData (
+    id TEXT PRIMARY KEY,
+    user_id INTEGER,
+    data TEXT NOT NULL,
+    meta_info TEXT,
+    deleted BOOLEAN DEFAULT FALSE,
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    FOREIGN KEY(user_id) REFERENCES Users(id)
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_revokedtokens_expires_at;
+DROP INDEX IF EXISTS idx_refreshtokens_expires_at;
+DROP TABLE IF EXISTS RevokedTokens;
+DROP TABLE IF EXISTS RefreshTokens;
\ No newline at end of file
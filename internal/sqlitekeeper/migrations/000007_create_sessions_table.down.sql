@@ -0,0 +1,2 @@
+ALTER TABLE RefreshTokens DROP COLUMN session_id;
+DROP TABLE IF EXISTS Sessions;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS Sessions (
+    id TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    device_id TEXT NOT NULL,
+    device_name TEXT,
+    ip TEXT,
+    revoked BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    last_seen TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    FOREIGN KEY(user_id) REFERENCES Users(id)
+);
+
+ALTER TABLE RefreshTokens ADD COLUMN session_id TEXT REFERENCES Sessions(id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS RecoveryCodes;
+DROP TABLE IF EXISTS UserMFA;
\ No newline at end of file
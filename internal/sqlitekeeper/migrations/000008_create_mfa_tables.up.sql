@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS UserMFA (
+    user_id INTEGER PRIMARY KEY,
+    secret TEXT,
+    pending_secret TEXT,
+    enabled BOOLEAN DEFAULT FALSE,
+    last_step BIGINT DEFAULT 0,
+    FOREIGN KEY(user_id) REFERENCES Users(id)
+);
+
+CREATE TABLE IF NOT EXISTS RecoveryCodes (
+    user_id INTEGER NOT NULL,
+    code_hash TEXT NOT NULL,
+    used BOOLEAN DEFAULT FALSE,
+    PRIMARY KEY(user_id, code_hash),
+    FOREIGN KEY(user_id) REFERENCES Users(id)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE FilesData DROP COLUMN version;
+ALTER TABLE TextData DROP COLUMN version;
+ALTER TABLE CreditCardData DROP COLUMN version;
+ALTER TABLE UserCredentials DROP COLUMN version;
\ No newline at end of file
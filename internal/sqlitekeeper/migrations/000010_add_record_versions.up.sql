@@ -0,0 +1,4 @@
+ALTER TABLE UserCredentials ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE CreditCardData ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE TextData ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE FilesData ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,11 @@
+DROP INDEX idx_filesdata_change_seq;
+DROP INDEX idx_textdata_change_seq;
+DROP INDEX idx_creditcarddata_change_seq;
+DROP INDEX idx_usercredentials_change_seq;
+
+ALTER TABLE FilesData DROP COLUMN change_seq;
+ALTER TABLE TextData DROP COLUMN change_seq;
+ALTER TABLE CreditCardData DROP COLUMN change_seq;
+ALTER TABLE UserCredentials DROP COLUMN change_seq;
+
+DROP TABLE IF EXISTS SyncSequences;
\ No newline at end of file
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS SyncSequences (
+    user_id INTEGER PRIMARY KEY,
+    last_seq BIGINT NOT NULL DEFAULT 0,
+    FOREIGN KEY(user_id) REFERENCES Users(id)
+);
+
+ALTER TABLE UserCredentials ADD COLUMN change_seq BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE CreditCardData ADD COLUMN change_seq BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE TextData ADD COLUMN change_seq BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE FilesData ADD COLUMN change_seq BIGINT NOT NULL DEFAULT 0;
+
+-- Number the existing records of every user in the order they were changed
+CREATE TEMPORARY TABLE SyncBackfill AS
+SELECT t, id, user_id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY updated_at, t, id) AS seq
+FROM (
+    SELECT 'UserCredentials' AS t, id, user_id, updated_at FROM UserCredentials
+    UNION ALL SELECT 'CreditCardData', id, user_id, updated_at FROM CreditCardData
+    UNION ALL SELECT 'TextData', id, user_id, updated_at FROM TextData
+    UNION ALL SELECT 'FilesData', id, user_id, updated_at FROM FilesData
+) AS records
+WHERE user_id IS NOT NULL;
+
+UPDATE UserCredentials AS r SET change_seq = b.seq FROM SyncBackfill AS b WHERE b.t = 'UserCredentials' AND b.id = r.id;
+UPDATE CreditCardData AS r SET change_seq = b.seq FROM SyncBackfill AS b WHERE b.t = 'CreditCardData' AND b.id = r.id;
+UPDATE TextData AS r SET change_seq = b.seq FROM SyncBackfill AS b WHERE b.t = 'TextData' AND b.id = r.id;
+UPDATE FilesData AS r SET change_seq = b.seq FROM SyncBackfill AS b WHERE b.t = 'FilesData' AND b.id = r.id;
+
+-- The WHERE clause keeps SQLite from reading ON CONFLICT as a join constraint
+INSERT INTO SyncSequences (user_id, last_seq)
+SELECT user_id, MAX(seq) FROM SyncBackfill WHERE TRUE GROUP BY user_id
+ON CONFLICT (user_id) DO UPDATE SET last_seq = excluded.last_seq;
+
+DROP TABLE SyncBackfill;
+
+CREATE INDEX IF NOT EXISTS idx_usercredentials_change_seq ON UserCredentials (user_id, change_seq);
+CREATE INDEX IF NOT EXISTS idx_creditcarddata_change_seq ON CreditCardData (user_id, change_seq);
+CREATE INDEX IF NOT EXISTS idx_textdata_change_seq ON TextData (user_id, change_seq);
+CREATE INDEX IF NOT EXISTS idx_filesdata_change_seq ON FilesData (user_id, change_seq);
@@ -0,0 +1,2 @@
+ALTER TABLE FilesData DROP COLUMN sha256;
+ALTER TABLE FilesData DROP COLUMN size;
\ No newline at end of file
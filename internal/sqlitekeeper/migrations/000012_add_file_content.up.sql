@@ -0,0 +1,2 @@
+ALTER TABLE FilesData ADD COLUMN size BIGINT;
+ALTER TABLE FilesData ADD COLUMN sha256 TEXT;
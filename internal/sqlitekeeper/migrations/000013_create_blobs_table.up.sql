@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS Blobs (
+    sha256 TEXT PRIMARY KEY,
+    size BIGINT NOT NULL,
+    ref_count INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO Blobs (sha256, size, ref_count)
+SELECT sha256, MAX(size), COUNT(*)
+FROM FilesData
+WHERE sha256 IS NOT NULL AND deleted = FALSE
+GROUP BY sha256
+ON CONFLICT (sha256) DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS idx_blobs_unreferenced ON Blobs (sha256) WHERE ref_count <= 0;
+
+ALTER TABLE FilesData ADD COLUMN legacy_blob BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE FilesData SET legacy_blob = TRUE WHERE sha256 IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_filesdata_legacy_blob ON FilesData (user_id, id) WHERE legacy_blob;
\ No newline at end of file
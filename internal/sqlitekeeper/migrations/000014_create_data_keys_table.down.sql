@@ -0,0 +1 @@
+DROP TABLE IF EXISTS DataKeys;
\ No newline at end of file
@@ -0,0 +1,1612 @@
+// Package sqlitekeeper provides a keeper that stores the data in an embedded SQLite
+// database file. It behaves like bdkeeper.BDKeeper, but needs no database server,
+// so it suits single-node deployments.
+package sqlitekeeper
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/schema"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	sqlitedriver "modernc.org/sqlite" // registers a pure Go sqlite driver.
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Scheme is the prefix of the DSNs that select this keeper, as in sqlite:///var/lib/gophkeeper.db.
+const Scheme = "sqlite://"
+
+// timeFormat is the format of the stored timestamps. The fixed width and the UTC zone
+// keep their text order the same as their time order, so that they can be compared in queries.
+const timeFormat = "2006-01-02 15:04:05.000000"
+
+// migrations mirror the PostgreSQL migrations with the SQLite dialect.
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Log represents a logging interface.
+type Log interface {
+	Info(string, ...zapcore.Field)
+}
+
+// SQLiteKeeper represents a SQLite database keeper.
+type SQLiteKeeper struct {
+	conn *sql.DB
+	log  Log
+}
+
+// NewSQLiteKeeper opens the database file named by a sqlite:// DSN, creating it if needed,
+// and migrates it to the current schema.
+func NewSQLiteKeeper(dsn func() string, log Log) (*SQLiteKeeper, error) {
+	path := strings.TrimPrefix(dsn(), Scheme)
+	if path == "" {
+		log.Info("database path is empty")
+		return nil, errors.New("database path is empty")
+	}
+
+	conn, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		log.Info("Unable to open database: ", zap.Error(err))
+		return nil, err
+	}
+
+	// A single connection serializes the transactions, which takes the place of the
+	// row locks the PostgreSQL keeper relies on
+	conn.SetMaxOpenConns(1)
+
+	if err := migrateUp(conn); err != nil {
+		log.Info("Error while performing migration: ", zap.Error(err))
+		conn.Close()
+		return nil, err
+	}
+
+	log.Info("Connected!")
+
+	return &SQLiteKeeper{
+		conn: conn,
+		log:  log,
+	}, nil
+}
+
+// migrateUp applies the embedded migrations to the database.
+func migrateUp(conn *sql.DB) error {
+	source, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return err
+	}
+
+	driver, err := sqlite.WithInstance(conn, new(sqlite.Config))
+	if err != nil {
+		return err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "sqlite", driver)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	return nil
+}
+
+// timestamp formats a time for storing it in the database.
+func timestamp(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+// Ping checks the connectivity to the SQLite database and returns true if successful, otherwise false.
+func (sk *SQLiteKeeper) Ping() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	if err := sk.conn.PingContext(ctx); err != nil {
+		return false
+	}
+
+	return true
+}
+
+// Close closes the SQLite database and returns true if successful, otherwise false.
+func (sk *SQLiteKeeper) Close() bool {
+	sk.log.Info("Stop database")
+	err := sk.conn.Close()
+	if err != nil {
+		sk.log.Info("Error closing database connection: ", zap.Error(err))
+		return false
+	}
+	sk.log.Info("All SQL queries are completed")
+	return true
+}
+
+// UserExists checks if a user exists in the database.
+func (sk *SQLiteKeeper) UserExists(ctx context.Context, username string) (bool, error) {
+	// Query to check if the user exists in the database.
+	query := `SELECT COUNT(*) FROM Users WHERE username = ?;`
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query, username)
+
+	// Get the result.
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// AddUser adds a new user to the database.
+func (sk *SQLiteKeeper) AddUser(ctx context.Context, username string, hashedPassword string) error {
+	// Query to add a new user to the database.
+	query := `INSERT INTO Users (username, password) VALUES (?, ?);`
+
+	// Execute the query.
+	_, err := sk.conn.ExecContext(ctx, query, username, hashedPassword)
+	return err
+}
+
+// AddSRPUser adds a new user that logs in with SRP to the database.
+// Such users have no password verifier, so the password login always fails for them.
+func (sk *SQLiteKeeper) AddSRPUser(ctx context.Context, username string, verifier models.SRPVerifier) error {
+	tx, err := sk.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query to add the user without a password.
+	userQuery := `INSERT INTO Users (username, password) VALUES (?, '') RETURNING id;`
+	var userID int
+	if err := tx.QueryRowContext(ctx, userQuery, username).Scan(&userID); err != nil {
+		return err
+	}
+
+	// Query to add the SRP verifier of the user.
+	verifierQuery := `INSERT INTO SRPVerifiers (user_id, salt, verifier) VALUES (?, ?, ?);`
+	if _, err := tx.ExecContext(ctx, verifierQuery, userID, verifier.Salt, verifier.Verifier); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetSRPVerifier retrieves the user ID and SRP verifier of a user from the database.
+// It returns storage.ErrNotFound if the user does not log in with SRP.
+func (sk *SQLiteKeeper) GetSRPVerifier(ctx context.Context, username string) (int, models.SRPVerifier, error) {
+	// Query to retrieve the verifier.
+	query := `SELECT u.id, v.salt, v.verifier FROM Users u
+		JOIN SRPVerifiers v ON v.user_id = u.id
+		WHERE u.username = ?;`
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query, username)
+
+	// Get the result.
+	var userID int
+	var verifier models.SRPVerifier
+	err := row.Scan(&userID, &verifier.Salt, &verifier.Verifier)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, models.SRPVerifier{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return 0, models.SRPVerifier{}, err
+	}
+
+	return userID, verifier, nil
+}
+
+// AddSRPChallenge stores the server side of an SRP login attempt in the database.
+// Expired attempts that were never answered are removed in the same transaction.
+func (sk *SQLiteKeeper) AddSRPChallenge(ctx context.Context, challenge models.SRPChallenge) error {
+	tx, err := sk.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM SRPChallenges WHERE expires_at < ?", timestamp(time.Now()))
+	if err != nil {
+		return err
+	}
+
+	// Query to add the challenge.
+	query := `INSERT INTO SRPChallenges (id, username, public_a, public_b, private_b, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?);`
+
+	// Execute the query.
+	_, err = tx.ExecContext(ctx, query, challenge.ID, challenge.Username,
+		challenge.A, challenge.B, challenge.PrivateB, timestamp(challenge.ExpiresAt))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ConsumeSRPChallenge removes an SRP login attempt from the database and returns it,
+// so that every attempt can be answered only once.
+// It returns storage.ErrNotFound if the challenge is unknown or expired.
+func (sk *SQLiteKeeper) ConsumeSRPChallenge(ctx context.Context, id string) (models.SRPChallenge, error) {
+	// Query to remove the challenge and return it in a single statement.
+	query := `DELETE FROM SRPChallenges WHERE id = ?
+		RETURNING username, public_a, public_b, private_b, expires_at;`
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query, id)
+
+	// Get the result.
+	challenge := models.SRPChallenge{ID: id}
+	err := row.Scan(&challenge.Username, &challenge.A, &challenge.B, &challenge.PrivateB, &challenge.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.SRPChallenge{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return models.SRPChallenge{}, err
+	}
+
+	if !challenge.ExpiresAt.After(time.Now().UTC()) {
+		return models.SRPChallenge{}, storage.ErrNotFound
+	}
+
+	return challenge, nil
+}
+
+// GetPassword retrieves the hashed password of a user from the database.
+func (sk *SQLiteKeeper) GetPassword(ctx context.Context, username string) (string, error) {
+	// Query to retrieve the hashed password of a user from the database.
+	query := `SELECT password FROM Users WHERE username = ?;`
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query, username)
+
+	// Get the result.
+	var password string
+	if err := row.Scan(&password); err != nil {
+		return "", err
+	}
+
+	return password, nil
+}
+
+// UpdatePassword replaces the hashed password of a user in the database.
+func (sk *SQLiteKeeper) UpdatePassword(ctx context.Context, username string, hashedPassword string) error {
+	tx, err := sk.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query to replace the hashed password of a user in the database.
+	query := `UPDATE Users SET password = ? WHERE username = ? RETURNING id;`
+
+	// Execute the query.
+	var userID int
+	err = tx.QueryRowContext(ctx, query, hashedPassword, username).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// A new password outdates the credentials other servers have cached
+	if _, err := nextChangeSeq(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetUserID retrieves the user ID of a user from the database.
+func (sk *SQLiteKeeper) GetUserID(ctx context.Context, username string) (int, error) {
+	// Query to retrieve the user ID of a user from the database.
+	query := `SELECT id FROM Users WHERE username = ?;`
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query, username)
+
+	// Get the result.
+	var id int
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetUsername retrieves the username of a user from the database.
+func (sk *SQLiteKeeper) GetUsername(ctx context.Context, userID int) (string, error) {
+	// Query to retrieve the username of a user from the database.
+	query := `SELECT username FROM Users WHERE id = ?;`
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query, userID)
+
+	// Get the result.
+	var username string
+	if err := row.Scan(&username); err != nil {
+		return "", err
+	}
+
+	return username, nil
+}
+
+// GetMFA retrieves the two-factor authentication settings of a user from the database.
+// It returns storage.ErrNotFound if the user has never enrolled.
+func (sk *SQLiteKeeper) GetMFA(ctx context.Context, userID int) (models.MFA, error) {
+	// Query to retrieve the settings.
+	query := `SELECT COALESCE(secret, ''), COALESCE(pending_secret, ''), enabled, last_step
+		FROM UserMFA WHERE user_id = ?;`
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query, userID)
+
+	// Get the result.
+	var mfa models.MFA
+	err := row.Scan(&mfa.Secret, &mfa.PendingSecret, &mfa.Enabled, &mfa.LastStep)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.MFA{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return models.MFA{}, err
+	}
+
+	return mfa, nil
+}
+
+// SetPendingTOTPSecret stores a TOTP secret that awaits confirmation by the user.
+// An already active secret stays in use until the new one is confirmed.
+func (sk *SQLiteKeeper) SetPendingTOTPSecret(ctx context.Context, userID int, secret string) error {
+	// Query to add or replace the pending secret.
+	query := `INSERT INTO UserMFA (user_id, pending_secret) VALUES (?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET pending_secret = excluded.pending_secret;`
+
+	// Execute the query.
+	_, err := sk.conn.ExecContext(ctx, query, userID, secret)
+	return err
+}
+
+// EnableMFA activates the pending TOTP secret of a user and replaces the recovery codes.
+// The TOTP step used for the confirmation is recorded, so that the code can not be replayed.
+// It returns storage.ErrNotFound if there is no pending secret.
+func (sk *SQLiteKeeper) EnableMFA(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error {
+	tx, err := sk.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query to activate the pending secret.
+	enableQuery := `UPDATE UserMFA SET secret = pending_secret, pending_secret = NULL, enabled = TRUE, last_step = ?
+		WHERE user_id = ? AND pending_secret IS NOT NULL;`
+	result, err := tx.ExecContext(ctx, enableQuery, step, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return storage.ErrNotFound
+	}
+
+	// Query to remove the previous recovery codes.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM RecoveryCodes WHERE user_id = ?;`, userID); err != nil {
+		return err
+	}
+
+	// Query to add the new recovery codes.
+	insertQuery := `INSERT INTO RecoveryCodes (user_id, code_hash) VALUES (?, ?);`
+	for _, codeHash := range recoveryCodeHashes {
+		if _, err := tx.ExecContext(ctx, insertQuery, userID, codeHash); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UseTOTPStep records a used TOTP time step of a user. It returns storage.ErrConflict
+// if the step is not newer than the last used one, which prevents code replay.
+func (sk *SQLiteKeeper) UseTOTPStep(ctx context.Context, userID int, step int64) error {
+	// Query to advance the last used step.
+	query := `UPDATE UserMFA SET last_step = ? WHERE user_id = ? AND last_step < ?;`
+
+	// Execute the query.
+	result, err := sk.conn.ExecContext(ctx, query, step, userID, step)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return storage.ErrConflict
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code of a user as used.
+// It returns storage.ErrNotFound if the code is unknown or already used.
+func (sk *SQLiteKeeper) UseRecoveryCode(ctx context.Context, userID int, codeHash string) error {
+	// Query to mark the code as used.
+	query := `UPDATE RecoveryCodes SET used = TRUE WHERE user_id = ? AND code_hash = ? AND used = FALSE;`
+
+	// Execute the query.
+	result, err := sk.conn.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+// AddSession registers a new device session in the database. Active sessions of the same
+// device are revoked, so each device has at most one active session.
+func (sk *SQLiteKeeper) AddSession(ctx context.Context, userID int, session models.Session) error {
+	tx, err := sk.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query to revoke the previous sessions of the device.
+	revokeQuery := `UPDATE Sessions SET revoked = TRUE WHERE user_id = ? AND device_id = ? AND revoked = FALSE;`
+	if _, err := tx.ExecContext(ctx, revokeQuery, userID, session.DeviceID); err != nil {
+		return err
+	}
+
+	// Query to add the new session.
+	insertQuery := `INSERT INTO Sessions (id, user_id, device_id, device_name, ip, created_at, last_seen)
+		VALUES (?, ?, ?, ?, ?, ?, ?);`
+	now := timestamp(time.Now())
+	if _, err := tx.ExecContext(ctx, insertQuery, session.ID, userID, session.DeviceID, session.DeviceName, session.IP, now, now); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListSessions retrieves the active sessions of a user from the database, most recently seen first.
+func (sk *SQLiteKeeper) ListSessions(ctx context.Context, userID int) ([]models.Session, error) {
+	// Query to retrieve the active sessions of the user.
+	query := `SELECT id, device_id, COALESCE(device_name, ''), COALESCE(ip, ''), created_at, last_seen
+		FROM Sessions WHERE user_id = ? AND revoked = FALSE ORDER BY last_seen DESC;`
+
+	rows, err := sk.conn.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := make([]models.Session, 0)
+	for rows.Next() {
+		var session models.Session
+		err := rows.Scan(&session.ID, &session.DeviceID, &session.DeviceName, &session.IP, &session.CreatedAt, &session.LastSeen)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows encountered an error: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// TouchSession updates the last-seen time and IP address of a session in the database.
+func (sk *SQLiteKeeper) TouchSession(ctx context.Context, sessionID string, ip string) error {
+	// Query to update the session.
+	query := `UPDATE Sessions SET last_seen = ?, ip = ? WHERE id = ?;`
+
+	// Execute the query.
+	_, err := sk.conn.ExecContext(ctx, query, timestamp(time.Now()), ip, sessionID)
+	return err
+}
+
+// IsSessionActive checks whether a session exists in the database and has not been revoked.
+func (sk *SQLiteKeeper) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	// Query to count the active sessions with the given ID.
+	query := `SELECT COUNT(*) FROM Sessions WHERE id = ? AND revoked = FALSE;`
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query, sessionID)
+
+	// Get the result.
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// RevokeSession revokes an active session of a user in the database.
+// It returns storage.ErrNotFound if the user has no such active session.
+func (sk *SQLiteKeeper) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	// Query to revoke the session.
+	query := `UPDATE Sessions SET revoked = TRUE WHERE id = ? AND user_id = ? AND revoked = FALSE;`
+
+	// Execute the query.
+	result, err := sk.conn.ExecContext(ctx, query, sessionID, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+// RevokeAllSessions revokes all active sessions of a user in the database.
+func (sk *SQLiteKeeper) RevokeAllSessions(ctx context.Context, userID int) error {
+	// Query to revoke the sessions.
+	query := `UPDATE Sessions SET revoked = TRUE WHERE user_id = ? AND revoked = FALSE;`
+
+	// Execute the query.
+	_, err := sk.conn.ExecContext(ctx, query, userID)
+	return err
+}
+
+// AddRefreshToken stores the hash of a refresh token issued to a user session.
+func (sk *SQLiteKeeper) AddRefreshToken(ctx context.Context, tokenHash string, userID int, sessionID string, expiresAt time.Time) error {
+	// Query to store a refresh token hash.
+	query := `INSERT INTO RefreshTokens (token_hash, user_id, session_id, expires_at) VALUES (?, ?, ?, ?);`
+
+	// Execute the query.
+	_, err := sk.conn.ExecContext(ctx, query, tokenHash, userID, sessionID, timestamp(expiresAt))
+	return err
+}
+
+// ConsumeRefreshToken revokes a valid refresh token and returns the IDs of its owner and session.
+// Revoked, expired and unknown tokens as well as tokens of revoked sessions yield sql.ErrNoRows,
+// so each token can be used only once.
+func (sk *SQLiteKeeper) ConsumeRefreshToken(ctx context.Context, tokenHash string) (int, string, error) {
+	// Query to revoke the token and return its owner in a single statement.
+	query := `UPDATE RefreshTokens SET revoked = TRUE
+		WHERE token_hash = ? AND revoked = FALSE AND expires_at > ?
+			AND session_id IN (SELECT id FROM Sessions WHERE revoked = FALSE)
+		RETURNING user_id, session_id;`
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query, tokenHash, timestamp(time.Now()))
+
+	// Get the result.
+	var userID int
+	var sessionID string
+	if err := row.Scan(&userID, &sessionID); err != nil {
+		return 0, "", err
+	}
+
+	return userID, sessionID, nil
+}
+
+// RevokeRefreshToken revokes a refresh token of the given user.
+func (sk *SQLiteKeeper) RevokeRefreshToken(ctx context.Context, tokenHash string, userID int) error {
+	// Query to revoke the refresh token.
+	query := `UPDATE RefreshTokens SET revoked = TRUE WHERE token_hash = ? AND user_id = ?;`
+
+	// Execute the query.
+	_, err := sk.conn.ExecContext(ctx, query, tokenHash, userID)
+	return err
+}
+
+// RevokeToken adds the ID of an access token to the revocation list until the token expires.
+func (sk *SQLiteKeeper) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	// Query to add the token ID to the revocation list.
+	query := `INSERT INTO RevokedTokens (jti, expires_at) VALUES (?, ?) ON CONFLICT (jti) DO NOTHING;`
+
+	// Execute the query.
+	_, err := sk.conn.ExecContext(ctx, query, jti, timestamp(expiresAt))
+	return err
+}
+
+// IsTokenRevoked checks whether the access token with the given ID has been revoked.
+func (sk *SQLiteKeeper) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	// Query to check if the token ID is in the revocation list.
+	query := `SELECT COUNT(*) FROM RevokedTokens WHERE jti = ?;`
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query, jti)
+
+	// Get the result.
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// PurgeExpiredTokens removes the refresh tokens and the revoked access token IDs that
+// expired before the given time and returns how many rows were removed. Such tokens are
+// rejected by their expiry anyway, so the rows are no longer needed.
+func (sk *SQLiteKeeper) PurgeExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := sk.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var purged int64
+	for _, query := range []string{
+		"DELETE FROM RefreshTokens WHERE expires_at < ?",
+		"DELETE FROM RevokedTokens WHERE expires_at < ?",
+	} {
+		result, err := tx.ExecContext(ctx, query, timestamp(before))
+		if err != nil {
+			return 0, err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		purged += rows
+	}
+
+	return purged, tx.Commit()
+}
+
+// AddData adds data to a table in the database.
+// It returns storage.ErrConflict if the table already holds a record with the same ID.
+func (sk *SQLiteKeeper) AddData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string) error {
+	// Only vault tables and their columns may end up in the statement
+	s, err := schema.Lookup(table)
+	if err != nil {
+		return err
+	}
+	if err := s.ValidateAdd(data); err != nil {
+		return err
+	}
+
+	tx, err := sk.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := addData(ctx, tx, s, user_id, entry_id, data); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// addData inserts a record within the transaction tx.
+func addData(ctx context.Context, tx *sql.Tx, s schema.Schema, user_id int, entry_id string, data map[string]string) error {
+	seq, err := nextChangeSeq(ctx, tx, user_id)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(data)+4)        // +4 for user_id, entry_id, change_seq and updated_at
+	values := make([]interface{}, 0, len(data)+4) // +4 for user_id, entry_id, change_seq and updated_at
+
+	// The column default has a lower precision than the other timestamps, so 'updated_at' is set here
+	keys = append(keys, "user_id", "id", "change_seq", "updated_at")
+	values = append(values, user_id, entry_id, seq, timestamp(time.Now()))
+
+	for key, value := range data {
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(values)), ",")
+	query := fmt.Sprintf("INSERT INTO %s(%s) VALUES(%s)", s.Table, strings.Join(keys, ","), placeholders)
+	_, err = tx.ExecContext(ctx, query, values...)
+	if isUniqueViolation(err) {
+		return storage.ErrConflict
+	}
+	return err
+}
+
+// isUniqueViolation reports whether err is caused by a violated primary key or unique constraint.
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlitedriver.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY || code == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
+// UpdateData updates data in a table in the database, refreshes the 'updated_at' field
+// and increments the version of the record. If baseVersion is not zero, the record is
+// updated only if its version still equals baseVersion; otherwise a *storage.ConflictError
+// with the current record is returned. It returns the new version of the record.
+func (sk *SQLiteKeeper) UpdateData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string, baseVersion int64) (int64, error) {
+	// Only vault tables and their columns may end up in the statement
+	s, err := schema.Lookup(table)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.ValidateUpdate(data); err != nil {
+		return 0, err
+	}
+
+	tx, err := sk.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	version, err := updateData(ctx, tx, s, user_id, entry_id, data, baseVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	return version, tx.Commit()
+}
+
+// updateData updates a record within the transaction tx and returns its new version.
+func updateData(ctx context.Context, tx *sql.Tx, s schema.Schema, user_id int, entry_id string, data map[string]string, baseVersion int64) (int64, error) {
+	seq, err := nextChangeSeq(ctx, tx, user_id)
+	if err != nil {
+		return 0, err
+	}
+
+	setClauses := make([]string, 0, len(data)+3)  // +3 for updated_at, change_seq and version
+	values := make([]interface{}, 0, len(data)+5) // +5 for updated_at, change_seq, user_id, id and version
+
+	for key, value := range data {
+		setClauses = append(setClauses, key+" = ?")
+		values = append(values, value)
+	}
+
+	// Keep 'updated_at', 'change_seq' and 'version' under server control so other devices see the change
+	setClauses = append(setClauses, "updated_at = ?", "change_seq = ?", "version = version + 1")
+	values = append(values, timestamp(time.Now()), seq)
+
+	condition := "user_id = ? AND id = ?"
+	values = append(values, user_id, entry_id)
+
+	// Update only the version the client has seen
+	if baseVersion != 0 {
+		condition += " AND version = ?"
+		values = append(values, baseVersion)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s RETURNING version", s.Table, strings.Join(setClauses, ","), condition)
+	row := tx.QueryRowContext(ctx, query, values...)
+
+	var version int64
+	err = row.Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Either the record does not exist or another device has changed it
+		current, err := getRecord(ctx, tx, s, user_id, entry_id)
+		if err != nil {
+			return 0, err
+		}
+
+		return 0, &storage.ConflictError{Current: current}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// LastChangeSeq returns the change sequence number of the latest change of the user's
+// records or password, which is zero if there was none.
+func (sk *SQLiteKeeper) LastChangeSeq(ctx context.Context, userID int) (int64, error) {
+	// Query to retrieve the counter.
+	query := `SELECT last_seq FROM SyncSequences WHERE user_id = ?;`
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query, userID)
+
+	// Get the result.
+	var seq int64
+	err := row.Scan(&seq)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return seq, nil
+}
+
+// GetDataKey returns the wrapped data key of a user from the database.
+// It returns storage.ErrNotFound if the user has no data key yet.
+func (sk *SQLiteKeeper) GetDataKey(ctx context.Context, userID int) (string, error) {
+	// Query to retrieve the key.
+	query := `SELECT wrapped_key FROM DataKeys WHERE user_id = ?;`
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query, userID)
+
+	// Get the result.
+	var wrappedKey string
+	err := row.Scan(&wrappedKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", storage.ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return wrappedKey, nil
+}
+
+// AddDataKey stores the wrapped data key of a user unless the user already has one,
+// which may have been added by another server, and returns the stored key.
+func (sk *SQLiteKeeper) AddDataKey(ctx context.Context, userID int, wrappedKey string) (string, error) {
+	// Query to add the key. The first key of a user wins.
+	query := `INSERT INTO DataKeys (user_id, wrapped_key) VALUES (?, ?)
+		ON CONFLICT (user_id) DO NOTHING;`
+
+	// Execute the query.
+	if _, err := sk.conn.ExecContext(ctx, query, userID, wrappedKey); err != nil {
+		return "", err
+	}
+
+	return sk.GetDataKey(ctx, userID)
+}
+
+// ReplaceDataKey replaces the wrapped data key of a user in the database, provided
+// that it is still oldKey. Otherwise it returns storage.ErrConflict.
+func (sk *SQLiteKeeper) ReplaceDataKey(ctx context.Context, userID int, oldKey, newKey string) error {
+	// Query to replace the key.
+	query := `UPDATE DataKeys SET wrapped_key = ? WHERE user_id = ? AND wrapped_key = ?;`
+
+	// Execute the query.
+	result, err := sk.conn.ExecContext(ctx, query, newKey, userID, oldKey)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return storage.ErrConflict
+	}
+
+	return nil
+}
+
+// ListUserIDs returns the IDs of at most limit users that are greater than afterID,
+// in ascending order.
+func (sk *SQLiteKeeper) ListUserIDs(ctx context.Context, afterID int, limit int) ([]int, error) {
+	// Query to retrieve the IDs.
+	query := `SELECT id FROM Users WHERE id > ? ORDER BY id LIMIT ?;`
+
+	// Execute the query.
+	rows, err := sk.conn.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Get the result.
+	var userIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, id)
+	}
+
+	return userIDs, rows.Err()
+}
+
+// CountUsers returns the number of users in the database.
+func (sk *SQLiteKeeper) CountUsers(ctx context.Context) (int64, error) {
+	// Query to count the users.
+	query := `SELECT COUNT(*) FROM Users;`
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query)
+
+	// Get the result.
+	var count int64
+	if err := row.Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// RewriteColumns replaces the columns of a record, deleted or not, provided that they
+// still hold the values in current. Otherwise it returns storage.ErrConflict. The version,
+// change sequence and 'updated_at' field stay as they are, so the rewrite is not synced
+// to other devices: it must not change what the columns mean, only how they are stored.
+func (sk *SQLiteKeeper) RewriteColumns(ctx context.Context, table string, userID int, entryID string, current, replacement map[string]string) error {
+	// Only vault tables and their columns may end up in the statement
+	s, err := schema.Lookup(table)
+	if err != nil {
+		return err
+	}
+	if err := s.ValidateUpdate(replacement); err != nil {
+		return err
+	}
+
+	columns := make([]string, 0, len(replacement))
+	for column := range replacement {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	setClauses := make([]string, 0, len(columns))
+	values := make([]interface{}, 0, 2*len(columns)+2) // +2 for user_id and id
+
+	for _, column := range columns {
+		values = append(values, replacement[column])
+		setClauses = append(setClauses, column+" = ?")
+	}
+
+	values = append(values, userID, entryID)
+	condition := "user_id = ? AND id = ?"
+
+	for _, column := range columns {
+		values = append(values, current[column])
+		condition += " AND " + column + " = ?"
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", s.Table, strings.Join(setClauses, ", "), condition)
+
+	// Execute the query.
+	result, err := sk.conn.ExecContext(ctx, query, values...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return storage.ErrConflict
+	}
+
+	return nil
+}
+
+// queryRower is implemented by both *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// nextChangeSeq advances the change sequence of a user and returns the new value.
+func nextChangeSeq(ctx context.Context, tx queryRower, userID int) (int64, error) {
+	// Query to advance the counter.
+	query := `INSERT INTO SyncSequences (user_id, last_seq) VALUES (?, 1)
+		ON CONFLICT (user_id) DO UPDATE SET last_seq = SyncSequences.last_seq + 1
+		RETURNING last_seq;`
+
+	// Execute the query.
+	row := tx.QueryRowContext(ctx, query, userID)
+
+	// Get the result.
+	var seq int64
+	if err := row.Scan(&seq); err != nil {
+		return 0, err
+	}
+
+	return seq, nil
+}
+
+// selectColumns returns the select list for the columns of a record. SQLite stores
+// booleans as numbers, so the deleted flag is spelled out as PostgreSQL returns it.
+func selectColumns(cols []string) string {
+	exprs := make([]string, len(cols))
+	for i, column := range cols {
+		exprs[i] = column
+		if column == "deleted" {
+			exprs[i] = "CASE WHEN deleted THEN 'true' ELSE 'false' END"
+		}
+	}
+
+	return strings.Join(exprs, ",")
+}
+
+// getRecord retrieves a single record of a user, including deleted ones, from the database.
+// It returns storage.ErrNotFound if there is no such record.
+func getRecord(ctx context.Context, q queryRower, s schema.Schema, userID int, entryID string) (map[string]string, error) {
+	cols := s.AllColumns()
+
+	// Query to retrieve the record.
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = ? AND id = ?", selectColumns(cols), s.Table)
+
+	// Execute the query.
+	row := q.QueryRowContext(ctx, query, userID, entryID)
+
+	// Get the result.
+	values := make([]interface{}, len(cols))
+	for i := range values {
+		values[i] = new(sql.NullString)
+	}
+	err := row.Scan(values...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record := make(map[string]string, len(cols))
+	for i, column := range cols {
+		record[column] = values[i].(*sql.NullString).String
+	}
+
+	return record, nil
+}
+
+// DeleteData marks data as deleted in a table in the database and updates the 'updated_at' field.
+// It returns storage.ErrNotFound if there is no such record or it is already deleted.
+func (sk *SQLiteKeeper) DeleteData(ctx context.Context, table string, user_id int, entry_id string) error {
+	// Check user_id and table
+	if user_id == 0 || table == "" {
+		return errors.New("user_id and table must be specified")
+	}
+
+	// Check entry_id
+	if entry_id == "" {
+		return errors.New("entry_id must be specified")
+	}
+
+	s, err := schema.Lookup(table)
+	if err != nil {
+		return err
+	}
+
+	tx, err := sk.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := deleteData(ctx, tx, s, user_id, entry_id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteData marks a record as deleted within the transaction tx.
+func deleteData(ctx context.Context, tx *sql.Tx, s schema.Schema, user_id int, entry_id string) error {
+	// A deleted file entry no longer refers to its content
+	if s.Table == schema.FilesData {
+		digest, err := fileContent(ctx, tx, user_id, entry_id)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+		if err := releaseBlob(ctx, tx, digest); err != nil {
+			return err
+		}
+	}
+
+	seq, err := nextChangeSeq(ctx, tx, user_id)
+	if err != nil {
+		return err
+	}
+
+	// Prepare the query to update the record's deleted flag and 'updated_at' field
+	updateQuery := fmt.Sprintf("UPDATE %s SET deleted = TRUE, updated_at = ?, change_seq = ?, version = version + 1 WHERE user_id = ? AND id = ? AND deleted = FALSE", s.Table)
+	args := []interface{}{timestamp(time.Now()), seq, user_id, entry_id}
+
+	// Execute the query to update the record's deleted flag and 'updated_at' field
+	result, err := tx.ExecContext(ctx, updateQuery, args...)
+	if err != nil {
+		return err
+	}
+
+	// A missing or already deleted record is not deleted again
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return storage.ErrNotFound
+	}
+
+	return nil
+}
+
+// Batch applies the operations of a user in order within a single transaction and returns
+// a result for each applied operation. Unless partial is set, the first failing operation
+// rolls back the whole batch and is returned as a *storage.BatchError. In partial mode every
+// operation runs in its own savepoint, so a failure undoes only that operation and is
+// reported in its result.
+func (sk *SQLiteKeeper) Batch(ctx context.Context, userID int, ops []models.BatchOperation, partial bool) ([]models.BatchResult, error) {
+	tx, err := sk.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]models.BatchResult, 0, len(ops))
+	for i, op := range ops {
+		if partial {
+			if _, err := tx.ExecContext(ctx, "SAVEPOINT batch_operation"); err != nil {
+				return nil, err
+			}
+		}
+
+		result := models.BatchResult{ID: op.ID}
+		result.Version, result.Err = applyOperation(ctx, tx, userID, op)
+
+		var conflict *storage.ConflictError
+		if errors.As(result.Err, &conflict) {
+			result.Current = conflict.Current
+		}
+		results = append(results, result)
+
+		if result.Err == nil {
+			if partial {
+				if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT batch_operation"); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		if !partial {
+			return results, &storage.BatchError{Index: i, Err: result.Err}
+		}
+
+		// Rolling back to a savepoint keeps it open, so it is released afterwards
+		if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch_operation"); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT batch_operation"); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, tx.Commit()
+}
+
+// applyOperation applies a single batch operation within the transaction tx and returns
+// the new version of the record, which is zero for deletions.
+func applyOperation(ctx context.Context, tx *sql.Tx, userID int, op models.BatchOperation) (int64, error) {
+	// Only vault tables and their columns may end up in the statements
+	s, err := schema.Lookup(op.Table)
+	if err != nil {
+		return 0, err
+	}
+	if op.ID == "" {
+		return 0, fmt.Errorf("%w: missing id", storage.ErrInvalidOperation)
+	}
+
+	switch op.Op {
+	case models.BatchAdd:
+		if err := s.ValidateAdd(op.Data); err != nil {
+			return 0, err
+		}
+		if err := addData(ctx, tx, s, userID, op.ID, op.Data); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	case models.BatchUpdate:
+		if err := s.ValidateUpdate(op.Data); err != nil {
+			return 0, err
+		}
+		return updateData(ctx, tx, s, userID, op.ID, op.Data, op.BaseVersion)
+	case models.BatchDelete:
+		return 0, deleteData(ctx, tx, s, userID, op.ID)
+	default:
+		return 0, fmt.Errorf("%w: %q", storage.ErrInvalidOperation, op.Op)
+	}
+}
+
+// SetFileContent records the size and SHA-256 digest of the uploaded content of a file entry
+// and moves the entry's blob reference to the new digest.
+// It returns storage.ErrNotFound if the entry does not exist or is deleted.
+func (sk *SQLiteKeeper) SetFileContent(ctx context.Context, userID int, entryID string, size int64, digest string) error {
+	tx, err := sk.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	previous, err := fileContent(ctx, tx, userID, entryID)
+	if err != nil {
+		return err
+	}
+
+	seq, err := nextChangeSeq(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Query to store the content attributes.
+	query := "UPDATE FilesData SET size = ?, sha256 = ?, updated_at = ?, change_seq = ?, version = version + 1 WHERE user_id = ? AND id = ? AND deleted = FALSE"
+
+	// Execute the query.
+	if _, err := tx.ExecContext(ctx, query, size, digest, timestamp(time.Now()), seq, userID, entryID); err != nil {
+		return err
+	}
+
+	if previous != digest {
+		if err := releaseBlob(ctx, tx, previous); err != nil {
+			return err
+		}
+
+		// Query to add a reference to the blob.
+		query = `INSERT INTO Blobs (sha256, size, ref_count) VALUES (?, ?, 1)
+			ON CONFLICT (sha256) DO UPDATE SET ref_count = Blobs.ref_count + 1`
+
+		// Execute the query.
+		if _, err := tx.ExecContext(ctx, query, digest, size); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// fileContent returns the digest of the content of a file entry that is not deleted,
+// which is empty if nothing was uploaded yet. It returns storage.ErrNotFound if there
+// is no such entry.
+func fileContent(ctx context.Context, tx queryRower, userID int, entryID string) (string, error) {
+	// Query to retrieve the digest.
+	query := "SELECT COALESCE(sha256, '') FROM FilesData WHERE user_id = ? AND id = ? AND deleted = FALSE"
+
+	// Execute the query.
+	row := tx.QueryRowContext(ctx, query, userID, entryID)
+
+	// Get the result.
+	var digest string
+	err := row.Scan(&digest)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", storage.ErrNotFound
+	}
+
+	return digest, err
+}
+
+// releaseBlob removes a reference to a blob. Blobs without references are left
+// for DeleteBlob, which removes them together with their content.
+func releaseBlob(ctx context.Context, tx *sql.Tx, digest string) error {
+	if digest == "" {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, "UPDATE Blobs SET ref_count = ref_count - 1 WHERE sha256 = ?", digest)
+	return err
+}
+
+// UnreferencedBlobs returns the digests of blobs that no file entry refers to.
+func (sk *SQLiteKeeper) UnreferencedBlobs(ctx context.Context) ([]string, error) {
+	// Query to retrieve the blobs.
+	query := "SELECT sha256 FROM Blobs WHERE ref_count <= 0 LIMIT 1000"
+
+	// Execute the query.
+	rows, err := sk.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Get the result.
+	var digests []string
+	for rows.Next() {
+		var digest string
+		if err := rows.Scan(&digest); err != nil {
+			return nil, err
+		}
+		digests = append(digests, digest)
+	}
+
+	return digests, rows.Err()
+}
+
+// DeleteBlob removes a blob without references. The transaction stays open while remove
+// deletes the content, so an upload of the same content waits and then stores it again.
+// It returns storage.ErrConflict if the blob got a new reference in the meantime.
+func (sk *SQLiteKeeper) DeleteBlob(ctx context.Context, digest string, remove func() error) error {
+	tx, err := sk.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Query to retrieve the references of the blob.
+	query := "SELECT ref_count FROM Blobs WHERE sha256 = ?"
+
+	// Execute the query.
+	row := tx.QueryRowContext(ctx, query, digest)
+
+	// Get the result.
+	var refCount int64
+	err = row.Scan(&refCount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if refCount > 0 {
+		return storage.ErrConflict
+	}
+
+	if err := remove(); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM Blobs WHERE sha256 = ?", digest); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// LegacyBlobs returns a batch of file entries, deleted ones included, whose content
+// may still be stored under the entry instead of its digest.
+func (sk *SQLiteKeeper) LegacyBlobs(ctx context.Context) ([]models.LegacyBlob, error) {
+	// Query to retrieve the entries.
+	query := "SELECT user_id, id, sha256, deleted FROM FilesData WHERE legacy_blob = TRUE AND sha256 IS NOT NULL ORDER BY user_id, id LIMIT 1000"
+
+	// Execute the query.
+	rows, err := sk.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Get the result.
+	var blobs []models.LegacyBlob
+	for rows.Next() {
+		var blob models.LegacyBlob
+		if err := rows.Scan(&blob.UserID, &blob.EntryID, &blob.SHA256, &blob.Deleted); err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, blob)
+	}
+
+	return blobs, rows.Err()
+}
+
+// ForgetLegacyBlob marks the content of a file entry as moved to its content key.
+// The version and change sequence of the entry stay the same, since its data does not change.
+func (sk *SQLiteKeeper) ForgetLegacyBlob(ctx context.Context, userID int, entryID string) error {
+	// Query to clear the mark.
+	query := "UPDATE FilesData SET legacy_blob = FALSE WHERE user_id = ? AND id = ?"
+
+	// Execute the query.
+	_, err := sk.conn.ExecContext(ctx, query, userID, entryID)
+	return err
+}
+
+// BlobStats summarizes the stored blobs and the space saved by sharing them.
+func (sk *SQLiteKeeper) BlobStats(ctx context.Context) (models.BlobStats, error) {
+	// Query to summarize the blobs.
+	query := `SELECT COUNT(*), COALESCE(SUM(ref_count), 0), COALESCE(SUM(size), 0), COALESCE(SUM(size * ref_count), 0)
+		FROM Blobs WHERE ref_count > 0`
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query)
+
+	// Get the result.
+	var stats models.BlobStats
+	err := row.Scan(&stats.Blobs, &stats.References, &stats.StoredBytes, &stats.LogicalBytes)
+	if err != nil {
+		return models.BlobStats{}, err
+	}
+	stats.SavedBytes = stats.LogicalBytes - stats.StoredBytes
+
+	return stats, nil
+}
+
+// GetFileContent retrieves the content attributes of a file entry.
+// It returns storage.ErrNotFound if the entry does not exist, is deleted or has no uploaded content.
+func (sk *SQLiteKeeper) GetFileContent(ctx context.Context, userID int, entryID string) (models.FileContent, error) {
+	// Query to retrieve the content attributes.
+	query := "SELECT size, sha256, updated_at FROM FilesData WHERE user_id = ? AND id = ? AND deleted = FALSE AND sha256 IS NOT NULL"
+
+	// Execute the query.
+	row := sk.conn.QueryRowContext(ctx, query, userID, entryID)
+
+	// Get the result.
+	var content models.FileContent
+	err := row.Scan(&content.Size, &content.SHA256, &content.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.FileContent{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return models.FileContent{}, err
+	}
+
+	return content, nil
+}
+
+// LegacyFiles retrieves the file entries that have no recorded content digest. Their content,
+// if any, was uploaded before the files were kept in per-user directories.
+func (sk *SQLiteKeeper) LegacyFiles(ctx context.Context) ([]models.LegacyFile, error) {
+	// Query to retrieve the entries without a digest.
+	query := "SELECT user_id, id FROM FilesData WHERE sha256 IS NULL AND deleted = FALSE"
+
+	// Execute the query.
+	rows, err := sk.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Get the result.
+	var files []models.LegacyFile
+	for rows.Next() {
+		var file models.LegacyFile
+		if err := rows.Scan(&file.UserID, &file.EntryID); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, rows.Err()
+}
+
+// Sync retrieves the records of all vault tables that a user changed after the given
+// change sequence number, ordered by the change. At most limit changes are returned;
+// hasMore reports whether further changes remain.
+func (sk *SQLiteKeeper) Sync(ctx context.Context, userID int, afterSeq int64, limit int) ([]models.Change, bool, error) {
+	var changes []models.Change
+
+	// Each table contributes at most limit+1 changes, which is enough to fill the page
+	// and to learn whether there is another one
+	for _, table := range schema.Tables() {
+		s, err := schema.Lookup(string(table))
+		if err != nil {
+			return nil, false, err
+		}
+		cols := s.AllColumns()
+
+		query := fmt.Sprintf("SELECT %s,change_seq FROM %s WHERE user_id = ? AND change_seq > ? ORDER BY change_seq LIMIT ?",
+			selectColumns(cols), s.Table)
+		rows, err := sk.conn.QueryContext(ctx, query, userID, afterSeq, limit+1)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to execute query: %w", err)
+		}
+
+		values := make([]interface{}, len(cols)+1)
+		for i := range cols {
+			values[i] = new(sql.NullString)
+		}
+		var seq int64
+		values[len(cols)] = &seq
+
+		for rows.Next() {
+			if err := rows.Scan(values...); err != nil {
+				rows.Close()
+				return nil, false, fmt.Errorf("failed to scan row: %w", err)
+			}
+
+			entry := make(map[string]string, len(cols))
+			for i, column := range cols {
+				entry[column] = values[i].(*sql.NullString).String
+			}
+			changes = append(changes, models.Change{Table: string(s.Table), Seq: seq, Entry: entry})
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, false, fmt.Errorf("rows encountered an error: %w", err)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Seq < changes[j].Seq })
+
+	hasMore := len(changes) > limit
+	if hasMore {
+		changes = changes[:limit]
+	}
+
+	return changes, hasMore, nil
+}
+
+// GetData retrieves a single record of a user from a table in the database.
+// It returns storage.ErrNotFound if there is no such record or it is deleted.
+func (sk *SQLiteKeeper) GetData(ctx context.Context, table string, userID int, entryID string) (map[string]string, error) {
+	s, err := schema.Lookup(table)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := getRecord(ctx, sk.conn, s, userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deleted records are kept only for sync, so they are not served
+	if deleted, _ := strconv.ParseBool(record["deleted"]); deleted {
+		return nil, storage.ErrNotFound
+	}
+
+	return record, nil
+}
+
+// GetAllData retrieves all data from a table in the database.
+func (sk *SQLiteKeeper) GetAllData(ctx context.Context, table string, userID int, lastSync time.Time, inclDel bool) ([]map[string]string, error) {
+	// The registry defines both the table name and the columns to select
+	s, err := schema.Lookup(table)
+	if err != nil {
+		return nil, err
+	}
+	cols := s.AllColumns()
+
+	// Build the condition for the query
+	var condition string
+	args := []interface{}{userID}
+	if !inclDel {
+		condition += " AND deleted = FALSE"
+	}
+	if !lastSync.IsZero() {
+		args = append(args, timestamp(lastSync))
+		condition += " AND updated_at > ?"
+	}
+
+	// Execute the query to fetch all data from the table for the given user ID considering the condition
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = ?%s", selectColumns(cols), s.Table, condition)
+	rows, err := sk.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]interface{}, len(cols))
+	for i := range values {
+		values[i] = new(sql.NullString)
+	}
+
+	var data []map[string]string
+	for rows.Next() {
+		if err := rows.Scan(values...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		row := make(map[string]string, len(cols))
+		for i, column := range cols {
+			row[column] = values[i].(*sql.NullString).String
+		}
+		data = append(data, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows encountered an error: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,113 @@
+package sqlitekeeper
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage/storagetest"
+	"go.uber.org/zap"
+)
+
+func TestSQLiteKeeper(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Keeper {
+		dsn := Scheme + filepath.Join(t.TempDir(), "gophkeeper.db")
+		keeper, err := NewSQLiteKeeper(func() string { return dsn }, zap.NewNop())
+		require.NoError(t, err)
+		t.Cleanup(func() { keeper.Close() })
+
+		return keeper
+	})
+}
+
+func TestNewSQLiteKeeper_Reopen(t *testing.T) {
+	dsn := Scheme + filepath.Join(t.TempDir(), "gophkeeper.db")
+
+	keeper, err := NewSQLiteKeeper(func() string { return dsn }, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, keeper.AddUser(context.Background(), "alice", "hash"))
+	require.True(t, keeper.Close())
+
+	// The migrations are applied once, the data stays
+	keeper, err = NewSQLiteKeeper(func() string { return dsn }, zap.NewNop())
+	require.NoError(t, err)
+	defer keeper.Close()
+
+	exists, err := keeper.UserExists(context.Background(), "alice")
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+func TestSQLiteKeeper_AddSRPChallenge_PurgesExpired(t *testing.T) {
+	ctx := context.Background()
+	dsn := Scheme + filepath.Join(t.TempDir(), "gophkeeper.db")
+	keeper, err := NewSQLiteKeeper(func() string { return dsn }, zap.NewNop())
+	require.NoError(t, err)
+	defer keeper.Close()
+
+	stale := models.SRPChallenge{ID: "stale", Username: "alice", A: "a", B: "b", PrivateB: "private",
+		ExpiresAt: time.Now().Add(-time.Minute)}
+	require.NoError(t, keeper.AddSRPChallenge(ctx, stale))
+
+	// Attempts that were never answered do not pile up
+	fresh := stale
+	fresh.ID = "fresh"
+	fresh.ExpiresAt = time.Now().Add(time.Minute)
+	require.NoError(t, keeper.AddSRPChallenge(ctx, fresh))
+
+	var ids []string
+	rows, err := keeper.conn.QueryContext(ctx, "SELECT id FROM SRPChallenges")
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		require.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, []string{"fresh"}, ids)
+}
+
+func TestSQLiteKeeper_LegacyBlobs(t *testing.T) {
+	ctx := context.Background()
+	dsn := Scheme + filepath.Join(t.TempDir(), "gophkeeper.db")
+	keeper, err := NewSQLiteKeeper(func() string { return dsn }, zap.NewNop())
+	require.NoError(t, err)
+	defer keeper.Close()
+
+	require.NoError(t, keeper.AddUser(ctx, "alice", "hash"))
+	userID, err := keeper.GetUserID(ctx, "alice")
+	require.NoError(t, err)
+	require.NoError(t, keeper.AddData(ctx, "FilesData", userID, "f1", map[string]string{"path": "a.txt"}))
+	require.NoError(t, keeper.SetFileContent(ctx, userID, "f1", 1, "d1"))
+
+	// Entries with content before the migration are marked by it
+	_, err = keeper.conn.ExecContext(ctx, "UPDATE FilesData SET legacy_blob = TRUE")
+	require.NoError(t, err)
+
+	blobs, err := keeper.LegacyBlobs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []models.LegacyBlob{{UserID: userID, EntryID: "f1", SHA256: "d1"}}, blobs)
+
+	content, err := keeper.GetFileContent(ctx, userID, "f1")
+	require.NoError(t, err)
+	require.NoError(t, keeper.ForgetLegacyBlob(ctx, userID, "f1"))
+
+	blobs, err = keeper.LegacyBlobs(ctx)
+	require.NoError(t, err)
+	require.Empty(t, blobs)
+
+	// Moving the content does not change the entry
+	moved, err := keeper.GetFileContent(ctx, userID, "f1")
+	require.NoError(t, err)
+	require.Equal(t, content, moved)
+}
+
+func TestNewSQLiteKeeper_EmptyPath(t *testing.T) {
+	_, err := NewSQLiteKeeper(func() string { return Scheme }, zap.NewNop())
+	require.Error(t, err)
+}
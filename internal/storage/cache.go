@@ -0,0 +1,200 @@
+package storage
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+)
+
+// CacheOptions limits the read-through cache of MemoryStorage.
+type CacheOptions struct {
+	// Size is the maximum number of cached entries. Zero disables the cache.
+	Size int
+	// TTL is how long an entry is served at most. Zero keeps entries until they are outdated or evicted.
+	TTL time.Duration
+}
+
+// cacheKey identifies a cache entry: either the records of a user in a table
+// or the credentials of a username.
+type cacheKey struct {
+	userID   int
+	table    string
+	username string
+}
+
+// cacheEntry is a cached value together with the change sequence number of its
+// user at the time it was read.
+type cacheEntry struct {
+	key     cacheKey
+	userID  int
+	seq     int64
+	expires time.Time
+	value   interface{}
+}
+
+// cache is a size-limited LRU cache of per-user values. Every value is tagged with the
+// change sequence number of its user, so that a value is served only as long as nobody,
+// on this or on another server, has changed the user's data since it was read.
+type cache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List // most recently used first
+	entries map[cacheKey]*list.Element
+	users   map[int]map[cacheKey]struct{}
+	stats   models.CacheStats
+}
+
+// newCache creates a cache with the given limits. It returns nil if the cache is disabled.
+func newCache(options CacheOptions) *cache {
+	if options.Size <= 0 {
+		return nil
+	}
+
+	return &cache{
+		size:    options.Size,
+		ttl:     options.TTL,
+		order:   list.New(),
+		entries: make(map[cacheKey]*list.Element),
+		users:   make(map[int]map[cacheKey]struct{}),
+	}
+}
+
+// lookup returns the element under key unless it has expired. The caller holds c.mu.
+func (c *cache) lookup(key cacheKey) (*list.Element, bool) {
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if c.ttl > 0 && time.Now().After(elem.Value.(*cacheEntry).expires) {
+		c.remove(elem)
+		c.stats.Evictions++
+		return nil, false
+	}
+
+	return elem, true
+}
+
+// peek returns the entry under key without checking its version. It counts neither
+// a hit nor a miss, since the caller decides whether the entry is usable.
+func (c *cache) peek(key cacheKey) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.lookup(key)
+	if !ok {
+		return cacheEntry{}, false
+	}
+
+	return *elem.Value.(*cacheEntry), true
+}
+
+// get returns the value under key if it was read at the change sequence number seq.
+// Outdated values are dropped. Every call counts as a hit or a miss.
+func (c *cache) get(key cacheKey, seq int64) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.lookup(key)
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if entry.seq != seq {
+		c.remove(elem)
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	c.stats.Hits++
+
+	return entry.value, true
+}
+
+// hit counts a value served from an entry returned by peek.
+func (c *cache) hit(key cacheKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+	}
+	c.stats.Hits++
+}
+
+// put stores a value of a user that was read at the change sequence number seq,
+// evicting the least recently used entries beyond the size limit.
+func (c *cache) put(key cacheKey, userID int, seq int64, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+
+	entry := &cacheEntry{key: key, userID: userID, seq: seq, value: value}
+	if c.ttl > 0 {
+		entry.expires = time.Now().Add(c.ttl)
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	if c.users[userID] == nil {
+		c.users[userID] = make(map[cacheKey]struct{})
+	}
+	c.users[userID][key] = struct{}{}
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// invalidate drops the entry under key.
+func (c *cache) invalidate(key cacheKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+		c.stats.Invalidations++
+	}
+}
+
+// invalidateUser drops all entries of a user.
+func (c *cache) invalidateUser(userID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.users[userID] {
+		c.remove(c.entries[key])
+		c.stats.Invalidations++
+	}
+}
+
+// remove drops an entry. The caller holds c.mu.
+func (c *cache) remove(elem *list.Element) {
+	entry := c.order.Remove(elem).(*cacheEntry)
+	delete(c.entries, entry.key)
+
+	keys := c.users[entry.userID]
+	delete(keys, entry.key)
+	if len(keys) == 0 {
+		delete(c.users, entry.userID)
+	}
+}
+
+// snapshot returns the counters of the cache.
+func (c *cache) snapshot() models.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = int64(c.order.Len())
+
+	return stats
+}
@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+)
+
+// countingKeeper counts the reads that reach the keeper. Its change sequence can be
+// advanced to simulate a change made through another server.
+type countingKeeper struct {
+	mockKeeper
+
+	mu        sync.Mutex
+	seq       int64
+	password  string
+	records   []map[string]string
+	passwords int
+	reads     int
+}
+
+func (k *countingKeeper) LastChangeSeq(ctx context.Context, userID int) (int64, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.seq, nil
+}
+
+func (k *countingKeeper) GetPassword(ctx context.Context, username string) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.passwords++
+	return k.password, nil
+}
+
+func (k *countingKeeper) UpdatePassword(ctx context.Context, username string, hashedPassword string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.password = hashedPassword
+	k.seq++
+	return nil
+}
+
+func (k *countingKeeper) AddData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.seq++
+	return nil
+}
+
+func (k *countingKeeper) GetAllData(ctx context.Context, table string, user_id int, last_sync time.Time, incl_del bool) ([]map[string]string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.reads++
+	return k.records, nil
+}
+
+func (k *countingKeeper) advance() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.seq++
+}
+
+func TestMemoryStorage_CachedGetAllData(t *testing.T) {
+	ctx := context.Background()
+	since := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	keeper := &countingKeeper{records: []map[string]string{
+		{"id": "old", "deleted": "false", "updated_at": "2024-01-01T00:00:00Z"},
+		{"id": "new", "deleted": "false", "updated_at": "2024-01-03T00:00:00.000001Z"},
+		{"id": "gone", "deleted": "true", "updated_at": "2024-01-03T00:00:00Z"},
+	}}
+	storage := NewMemoryStorage(keeper, &mockLogger{}, CacheOptions{Size: 10})
+
+	// One read of the keeper serves all sync times
+	data, err := storage.GetAllData(ctx, "TextData", 1, time.Time{}, false)
+	require.NoError(t, err)
+	assert.Len(t, data, 2)
+
+	data, err = storage.GetAllData(ctx, "TextData", 1, since, true)
+	require.NoError(t, err)
+	require.Len(t, data, 2)
+	assert.Equal(t, "new", data[0]["id"])
+	assert.Equal(t, "gone", data[1]["id"])
+	assert.Equal(t, 1, keeper.reads)
+	assert.Equal(t, models.CacheStats{Entries: 1, Hits: 1, Misses: 1}, storage.CacheStats())
+
+	// Callers get copies of the cached records
+	data[0]["id"] = "changed"
+	data, err = storage.GetAllData(ctx, "TextData", 1, since, false)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]string{keeper.records[1]}, data)
+
+	// Other users and tables have their own entries
+	_, err = storage.GetAllData(ctx, "TextData", 2, time.Time{}, false)
+	require.NoError(t, err)
+	_, err = storage.GetAllData(ctx, "FilesData", 1, time.Time{}, false)
+	require.NoError(t, err)
+	assert.Equal(t, 3, keeper.reads)
+}
+
+func TestMemoryStorage_CacheVersionCheck(t *testing.T) {
+	ctx := context.Background()
+	keeper := &countingKeeper{}
+	storage := NewMemoryStorage(keeper, &mockLogger{}, CacheOptions{Size: 10})
+
+	_, err := storage.GetAllData(ctx, "TextData", 1, time.Time{}, false)
+	require.NoError(t, err)
+
+	// A change through another server outdates the entry
+	keeper.advance()
+	_, err = storage.GetAllData(ctx, "TextData", 1, time.Time{}, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, keeper.reads)
+
+	// A change through this server drops the entries of the user right away
+	require.NoError(t, storage.AddData(ctx, "TextData", 1, "a", map[string]string{"data": "a"}))
+	stats := storage.CacheStats()
+	assert.Equal(t, int64(0), stats.Entries)
+	assert.Equal(t, int64(1), stats.Invalidations)
+
+	_, err = storage.GetAllData(ctx, "TextData", 1, time.Time{}, false)
+	require.NoError(t, err)
+	assert.Equal(t, 3, keeper.reads)
+	assert.Equal(t, int64(3), storage.CacheStats().Misses)
+}
+
+func TestMemoryStorage_CachedCredentials(t *testing.T) {
+	ctx := context.Background()
+	keeper := &countingKeeper{password: "hash"}
+	storage := NewMemoryStorage(keeper, &mockLogger{}, CacheOptions{Size: 10})
+
+	for i := 0; i < 2; i++ {
+		password, err := storage.GetPassword(ctx, "test")
+		require.NoError(t, err)
+		assert.Equal(t, "hash", password)
+	}
+	assert.Equal(t, 1, keeper.passwords)
+
+	// The user ID comes with the credentials
+	userID, err := storage.GetUserID(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, 123, userID)
+	assert.Equal(t, int64(2), storage.CacheStats().Hits)
+
+	// A new password is read again, whichever server has changed it
+	require.NoError(t, storage.UpdatePassword(ctx, "test", "rehashed"))
+	password, err := storage.GetPassword(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "rehashed", password)
+
+	require.NoError(t, keeper.UpdatePassword(ctx, "test", "elsewhere"))
+	password, err = storage.GetPassword(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "elsewhere", password)
+	assert.Equal(t, 3, keeper.passwords)
+}
+
+func TestMemoryStorage_CacheLimits(t *testing.T) {
+	ctx := context.Background()
+	keeper := &countingKeeper{}
+
+	// The least recently used entry is evicted beyond the size
+	storage := NewMemoryStorage(keeper, &mockLogger{}, CacheOptions{Size: 2})
+	for _, userID := range []int{1, 2, 1, 3} {
+		_, err := storage.GetAllData(ctx, "TextData", userID, time.Time{}, false)
+		require.NoError(t, err)
+	}
+	stats := storage.CacheStats()
+	assert.Equal(t, int64(2), stats.Entries)
+	assert.Equal(t, int64(1), stats.Evictions)
+
+	_, err := storage.GetAllData(ctx, "TextData", 1, time.Time{}, false)
+	require.NoError(t, err)
+	_, err = storage.GetAllData(ctx, "TextData", 2, time.Time{}, false)
+	require.NoError(t, err)
+	assert.Equal(t, 4, keeper.reads)
+
+	// Entries are not served after the TTL
+	keeper.reads = 0
+	storage = NewMemoryStorage(keeper, &mockLogger{}, CacheOptions{Size: 2, TTL: time.Millisecond})
+	_, err = storage.GetAllData(ctx, "TextData", 1, time.Time{}, false)
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	_, err = storage.GetAllData(ctx, "TextData", 1, time.Time{}, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, keeper.reads)
+	assert.Equal(t, int64(1), storage.CacheStats().Evictions)
+
+	// A zero size disables the cache
+	keeper.reads = 0
+	storage = NewMemoryStorage(keeper, &mockLogger{}, CacheOptions{})
+	for i := 0; i < 2; i++ {
+		_, err = storage.GetAllData(ctx, "TextData", 1, time.Time{}, false)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, keeper.reads)
+	assert.Equal(t, models.CacheStats{}, storage.CacheStats())
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// syncCursorPrefix versions the cursor format, so that it can change without breaking clients.
+const syncCursorPrefix = "v1:"
+
+// EncodeSyncCursor wraps a change sequence number into the opaque cursor handed to clients.
+func EncodeSyncCursor(seq int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(syncCursorPrefix + strconv.FormatInt(seq, 10)))
+}
+
+// DecodeSyncCursor extracts the change sequence number from a cursor issued by EncodeSyncCursor.
+func DecodeSyncCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), syncCursorPrefix) {
+		return 0, errors.New("invalid cursor")
+	}
+
+	seq, err := strconv.ParseInt(strings.TrimPrefix(string(raw), syncCursorPrefix), 10, 64)
+	if err != nil || seq < 0 {
+		return 0, errors.New("invalid cursor")
+	}
+
+	return seq, nil
+}
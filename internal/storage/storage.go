@@ -4,13 +4,58 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-// ErrConflict indicates a data conflict in the store.
-var ErrConflict = errors.New("data conflict")
+var (
+	// ErrConflict indicates a data conflict in the store.
+	ErrConflict = errors.New("data conflict")
+	// ErrNotFound indicates that the requested record does not exist in the store.
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidOperation indicates a batch operation that is malformed or not add, update or delete.
+	ErrInvalidOperation = errors.New("invalid batch operation")
+	// ErrReencryptionRunning indicates that a re-encryption was started while one is running.
+	ErrReencryptionRunning = errors.New("re-encryption is already running")
+)
+
+// ConflictError is returned when a record has been changed since the version
+// a client based its update on. It carries the current server copy of the record.
+type ConflictError struct {
+	Current map[string]string
+}
+
+// Error returns the message of ErrConflict.
+func (e *ConflictError) Error() string {
+	return ErrConflict.Error()
+}
+
+// Unwrap allows errors.Is(err, ErrConflict).
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// BatchError is returned when an operation of an atomic batch fails and the whole batch
+// is rolled back. Index is the position of the failed operation in the batch.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+// Error returns the error of the failed operation with its position.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch operation %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the error of the failed operation.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
 
 // Log is an interface representing a logger with Info method.
 type Log interface {
@@ -18,9 +63,18 @@ type Log interface {
 }
 
 // MemoryStorage is an in-memory storage implementation with CRUD operations for URL and user data.
+// It keeps recently read record sets and credentials in a cache and serves them as long as
+// the change sequence of their user in the keeper shows that they are up to date.
 type MemoryStorage struct {
 	keeper Keeper
 	log    Log
+	cache  *cache
+}
+
+// credentials are the cached user ID and password hash of a username.
+type credentials struct {
+	userID   int
+	password string
 }
 
 // Keeper represents the storage keeper interface.
@@ -31,24 +85,153 @@ type Keeper interface {
 	AddUser(ctx context.Context, username string, hashedPassword string) error
 	// GetPassword retrieves the password for the given username.
 	GetPassword(ctx context.Context, username string) (string, error)
+	// UpdatePassword replaces the stored password hash of the given user.
+	UpdatePassword(ctx context.Context, username string, hashedPassword string) error
 	// GetUserID retrieves the user ID for the given username.
 	GetUserID(ctx context.Context, username string) (int, error)
-	// AddData adds data to the storage.
+	// AddSRPUser adds a new user that logs in with SRP instead of a password.
+	AddSRPUser(ctx context.Context, username string, verifier models.SRPVerifier) error
+	// GetSRPVerifier retrieves the user ID and SRP verifier of a user.
+	GetSRPVerifier(ctx context.Context, username string) (int, models.SRPVerifier, error)
+	// AddSRPChallenge stores the server side of an SRP login attempt.
+	AddSRPChallenge(ctx context.Context, challenge models.SRPChallenge) error
+	// ConsumeSRPChallenge removes an SRP login attempt and returns it.
+	ConsumeSRPChallenge(ctx context.Context, id string) (models.SRPChallenge, error)
+	// GetUsername retrieves the username for the given user ID.
+	GetUsername(ctx context.Context, userID int) (string, error)
+	// GetMFA retrieves the two-factor authentication settings of the user.
+	GetMFA(ctx context.Context, userID int) (models.MFA, error)
+	// SetPendingTOTPSecret stores a TOTP secret that awaits confirmation by the user.
+	SetPendingTOTPSecret(ctx context.Context, userID int, secret string) error
+	// EnableMFA activates the pending TOTP secret and replaces the recovery codes of the user.
+	EnableMFA(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error
+	// UseTOTPStep records a used TOTP time step and rejects steps that are not newer than the last one.
+	UseTOTPStep(ctx context.Context, userID int, step int64) error
+	// UseRecoveryCode marks an unused recovery code of the user as used.
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) error
+	// AddSession registers a new device session and revokes older sessions of the same device.
+	AddSession(ctx context.Context, userID int, session models.Session) error
+	// ListSessions retrieves the active sessions of the user.
+	ListSessions(ctx context.Context, userID int) ([]models.Session, error)
+	// TouchSession updates the last-seen time and IP address of the session.
+	TouchSession(ctx context.Context, sessionID string, ip string) error
+	// IsSessionActive checks whether the session exists and has not been revoked.
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+	// RevokeSession revokes a session of the user.
+	RevokeSession(ctx context.Context, userID int, sessionID string) error
+	// RevokeAllSessions revokes all sessions of the user.
+	RevokeAllSessions(ctx context.Context, userID int) error
+	// AddRefreshToken stores the hash of a refresh token issued to a user session.
+	AddRefreshToken(ctx context.Context, tokenHash string, userID int, sessionID string, expiresAt time.Time) error
+	// ConsumeRefreshToken revokes a valid refresh token and returns the IDs of its owner and session.
+	ConsumeRefreshToken(ctx context.Context, tokenHash string) (int, string, error)
+	// RevokeRefreshToken revokes a refresh token of the given user.
+	RevokeRefreshToken(ctx context.Context, tokenHash string, userID int) error
+	// RevokeToken adds the ID of an access token to the revocation list.
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	// IsTokenRevoked checks whether the access token with the given ID has been revoked.
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	// PurgeExpiredTokens removes the refresh tokens and revoked access token IDs that expired
+	// before the given time and returns how many were removed.
+	PurgeExpiredTokens(ctx context.Context, before time.Time) (int64, error)
+	// AddData adds data to the storage. It returns ErrConflict if the user already has a record with the ID.
 	AddData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string) error
-	// UpdateData updates existing data in the storage.
-	UpdateData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string) error
+	// Sync retrieves the records of all kinds that were changed after the given change sequence number.
+	Sync(ctx context.Context, userID int, afterSeq int64, limit int) ([]models.Change, bool, error)
+	// LastChangeSeq returns the change sequence number of the latest change of the user's
+	// records or password, which is zero if there was none.
+	LastChangeSeq(ctx context.Context, userID int) (int64, error)
+	// GetFileContent retrieves the content attributes of a file entry that is not deleted.
+	GetFileContent(ctx context.Context, userID int, entryID string) (models.FileContent, error)
+	// SetFileContent records the size and SHA-256 digest of the uploaded content of a file entry.
+	SetFileContent(ctx context.Context, userID int, entryID string, size int64, digest string) error
+	// LegacyFiles retrieves the file entries that have no recorded content digest.
+	LegacyFiles(ctx context.Context) ([]models.LegacyFile, error)
+	// UnreferencedBlobs returns the digests of blobs that no file entry refers to.
+	UnreferencedBlobs(ctx context.Context) ([]string, error)
+	// DeleteBlob removes an unreferenced blob, calling remove to delete its content.
+	DeleteBlob(ctx context.Context, digest string, remove func() error) error
+	// LegacyBlobs returns file entries whose content may still be stored under the entry.
+	LegacyBlobs(ctx context.Context) ([]models.LegacyBlob, error)
+	// ForgetLegacyBlob marks the content of a file entry as moved to its content key.
+	ForgetLegacyBlob(ctx context.Context, userID int, entryID string) error
+	// BlobStats summarizes the stored blobs and the space saved by sharing them.
+	BlobStats(ctx context.Context) (models.BlobStats, error)
+	// UpdateData updates existing data in the storage and returns the new version of the record.
+	// A non-zero baseVersion makes the update conditional on the current version.
+	UpdateData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string, baseVersion int64) (int64, error)
 	// DeleteData deletes data from the storage.
+	// It returns ErrNotFound if there is no such record or it is already deleted.
 	DeleteData(ctx context.Context, table string, user_id int, entry_id string) error
+	// Batch applies add, update and delete operations in order within a single transaction.
+	// Unless partial is set, a failing operation rolls back the whole batch.
+	Batch(ctx context.Context, userID int, ops []models.BatchOperation, partial bool) ([]models.BatchResult, error)
+	// GetData retrieves a single record that is not deleted from the storage.
+	GetData(ctx context.Context, table string, userID int, entryID string) (map[string]string, error)
 	// GetAllData retrieves all data from the storage.
 	GetAllData(ctx context.Context, table string, user_id int, last_sync time.Time, incl_del bool) ([]map[string]string, error)
 }
 
 // NewMemoryStorage creates a new MemoryStorage instance with the provided Keeper and logger.
-func NewMemoryStorage(keeper Keeper, log Log) *MemoryStorage {
+// A zero cacheOptions.Size disables the cache.
+func NewMemoryStorage(keeper Keeper, log Log, cacheOptions CacheOptions) *MemoryStorage {
 	return &MemoryStorage{
 		keeper: keeper,
 		log:    log,
+		cache:  newCache(cacheOptions),
+	}
+}
+
+// CacheStats returns the counters of the cache, which are zero if the cache is disabled.
+func (ms *MemoryStorage) CacheStats() models.CacheStats {
+	if ms.cache == nil {
+		return models.CacheStats{}
+	}
+
+	return ms.cache.snapshot()
+}
+
+// invalidate drops the cached data of a user after a change.
+func (ms *MemoryStorage) invalidate(userID int) {
+	if ms.cache != nil {
+		ms.cache.invalidateUser(userID)
+	}
+}
+
+// credentials returns the user ID and password hash of a username, from the cache
+// if no change of the user has happened since they were read.
+func (ms *MemoryStorage) credentials(ctx context.Context, username string) (credentials, error) {
+	key := cacheKey{username: username}
+
+	// User IDs never change, so a cached one saves a lookup even if the rest is outdated
+	var userID int
+	if entry, ok := ms.cache.peek(key); ok {
+		userID = entry.userID
+	} else {
+		var err error
+		if userID, err = ms.keeper.GetUserID(ctx, username); err != nil {
+			return credentials{}, err
+		}
+	}
+
+	// The version is read first, so that a change made in the meantime outdates the entry
+	seq, err := ms.keeper.LastChangeSeq(ctx, userID)
+	if err != nil {
+		return credentials{}, err
 	}
+	if value, ok := ms.cache.get(key, seq); ok {
+		return value.(credentials), nil
+	}
+
+	password, err := ms.keeper.GetPassword(ctx, username)
+	if err != nil {
+		return credentials{}, err
+	}
+
+	creds := credentials{userID: userID, password: password}
+	ms.cache.put(key, userID, seq, creds)
+
+	return creds, nil
 }
 
 // UserExists checks if a user exists.
@@ -63,30 +246,308 @@ func (ms *MemoryStorage) AddUser(ctx context.Context, username string, hashedPas
 
 // GetPassword retrieves the password for the given username.
 func (ms *MemoryStorage) GetPassword(ctx context.Context, username string) (string, error) {
-	return ms.keeper.GetPassword(ctx, username)
+	if ms.cache == nil {
+		return ms.keeper.GetPassword(ctx, username)
+	}
+
+	creds, err := ms.credentials(ctx, username)
+	if err != nil {
+		return "", err
+	}
+
+	return creds.password, nil
+}
+
+// UpdatePassword replaces the stored password hash of the given user.
+func (ms *MemoryStorage) UpdatePassword(ctx context.Context, username string, hashedPassword string) error {
+	err := ms.keeper.UpdatePassword(ctx, username, hashedPassword)
+	if ms.cache != nil {
+		ms.cache.invalidate(cacheKey{username: username})
+	}
+
+	return err
 }
 
 // GetUserID retrieves the user ID for the given username.
 func (ms *MemoryStorage) GetUserID(ctx context.Context, username string) (int, error) {
+	if ms.cache != nil {
+		key := cacheKey{username: username}
+		if entry, ok := ms.cache.peek(key); ok {
+			ms.cache.hit(key)
+			return entry.userID, nil
+		}
+	}
+
 	return ms.keeper.GetUserID(ctx, username)
 }
 
+// AddSRPUser adds a new user that logs in with SRP instead of a password.
+func (ms *MemoryStorage) AddSRPUser(ctx context.Context, username string, verifier models.SRPVerifier) error {
+	return ms.keeper.AddSRPUser(ctx, username, verifier)
+}
+
+// GetSRPVerifier retrieves the user ID and SRP verifier of a user.
+func (ms *MemoryStorage) GetSRPVerifier(ctx context.Context, username string) (int, models.SRPVerifier, error) {
+	return ms.keeper.GetSRPVerifier(ctx, username)
+}
+
+// AddSRPChallenge stores the server side of an SRP login attempt.
+func (ms *MemoryStorage) AddSRPChallenge(ctx context.Context, challenge models.SRPChallenge) error {
+	return ms.keeper.AddSRPChallenge(ctx, challenge)
+}
+
+// ConsumeSRPChallenge removes an SRP login attempt and returns it.
+func (ms *MemoryStorage) ConsumeSRPChallenge(ctx context.Context, id string) (models.SRPChallenge, error) {
+	return ms.keeper.ConsumeSRPChallenge(ctx, id)
+}
+
+// GetUsername retrieves the username for the given user ID.
+func (ms *MemoryStorage) GetUsername(ctx context.Context, userID int) (string, error) {
+	return ms.keeper.GetUsername(ctx, userID)
+}
+
+// GetMFA retrieves the two-factor authentication settings of the user.
+func (ms *MemoryStorage) GetMFA(ctx context.Context, userID int) (models.MFA, error) {
+	return ms.keeper.GetMFA(ctx, userID)
+}
+
+// SetPendingTOTPSecret stores a TOTP secret that awaits confirmation by the user.
+func (ms *MemoryStorage) SetPendingTOTPSecret(ctx context.Context, userID int, secret string) error {
+	return ms.keeper.SetPendingTOTPSecret(ctx, userID, secret)
+}
+
+// EnableMFA activates the pending TOTP secret and replaces the recovery codes of the user.
+func (ms *MemoryStorage) EnableMFA(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error {
+	return ms.keeper.EnableMFA(ctx, userID, step, recoveryCodeHashes)
+}
+
+// UseTOTPStep records a used TOTP time step and rejects steps that are not newer than the last one.
+func (ms *MemoryStorage) UseTOTPStep(ctx context.Context, userID int, step int64) error {
+	return ms.keeper.UseTOTPStep(ctx, userID, step)
+}
+
+// UseRecoveryCode marks an unused recovery code of the user as used.
+func (ms *MemoryStorage) UseRecoveryCode(ctx context.Context, userID int, codeHash string) error {
+	return ms.keeper.UseRecoveryCode(ctx, userID, codeHash)
+}
+
+// AddSession registers a new device session and revokes older sessions of the same device.
+func (ms *MemoryStorage) AddSession(ctx context.Context, userID int, session models.Session) error {
+	return ms.keeper.AddSession(ctx, userID, session)
+}
+
+// ListSessions retrieves the active sessions of the user.
+func (ms *MemoryStorage) ListSessions(ctx context.Context, userID int) ([]models.Session, error) {
+	return ms.keeper.ListSessions(ctx, userID)
+}
+
+// TouchSession updates the last-seen time and IP address of the session.
+func (ms *MemoryStorage) TouchSession(ctx context.Context, sessionID string, ip string) error {
+	return ms.keeper.TouchSession(ctx, sessionID, ip)
+}
+
+// IsSessionActive checks whether the session exists and has not been revoked.
+func (ms *MemoryStorage) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return ms.keeper.IsSessionActive(ctx, sessionID)
+}
+
+// RevokeSession revokes a session of the user.
+func (ms *MemoryStorage) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	return ms.keeper.RevokeSession(ctx, userID, sessionID)
+}
+
+// RevokeAllSessions revokes all sessions of the user.
+func (ms *MemoryStorage) RevokeAllSessions(ctx context.Context, userID int) error {
+	return ms.keeper.RevokeAllSessions(ctx, userID)
+}
+
+// AddRefreshToken stores the hash of a refresh token issued to a user session.
+func (ms *MemoryStorage) AddRefreshToken(ctx context.Context, tokenHash string, userID int, sessionID string, expiresAt time.Time) error {
+	return ms.keeper.AddRefreshToken(ctx, tokenHash, userID, sessionID, expiresAt)
+}
+
+// ConsumeRefreshToken revokes a valid refresh token and returns the IDs of its owner and session.
+func (ms *MemoryStorage) ConsumeRefreshToken(ctx context.Context, tokenHash string) (int, string, error) {
+	return ms.keeper.ConsumeRefreshToken(ctx, tokenHash)
+}
+
+// RevokeRefreshToken revokes a refresh token of the given user.
+func (ms *MemoryStorage) RevokeRefreshToken(ctx context.Context, tokenHash string, userID int) error {
+	return ms.keeper.RevokeRefreshToken(ctx, tokenHash, userID)
+}
+
+// RevokeToken adds the ID of an access token to the revocation list.
+func (ms *MemoryStorage) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	return ms.keeper.RevokeToken(ctx, jti, expiresAt)
+}
+
+// IsTokenRevoked checks whether the access token with the given ID has been revoked.
+func (ms *MemoryStorage) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return ms.keeper.IsTokenRevoked(ctx, jti)
+}
+
+// RunTokenPurge removes expired tokens every interval until ctx is done, so that the
+// revocation list checked on every request does not grow with every logout and refresh.
+func (ms *MemoryStorage) RunTokenPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := ms.PurgeExpiredTokens(ctx, time.Now())
+			if err != nil && !errors.Is(err, context.Canceled) {
+				ms.log.Info("cannot purge expired tokens: ", zap.Error(err))
+			}
+			if purged > 0 {
+				ms.log.Info("expired tokens purged", zap.Int64("count", purged))
+			}
+		}
+	}
+}
+
+// PurgeExpiredTokens removes the refresh tokens and revoked access token IDs that expired
+// before the given time and returns how many were removed.
+func (ms *MemoryStorage) PurgeExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
+	return ms.keeper.PurgeExpiredTokens(ctx, before)
+}
+
 // AddData adds data to the storage.
 func (ms *MemoryStorage) AddData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string) error {
+	defer ms.invalidate(user_id)
 	return ms.keeper.AddData(ctx, table, user_id, entry_id, data)
 }
 
-// UpdateData updates existing data in the storage.
-func (ms *MemoryStorage) UpdateData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string) error {
-	return ms.keeper.UpdateData(ctx, table, user_id, entry_id, data)
+// LastChangeSeq returns the change sequence number of the latest change of the user's records or password.
+func (ms *MemoryStorage) LastChangeSeq(ctx context.Context, userID int) (int64, error) {
+	return ms.keeper.LastChangeSeq(ctx, userID)
+}
+
+// Sync retrieves the records of all kinds that were changed after the given change sequence number.
+func (ms *MemoryStorage) Sync(ctx context.Context, userID int, afterSeq int64, limit int) ([]models.Change, bool, error) {
+	return ms.keeper.Sync(ctx, userID, afterSeq, limit)
+}
+
+// GetFileContent retrieves the content attributes of a file entry that is not deleted.
+func (ms *MemoryStorage) GetFileContent(ctx context.Context, userID int, entryID string) (models.FileContent, error) {
+	return ms.keeper.GetFileContent(ctx, userID, entryID)
+}
+
+// SetFileContent records the size and SHA-256 digest of the uploaded content of a file entry.
+func (ms *MemoryStorage) SetFileContent(ctx context.Context, userID int, entryID string, size int64, digest string) error {
+	defer ms.invalidate(userID)
+	return ms.keeper.SetFileContent(ctx, userID, entryID, size, digest)
+}
+
+// LegacyFiles retrieves the file entries that have no recorded content digest.
+func (ms *MemoryStorage) LegacyFiles(ctx context.Context) ([]models.LegacyFile, error) {
+	return ms.keeper.LegacyFiles(ctx)
+}
+
+// UnreferencedBlobs returns the digests of blobs that no file entry refers to.
+func (ms *MemoryStorage) UnreferencedBlobs(ctx context.Context) ([]string, error) {
+	return ms.keeper.UnreferencedBlobs(ctx)
+}
+
+// DeleteBlob removes an unreferenced blob, calling remove to delete its content.
+func (ms *MemoryStorage) DeleteBlob(ctx context.Context, digest string, remove func() error) error {
+	return ms.keeper.DeleteBlob(ctx, digest, remove)
+}
+
+// LegacyBlobs returns file entries whose content may still be stored under the entry.
+func (ms *MemoryStorage) LegacyBlobs(ctx context.Context) ([]models.LegacyBlob, error) {
+	return ms.keeper.LegacyBlobs(ctx)
+}
+
+// ForgetLegacyBlob marks the content of a file entry as moved to its content key.
+func (ms *MemoryStorage) ForgetLegacyBlob(ctx context.Context, userID int, entryID string) error {
+	return ms.keeper.ForgetLegacyBlob(ctx, userID, entryID)
+}
+
+// BlobStats summarizes the stored blobs and the space saved by sharing them.
+func (ms *MemoryStorage) BlobStats(ctx context.Context) (models.BlobStats, error) {
+	return ms.keeper.BlobStats(ctx)
+}
+
+// UpdateData updates existing data in the storage and returns the new version of the record.
+// A non-zero baseVersion makes the update conditional on the current version.
+func (ms *MemoryStorage) UpdateData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string, baseVersion int64) (int64, error) {
+	defer ms.invalidate(user_id)
+	return ms.keeper.UpdateData(ctx, table, user_id, entry_id, data, baseVersion)
 }
 
 // DeleteData deletes data from the storage.
 func (ms *MemoryStorage) DeleteData(ctx context.Context, table string, user_id int, entry_id string) error {
+	defer ms.invalidate(user_id)
 	return ms.keeper.DeleteData(ctx, table, user_id, entry_id)
 }
 
-// GetAllData retrieves all data from the storage.
+// Batch applies add, update and delete operations in order within a single transaction.
+// Unless partial is set, a failing operation rolls back the whole batch.
+func (ms *MemoryStorage) Batch(ctx context.Context, userID int, ops []models.BatchOperation, partial bool) ([]models.BatchResult, error) {
+	defer ms.invalidate(userID)
+	return ms.keeper.Batch(ctx, userID, ops, partial)
+}
+
+// GetData retrieves a single record that is not deleted from the storage.
+func (ms *MemoryStorage) GetData(ctx context.Context, table string, userID int, entryID string) (map[string]string, error) {
+	return ms.keeper.GetData(ctx, table, userID, entryID)
+}
+
+// GetAllData retrieves all data from the storage. With the cache enabled, all records of
+// the table are cached together and filtered for each request, so that clients polling
+// with different sync times share one entry.
 func (ms *MemoryStorage) GetAllData(ctx context.Context, table string, user_id int, last_sync time.Time, incl_del bool) ([]map[string]string, error) {
-	return ms.keeper.GetAllData(ctx, table, user_id, last_sync, incl_del)
+	if ms.cache == nil {
+		return ms.keeper.GetAllData(ctx, table, user_id, last_sync, incl_del)
+	}
+
+	// The version is read first, so that a change made in the meantime outdates the entry
+	seq, err := ms.keeper.LastChangeSeq(ctx, user_id)
+	if err != nil {
+		return nil, err
+	}
+
+	key := cacheKey{userID: user_id, table: table}
+	value, ok := ms.cache.get(key, seq)
+	if !ok {
+		records, err := ms.keeper.GetAllData(ctx, table, user_id, time.Time{}, true)
+		if err != nil {
+			return nil, err
+		}
+		ms.cache.put(key, user_id, seq, records)
+		value = records
+	}
+
+	return filterRecords(value.([]map[string]string), last_sync, incl_del), nil
+}
+
+// filterRecords returns copies of the records changed after lastSync, leaving out deleted
+// ones unless inclDel is set, the same way the keepers filter them.
+func filterRecords(records []map[string]string, lastSync time.Time, inclDel bool) []map[string]string {
+	// Stored timestamps have a precision of microseconds
+	lastSync = lastSync.Truncate(time.Microsecond)
+
+	var filtered []map[string]string
+	for _, record := range records {
+		if deleted, _ := strconv.ParseBool(record["deleted"]); deleted && !inclDel {
+			continue
+		}
+		if !lastSync.IsZero() {
+			updatedAt, err := time.Parse(time.RFC3339Nano, record["updated_at"])
+			if err == nil && !updatedAt.After(lastSync) {
+				continue
+			}
+		}
+
+		copied := make(map[string]string, len(record))
+		for column, value := range record {
+			copied[column] = value
+		}
+		filtered = append(filtered, copied)
+	}
+
+	return filtered
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -23,22 +24,169 @@ func (m *mockKeeper) GetPassword(ctx context.Context, username string) (string,
 	return "hashedPassword", nil
 }
 
+func (m *mockKeeper) UpdatePassword(ctx context.Context, username string, hashedPassword string) error {
+	return nil
+}
+
 func (m *mockKeeper) GetUserID(ctx context.Context, username string) (int, error) {
 	return 123, nil
 }
 
+func (m *mockKeeper) AddSRPUser(ctx context.Context, username string, verifier models.SRPVerifier) error {
+	return nil
+}
+
+func (m *mockKeeper) GetSRPVerifier(ctx context.Context, username string) (int, models.SRPVerifier, error) {
+	return 1, models.SRPVerifier{Salt: "salt", Verifier: "verifier"}, nil
+}
+
+func (m *mockKeeper) AddSRPChallenge(ctx context.Context, challenge models.SRPChallenge) error {
+	return nil
+}
+
+func (m *mockKeeper) ConsumeSRPChallenge(ctx context.Context, id string) (models.SRPChallenge, error) {
+	return models.SRPChallenge{ID: id}, nil
+}
+
+func (m *mockKeeper) GetUsername(ctx context.Context, userID int) (string, error) {
+	return "test", nil
+}
+
+func (m *mockKeeper) GetMFA(ctx context.Context, userID int) (models.MFA, error) {
+	return models.MFA{Enabled: true}, nil
+}
+
+func (m *mockKeeper) SetPendingTOTPSecret(ctx context.Context, userID int, secret string) error {
+	return nil
+}
+
+func (m *mockKeeper) EnableMFA(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error {
+	return nil
+}
+
+func (m *mockKeeper) UseTOTPStep(ctx context.Context, userID int, step int64) error {
+	return nil
+}
+
+func (m *mockKeeper) UseRecoveryCode(ctx context.Context, userID int, codeHash string) error {
+	return nil
+}
+
+func (m *mockKeeper) AddSession(ctx context.Context, userID int, session models.Session) error {
+	return nil
+}
+
+func (m *mockKeeper) ListSessions(ctx context.Context, userID int) ([]models.Session, error) {
+	return []models.Session{{ID: "session"}}, nil
+}
+
+func (m *mockKeeper) TouchSession(ctx context.Context, sessionID string, ip string) error {
+	return nil
+}
+
+func (m *mockKeeper) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockKeeper) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	return nil
+}
+
+func (m *mockKeeper) RevokeAllSessions(ctx context.Context, userID int) error {
+	return nil
+}
+
+func (m *mockKeeper) AddRefreshToken(ctx context.Context, tokenHash string, userID int, sessionID string, expiresAt time.Time) error {
+	return nil
+}
+
+func (m *mockKeeper) ConsumeRefreshToken(ctx context.Context, tokenHash string) (int, string, error) {
+	return 123, "session", nil
+}
+
+func (m *mockKeeper) RevokeRefreshToken(ctx context.Context, tokenHash string, userID int) error {
+	return nil
+}
+
+func (m *mockKeeper) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	return nil
+}
+
+func (m *mockKeeper) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockKeeper) PurgeExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
 func (m *mockKeeper) AddData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string) error {
 	return nil
 }
 
-func (m *mockKeeper) UpdateData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string) error {
+func (m *mockKeeper) UpdateData(ctx context.Context, table string, user_id int, entry_id string, data map[string]string, baseVersion int64) (int64, error) {
+	return baseVersion + 1, nil
+}
+
+func (m *mockKeeper) LastChangeSeq(ctx context.Context, userID int) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockKeeper) Sync(ctx context.Context, userID int, afterSeq int64, limit int) ([]models.Change, bool, error) {
+	return []models.Change{{Table: "TextData", Seq: afterSeq + 1}}, false, nil
+}
+
+func (m *mockKeeper) GetFileContent(ctx context.Context, userID int, entryID string) (models.FileContent, error) {
+	return models.FileContent{Size: 5, SHA256: "digest"}, nil
+}
+
+func (m *mockKeeper) SetFileContent(ctx context.Context, userID int, entryID string, size int64, digest string) error {
+	return nil
+}
+
+func (m *mockKeeper) LegacyFiles(ctx context.Context) ([]models.LegacyFile, error) {
+	return []models.LegacyFile{{UserID: 1, EntryID: "entry"}}, nil
+}
+
+func (m *mockKeeper) UnreferencedBlobs(ctx context.Context) ([]string, error) {
+	return []string{"digest"}, nil
+}
+
+func (m *mockKeeper) DeleteBlob(ctx context.Context, digest string, remove func() error) error {
+	return remove()
+}
+
+func (m *mockKeeper) LegacyBlobs(ctx context.Context) ([]models.LegacyBlob, error) {
+	return nil, nil
+}
+
+func (m *mockKeeper) ForgetLegacyBlob(ctx context.Context, userID int, entryID string) error {
 	return nil
 }
 
+func (m *mockKeeper) BlobStats(ctx context.Context) (models.BlobStats, error) {
+	return models.BlobStats{Blobs: 1, References: 2, StoredBytes: 10, LogicalBytes: 20, SavedBytes: 10}, nil
+}
+
 func (m *mockKeeper) DeleteData(ctx context.Context, table string, user_id int, entry_id string) error {
 	return nil
 }
 
+func (m *mockKeeper) Batch(ctx context.Context, userID int, ops []models.BatchOperation, partial bool) ([]models.BatchResult, error) {
+	results := make([]models.BatchResult, len(ops))
+	for i, op := range ops {
+		results[i] = models.BatchResult{ID: op.ID, Version: 1}
+	}
+	return results, nil
+}
+
+func (m *mockKeeper) GetData(ctx context.Context, table string, userID int, entryID string) (map[string]string, error) {
+	if entryID == "missing" {
+		return nil, ErrNotFound
+	}
+	return map[string]string{"id": entryID, "version": "1"}, nil
+}
+
 func (m *mockKeeper) GetAllData(ctx context.Context, table string, user_id int, last_sync time.Time, incl_del bool) ([]map[string]string, error) {
 	return nil, nil
 }
@@ -48,53 +196,228 @@ type mockLogger struct{}
 func (m *mockLogger) Info(string, ...zapcore.Field) {}
 
 func TestMemoryStorage_UserExists(t *testing.T) {
-	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{})
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
 	exists, err := storage.UserExists(context.Background(), "test")
 	assert.NoError(t, err)
 	assert.True(t, exists)
 }
 
 func TestMemoryStorage_AddUser(t *testing.T) {
-	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{})
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
 	err := storage.AddUser(context.Background(), "test", "hashedPassword")
 	assert.NoError(t, err)
 }
 
 func TestMemoryStorage_GetPassword(t *testing.T) {
-	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{})
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
 	password, err := storage.GetPassword(context.Background(), "test")
 	assert.NoError(t, err)
 	assert.Equal(t, "hashedPassword", password)
 }
 
+func TestMemoryStorage_UpdatePassword(t *testing.T) {
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	err := storage.UpdatePassword(context.Background(), "test", "newHashedPassword")
+	assert.NoError(t, err)
+}
+
 func TestMemoryStorage_GetUserID(t *testing.T) {
-	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{})
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
 	userID, err := storage.GetUserID(context.Background(), "test")
 	assert.NoError(t, err)
 	assert.Equal(t, 123, userID)
 }
 
+func TestMemoryStorage_RefreshTokens(t *testing.T) {
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	ctx := context.Background()
+
+	assert.NoError(t, storage.AddRefreshToken(ctx, "hash", 123, "session", time.Now()))
+
+	userID, sessionID, err := storage.ConsumeRefreshToken(ctx, "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, 123, userID)
+	assert.Equal(t, "session", sessionID)
+
+	assert.NoError(t, storage.RevokeRefreshToken(ctx, "hash", 123))
+}
+
+func TestMemoryStorage_GetUsername(t *testing.T) {
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	username, err := storage.GetUsername(context.Background(), 123)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", username)
+}
+
+func TestMemoryStorage_SRP(t *testing.T) {
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	ctx := context.Background()
+
+	assert.NoError(t, storage.AddSRPUser(ctx, "test", models.SRPVerifier{Salt: "salt", Verifier: "verifier"}))
+
+	userID, verifier, err := storage.GetSRPVerifier(ctx, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, userID)
+	assert.Equal(t, "verifier", verifier.Verifier)
+
+	assert.NoError(t, storage.AddSRPChallenge(ctx, models.SRPChallenge{ID: "challenge"}))
+
+	challenge, err := storage.ConsumeSRPChallenge(ctx, "challenge")
+	assert.NoError(t, err)
+	assert.Equal(t, "challenge", challenge.ID)
+}
+
+func TestMemoryStorage_MFA(t *testing.T) {
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	ctx := context.Background()
+
+	assert.NoError(t, storage.SetPendingTOTPSecret(ctx, 123, "secret"))
+	assert.NoError(t, storage.EnableMFA(ctx, 123, 1, []string{"hash"}))
+
+	mfa, err := storage.GetMFA(ctx, 123)
+	assert.NoError(t, err)
+	assert.True(t, mfa.Enabled)
+
+	assert.NoError(t, storage.UseTOTPStep(ctx, 123, 2))
+	assert.NoError(t, storage.UseRecoveryCode(ctx, 123, "hash"))
+}
+
+func TestMemoryStorage_Sessions(t *testing.T) {
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	ctx := context.Background()
+
+	assert.NoError(t, storage.AddSession(ctx, 123, models.Session{ID: "session", DeviceID: "device"}))
+
+	sessions, err := storage.ListSessions(ctx, 123)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+
+	assert.NoError(t, storage.TouchSession(ctx, "session", "127.0.0.1"))
+
+	active, err := storage.IsSessionActive(ctx, "session")
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	assert.NoError(t, storage.RevokeSession(ctx, 123, "session"))
+	assert.NoError(t, storage.RevokeAllSessions(ctx, 123))
+}
+
+func TestMemoryStorage_RevokeToken(t *testing.T) {
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	ctx := context.Background()
+
+	assert.NoError(t, storage.RevokeToken(ctx, "jti", time.Now()))
+
+	revoked, err := storage.IsTokenRevoked(ctx, "jti")
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
+
 func TestMemoryStorage_AddData(t *testing.T) {
-	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{})
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
 	err := storage.AddData(context.Background(), "table", 123, "entry", map[string]string{"key": "value"})
 	assert.NoError(t, err)
 }
 
 func TestMemoryStorage_UpdateData(t *testing.T) {
-	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{})
-	err := storage.UpdateData(context.Background(), "table", 123, "entry", map[string]string{"key": "value"})
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	version, err := storage.UpdateData(context.Background(), "table", 123, "entry", map[string]string{"key": "value"}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+}
+
+func TestConflictError(t *testing.T) {
+	var err error = &ConflictError{Current: map[string]string{"version": "2"}}
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Equal(t, ErrConflict.Error(), err.Error())
+}
+
+func TestMemoryStorage_Sync(t *testing.T) {
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	changes, hasMore, err := storage.Sync(context.Background(), 123, 5, 10)
+	assert.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, []models.Change{{Table: "TextData", Seq: 6}}, changes)
+}
+
+func TestMemoryStorage_SetFileContent(t *testing.T) {
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	err := storage.SetFileContent(context.Background(), 123, "entry", 5, "digest")
+	assert.NoError(t, err)
+}
+
+func TestMemoryStorage_LegacyFiles(t *testing.T) {
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	files, err := storage.LegacyFiles(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.LegacyFile{{UserID: 1, EntryID: "entry"}}, files)
+}
+
+func TestMemoryStorage_GetFileContent(t *testing.T) {
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	content, err := storage.GetFileContent(context.Background(), 123, "entry")
+	assert.NoError(t, err)
+	assert.Equal(t, "digest", content.SHA256)
+}
+
+func TestMemoryStorage_Blobs(t *testing.T) {
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	ctx := context.Background()
+
+	digests, err := storage.UnreferencedBlobs(ctx)
 	assert.NoError(t, err)
+	assert.Equal(t, []string{"digest"}, digests)
+
+	removed := false
+	assert.NoError(t, storage.DeleteBlob(ctx, "digest", func() error {
+		removed = true
+		return nil
+	}))
+	assert.True(t, removed)
+
+	stats, err := storage.BlobStats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), stats.SavedBytes)
 }
 
 func TestMemoryStorage_DeleteData(t *testing.T) {
-	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{})
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
 	err := storage.DeleteData(context.Background(), "table", 123, "entry")
 	assert.NoError(t, err)
 }
 
+func TestMemoryStorage_Batch(t *testing.T) {
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	results, err := storage.Batch(context.Background(), 123, []models.BatchOperation{{Op: models.BatchAdd, ID: "a"}, {Op: models.BatchDelete, ID: "b"}}, false)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "b", results[1].ID)
+}
+
+func TestMemoryStorage_GetData(t *testing.T) {
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
+	record, err := storage.GetData(context.Background(), "table", 123, "entry")
+	assert.NoError(t, err)
+	assert.Equal(t, "entry", record["id"])
+
+	_, err = storage.GetData(context.Background(), "table", 123, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
 func TestMemoryStorage_GetAllData(t *testing.T) {
-	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{})
+	storage := NewMemoryStorage(&mockKeeper{}, &mockLogger{}, CacheOptions{})
 	data, err := storage.GetAllData(context.Background(), "table", 123, time.Now(), false)
 	assert.NoError(t, err)
 	assert.Nil(t, data)
 }
+
+func TestSyncCursor(t *testing.T) {
+	seq, err := DecodeSyncCursor(EncodeSyncCursor(42))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), seq)
+
+	for _, cursor := range []string{"", "invalid", EncodeSyncCursor(1)[1:]} {
+		_, err := DecodeSyncCursor(cursor)
+		assert.Error(t, err, cursor)
+	}
+}
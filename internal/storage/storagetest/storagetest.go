@@ -0,0 +1,798 @@
+// Package storagetest provides a conformance suite for storage.Keeper implementations,
+// so that every keeper behaves the same towards the storage layer.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+	"github.com/wurt83ow/gophkeeper-server/internal/schema"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+)
+
+// NewKeeper returns an empty keeper for a single test.
+type NewKeeper func(t *testing.T) storage.Keeper
+
+// Run runs the conformance suite against the keepers made by newKeeper.
+// Every test gets its own keeper.
+func Run(t *testing.T, newKeeper NewKeeper) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, k storage.Keeper)
+	}{
+		{"Users", testUsers},
+		{"SRP", testSRP},
+		{"MFA", testMFA},
+		{"Sessions", testSessions},
+		{"Tokens", testTokens},
+		{"Records", testRecords},
+		{"Sync", testSync},
+		{"LastChangeSeq", testLastChangeSeq},
+		{"DataKeys", testDataKeys},
+		{"RewriteColumns", testRewriteColumns},
+		{"Batch", testBatch},
+		{"Files", testFiles},
+		{"Concurrency", testConcurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newKeeper(t))
+		})
+	}
+}
+
+// addUser registers a user and returns its ID.
+func addUser(t *testing.T, k storage.Keeper, username string) int {
+	t.Helper()
+	ctx := context.Background()
+
+	require.NoError(t, k.AddUser(ctx, username, "hash"))
+	userID, err := k.GetUserID(ctx, username)
+	require.NoError(t, err)
+
+	return userID
+}
+
+func testUsers(t *testing.T, k storage.Keeper) {
+	ctx := context.Background()
+
+	exists, err := k.UserExists(ctx, "alice")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	alice := addUser(t, k, "alice")
+	bob := addUser(t, k, "bob")
+	assert.NotEqual(t, alice, bob)
+
+	exists, err = k.UserExists(ctx, "alice")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	// Usernames are unique
+	assert.Error(t, k.AddUser(ctx, "alice", "other"))
+
+	password, err := k.GetPassword(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "hash", password)
+
+	require.NoError(t, k.UpdatePassword(ctx, "alice", "rehashed"))
+	password, err = k.GetPassword(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "rehashed", password)
+
+	username, err := k.GetUsername(ctx, bob)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", username)
+
+	_, err = k.GetPassword(ctx, "nobody")
+	assert.Error(t, err)
+	_, err = k.GetUserID(ctx, "nobody")
+	assert.Error(t, err)
+	_, err = k.GetUsername(ctx, bob+alice)
+	assert.Error(t, err)
+}
+
+func testSRP(t *testing.T, k storage.Keeper) {
+	ctx := context.Background()
+
+	verifier := models.SRPVerifier{Salt: "salt", Verifier: "verifier"}
+	require.NoError(t, k.AddSRPUser(ctx, "alice", verifier))
+	assert.Error(t, k.AddSRPUser(ctx, "alice", verifier))
+
+	userID, got, err := k.GetSRPVerifier(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, verifier, got)
+
+	aliceID, err := k.GetUserID(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, aliceID, userID)
+
+	// SRP users have no password
+	password, err := k.GetPassword(ctx, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, password)
+
+	addUser(t, k, "bob")
+	_, _, err = k.GetSRPVerifier(ctx, "bob")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	challenge := models.SRPChallenge{ID: "challenge", Username: "alice", A: "a", B: "b", PrivateB: "private",
+		ExpiresAt: time.Now().Add(time.Minute).UTC().Truncate(time.Microsecond)}
+	require.NoError(t, k.AddSRPChallenge(ctx, challenge))
+
+	// A challenge can be answered only once
+	got2, err := k.ConsumeSRPChallenge(ctx, "challenge")
+	require.NoError(t, err)
+	assert.Equal(t, challenge.Username, got2.Username)
+	assert.Equal(t, challenge.PrivateB, got2.PrivateB)
+	assert.True(t, challenge.ExpiresAt.Equal(got2.ExpiresAt))
+
+	_, err = k.ConsumeSRPChallenge(ctx, "challenge")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	expired := challenge
+	expired.ID = "expired"
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	require.NoError(t, k.AddSRPChallenge(ctx, expired))
+	_, err = k.ConsumeSRPChallenge(ctx, "expired")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func testMFA(t *testing.T, k storage.Keeper) {
+	ctx := context.Background()
+	userID := addUser(t, k, "alice")
+
+	_, err := k.GetMFA(ctx, userID)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.ErrorIs(t, k.EnableMFA(ctx, userID, 1, nil), storage.ErrNotFound)
+
+	require.NoError(t, k.SetPendingTOTPSecret(ctx, userID, "pending"))
+	mfa, err := k.GetMFA(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, models.MFA{PendingSecret: "pending"}, mfa)
+
+	require.NoError(t, k.EnableMFA(ctx, userID, 10, []string{"code1", "code2"}))
+	mfa, err = k.GetMFA(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, models.MFA{Secret: "pending", Enabled: true, LastStep: 10}, mfa)
+
+	// A new secret awaits confirmation while the active one stays in use
+	require.NoError(t, k.SetPendingTOTPSecret(ctx, userID, "next"))
+	mfa, err = k.GetMFA(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, models.MFA{Secret: "pending", PendingSecret: "next", Enabled: true, LastStep: 10}, mfa)
+
+	// Steps can not be replayed
+	assert.ErrorIs(t, k.UseTOTPStep(ctx, userID, 10), storage.ErrConflict)
+	require.NoError(t, k.UseTOTPStep(ctx, userID, 11))
+	assert.ErrorIs(t, k.UseTOTPStep(ctx, userID, 11), storage.ErrConflict)
+
+	// Recovery codes can be used once
+	require.NoError(t, k.UseRecoveryCode(ctx, userID, "code1"))
+	assert.ErrorIs(t, k.UseRecoveryCode(ctx, userID, "code1"), storage.ErrNotFound)
+	assert.ErrorIs(t, k.UseRecoveryCode(ctx, userID, "unknown"), storage.ErrNotFound)
+
+	// Enabling again replaces the recovery codes
+	require.NoError(t, k.EnableMFA(ctx, userID, 12, []string{"code3"}))
+	assert.ErrorIs(t, k.UseRecoveryCode(ctx, userID, "code2"), storage.ErrNotFound)
+	require.NoError(t, k.UseRecoveryCode(ctx, userID, "code3"))
+}
+
+func testSessions(t *testing.T, k storage.Keeper) {
+	ctx := context.Background()
+	alice := addUser(t, k, "alice")
+	bob := addUser(t, k, "bob")
+
+	require.NoError(t, k.AddSession(ctx, alice, models.Session{ID: "s1", DeviceID: "laptop", DeviceName: "Laptop", IP: "10.0.0.1"}))
+	require.NoError(t, k.AddSession(ctx, alice, models.Session{ID: "s2", DeviceID: "phone", DeviceName: "Phone"}))
+	require.NoError(t, k.AddSession(ctx, bob, models.Session{ID: "s3", DeviceID: "laptop"}))
+
+	sessions, err := k.ListSessions(ctx, alice)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"s1", "s2"}, sessionIDs(sessions))
+
+	// A new login from the same device replaces its session
+	require.NoError(t, k.AddSession(ctx, alice, models.Session{ID: "s4", DeviceID: "laptop", DeviceName: "Laptop"}))
+	active, err := k.IsSessionActive(ctx, "s1")
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	active, err = k.IsSessionActive(ctx, "s3")
+	require.NoError(t, err)
+	assert.True(t, active)
+
+	// Sessions are listed most recently seen first
+	time.Sleep(time.Millisecond)
+	require.NoError(t, k.TouchSession(ctx, "s2", "10.0.0.2"))
+	sessions, err = k.ListSessions(ctx, alice)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s2", "s4"}, sessionIDs(sessions))
+	assert.Equal(t, "10.0.0.2", sessions[0].IP)
+	assert.Equal(t, "Phone", sessions[0].DeviceName)
+	assert.True(t, sessions[0].LastSeen.After(sessions[0].CreatedAt))
+
+	// Users can revoke only their own active sessions
+	assert.ErrorIs(t, k.RevokeSession(ctx, bob, "s2"), storage.ErrNotFound)
+	require.NoError(t, k.RevokeSession(ctx, alice, "s2"))
+	assert.ErrorIs(t, k.RevokeSession(ctx, alice, "s2"), storage.ErrNotFound)
+
+	require.NoError(t, k.RevokeAllSessions(ctx, alice))
+	sessions, err = k.ListSessions(ctx, alice)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	sessions, err = k.ListSessions(ctx, bob)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s3"}, sessionIDs(sessions))
+}
+
+func sessionIDs(sessions []models.Session) []string {
+	ids := make([]string, 0, len(sessions))
+	for _, s := range sessions {
+		ids = append(ids, s.ID)
+	}
+
+	return ids
+}
+
+func testTokens(t *testing.T, k storage.Keeper) {
+	ctx := context.Background()
+	userID := addUser(t, k, "alice")
+	require.NoError(t, k.AddSession(ctx, userID, models.Session{ID: "s1", DeviceID: "laptop"}))
+	require.NoError(t, k.AddSession(ctx, userID, models.Session{ID: "s2", DeviceID: "phone"}))
+
+	expiresAt := time.Now().Add(time.Hour)
+	require.NoError(t, k.AddRefreshToken(ctx, "valid", userID, "s1", expiresAt))
+	require.NoError(t, k.AddRefreshToken(ctx, "expired", userID, "s1", time.Now().Add(-time.Hour)))
+	require.NoError(t, k.AddRefreshToken(ctx, "revoked", userID, "s1", expiresAt))
+	require.NoError(t, k.AddRefreshToken(ctx, "session", userID, "s2", expiresAt))
+
+	// Each refresh token can be used only once
+	owner, sessionID, err := k.ConsumeRefreshToken(ctx, "valid")
+	require.NoError(t, err)
+	assert.Equal(t, userID, owner)
+	assert.Equal(t, "s1", sessionID)
+
+	_, _, err = k.ConsumeRefreshToken(ctx, "valid")
+	assert.Error(t, err)
+	_, _, err = k.ConsumeRefreshToken(ctx, "expired")
+	assert.Error(t, err)
+	_, _, err = k.ConsumeRefreshToken(ctx, "unknown")
+	assert.Error(t, err)
+
+	// Tokens are revoked only by their owner
+	require.NoError(t, k.RevokeRefreshToken(ctx, "revoked", userID+1))
+	require.NoError(t, k.RevokeRefreshToken(ctx, "revoked", userID))
+	_, _, err = k.ConsumeRefreshToken(ctx, "revoked")
+	assert.Error(t, err)
+
+	// Tokens of revoked sessions are not accepted
+	require.NoError(t, k.RevokeSession(ctx, userID, "s2"))
+	_, _, err = k.ConsumeRefreshToken(ctx, "session")
+	assert.Error(t, err)
+
+	revoked, err := k.IsTokenRevoked(ctx, "jti")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, k.RevokeToken(ctx, "jti", expiresAt))
+	require.NoError(t, k.RevokeToken(ctx, "jti", expiresAt))
+	revoked, err = k.IsTokenRevoked(ctx, "jti")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	// Expired rows are purged, the others are kept
+	require.NoError(t, k.RevokeToken(ctx, "old", time.Now().Add(-time.Hour)))
+	purged, err := k.PurgeExpiredTokens(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+
+	revoked, err = k.IsTokenRevoked(ctx, "old")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+	revoked, err = k.IsTokenRevoked(ctx, "jti")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	require.NoError(t, k.AddSession(ctx, userID, models.Session{ID: "s3", DeviceID: "tablet"}))
+	require.NoError(t, k.AddRefreshToken(ctx, "kept", userID, "s3", expiresAt))
+	_, _, err = k.ConsumeRefreshToken(ctx, "kept")
+	assert.NoError(t, err)
+}
+
+func testRecords(t *testing.T, k storage.Keeper) {
+	ctx := context.Background()
+	alice := addUser(t, k, "alice")
+	bob := addUser(t, k, "bob")
+
+	require.NoError(t, k.AddData(ctx, "TextData", alice, "note", map[string]string{"data": "text"}))
+	require.NoError(t, k.AddData(ctx, "UserCredentials", alice, "site", map[string]string{"login": "alice", "password": "secret", "meta_info": "meta"}))
+
+	record, err := k.GetData(ctx, "TextData", alice, "note")
+	require.NoError(t, err)
+	updatedAt, err := time.Parse(time.RFC3339Nano, record["updated_at"])
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), updatedAt, time.Minute)
+	delete(record, "updated_at")
+	assert.Equal(t, map[string]string{
+		"id": "note", "user_id": strconv.Itoa(alice), "deleted": "false", "version": "1", "data": "text", "meta_info": "",
+	}, record)
+
+	// Table names are matched case-insensitively and the columns are checked
+	_, err = k.GetData(ctx, "textdata", alice, "note")
+	require.NoError(t, err)
+	assert.Error(t, k.AddData(ctx, "Users", alice, "user", map[string]string{"username": "x"}))
+	assert.Error(t, k.AddData(ctx, "TextData", alice, "bad", map[string]string{"unknown": "x"}))
+	assert.Error(t, k.AddData(ctx, "TextData", alice, "bad", map[string]string{"meta_info": "x"}))
+	_, err = k.UpdateData(ctx, "TextData", alice, "note", map[string]string{}, 0)
+	assert.Error(t, err)
+
+	// Record IDs are unique within a table
+	assert.ErrorIs(t, k.AddData(ctx, "TextData", alice, "note", map[string]string{"data": "again"}), storage.ErrConflict)
+
+	// Updates merge the columns and increment the version
+	version, err := k.UpdateData(ctx, "TextData", alice, "note", map[string]string{"meta_info": "meta"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+
+	version, err = k.UpdateData(ctx, "TextData", alice, "note", map[string]string{"data": "changed"}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+
+	record, err = k.GetData(ctx, "TextData", alice, "note")
+	require.NoError(t, err)
+	assert.Equal(t, "changed", record["data"])
+	assert.Equal(t, "meta", record["meta_info"])
+	assert.Equal(t, "3", record["version"])
+
+	// An update based on an outdated version gets the current record
+	_, err = k.UpdateData(ctx, "TextData", alice, "note", map[string]string{"data": "stale"}, 2)
+	var conflict *storage.ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, "3", conflict.Current["version"])
+	assert.Equal(t, "changed", conflict.Current["data"])
+
+	_, err = k.UpdateData(ctx, "TextData", alice, "missing", map[string]string{"data": "x"}, 0)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Records of other users are not visible
+	_, err = k.GetData(ctx, "TextData", bob, "note")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = k.UpdateData(ctx, "TextData", bob, "note", map[string]string{"data": "x"}, 0)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.ErrorIs(t, k.DeleteData(ctx, "TextData", bob, "note"), storage.ErrNotFound)
+
+	records, err := k.GetAllData(ctx, "TextData", bob, time.Time{}, true)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	// Deleted records are kept for sync only
+	time.Sleep(time.Millisecond)
+	since := time.Now().UTC()
+	time.Sleep(time.Millisecond)
+	require.NoError(t, k.DeleteData(ctx, "TextData", alice, "note"))
+
+	_, err = k.GetData(ctx, "TextData", alice, "note")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// A record is deleted only once
+	assert.ErrorIs(t, k.DeleteData(ctx, "TextData", alice, "note"), storage.ErrNotFound)
+	assert.ErrorIs(t, k.DeleteData(ctx, "TextData", alice, "missing"), storage.ErrNotFound)
+
+	records, err = k.GetAllData(ctx, "TextData", alice, time.Time{}, false)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	records, err = k.GetAllData(ctx, "TextData", alice, time.Time{}, true)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "true", records[0]["deleted"])
+	assert.Equal(t, "4", records[0]["version"])
+
+	// Only records changed after lastSync are listed
+	records, err = k.GetAllData(ctx, "UserCredentials", alice, since, true)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	records, err = k.GetAllData(ctx, "TextData", alice, since, true)
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	records, err = k.GetAllData(ctx, "UserCredentials", alice, time.Time{}, false)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "secret", records[0]["password"])
+
+	assert.Error(t, k.DeleteData(ctx, "TextData", alice, ""))
+	assert.Error(t, k.DeleteData(ctx, "Users", alice, "note"))
+}
+
+func testLastChangeSeq(t *testing.T, k storage.Keeper) {
+	ctx := context.Background()
+	alice := addUser(t, k, "alice")
+	bob := addUser(t, k, "bob")
+
+	seq, err := k.LastChangeSeq(ctx, alice)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), seq)
+
+	// Every change of a record advances the sequence to the change's number
+	require.NoError(t, k.AddData(ctx, "TextData", alice, "a", map[string]string{"data": "a"}))
+	require.NoError(t, k.DeleteData(ctx, "TextData", alice, "a"))
+	changes, _, err := k.Sync(ctx, alice, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+
+	seq, err = k.LastChangeSeq(ctx, alice)
+	require.NoError(t, err)
+	assert.Equal(t, changes[0].Seq, seq)
+
+	// So does a new password, which outdates cached credentials
+	require.NoError(t, k.UpdatePassword(ctx, "alice", "rehashed"))
+	next, err := k.LastChangeSeq(ctx, alice)
+	require.NoError(t, err)
+	assert.Greater(t, next, seq)
+
+	seq, err = k.LastChangeSeq(ctx, bob)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), seq)
+}
+
+// dataKeeper is implemented by the keepers that store the data keys of encrypted records.
+type dataKeeper interface {
+	GetDataKey(ctx context.Context, userID int) (string, error)
+	AddDataKey(ctx context.Context, userID int, wrappedKey string) (string, error)
+	ReplaceDataKey(ctx context.Context, userID int, oldKey, newKey string) error
+	ListUserIDs(ctx context.Context, afterID int, limit int) ([]int, error)
+	CountUsers(ctx context.Context) (int64, error)
+	RewriteColumns(ctx context.Context, table string, userID int, entryID string, current, replacement map[string]string) error
+}
+
+func testDataKeys(t *testing.T, k storage.Keeper) {
+	dk, ok := k.(dataKeeper)
+	if !ok {
+		t.Skip("the keeper does not store data keys")
+	}
+
+	ctx := context.Background()
+	alice := addUser(t, k, "alice")
+	bob := addUser(t, k, "bob")
+
+	_, err := dk.GetDataKey(ctx, alice)
+	assert.True(t, errors.Is(err, storage.ErrNotFound))
+
+	stored, err := dk.AddDataKey(ctx, alice, "first")
+	require.NoError(t, err)
+	assert.Equal(t, "first", stored)
+
+	// A key added by another server later is not stored, the first one is returned
+	stored, err = dk.AddDataKey(ctx, alice, "second")
+	require.NoError(t, err)
+	assert.Equal(t, "first", stored)
+
+	wrappedKey, err := dk.GetDataKey(ctx, alice)
+	require.NoError(t, err)
+	assert.Equal(t, "first", wrappedKey)
+
+	_, err = dk.GetDataKey(ctx, bob)
+	assert.True(t, errors.Is(err, storage.ErrNotFound))
+
+	// A key is replaced only if nobody has replaced it in the meantime
+	require.NoError(t, dk.ReplaceDataKey(ctx, alice, "first", "rewrapped"))
+	assert.ErrorIs(t, dk.ReplaceDataKey(ctx, alice, "first", "again"), storage.ErrConflict)
+	assert.ErrorIs(t, dk.ReplaceDataKey(ctx, bob, "", "none"), storage.ErrConflict)
+
+	wrappedKey, err = dk.GetDataKey(ctx, alice)
+	require.NoError(t, err)
+	assert.Equal(t, "rewrapped", wrappedKey)
+
+	// The users are listed in pages
+	carol := addUser(t, k, "carol")
+	userIDs, err := dk.ListUserIDs(ctx, 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{alice, bob}, userIDs)
+
+	userIDs, err = dk.ListUserIDs(ctx, bob, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{carol}, userIDs)
+
+	userIDs, err = dk.ListUserIDs(ctx, carol, 2)
+	require.NoError(t, err)
+	assert.Empty(t, userIDs)
+
+	count, err := dk.CountUsers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func testRewriteColumns(t *testing.T, k storage.Keeper) {
+	dk, ok := k.(dataKeeper)
+	if !ok {
+		t.Skip("the keeper does not store data keys")
+	}
+
+	ctx := context.Background()
+	alice := addUser(t, k, "alice")
+	require.NoError(t, k.AddData(ctx, "TextData", alice, "note", map[string]string{"data": "text", "meta_info": "old"}))
+	require.NoError(t, k.AddData(ctx, "TextData", alice, "gone", map[string]string{"data": "text", "meta_info": "old"}))
+	require.NoError(t, k.DeleteData(ctx, "TextData", alice, "gone"))
+	seq, err := k.LastChangeSeq(ctx, alice)
+	require.NoError(t, err)
+	before, err := k.GetData(ctx, "TextData", alice, "note")
+	require.NoError(t, err)
+
+	// The meta info is stored as it is, even by keepers that encrypt the data.
+	// Columns are replaced only while they hold the expected values, deleted records included
+	require.NoError(t, dk.RewriteColumns(ctx, "TextData", alice, "note", map[string]string{"meta_info": "old"}, map[string]string{"meta_info": "new"}))
+	require.NoError(t, dk.RewriteColumns(ctx, "TextData", alice, "gone", map[string]string{"meta_info": "old"}, map[string]string{"meta_info": "new"}))
+	assert.ErrorIs(t, dk.RewriteColumns(ctx, "TextData", alice, "note", map[string]string{"meta_info": "old"}, map[string]string{"meta_info": "again"}), storage.ErrConflict)
+	assert.ErrorIs(t, dk.RewriteColumns(ctx, "TextData", alice, "missing", map[string]string{"meta_info": "old"}, map[string]string{"meta_info": "again"}), storage.ErrConflict)
+	assert.ErrorIs(t, dk.RewriteColumns(ctx, "TextData", alice, "note", nil, map[string]string{"unknown": "x"}), schema.ErrUnknownColumn)
+
+	// The records keep their versions and nothing is left to sync
+	after, err := k.GetData(ctx, "TextData", alice, "note")
+	require.NoError(t, err)
+	assert.Equal(t, "new", after["meta_info"])
+	assert.Equal(t, "text", after["data"])
+	assert.Equal(t, before["version"], after["version"])
+	assert.Equal(t, before["updated_at"], after["updated_at"])
+
+	records, err := k.GetAllData(ctx, "TextData", alice, time.Time{}, true)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	for _, record := range records {
+		assert.Equal(t, "new", record["meta_info"])
+	}
+
+	lastSeq, err := k.LastChangeSeq(ctx, alice)
+	require.NoError(t, err)
+	assert.Equal(t, seq, lastSeq)
+}
+
+func testSync(t *testing.T, k storage.Keeper) {
+	ctx := context.Background()
+	alice := addUser(t, k, "alice")
+	bob := addUser(t, k, "bob")
+
+	require.NoError(t, k.AddData(ctx, "TextData", alice, "a", map[string]string{"data": "a"}))
+	require.NoError(t, k.AddData(ctx, "UserCredentials", alice, "b", map[string]string{"login": "l", "password": "p"}))
+	require.NoError(t, k.AddData(ctx, "CreditCardData", alice, "c", map[string]string{"card_number": "1", "expiration_date": "2", "cvv": "3"}))
+	require.NoError(t, k.AddData(ctx, "TextData", bob, "d", map[string]string{"data": "d"}))
+	_, err := k.UpdateData(ctx, "TextData", alice, "a", map[string]string{"data": "changed"}, 0)
+	require.NoError(t, err)
+	require.NoError(t, k.DeleteData(ctx, "UserCredentials", alice, "b"))
+
+	// Every record is listed once in its latest state, ordered by the change
+	changes, hasMore, err := k.Sync(ctx, alice, 0, 10)
+	require.NoError(t, err)
+	assert.False(t, hasMore)
+	require.Len(t, changes, 3)
+	assert.Equal(t, []string{"c", "a", "b"}, changeIDs(changes))
+	assert.Equal(t, []string{"CreditCardData", "TextData", "UserCredentials"},
+		[]string{changes[0].Table, changes[1].Table, changes[2].Table})
+	assert.Equal(t, "changed", changes[1].Entry["data"])
+	assert.Equal(t, "true", changes[2].Entry["deleted"])
+	assert.Less(t, changes[0].Seq, changes[1].Seq)
+	assert.Less(t, changes[1].Seq, changes[2].Seq)
+
+	// Pages continue after the last change of the previous one
+	changes, hasMore, err = k.Sync(ctx, alice, 0, 2)
+	require.NoError(t, err)
+	assert.True(t, hasMore)
+	assert.Equal(t, []string{"c", "a"}, changeIDs(changes))
+
+	changes, hasMore, err = k.Sync(ctx, alice, changes[1].Seq, 2)
+	require.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.Equal(t, []string{"b"}, changeIDs(changes))
+
+	changes, _, err = k.Sync(ctx, alice, changes[0].Seq, 2)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// Change sequences are kept per user
+	changes, _, err = k.Sync(ctx, bob, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d"}, changeIDs(changes))
+	assert.Equal(t, int64(1), changes[0].Seq)
+}
+
+func changeIDs(changes []models.Change) []string {
+	ids := make([]string, 0, len(changes))
+	for _, change := range changes {
+		ids = append(ids, change.Entry["id"])
+	}
+
+	return ids
+}
+
+func testBatch(t *testing.T, k storage.Keeper) {
+	ctx := context.Background()
+	userID := addUser(t, k, "alice")
+	require.NoError(t, k.AddData(ctx, "TextData", userID, "existing", map[string]string{"data": "x"}))
+
+	// A failing operation rolls back the whole batch
+	results, err := k.Batch(ctx, userID, []models.BatchOperation{
+		{Op: models.BatchAdd, Table: "TextData", ID: "new", Data: map[string]string{"data": "new"}},
+		{Op: models.BatchDelete, Table: "TextData", ID: "existing"},
+		{Op: models.BatchUpdate, Table: "TextData", ID: "existing", Data: map[string]string{"data": "y"}, BaseVersion: 1},
+		{Op: models.BatchAdd, Table: "TextData", ID: "never", Data: map[string]string{"data": "never"}},
+	}, false)
+	var batchErr *storage.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 2, batchErr.Index)
+	assert.ErrorIs(t, err, storage.ErrConflict)
+	require.Len(t, results, 3)
+	assert.Equal(t, "2", results[2].Current["version"])
+
+	_, err = k.GetData(ctx, "TextData", userID, "new")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	record, err := k.GetData(ctx, "TextData", userID, "existing")
+	require.NoError(t, err)
+	assert.Equal(t, "1", record["version"])
+
+	changes, _, err := k.Sync(ctx, userID, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"existing"}, changeIDs(changes))
+
+	// A committed batch applies all operations in order
+	results, err = k.Batch(ctx, userID, []models.BatchOperation{
+		{Op: models.BatchAdd, Table: "TextData", ID: "new", Data: map[string]string{"data": "new"}},
+		{Op: models.BatchUpdate, Table: "TextData", ID: "new", Data: map[string]string{"data": "newer"}, BaseVersion: 1},
+		{Op: models.BatchDelete, Table: "TextData", ID: "existing"},
+	}, false)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, int64(1), results[0].Version)
+	assert.Equal(t, int64(2), results[1].Version)
+	assert.NoError(t, results[2].Err)
+
+	record, err = k.GetData(ctx, "TextData", userID, "new")
+	require.NoError(t, err)
+	assert.Equal(t, "newer", record["data"])
+	_, err = k.GetData(ctx, "TextData", userID, "existing")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// In partial mode only the failing operations are undone
+	results, err = k.Batch(ctx, userID, []models.BatchOperation{
+		{Op: models.BatchUpdate, Table: "TextData", ID: "missing", Data: map[string]string{"data": "x"}},
+		{Op: models.BatchAdd, Table: "TextData", ID: "partial", Data: map[string]string{"data": "x"}},
+		{Op: models.BatchAdd, Table: "TextData", ID: "partial", Data: map[string]string{"data": "again"}},
+		{Op: "upsert", Table: "TextData", ID: "x"},
+		{Op: models.BatchAdd, Table: "TextData", Data: map[string]string{"data": "x"}},
+		{Op: models.BatchAdd, Table: "Users", ID: "user", Data: map[string]string{"username": "x"}},
+	}, true)
+	require.NoError(t, err)
+	require.Len(t, results, 6)
+	assert.ErrorIs(t, results[0].Err, storage.ErrNotFound)
+	assert.NoError(t, results[1].Err)
+	assert.ErrorIs(t, results[2].Err, storage.ErrConflict)
+	assert.ErrorIs(t, results[3].Err, storage.ErrInvalidOperation)
+	assert.ErrorIs(t, results[4].Err, storage.ErrInvalidOperation)
+	assert.Error(t, results[5].Err)
+
+	record, err = k.GetData(ctx, "TextData", userID, "partial")
+	require.NoError(t, err)
+	assert.Equal(t, "x", record["data"])
+}
+
+func testFiles(t *testing.T, k storage.Keeper) {
+	ctx := context.Background()
+	userID := addUser(t, k, "alice")
+	require.NoError(t, k.AddData(ctx, "FilesData", userID, "f1", map[string]string{"path": "a.txt"}))
+	require.NoError(t, k.AddData(ctx, "FilesData", userID, "f2", map[string]string{"path": "b.txt"}))
+
+	// Entries have no content until it is uploaded
+	_, err := k.GetFileContent(ctx, userID, "f1")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	record, err := k.GetData(ctx, "FilesData", userID, "f1")
+	require.NoError(t, err)
+	assert.Equal(t, "", record["sha256"])
+	assert.Equal(t, "", record["size"])
+
+	require.NoError(t, k.SetFileContent(ctx, userID, "f1", 3, "d1"))
+	content, err := k.GetFileContent(ctx, userID, "f1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), content.Size)
+	assert.Equal(t, "d1", content.SHA256)
+	assert.WithinDuration(t, time.Now(), content.UpdatedAt, time.Minute)
+
+	record, err = k.GetData(ctx, "FilesData", userID, "f1")
+	require.NoError(t, err)
+	assert.Equal(t, "d1", record["sha256"])
+	assert.Equal(t, "3", record["size"])
+	assert.Equal(t, "2", record["version"])
+
+	assert.ErrorIs(t, k.SetFileContent(ctx, userID, "missing", 3, "d1"), storage.ErrNotFound)
+	assert.ErrorIs(t, k.SetFileContent(ctx, userID+1, "f1", 3, "d1"), storage.ErrNotFound)
+
+	// Identical content is stored once
+	require.NoError(t, k.SetFileContent(ctx, userID, "f2", 3, "d1"))
+	stats, err := k.BlobStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.BlobStats{Blobs: 1, References: 2, StoredBytes: 3, LogicalBytes: 6, SavedBytes: 3}, stats)
+
+	// New content moves the reference
+	require.NoError(t, k.SetFileContent(ctx, userID, "f1", 5, "d2"))
+	stats, err = k.BlobStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.BlobStats{Blobs: 2, References: 2, StoredBytes: 8, LogicalBytes: 8}, stats)
+
+	digests, err := k.UnreferencedBlobs(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, digests)
+
+	// Deleted entries release their content
+	require.NoError(t, k.DeleteData(ctx, "FilesData", userID, "f2"))
+	_, err = k.GetFileContent(ctx, userID, "f2")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.ErrorIs(t, k.SetFileContent(ctx, userID, "f2", 3, "d1"), storage.ErrNotFound)
+
+	digests, err = k.UnreferencedBlobs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d1"}, digests)
+
+	assert.ErrorIs(t, k.DeleteBlob(ctx, "d2", func() error { return nil }), storage.ErrConflict)
+	assert.ErrorIs(t, k.DeleteBlob(ctx, "unknown", func() error { return nil }), storage.ErrNotFound)
+
+	// A blob is kept if its content can not be removed
+	errRemove := errors.New("remove failed")
+	assert.ErrorIs(t, k.DeleteBlob(ctx, "d1", func() error { return errRemove }), errRemove)
+
+	removed := false
+	require.NoError(t, k.DeleteBlob(ctx, "d1", func() error {
+		removed = true
+		return nil
+	}))
+	assert.True(t, removed)
+	assert.ErrorIs(t, k.DeleteBlob(ctx, "d1", func() error { return nil }), storage.ErrNotFound)
+
+	stats, err = k.BlobStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.BlobStats{Blobs: 1, References: 1, StoredBytes: 5, LogicalBytes: 5}, stats)
+}
+
+func testConcurrency(t *testing.T, k storage.Keeper) {
+	ctx := context.Background()
+	userID := addUser(t, k, "alice")
+	require.NoError(t, k.AddData(ctx, "TextData", userID, "shared", map[string]string{"data": "x"}))
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := "entry" + strconv.Itoa(i)
+			assert.NoError(t, k.AddData(ctx, "TextData", userID, id, map[string]string{"data": id}))
+			_, err := k.UpdateData(ctx, "TextData", userID, "shared", map[string]string{"data": id}, 0)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	// Every change got its own sequence number
+	changes, _, err := k.Sync(ctx, userID, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, changes, workers+1)
+
+	seqs := make([]int64, 0, len(changes))
+	for _, change := range changes {
+		seqs = append(seqs, change.Seq)
+	}
+	assert.True(t, sort.SliceIsSorted(seqs, func(i, j int) bool { return seqs[i] < seqs[j] }))
+
+	record, err := k.GetData(ctx, "TextData", userID, "shared")
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(workers+1), record["version"])
+	assert.Equal(t, int64(2*workers+1), changes[len(changes)-1].Seq)
+}
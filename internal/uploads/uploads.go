@@ -0,0 +1,340 @@
+// Package uploads provides resumable chunked uploads of vault files.
+package uploads
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Errors returned by the upload store.
+var (
+	ErrNotFound       = errors.New("upload not found")
+	ErrOffsetMismatch = errors.New("upload offset mismatch")
+	ErrTooLarge       = errors.New("upload exceeds its length")
+	ErrBusy           = errors.New("upload is in use")
+	ErrIncomplete     = errors.New("upload is incomplete")
+)
+
+// uploadsDir is the directory inside a user directory that holds unfinished uploads.
+const uploadsDir = ".uploads"
+
+// idPattern matches upload IDs, so an ID taken from a request cannot leave the uploads directory.
+var idPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// Log is an interface for logging operations.
+type Log interface {
+	Info(string, ...zapcore.Field)
+}
+
+// Session describes an unfinished upload.
+type Session struct {
+	ID        string    `json:"id"`
+	EntryID   string    `json:"entryID"`
+	Length    int64     `json:"length"`
+	Offset    int64     `json:"offset"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Result describes the content of a completed upload.
+type Result struct {
+	// Path is the file that holds the content until it is moved to its final place.
+	Path   string
+	Size   int64
+	SHA256 string
+}
+
+// Store keeps unfinished uploads as files next to the files of their user:
+// <root>/<userID>/.uploads/<id>.json holds the session and <id>.part the received bytes.
+// The upload offset is the size of the part file, so it survives restarts.
+type Store struct {
+	root string
+
+	mu   sync.Mutex
+	busy map[string]bool
+}
+
+// NewStore creates a Store for the file storage directory root.
+func NewStore(root string) *Store {
+	return &Store{
+		root: root,
+		busy: make(map[string]bool),
+	}
+}
+
+// Create starts an upload of length bytes for the FilesData entry entryID.
+func (s *Store) Create(userID int, entryID string, length int64) (Session, error) {
+	dir := s.dir(userID)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return Session{}, err
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return Session{}, err
+	}
+
+	session := Session{
+		ID:        hex.EncodeToString(buf),
+		EntryID:   entryID,
+		Length:    length,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	meta, err := json.Marshal(session)
+	if err != nil {
+		return Session{}, err
+	}
+
+	base := filepath.Join(dir, session.ID)
+	if err := os.WriteFile(base+".part", nil, 0600); err != nil {
+		return Session{}, err
+	}
+	if err := os.WriteFile(base+".json", meta, 0600); err != nil {
+		os.Remove(base + ".part")
+		return Session{}, err
+	}
+
+	return session, nil
+}
+
+// Get returns an upload with its current offset.
+func (s *Store) Get(userID int, id string) (Session, error) {
+	if !idPattern.MatchString(id) {
+		return Session{}, ErrNotFound
+	}
+	base := filepath.Join(s.dir(userID), id)
+
+	meta, err := os.ReadFile(base + ".json")
+	if errors.Is(err, os.ErrNotExist) {
+		return Session{}, ErrNotFound
+	}
+	if err != nil {
+		return Session{}, err
+	}
+
+	var session Session
+	if err := json.Unmarshal(meta, &session); err != nil {
+		return Session{}, err
+	}
+
+	info, err := os.Stat(base + ".part")
+	if errors.Is(err, os.ErrNotExist) {
+		return Session{}, ErrNotFound
+	}
+	if err != nil {
+		return Session{}, err
+	}
+	session.Offset = info.Size()
+
+	return session, nil
+}
+
+// Append writes the chunk r at offset, which must be the current offset of the upload.
+// Bytes received before a read error are kept, so the client can resume from the
+// returned offset.
+func (s *Store) Append(userID int, id string, offset int64, r io.Reader) (int64, error) {
+	unlock, err := s.lock(userID, id)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	session, err := s.Get(userID, id)
+	if err != nil {
+		return 0, err
+	}
+	if offset != session.Offset {
+		return session.Offset, ErrOffsetMismatch
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.dir(userID), id+".part"), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return offset, err
+	}
+	defer f.Close()
+
+	n, copyErr := io.Copy(f, io.LimitReader(r, session.Length-offset))
+	offset += n
+
+	// The received bytes are acknowledged with the new offset, so they must be on disk
+	if err := f.Sync(); err != nil {
+		return offset, err
+	}
+	if copyErr != nil {
+		return offset, copyErr
+	}
+
+	// Anything beyond the announced length is rejected
+	var extra [1]byte
+	if n, _ := r.Read(extra[:]); n > 0 {
+		return offset, ErrTooLarge
+	}
+
+	return offset, nil
+}
+
+// Finish checks that an upload is complete and hashes its content.
+// The caller moves Result.Path to its final place and then removes the upload.
+func (s *Store) Finish(userID int, id string) (Result, error) {
+	unlock, err := s.lock(userID, id)
+	if err != nil {
+		return Result{}, err
+	}
+	defer unlock()
+
+	session, err := s.Get(userID, id)
+	if err != nil {
+		return Result{}, err
+	}
+	if session.Offset != session.Length {
+		return Result{}, ErrIncomplete
+	}
+
+	path := filepath.Join(s.dir(userID), id+".part")
+	f, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Path:   path,
+		Size:   session.Length,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// Remove deletes an upload and the bytes received for it.
+func (s *Store) Remove(userID int, id string) error {
+	if !idPattern.MatchString(id) {
+		return ErrNotFound
+	}
+	base := filepath.Join(s.dir(userID), id)
+
+	err := os.Remove(base + ".json")
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	// The part file is already gone if the upload was finished
+	if err := os.Remove(base + ".part"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// Collect removes the uploads of all users that received no bytes since before.
+// It returns the number of removed uploads.
+func (s *Store) Collect(before time.Time) (int, error) {
+	metas, err := filepath.Glob(filepath.Join(s.root, "*", uploadsDir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, meta := range metas {
+		base := meta[:len(meta)-len(".json")]
+
+		// Every appended chunk updates the modification time of the part file
+		info, err := os.Stat(base + ".part")
+		if err != nil {
+			info, err = os.Stat(meta)
+		}
+		if err != nil || !info.ModTime().Before(before) {
+			continue
+		}
+
+		key := filepath.Dir(filepath.Dir(base)) + "/" + filepath.Base(base)
+		if !s.tryLock(key) {
+			continue
+		}
+		os.Remove(base + ".part")
+		err = os.Remove(meta)
+		s.unlock(key)
+		if err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// RunCollector removes uploads that were inactive for longer than ttl every interval
+// until ctx is done.
+func (s *Store) RunCollector(ctx context.Context, interval, ttl time.Duration, log Log) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := s.Collect(time.Now().Add(-ttl))
+			if err != nil {
+				log.Info("cannot collect stale uploads: ", zap.Error(err))
+			}
+			if removed > 0 {
+				log.Info("stale uploads removed", zap.Int("count", removed))
+			}
+		}
+	}
+}
+
+// dir returns the uploads directory of a user.
+func (s *Store) dir(userID int) string {
+	return filepath.Join(s.root, strconv.Itoa(userID), uploadsDir)
+}
+
+// lock reserves an upload for a single request; concurrent requests get ErrBusy.
+func (s *Store) lock(userID int, id string) (func(), error) {
+	key := filepath.Join(s.root, strconv.Itoa(userID)) + "/" + id
+	if !s.tryLock(key) {
+		return nil, ErrBusy
+	}
+
+	return func() { s.unlock(key) }, nil
+}
+
+func (s *Store) tryLock(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.busy[key] {
+		return false
+	}
+	s.busy[key] = true
+
+	return true
+}
+
+func (s *Store) unlock(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.busy, key)
+}
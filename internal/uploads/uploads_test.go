@@ -0,0 +1,120 @@
+package uploads
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingReader returns its data and then a broken connection.
+type failingReader struct {
+	data string
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, errors.New("connection reset")
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestStore_Resume(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	session, err := store.Create(1, "entry", 11)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), session.Offset)
+
+	// A dropped connection keeps the bytes received so far
+	offset, err := store.Append(1, session.ID, 0, &failingReader{data: "hello"})
+	assert.Error(t, err)
+	assert.Equal(t, int64(5), offset)
+
+	session, err = store.Get(1, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), session.Offset)
+	assert.Equal(t, "entry", session.EntryID)
+
+	// A chunk for a stale offset is rejected
+	offset, err = store.Append(1, session.ID, 0, strings.NewReader("hello world"))
+	assert.ErrorIs(t, err, ErrOffsetMismatch)
+	assert.Equal(t, int64(5), offset)
+
+	_, err = store.Finish(1, session.ID)
+	assert.ErrorIs(t, err, ErrIncomplete)
+
+	offset, err = store.Append(1, session.ID, 5, strings.NewReader(" world"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), offset)
+
+	result, err := store.Finish(1, session.ID)
+	require.NoError(t, err)
+	sum := sha256.Sum256([]byte("hello world"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), result.SHA256)
+	assert.Equal(t, int64(11), result.Size)
+
+	content, err := os.ReadFile(result.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(content))
+
+	require.NoError(t, store.Remove(1, session.ID))
+	_, err = store.Get(1, session.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStore_Limits(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	session, err := store.Create(1, "entry", 4)
+	require.NoError(t, err)
+
+	offset, err := store.Append(1, session.ID, 0, strings.NewReader("too long"))
+	assert.ErrorIs(t, err, ErrTooLarge)
+	assert.Equal(t, int64(4), offset)
+
+	// Uploads of other users and malformed IDs are not found
+	_, err = store.Get(2, session.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Get(1, "../../1/.uploads/"+session.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	unlock, err := store.lock(1, session.ID)
+	require.NoError(t, err)
+	_, err = store.Append(1, session.ID, 4, strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrBusy)
+	unlock()
+}
+
+func TestStore_Collect(t *testing.T) {
+	root := t.TempDir()
+	store := NewStore(root)
+
+	stale, err := store.Create(1, "stale", 4)
+	require.NoError(t, err)
+	active, err := store.Create(2, "active", 4)
+	require.NoError(t, err)
+
+	old := time.Now().Add(-2 * time.Hour)
+	base := filepath.Join(root, "1", uploadsDir, stale.ID)
+	require.NoError(t, os.Chtimes(base+".part", old, old))
+	require.NoError(t, os.Chtimes(base+".json", old, old))
+
+	removed, err := store.Collect(time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	_, err = store.Get(1, stale.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Get(2, active.ID)
+	assert.NoError(t, err)
+}
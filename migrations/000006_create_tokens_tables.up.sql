@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS RefreshTokens (
+    token_hash TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES Users(id)
+);
+
+CREATE TABLE IF NOT EXISTS RevokedTokens (
+    jti TEXT PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_refreshtokens_expires_at ON RefreshTokens (expires_at);
+CREATE INDEX IF NOT EXISTS idx_revokedtokens_expires_at ON RevokedTokens (expires_at);
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE RefreshTokens DROP COLUMN IF EXISTS session_id;
+DROP TABLE IF EXISTS Sessions;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_srpchallenges_expires_at;
+DROP TABLE IF EXISTS SRPChallenges;
+DROP TABLE IF EXISTS SRPVerifiers;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS SRPVerifiers (
+    user_id INTEGER PRIMARY KEY,
+    salt TEXT NOT NULL,
+    verifier TEXT NOT NULL,
+    FOREIGN KEY(user_id) REFERENCES Users(id)
+);
+
+CREATE TABLE IF NOT EXISTS SRPChallenges (
+    id TEXT PRIMARY KEY,
+    username TEXT NOT NULL,
+    public_a TEXT NOT NULL,
+    public_b TEXT NOT NULL,
+    private_b TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_srpchallenges_expires_at ON SRPChallenges (expires_at);
\ No newline at end of file
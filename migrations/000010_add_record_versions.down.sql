@@ -0,0 +1,4 @@
+ALTER TABLE FilesData DROP COLUMN IF EXISTS version;
+ALTER TABLE TextData DROP COLUMN IF EXISTS version;
+ALTER TABLE CreditCardData DROP COLUMN IF EXISTS version;
+ALTER TABLE UserCredentials DROP COLUMN IF EXISTS version;
\ No newline at end of file
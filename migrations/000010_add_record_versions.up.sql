@@ -0,0 +1,4 @@
+ALTER TABLE UserCredentials ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE CreditCardData ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE TextData ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE FilesData ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
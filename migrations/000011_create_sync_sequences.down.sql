@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_filesdata_change_seq;
+DROP INDEX IF EXISTS idx_textdata_change_seq;
+DROP INDEX IF EXISTS idx_creditcarddata_change_seq;
+DROP INDEX IF EXISTS idx_usercredentials_change_seq;
+
+ALTER TABLE FilesData DROP COLUMN IF EXISTS change_seq;
+ALTER TABLE TextData DROP COLUMN IF EXISTS change_seq;
+ALTER TABLE CreditCardData DROP COLUMN IF EXISTS change_seq;
+ALTER TABLE UserCredentials DROP COLUMN IF EXISTS change_seq;
+
+DROP TABLE IF EXISTS SyncSequences;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE FilesData DROP COLUMN IF EXISTS sha256;
+ALTER TABLE FilesData DROP COLUMN IF EXISTS size;
\ No newline at end of file
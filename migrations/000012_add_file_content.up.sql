@@ -0,0 +1,2 @@
+ALTER TABLE FilesData ADD COLUMN IF NOT EXISTS size BIGINT;
+ALTER TABLE FilesData ADD COLUMN IF NOT EXISTS sha256 TEXT;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_filesdata_legacy_blob;
+ALTER TABLE FilesData DROP COLUMN IF EXISTS legacy_blob;
+
+DROP INDEX IF EXISTS idx_blobs_unreferenced;
+
+DROP TABLE IF EXISTS Blobs;
\ No newline at end of file
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS DataKeys (
+    user_id INTEGER PRIMARY KEY,
+    wrapped_key TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES Users(id)
+);
@@ -0,0 +1,4816 @@
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.3.0 DO NOT EDIT.
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+const (
+	TokenAuthScopes = "tokenAuth.Scopes"
+)
+
+// Defines values for BatchOperationOp.
+const (
+	Add    BatchOperationOp = "add"
+	Delete BatchOperationOp = "delete"
+	Update BatchOperationOp = "update"
+)
+
+// Defines values for RecordDeleted.
+const (
+	False RecordDeleted = "false"
+	True  RecordDeleted = "true"
+)
+
+// BatchOperation defines model for BatchOperation.
+type BatchOperation struct {
+	// BaseVersion BaseVersion makes an update conditional on the current version of the record.
+	BaseVersion int64             `json:"baseVersion,omitempty"`
+	Data        map[string]string `json:"data,omitempty"`
+	Id          string            `json:"id"`
+	Op          BatchOperationOp  `json:"op"`
+	Table       string            `json:"table"`
+}
+
+// BatchOperationOp defines model for BatchOperation.Op.
+type BatchOperationOp string
+
+// BatchResponse defines model for BatchResponse.
+type BatchResponse struct {
+	Committed bool          `json:"committed"`
+	Results   []BatchResult `json:"results"`
+}
+
+// BatchResult defines model for BatchResult.
+type BatchResult struct {
+	// Current A stored vault record with its service fields.
+	Current *Record `json:"current,omitempty"`
+	Error   *string `json:"error,omitempty"`
+	Id      string  `json:"id"`
+	Status  int     `json:"status"`
+	Version *int64  `json:"version,omitempty"`
+}
+
+// Binary A file; its content is uploaded separately with /sendFile or /uploads.
+type Binary struct {
+	Extension *string `json:"extension,omitempty"`
+	MetaInfo  *string `json:"meta_info,omitempty"`
+	Path      string  `json:"path"`
+}
+
+// BlobStats defines model for BlobStats.
+type BlobStats struct {
+	Blobs        int64 `json:"blobs"`
+	LogicalBytes int64 `json:"logicalBytes"`
+	References   int64 `json:"references"`
+	SavedBytes   int64 `json:"savedBytes"`
+	StoredBytes  int64 `json:"storedBytes"`
+}
+
+// CacheStats defines model for CacheStats.
+type CacheStats struct {
+	Entries       int64 `json:"entries"`
+	Evictions     int64 `json:"evictions"`
+	Hits          int64 `json:"hits"`
+	Invalidations int64 `json:"invalidations"`
+	Misses        int64 `json:"misses"`
+}
+
+// Card A bank card.
+type Card struct {
+	CardNumber     string  `json:"card_number"`
+	Cvv            string  `json:"cvv"`
+	ExpirationDate string  `json:"expiration_date"`
+	MetaInfo       *string `json:"meta_info,omitempty"`
+}
+
+// Change defines model for Change.
+type Change struct {
+	// Entry A stored vault record with its service fields.
+	Entry Record `json:"entry"`
+	Table string `json:"table"`
+}
+
+// Credentials A login and password for a site or an application.
+type Credentials struct {
+	Login    string  `json:"login"`
+	MetaInfo *string `json:"meta_info,omitempty"`
+	Password string  `json:"password"`
+}
+
+// FileContentResponse defines model for FileContentResponse.
+type FileContentResponse struct {
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// Item The fields of a new vault record; the kind must match the {table} path parameter.
+type Item struct {
+	union json.RawMessage
+}
+
+// ItemPatch The changed fields of a vault record.
+type ItemPatch struct {
+	// BaseVersion The version the changes are based on, if it is not sent in If-Match.
+	BaseVersion    *string `json:"base_version,omitempty"`
+	CardNumber     *string `json:"card_number,omitempty"`
+	Cvv            *string `json:"cvv,omitempty"`
+	Data           *string `json:"data,omitempty"`
+	ExpirationDate *string `json:"expiration_date,omitempty"`
+	Extension      *string `json:"extension,omitempty"`
+	Login          *string `json:"login,omitempty"`
+	MetaInfo       *string `json:"meta_info,omitempty"`
+	Password       *string `json:"password,omitempty"`
+	Path           *string `json:"path,omitempty"`
+}
+
+// LoginResponse defines model for LoginResponse.
+type LoginResponse struct {
+	ChallengeToken *string `json:"challengeToken,omitempty"`
+	DeviceID       *string `json:"deviceID,omitempty"`
+	MfaRequired    *bool   `json:"mfa_required,omitempty"`
+	RefreshToken   *string `json:"refreshToken,omitempty"`
+
+	// ServerProof The SRP-6a server proof of logins made with /srp/login/verify.
+	ServerProof *string `json:"serverProof,omitempty"`
+	SessionID   *string `json:"sessionID,omitempty"`
+	Token       *string `json:"token,omitempty"`
+	UserID      *int    `json:"userID,omitempty"`
+}
+
+// MFAEnrollment defines model for MFAEnrollment.
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
+
+// Record A stored vault record with its service fields.
+type Record struct {
+	CardNumber     *string       `json:"card_number,omitempty"`
+	Cvv            *string       `json:"cvv,omitempty"`
+	Data           *string       `json:"data,omitempty"`
+	Deleted        RecordDeleted `json:"deleted"`
+	ExpirationDate *string       `json:"expiration_date,omitempty"`
+	Extension      *string       `json:"extension,omitempty"`
+	Id             string        `json:"id"`
+	Login          *string       `json:"login,omitempty"`
+	MetaInfo       *string       `json:"meta_info,omitempty"`
+	Password       *string       `json:"password,omitempty"`
+	Path           *string       `json:"path,omitempty"`
+
+	// Sha256 The SHA-256 digest of the uploaded content of a file.
+	Sha256 *string `json:"sha256,omitempty"`
+
+	// Size The size of the uploaded content of a file.
+	Size      *string `json:"size,omitempty"`
+	UpdatedAt string  `json:"updated_at"`
+	UserId    string  `json:"user_id"`
+	Version   string  `json:"version"`
+}
+
+// RecordDeleted defines model for Record.Deleted.
+type RecordDeleted string
+
+// RecoveryCodes defines model for RecoveryCodes.
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// ReencryptionProgress defines model for ReencryptionProgress.
+type ReencryptionProgress struct {
+	// DataKeys The number of data keys wrapped with the current master key.
+	DataKeys   int64      `json:"dataKeys"`
+	Error      *string    `json:"error,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	// KeyID The ID of the master key the run moves the data to.
+	KeyID string `json:"keyID"`
+
+	// Records The number of records that were encrypted.
+	Records   int64      `json:"records"`
+	Running   bool       `json:"running"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+
+	// Total The number of users when the run started. Users registered during the run are processed as well, so users may end up above total.
+	Total int64 `json:"total"`
+
+	// Users The number of users processed so far.
+	Users int64 `json:"users"`
+}
+
+// SRPChallenge defines model for SRPChallenge.
+type SRPChallenge struct {
+	ChallengeID string `json:"challengeID"`
+	PublicB     string `json:"publicB"`
+	Salt        string `json:"salt"`
+}
+
+// Session defines model for Session.
+type Session struct {
+	CreatedAt  time.Time `json:"createdAt"`
+	Current    bool      `json:"current"`
+	DeviceID   string    `json:"deviceID"`
+	DeviceName string    `json:"deviceName"`
+	Id         string    `json:"id"`
+	Ip         string    `json:"ip"`
+	LastSeen   time.Time `json:"lastSeen"`
+}
+
+// SyncResponse defines model for SyncResponse.
+type SyncResponse struct {
+	Changes []Change `json:"changes"`
+	Cursor  string   `json:"cursor"`
+	HasMore bool     `json:"hasMore"`
+}
+
+// Text An arbitrary text.
+type Text struct {
+	Data     string  `json:"data"`
+	MetaInfo *string `json:"meta_info,omitempty"`
+}
+
+// UploadSession defines model for UploadSession.
+type UploadSession struct {
+	CreatedAt time.Time `json:"createdAt"`
+	EntryID   string    `json:"entryID"`
+	Id        string    `json:"id"`
+	Length    int64     `json:"length"`
+	Offset    int64     `json:"offset"`
+}
+
+// Version defines model for Version.
+type Version struct {
+	Version int64 `json:"version"`
+}
+
+// EntryID defines model for EntryID.
+type EntryID = string
+
+// Table defines model for Table.
+type Table = string
+
+// UploadID defines model for UploadID.
+type UploadID = string
+
+// UserID defines model for UserID.
+type UserID = int
+
+// Login defines model for Login.
+type Login = LoginResponse
+
+// PostBatchUserIDJSONBody defines parameters for PostBatchUserID.
+type PostBatchUserIDJSONBody struct {
+	Operations []BatchOperation `json:"operations"`
+
+	// Partial Partial keeps the successful operations when others fail.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// PostLoginJSONBody defines parameters for PostLogin.
+type PostLoginJSONBody struct {
+	DeviceID   string `json:"deviceID,omitempty"`
+	DeviceName string `json:"deviceName,omitempty"`
+	Password   string `json:"password"`
+	Username   string `json:"username"`
+}
+
+// PostLoginMfaJSONBody defines parameters for PostLoginMfa.
+type PostLoginMfaJSONBody struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code,omitempty"`
+	RecoveryCode   string `json:"recoveryCode,omitempty"`
+}
+
+// PostLogoutJSONBody defines parameters for PostLogout.
+type PostLogoutJSONBody struct {
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+// PostMfaUserIDEnrollJSONBody defines parameters for PostMfaUserIDEnroll.
+type PostMfaUserIDEnrollJSONBody struct {
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recoveryCode,omitempty"`
+}
+
+// PostMfaUserIDVerifyJSONBody defines parameters for PostMfaUserIDVerify.
+type PostMfaUserIDVerifyJSONBody struct {
+	Code string `json:"code"`
+}
+
+// PostRegisterJSONBody defines parameters for PostRegister.
+type PostRegisterJSONBody struct {
+	Password string `json:"password"`
+	Username string `json:"username"`
+}
+
+// PostSrpLoginInitJSONBody defines parameters for PostSrpLoginInit.
+type PostSrpLoginInitJSONBody struct {
+	PublicA  string `json:"publicA"`
+	Username string `json:"username"`
+}
+
+// PostSrpLoginVerifyJSONBody defines parameters for PostSrpLoginVerify.
+type PostSrpLoginVerifyJSONBody struct {
+	ChallengeID string `json:"challengeID"`
+	ClientProof string `json:"clientProof"`
+	DeviceID    string `json:"deviceID,omitempty"`
+	DeviceName  string `json:"deviceName,omitempty"`
+}
+
+// PostSrpRegisterJSONBody defines parameters for PostSrpRegister.
+type PostSrpRegisterJSONBody struct {
+	Salt     string `json:"salt"`
+	Username string `json:"username"`
+	Verifier string `json:"verifier"`
+}
+
+// GetSyncUserIDParams defines parameters for GetSyncUserID.
+type GetSyncUserIDParams struct {
+	// Cursor Cursor is the opaque position returned by the previous call. Without it, all records are returned.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Limit Limit is the maximum number of changes in the response.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PostTokenRefreshJSONBody defines parameters for PostTokenRefresh.
+type PostTokenRefreshJSONBody struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// PostUploadsUserIDJSONBody defines parameters for PostUploadsUserID.
+type PostUploadsUserIDJSONBody struct {
+	EntryID string `json:"entryID"`
+	Length  int64  `json:"length"`
+}
+
+// PatchUploadsUserIDUploadIDParams defines parameters for PatchUploadsUserIDUploadID.
+type PatchUploadsUserIDUploadIDParams struct {
+	// UploadOffset The offset the chunk starts at, which must be the current offset of the upload.
+	UploadOffset int64 `json:"Upload-Offset"`
+}
+
+// PostAddDataTableUserIDEntryIDJSONRequestBody defines body for PostAddDataTableUserIDEntryID for application/json ContentType.
+type PostAddDataTableUserIDEntryIDJSONRequestBody = Item
+
+// PostBatchUserIDJSONRequestBody defines body for PostBatchUserID for application/json ContentType.
+type PostBatchUserIDJSONRequestBody PostBatchUserIDJSONBody
+
+// PostLoginJSONRequestBody defines body for PostLogin for application/json ContentType.
+type PostLoginJSONRequestBody PostLoginJSONBody
+
+// PostLoginMfaJSONRequestBody defines body for PostLoginMfa for application/json ContentType.
+type PostLoginMfaJSONRequestBody PostLoginMfaJSONBody
+
+// PostLogoutJSONRequestBody defines body for PostLogout for application/json ContentType.
+type PostLogoutJSONRequestBody PostLogoutJSONBody
+
+// PostMfaUserIDEnrollJSONRequestBody defines body for PostMfaUserIDEnroll for application/json ContentType.
+type PostMfaUserIDEnrollJSONRequestBody PostMfaUserIDEnrollJSONBody
+
+// PostMfaUserIDVerifyJSONRequestBody defines body for PostMfaUserIDVerify for application/json ContentType.
+type PostMfaUserIDVerifyJSONRequestBody PostMfaUserIDVerifyJSONBody
+
+// PostRegisterJSONRequestBody defines body for PostRegister for application/json ContentType.
+type PostRegisterJSONRequestBody PostRegisterJSONBody
+
+// PostSrpLoginInitJSONRequestBody defines body for PostSrpLoginInit for application/json ContentType.
+type PostSrpLoginInitJSONRequestBody PostSrpLoginInitJSONBody
+
+// PostSrpLoginVerifyJSONRequestBody defines body for PostSrpLoginVerify for application/json ContentType.
+type PostSrpLoginVerifyJSONRequestBody PostSrpLoginVerifyJSONBody
+
+// PostSrpRegisterJSONRequestBody defines body for PostSrpRegister for application/json ContentType.
+type PostSrpRegisterJSONRequestBody PostSrpRegisterJSONBody
+
+// PostTokenRefreshJSONRequestBody defines body for PostTokenRefresh for application/json ContentType.
+type PostTokenRefreshJSONRequestBody PostTokenRefreshJSONBody
+
+// PutUpdateDataTableUserIDEntryIDJSONRequestBody defines body for PutUpdateDataTableUserIDEntryID for application/json ContentType.
+type PutUpdateDataTableUserIDEntryIDJSONRequestBody = ItemPatch
+
+// PostUploadsUserIDJSONRequestBody defines body for PostUploadsUserID for application/json ContentType.
+type PostUploadsUserIDJSONRequestBody PostUploadsUserIDJSONBody
+
+// AsCredentials returns the union data inside the Item as a Credentials
+func (t Item) AsCredentials() (Credentials, error) {
+	var body Credentials
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromCredentials overwrites any union data inside the Item as the provided Credentials
+func (t *Item) FromCredentials(v Credentials) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeCredentials performs a merge with any union data inside the Item, using the provided Credentials
+func (t *Item) MergeCredentials(v Credentials) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsCard returns the union data inside the Item as a Card
+func (t Item) AsCard() (Card, error) {
+	var body Card
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromCard overwrites any union data inside the Item as the provided Card
+func (t *Item) FromCard(v Card) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeCard performs a merge with any union data inside the Item, using the provided Card
+func (t *Item) MergeCard(v Card) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsText returns the union data inside the Item as a Text
+func (t Item) AsText() (Text, error) {
+	var body Text
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromText overwrites any union data inside the Item as the provided Text
+func (t *Item) FromText(v Text) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeText performs a merge with any union data inside the Item, using the provided Text
+func (t *Item) MergeText(v Text) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsBinary returns the union data inside the Item as a Binary
+func (t Item) AsBinary() (Binary, error) {
+	var body Binary
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromBinary overwrites any union data inside the Item as the provided Binary
+func (t *Item) FromBinary(v Binary) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeBinary performs a merge with any union data inside the Item, using the provided Binary
+func (t *Item) MergeBinary(v Binary) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+func (t Item) MarshalJSON() ([]byte, error) {
+	b, err := t.union.MarshalJSON()
+	return b, err
+}
+
+func (t *Item) UnmarshalJSON(b []byte) error {
+	err := t.union.UnmarshalJSON(b)
+	return err
+}
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// PostAddDataTableUserIDEntryIDWithBody request with any body
+	PostAddDataTableUserIDEntryIDWithBody(ctx context.Context, table Table, userID UserID, entryID EntryID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostAddDataTableUserIDEntryID(ctx context.Context, table Table, userID UserID, entryID EntryID, body PostAddDataTableUserIDEntryIDJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetAdminCacheStats request
+	GetAdminCacheStats(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetAdminReencryption request
+	GetAdminReencryption(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostAdminReencryption request
+	PostAdminReencryption(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetAdminStats request
+	GetAdminStats(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostBatchUserIDWithBody request with any body
+	PostBatchUserIDWithBody(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostBatchUserID(ctx context.Context, userID UserID, body PostBatchUserIDJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteDeleteDataTableUserIDEntryID request
+	DeleteDeleteDataTableUserIDEntryID(ctx context.Context, table Table, userID UserID, entryID EntryID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetEventsUserID request
+	GetEventsUserID(ctx context.Context, userID UserID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetGetAllDataTableUserID request
+	GetGetAllDataTableUserID(ctx context.Context, table Table, userID UserID, lastSync string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetGetDataTableUserIDEntryID request
+	GetGetDataTableUserIDEntryID(ctx context.Context, table Table, userID UserID, entryID EntryID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetGetFileUserIDEntryID request
+	GetGetFileUserIDEntryID(ctx context.Context, userID UserID, entryID EntryID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetGetPasswordUsername request
+	GetGetPasswordUsername(ctx context.Context, username string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetGetUserIDUsername request
+	GetGetUserIDUsername(ctx context.Context, username string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostLoginWithBody request with any body
+	PostLoginWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostLogin(ctx context.Context, body PostLoginJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostLoginMfaWithBody request with any body
+	PostLoginMfaWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostLoginMfa(ctx context.Context, body PostLoginMfaJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostLogoutWithBody request with any body
+	PostLogoutWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostLogout(ctx context.Context, body PostLogoutJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostMfaUserIDEnrollWithBody request with any body
+	PostMfaUserIDEnrollWithBody(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostMfaUserIDEnroll(ctx context.Context, userID UserID, body PostMfaUserIDEnrollJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostMfaUserIDVerifyWithBody request with any body
+	PostMfaUserIDVerifyWithBody(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostMfaUserIDVerify(ctx context.Context, userID UserID, body PostMfaUserIDVerifyJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostRegisterWithBody request with any body
+	PostRegisterWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostRegister(ctx context.Context, body PostRegisterJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostSendFileUserIDWithBody request with any body
+	PostSendFileUserIDWithBody(ctx context.Context, userID UserID, fileName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteSessionsUserID request
+	DeleteSessionsUserID(ctx context.Context, userID UserID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetSessionsUserID request
+	GetSessionsUserID(ctx context.Context, userID UserID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteSessionsUserIDSessionID request
+	DeleteSessionsUserIDSessionID(ctx context.Context, userID UserID, sessionID string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostSrpLoginInitWithBody request with any body
+	PostSrpLoginInitWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostSrpLoginInit(ctx context.Context, body PostSrpLoginInitJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostSrpLoginVerifyWithBody request with any body
+	PostSrpLoginVerifyWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostSrpLoginVerify(ctx context.Context, body PostSrpLoginVerifyJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostSrpRegisterWithBody request with any body
+	PostSrpRegisterWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostSrpRegister(ctx context.Context, body PostSrpRegisterJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetSyncUserID request
+	GetSyncUserID(ctx context.Context, userID UserID, params *GetSyncUserIDParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostTokenRefreshWithBody request with any body
+	PostTokenRefreshWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostTokenRefresh(ctx context.Context, body PostTokenRefreshJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PutUpdateDataTableUserIDEntryIDWithBody request with any body
+	PutUpdateDataTableUserIDEntryIDWithBody(ctx context.Context, table Table, userID UserID, entryID EntryID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PutUpdateDataTableUserIDEntryID(ctx context.Context, table Table, userID UserID, entryID EntryID, body PutUpdateDataTableUserIDEntryIDJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostUploadsUserIDWithBody request with any body
+	PostUploadsUserIDWithBody(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostUploadsUserID(ctx context.Context, userID UserID, body PostUploadsUserIDJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteUploadsUserIDUploadID request
+	DeleteUploadsUserIDUploadID(ctx context.Context, userID UserID, uploadID UploadID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// HeadUploadsUserIDUploadID request
+	HeadUploadsUserIDUploadID(ctx context.Context, userID UserID, uploadID UploadID, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PatchUploadsUserIDUploadIDWithBody request with any body
+	PatchUploadsUserIDUploadIDWithBody(ctx context.Context, userID UserID, uploadID UploadID, params *PatchUploadsUserIDUploadIDParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostUploadsUserIDUploadIDFinish request
+	PostUploadsUserIDUploadIDFinish(ctx context.Context, userID UserID, uploadID UploadID, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) PostAddDataTableUserIDEntryIDWithBody(ctx context.Context, table Table, userID UserID, entryID EntryID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostAddDataTableUserIDEntryIDRequestWithBody(c.Server, table, userID, entryID, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostAddDataTableUserIDEntryID(ctx context.Context, table Table, userID UserID, entryID EntryID, body PostAddDataTableUserIDEntryIDJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostAddDataTableUserIDEntryIDRequest(c.Server, table, userID, entryID, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetAdminCacheStats(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetAdminCacheStatsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetAdminReencryption(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetAdminReencryptionRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostAdminReencryption(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostAdminReencryptionRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetAdminStats(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetAdminStatsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostBatchUserIDWithBody(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostBatchUserIDRequestWithBody(c.Server, userID, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostBatchUserID(ctx context.Context, userID UserID, body PostBatchUserIDJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostBatchUserIDRequest(c.Server, userID, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DeleteDeleteDataTableUserIDEntryID(ctx context.Context, table Table, userID UserID, entryID EntryID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteDeleteDataTableUserIDEntryIDRequest(c.Server, table, userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetEventsUserID(ctx context.Context, userID UserID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetEventsUserIDRequest(c.Server, userID)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetGetAllDataTableUserID(ctx context.Context, table Table, userID UserID, lastSync string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetGetAllDataTableUserIDRequest(c.Server, table, userID, lastSync)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetGetDataTableUserIDEntryID(ctx context.Context, table Table, userID UserID, entryID EntryID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetGetDataTableUserIDEntryIDRequest(c.Server, table, userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetGetFileUserIDEntryID(ctx context.Context, userID UserID, entryID EntryID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetGetFileUserIDEntryIDRequest(c.Server, userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetGetPasswordUsername(ctx context.Context, username string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetGetPasswordUsernameRequest(c.Server, username)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetGetUserIDUsername(ctx context.Context, username string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetGetUserIDUsernameRequest(c.Server, username)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostLoginWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostLoginRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostLogin(ctx context.Context, body PostLoginJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostLoginRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostLoginMfaWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostLoginMfaRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostLoginMfa(ctx context.Context, body PostLoginMfaJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostLoginMfaRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostLogoutWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostLogoutRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostLogout(ctx context.Context, body PostLogoutJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostLogoutRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostMfaUserIDEnrollWithBody(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostMfaUserIDEnrollRequestWithBody(c.Server, userID, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostMfaUserIDEnroll(ctx context.Context, userID UserID, body PostMfaUserIDEnrollJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostMfaUserIDEnrollRequest(c.Server, userID, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostMfaUserIDVerifyWithBody(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostMfaUserIDVerifyRequestWithBody(c.Server, userID, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostMfaUserIDVerify(ctx context.Context, userID UserID, body PostMfaUserIDVerifyJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostMfaUserIDVerifyRequest(c.Server, userID, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostRegisterWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostRegisterRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostRegister(ctx context.Context, body PostRegisterJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostRegisterRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostSendFileUserIDWithBody(ctx context.Context, userID UserID, fileName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostSendFileUserIDRequestWithBody(c.Server, userID, fileName, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DeleteSessionsUserID(ctx context.Context, userID UserID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteSessionsUserIDRequest(c.Server, userID)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetSessionsUserID(ctx context.Context, userID UserID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetSessionsUserIDRequest(c.Server, userID)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DeleteSessionsUserIDSessionID(ctx context.Context, userID UserID, sessionID string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteSessionsUserIDSessionIDRequest(c.Server, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostSrpLoginInitWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostSrpLoginInitRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostSrpLoginInit(ctx context.Context, body PostSrpLoginInitJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostSrpLoginInitRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostSrpLoginVerifyWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostSrpLoginVerifyRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostSrpLoginVerify(ctx context.Context, body PostSrpLoginVerifyJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostSrpLoginVerifyRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostSrpRegisterWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostSrpRegisterRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostSrpRegister(ctx context.Context, body PostSrpRegisterJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostSrpRegisterRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetSyncUserID(ctx context.Context, userID UserID, params *GetSyncUserIDParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetSyncUserIDRequest(c.Server, userID, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTokenRefreshWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTokenRefreshRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostTokenRefresh(ctx context.Context, body PostTokenRefreshJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostTokenRefreshRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PutUpdateDataTableUserIDEntryIDWithBody(ctx context.Context, table Table, userID UserID, entryID EntryID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPutUpdateDataTableUserIDEntryIDRequestWithBody(c.Server, table, userID, entryID, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PutUpdateDataTableUserIDEntryID(ctx context.Context, table Table, userID UserID, entryID EntryID, body PutUpdateDataTableUserIDEntryIDJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPutUpdateDataTableUserIDEntryIDRequest(c.Server, table, userID, entryID, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostUploadsUserIDWithBody(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostUploadsUserIDRequestWithBody(c.Server, userID, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostUploadsUserID(ctx context.Context, userID UserID, body PostUploadsUserIDJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostUploadsUserIDRequest(c.Server, userID, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DeleteUploadsUserIDUploadID(ctx context.Context, userID UserID, uploadID UploadID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteUploadsUserIDUploadIDRequest(c.Server, userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) HeadUploadsUserIDUploadID(ctx context.Context, userID UserID, uploadID UploadID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewHeadUploadsUserIDUploadIDRequest(c.Server, userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PatchUploadsUserIDUploadIDWithBody(ctx context.Context, userID UserID, uploadID UploadID, params *PatchUploadsUserIDUploadIDParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPatchUploadsUserIDUploadIDRequestWithBody(c.Server, userID, uploadID, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostUploadsUserIDUploadIDFinish(ctx context.Context, userID UserID, uploadID UploadID, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostUploadsUserIDUploadIDFinishRequest(c.Server, userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewPostAddDataTableUserIDEntryIDRequest calls the generic PostAddDataTableUserIDEntryID builder with application/json body
+func NewPostAddDataTableUserIDEntryIDRequest(server string, table Table, userID UserID, entryID EntryID, body PostAddDataTableUserIDEntryIDJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostAddDataTableUserIDEntryIDRequestWithBody(server, table, userID, entryID, "application/json", bodyReader)
+}
+
+// NewPostAddDataTableUserIDEntryIDRequestWithBody generates requests for PostAddDataTableUserIDEntryID with any type of body
+func NewPostAddDataTableUserIDEntryIDRequestWithBody(server string, table Table, userID UserID, entryID EntryID, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "table", runtime.ParamLocationPath, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam2 string
+
+	pathParam2, err = runtime.StyleParamWithLocation("simple", false, "entryID", runtime.ParamLocationPath, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/addData/%s/%s/%s", pathParam0, pathParam1, pathParam2)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetAdminCacheStatsRequest generates requests for GetAdminCacheStats
+func NewGetAdminCacheStatsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/admin/cache-stats")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetAdminReencryptionRequest generates requests for GetAdminReencryption
+func NewGetAdminReencryptionRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/admin/reencryption")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostAdminReencryptionRequest generates requests for PostAdminReencryption
+func NewPostAdminReencryptionRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/admin/reencryption")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetAdminStatsRequest generates requests for GetAdminStats
+func NewGetAdminStatsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/admin/stats")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostBatchUserIDRequest calls the generic PostBatchUserID builder with application/json body
+func NewPostBatchUserIDRequest(server string, userID UserID, body PostBatchUserIDJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostBatchUserIDRequestWithBody(server, userID, "application/json", bodyReader)
+}
+
+// NewPostBatchUserIDRequestWithBody generates requests for PostBatchUserID with any type of body
+func NewPostBatchUserIDRequestWithBody(server string, userID UserID, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/batch/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewDeleteDeleteDataTableUserIDEntryIDRequest generates requests for DeleteDeleteDataTableUserIDEntryID
+func NewDeleteDeleteDataTableUserIDEntryIDRequest(server string, table Table, userID UserID, entryID EntryID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "table", runtime.ParamLocationPath, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam2 string
+
+	pathParam2, err = runtime.StyleParamWithLocation("simple", false, "entryID", runtime.ParamLocationPath, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/deleteData/%s/%s/%s", pathParam0, pathParam1, pathParam2)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetEventsUserIDRequest generates requests for GetEventsUserID
+func NewGetEventsUserIDRequest(server string, userID UserID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/events/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetGetAllDataTableUserIDRequest generates requests for GetGetAllDataTableUserID
+func NewGetGetAllDataTableUserIDRequest(server string, table Table, userID UserID, lastSync string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "table", runtime.ParamLocationPath, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam2 string
+
+	pathParam2, err = runtime.StyleParamWithLocation("simple", false, "lastSync", runtime.ParamLocationPath, lastSync)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/getAllData/%s/%s/%s", pathParam0, pathParam1, pathParam2)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetGetDataTableUserIDEntryIDRequest generates requests for GetGetDataTableUserIDEntryID
+func NewGetGetDataTableUserIDEntryIDRequest(server string, table Table, userID UserID, entryID EntryID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "table", runtime.ParamLocationPath, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam2 string
+
+	pathParam2, err = runtime.StyleParamWithLocation("simple", false, "entryID", runtime.ParamLocationPath, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/getData/%s/%s/%s", pathParam0, pathParam1, pathParam2)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetGetFileUserIDEntryIDRequest generates requests for GetGetFileUserIDEntryID
+func NewGetGetFileUserIDEntryIDRequest(server string, userID UserID, entryID EntryID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "entryID", runtime.ParamLocationPath, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/getFile/%s/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetGetPasswordUsernameRequest generates requests for GetGetPasswordUsername
+func NewGetGetPasswordUsernameRequest(server string, username string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "username", runtime.ParamLocationPath, username)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/getPassword/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetGetUserIDUsernameRequest generates requests for GetGetUserIDUsername
+func NewGetGetUserIDUsernameRequest(server string, username string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "username", runtime.ParamLocationPath, username)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/getUserID/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostLoginRequest calls the generic PostLogin builder with application/json body
+func NewPostLoginRequest(server string, body PostLoginJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostLoginRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostLoginRequestWithBody generates requests for PostLogin with any type of body
+func NewPostLoginRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/login")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostLoginMfaRequest calls the generic PostLoginMfa builder with application/json body
+func NewPostLoginMfaRequest(server string, body PostLoginMfaJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostLoginMfaRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostLoginMfaRequestWithBody generates requests for PostLoginMfa with any type of body
+func NewPostLoginMfaRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/login/mfa")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostLogoutRequest calls the generic PostLogout builder with application/json body
+func NewPostLogoutRequest(server string, body PostLogoutJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostLogoutRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostLogoutRequestWithBody generates requests for PostLogout with any type of body
+func NewPostLogoutRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/logout")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostMfaUserIDEnrollRequest calls the generic PostMfaUserIDEnroll builder with application/json body
+func NewPostMfaUserIDEnrollRequest(server string, userID UserID, body PostMfaUserIDEnrollJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostMfaUserIDEnrollRequestWithBody(server, userID, "application/json", bodyReader)
+}
+
+// NewPostMfaUserIDEnrollRequestWithBody generates requests for PostMfaUserIDEnroll with any type of body
+func NewPostMfaUserIDEnrollRequestWithBody(server string, userID UserID, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/mfa/%s/enroll", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostMfaUserIDVerifyRequest calls the generic PostMfaUserIDVerify builder with application/json body
+func NewPostMfaUserIDVerifyRequest(server string, userID UserID, body PostMfaUserIDVerifyJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostMfaUserIDVerifyRequestWithBody(server, userID, "application/json", bodyReader)
+}
+
+// NewPostMfaUserIDVerifyRequestWithBody generates requests for PostMfaUserIDVerify with any type of body
+func NewPostMfaUserIDVerifyRequestWithBody(server string, userID UserID, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/mfa/%s/verify", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostRegisterRequest calls the generic PostRegister builder with application/json body
+func NewPostRegisterRequest(server string, body PostRegisterJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostRegisterRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostRegisterRequestWithBody generates requests for PostRegister with any type of body
+func NewPostRegisterRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/register")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostSendFileUserIDRequestWithBody generates requests for PostSendFileUserID with any type of body
+func NewPostSendFileUserIDRequestWithBody(server string, userID UserID, fileName string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "fileName", runtime.ParamLocationPath, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/sendFile/%s/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewDeleteSessionsUserIDRequest generates requests for DeleteSessionsUserID
+func NewDeleteSessionsUserIDRequest(server string, userID UserID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/sessions/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetSessionsUserIDRequest generates requests for GetSessionsUserID
+func NewGetSessionsUserIDRequest(server string, userID UserID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/sessions/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewDeleteSessionsUserIDSessionIDRequest generates requests for DeleteSessionsUserIDSessionID
+func NewDeleteSessionsUserIDSessionIDRequest(server string, userID UserID, sessionID string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "sessionID", runtime.ParamLocationPath, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/sessions/%s/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostSrpLoginInitRequest calls the generic PostSrpLoginInit builder with application/json body
+func NewPostSrpLoginInitRequest(server string, body PostSrpLoginInitJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostSrpLoginInitRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostSrpLoginInitRequestWithBody generates requests for PostSrpLoginInit with any type of body
+func NewPostSrpLoginInitRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/srp/login/init")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostSrpLoginVerifyRequest calls the generic PostSrpLoginVerify builder with application/json body
+func NewPostSrpLoginVerifyRequest(server string, body PostSrpLoginVerifyJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostSrpLoginVerifyRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostSrpLoginVerifyRequestWithBody generates requests for PostSrpLoginVerify with any type of body
+func NewPostSrpLoginVerifyRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/srp/login/verify")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostSrpRegisterRequest calls the generic PostSrpRegister builder with application/json body
+func NewPostSrpRegisterRequest(server string, body PostSrpRegisterJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostSrpRegisterRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostSrpRegisterRequestWithBody generates requests for PostSrpRegister with any type of body
+func NewPostSrpRegisterRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/srp/register")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetSyncUserIDRequest generates requests for GetSyncUserID
+func NewGetSyncUserIDRequest(server string, userID UserID, params *GetSyncUserIDParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/sync/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Cursor != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "cursor", runtime.ParamLocationQuery, *params.Cursor); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostTokenRefreshRequest calls the generic PostTokenRefresh builder with application/json body
+func NewPostTokenRefreshRequest(server string, body PostTokenRefreshJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostTokenRefreshRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostTokenRefreshRequestWithBody generates requests for PostTokenRefresh with any type of body
+func NewPostTokenRefreshRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/token/refresh")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPutUpdateDataTableUserIDEntryIDRequest calls the generic PutUpdateDataTableUserIDEntryID builder with application/json body
+func NewPutUpdateDataTableUserIDEntryIDRequest(server string, table Table, userID UserID, entryID EntryID, body PutUpdateDataTableUserIDEntryIDJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPutUpdateDataTableUserIDEntryIDRequestWithBody(server, table, userID, entryID, "application/json", bodyReader)
+}
+
+// NewPutUpdateDataTableUserIDEntryIDRequestWithBody generates requests for PutUpdateDataTableUserIDEntryID with any type of body
+func NewPutUpdateDataTableUserIDEntryIDRequestWithBody(server string, table Table, userID UserID, entryID EntryID, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "table", runtime.ParamLocationPath, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam2 string
+
+	pathParam2, err = runtime.StyleParamWithLocation("simple", false, "entryID", runtime.ParamLocationPath, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/updateData/%s/%s/%s", pathParam0, pathParam1, pathParam2)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PUT", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostUploadsUserIDRequest calls the generic PostUploadsUserID builder with application/json body
+func NewPostUploadsUserIDRequest(server string, userID UserID, body PostUploadsUserIDJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostUploadsUserIDRequestWithBody(server, userID, "application/json", bodyReader)
+}
+
+// NewPostUploadsUserIDRequestWithBody generates requests for PostUploadsUserID with any type of body
+func NewPostUploadsUserIDRequestWithBody(server string, userID UserID, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/uploads/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewDeleteUploadsUserIDUploadIDRequest generates requests for DeleteUploadsUserIDUploadID
+func NewDeleteUploadsUserIDUploadIDRequest(server string, userID UserID, uploadID UploadID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "uploadID", runtime.ParamLocationPath, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/uploads/%s/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewHeadUploadsUserIDUploadIDRequest generates requests for HeadUploadsUserIDUploadID
+func NewHeadUploadsUserIDUploadIDRequest(server string, userID UserID, uploadID UploadID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "uploadID", runtime.ParamLocationPath, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/uploads/%s/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("HEAD", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPatchUploadsUserIDUploadIDRequestWithBody generates requests for PatchUploadsUserIDUploadID with any type of body
+func NewPatchUploadsUserIDUploadIDRequestWithBody(server string, userID UserID, uploadID UploadID, params *PatchUploadsUserIDUploadIDParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "uploadID", runtime.ParamLocationPath, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/uploads/%s/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Upload-Offset", runtime.ParamLocationHeader, params.UploadOffset)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Upload-Offset", headerParam0)
+
+	}
+
+	return req, nil
+}
+
+// NewPostUploadsUserIDUploadIDFinishRequest generates requests for PostUploadsUserIDUploadIDFinish
+func NewPostUploadsUserIDUploadIDFinishRequest(server string, userID UserID, uploadID UploadID) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "userID", runtime.ParamLocationPath, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "uploadID", runtime.ParamLocationPath, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/uploads/%s/%s/finish", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// PostAddDataTableUserIDEntryIDWithBodyWithResponse request with any body
+	PostAddDataTableUserIDEntryIDWithBodyWithResponse(ctx context.Context, table Table, userID UserID, entryID EntryID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostAddDataTableUserIDEntryIDResponse, error)
+
+	PostAddDataTableUserIDEntryIDWithResponse(ctx context.Context, table Table, userID UserID, entryID EntryID, body PostAddDataTableUserIDEntryIDJSONRequestBody, reqEditors ...RequestEditorFn) (*PostAddDataTableUserIDEntryIDResponse, error)
+
+	// GetAdminCacheStatsWithResponse request
+	GetAdminCacheStatsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAdminCacheStatsResponse, error)
+
+	// GetAdminReencryptionWithResponse request
+	GetAdminReencryptionWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAdminReencryptionResponse, error)
+
+	// PostAdminReencryptionWithResponse request
+	PostAdminReencryptionWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*PostAdminReencryptionResponse, error)
+
+	// GetAdminStatsWithResponse request
+	GetAdminStatsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAdminStatsResponse, error)
+
+	// PostBatchUserIDWithBodyWithResponse request with any body
+	PostBatchUserIDWithBodyWithResponse(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostBatchUserIDResponse, error)
+
+	PostBatchUserIDWithResponse(ctx context.Context, userID UserID, body PostBatchUserIDJSONRequestBody, reqEditors ...RequestEditorFn) (*PostBatchUserIDResponse, error)
+
+	// DeleteDeleteDataTableUserIDEntryIDWithResponse request
+	DeleteDeleteDataTableUserIDEntryIDWithResponse(ctx context.Context, table Table, userID UserID, entryID EntryID, reqEditors ...RequestEditorFn) (*DeleteDeleteDataTableUserIDEntryIDResponse, error)
+
+	// GetEventsUserIDWithResponse request
+	GetEventsUserIDWithResponse(ctx context.Context, userID UserID, reqEditors ...RequestEditorFn) (*GetEventsUserIDResponse, error)
+
+	// GetGetAllDataTableUserIDWithResponse request
+	GetGetAllDataTableUserIDWithResponse(ctx context.Context, table Table, userID UserID, lastSync string, reqEditors ...RequestEditorFn) (*GetGetAllDataTableUserIDResponse, error)
+
+	// GetGetDataTableUserIDEntryIDWithResponse request
+	GetGetDataTableUserIDEntryIDWithResponse(ctx context.Context, table Table, userID UserID, entryID EntryID, reqEditors ...RequestEditorFn) (*GetGetDataTableUserIDEntryIDResponse, error)
+
+	// GetGetFileUserIDEntryIDWithResponse request
+	GetGetFileUserIDEntryIDWithResponse(ctx context.Context, userID UserID, entryID EntryID, reqEditors ...RequestEditorFn) (*GetGetFileUserIDEntryIDResponse, error)
+
+	// GetGetPasswordUsernameWithResponse request
+	GetGetPasswordUsernameWithResponse(ctx context.Context, username string, reqEditors ...RequestEditorFn) (*GetGetPasswordUsernameResponse, error)
+
+	// GetGetUserIDUsernameWithResponse request
+	GetGetUserIDUsernameWithResponse(ctx context.Context, username string, reqEditors ...RequestEditorFn) (*GetGetUserIDUsernameResponse, error)
+
+	// PostLoginWithBodyWithResponse request with any body
+	PostLoginWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostLoginResponse, error)
+
+	PostLoginWithResponse(ctx context.Context, body PostLoginJSONRequestBody, reqEditors ...RequestEditorFn) (*PostLoginResponse, error)
+
+	// PostLoginMfaWithBodyWithResponse request with any body
+	PostLoginMfaWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostLoginMfaResponse, error)
+
+	PostLoginMfaWithResponse(ctx context.Context, body PostLoginMfaJSONRequestBody, reqEditors ...RequestEditorFn) (*PostLoginMfaResponse, error)
+
+	// PostLogoutWithBodyWithResponse request with any body
+	PostLogoutWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostLogoutResponse, error)
+
+	PostLogoutWithResponse(ctx context.Context, body PostLogoutJSONRequestBody, reqEditors ...RequestEditorFn) (*PostLogoutResponse, error)
+
+	// PostMfaUserIDEnrollWithBodyWithResponse request with any body
+	PostMfaUserIDEnrollWithBodyWithResponse(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostMfaUserIDEnrollResponse, error)
+
+	PostMfaUserIDEnrollWithResponse(ctx context.Context, userID UserID, body PostMfaUserIDEnrollJSONRequestBody, reqEditors ...RequestEditorFn) (*PostMfaUserIDEnrollResponse, error)
+
+	// PostMfaUserIDVerifyWithBodyWithResponse request with any body
+	PostMfaUserIDVerifyWithBodyWithResponse(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostMfaUserIDVerifyResponse, error)
+
+	PostMfaUserIDVerifyWithResponse(ctx context.Context, userID UserID, body PostMfaUserIDVerifyJSONRequestBody, reqEditors ...RequestEditorFn) (*PostMfaUserIDVerifyResponse, error)
+
+	// PostRegisterWithBodyWithResponse request with any body
+	PostRegisterWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostRegisterResponse, error)
+
+	PostRegisterWithResponse(ctx context.Context, body PostRegisterJSONRequestBody, reqEditors ...RequestEditorFn) (*PostRegisterResponse, error)
+
+	// PostSendFileUserIDWithBodyWithResponse request with any body
+	PostSendFileUserIDWithBodyWithResponse(ctx context.Context, userID UserID, fileName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostSendFileUserIDResponse, error)
+
+	// DeleteSessionsUserIDWithResponse request
+	DeleteSessionsUserIDWithResponse(ctx context.Context, userID UserID, reqEditors ...RequestEditorFn) (*DeleteSessionsUserIDResponse, error)
+
+	// GetSessionsUserIDWithResponse request
+	GetSessionsUserIDWithResponse(ctx context.Context, userID UserID, reqEditors ...RequestEditorFn) (*GetSessionsUserIDResponse, error)
+
+	// DeleteSessionsUserIDSessionIDWithResponse request
+	DeleteSessionsUserIDSessionIDWithResponse(ctx context.Context, userID UserID, sessionID string, reqEditors ...RequestEditorFn) (*DeleteSessionsUserIDSessionIDResponse, error)
+
+	// PostSrpLoginInitWithBodyWithResponse request with any body
+	PostSrpLoginInitWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostSrpLoginInitResponse, error)
+
+	PostSrpLoginInitWithResponse(ctx context.Context, body PostSrpLoginInitJSONRequestBody, reqEditors ...RequestEditorFn) (*PostSrpLoginInitResponse, error)
+
+	// PostSrpLoginVerifyWithBodyWithResponse request with any body
+	PostSrpLoginVerifyWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostSrpLoginVerifyResponse, error)
+
+	PostSrpLoginVerifyWithResponse(ctx context.Context, body PostSrpLoginVerifyJSONRequestBody, reqEditors ...RequestEditorFn) (*PostSrpLoginVerifyResponse, error)
+
+	// PostSrpRegisterWithBodyWithResponse request with any body
+	PostSrpRegisterWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostSrpRegisterResponse, error)
+
+	PostSrpRegisterWithResponse(ctx context.Context, body PostSrpRegisterJSONRequestBody, reqEditors ...RequestEditorFn) (*PostSrpRegisterResponse, error)
+
+	// GetSyncUserIDWithResponse request
+	GetSyncUserIDWithResponse(ctx context.Context, userID UserID, params *GetSyncUserIDParams, reqEditors ...RequestEditorFn) (*GetSyncUserIDResponse, error)
+
+	// PostTokenRefreshWithBodyWithResponse request with any body
+	PostTokenRefreshWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTokenRefreshResponse, error)
+
+	PostTokenRefreshWithResponse(ctx context.Context, body PostTokenRefreshJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTokenRefreshResponse, error)
+
+	// PutUpdateDataTableUserIDEntryIDWithBodyWithResponse request with any body
+	PutUpdateDataTableUserIDEntryIDWithBodyWithResponse(ctx context.Context, table Table, userID UserID, entryID EntryID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutUpdateDataTableUserIDEntryIDResponse, error)
+
+	PutUpdateDataTableUserIDEntryIDWithResponse(ctx context.Context, table Table, userID UserID, entryID EntryID, body PutUpdateDataTableUserIDEntryIDJSONRequestBody, reqEditors ...RequestEditorFn) (*PutUpdateDataTableUserIDEntryIDResponse, error)
+
+	// PostUploadsUserIDWithBodyWithResponse request with any body
+	PostUploadsUserIDWithBodyWithResponse(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostUploadsUserIDResponse, error)
+
+	PostUploadsUserIDWithResponse(ctx context.Context, userID UserID, body PostUploadsUserIDJSONRequestBody, reqEditors ...RequestEditorFn) (*PostUploadsUserIDResponse, error)
+
+	// DeleteUploadsUserIDUploadIDWithResponse request
+	DeleteUploadsUserIDUploadIDWithResponse(ctx context.Context, userID UserID, uploadID UploadID, reqEditors ...RequestEditorFn) (*DeleteUploadsUserIDUploadIDResponse, error)
+
+	// HeadUploadsUserIDUploadIDWithResponse request
+	HeadUploadsUserIDUploadIDWithResponse(ctx context.Context, userID UserID, uploadID UploadID, reqEditors ...RequestEditorFn) (*HeadUploadsUserIDUploadIDResponse, error)
+
+	// PatchUploadsUserIDUploadIDWithBodyWithResponse request with any body
+	PatchUploadsUserIDUploadIDWithBodyWithResponse(ctx context.Context, userID UserID, uploadID UploadID, params *PatchUploadsUserIDUploadIDParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PatchUploadsUserIDUploadIDResponse, error)
+
+	// PostUploadsUserIDUploadIDFinishWithResponse request
+	PostUploadsUserIDUploadIDFinishWithResponse(ctx context.Context, userID UserID, uploadID UploadID, reqEditors ...RequestEditorFn) (*PostUploadsUserIDUploadIDFinishResponse, error)
+}
+
+type PostAddDataTableUserIDEntryIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r PostAddDataTableUserIDEntryIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostAddDataTableUserIDEntryIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetAdminCacheStatsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *CacheStats
+}
+
+// Status returns HTTPResponse.Status
+func (r GetAdminCacheStatsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetAdminCacheStatsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetAdminReencryptionResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ReencryptionProgress
+}
+
+// Status returns HTTPResponse.Status
+func (r GetAdminReencryptionResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetAdminReencryptionResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostAdminReencryptionResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *ReencryptionProgress
+}
+
+// Status returns HTTPResponse.Status
+func (r PostAdminReencryptionResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostAdminReencryptionResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetAdminStatsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *BlobStats
+}
+
+// Status returns HTTPResponse.Status
+func (r GetAdminStatsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetAdminStatsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostBatchUserIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSONDefault  *BatchResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PostBatchUserIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostBatchUserIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteDeleteDataTableUserIDEntryIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteDeleteDataTableUserIDEntryIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteDeleteDataTableUserIDEntryIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetEventsUserIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r GetEventsUserIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetEventsUserIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetGetAllDataTableUserIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]Record
+}
+
+// Status returns HTTPResponse.Status
+func (r GetGetAllDataTableUserIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetGetAllDataTableUserIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetGetDataTableUserIDEntryIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Record
+}
+
+// Status returns HTTPResponse.Status
+func (r GetGetDataTableUserIDEntryIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetGetDataTableUserIDEntryIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetGetFileUserIDEntryIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r GetGetFileUserIDEntryIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetGetFileUserIDEntryIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetGetPasswordUsernameResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r GetGetPasswordUsernameResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetGetPasswordUsernameResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetGetUserIDUsernameResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *int
+}
+
+// Status returns HTTPResponse.Status
+func (r GetGetUserIDUsernameResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetGetUserIDUsernameResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostLoginResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Login
+}
+
+// Status returns HTTPResponse.Status
+func (r PostLoginResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostLoginResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostLoginMfaResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Login
+}
+
+// Status returns HTTPResponse.Status
+func (r PostLoginMfaResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostLoginMfaResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostLogoutResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r PostLogoutResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostLogoutResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostMfaUserIDEnrollResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *MFAEnrollment
+}
+
+// Status returns HTTPResponse.Status
+func (r PostMfaUserIDEnrollResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostMfaUserIDEnrollResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostMfaUserIDVerifyResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *RecoveryCodes
+}
+
+// Status returns HTTPResponse.Status
+func (r PostMfaUserIDVerifyResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostMfaUserIDVerifyResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostRegisterResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r PostRegisterResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostRegisterResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostSendFileUserIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *FileContentResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PostSendFileUserIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostSendFileUserIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteSessionsUserIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteSessionsUserIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteSessionsUserIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetSessionsUserIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]Session
+}
+
+// Status returns HTTPResponse.Status
+func (r GetSessionsUserIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetSessionsUserIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteSessionsUserIDSessionIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteSessionsUserIDSessionIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteSessionsUserIDSessionIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostSrpLoginInitResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SRPChallenge
+}
+
+// Status returns HTTPResponse.Status
+func (r PostSrpLoginInitResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostSrpLoginInitResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostSrpLoginVerifyResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Login
+}
+
+// Status returns HTTPResponse.Status
+func (r PostSrpLoginVerifyResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostSrpLoginVerifyResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostSrpRegisterResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r PostSrpRegisterResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostSrpRegisterResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetSyncUserIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *SyncResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r GetSyncUserIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetSyncUserIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostTokenRefreshResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Login
+}
+
+// Status returns HTTPResponse.Status
+func (r PostTokenRefreshResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostTokenRefreshResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PutUpdateDataTableUserIDEntryIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Version
+	JSON409      *Record
+}
+
+// Status returns HTTPResponse.Status
+func (r PutUpdateDataTableUserIDEntryIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PutUpdateDataTableUserIDEntryIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostUploadsUserIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *UploadSession
+}
+
+// Status returns HTTPResponse.Status
+func (r PostUploadsUserIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostUploadsUserIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteUploadsUserIDUploadIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteUploadsUserIDUploadIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteUploadsUserIDUploadIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type HeadUploadsUserIDUploadIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r HeadUploadsUserIDUploadIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r HeadUploadsUserIDUploadIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PatchUploadsUserIDUploadIDResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r PatchUploadsUserIDUploadIDResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PatchUploadsUserIDUploadIDResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostUploadsUserIDUploadIDFinishResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *FileContentResponse
+}
+
+// Status returns HTTPResponse.Status
+func (r PostUploadsUserIDUploadIDFinishResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostUploadsUserIDUploadIDFinishResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// PostAddDataTableUserIDEntryIDWithBodyWithResponse request with arbitrary body returning *PostAddDataTableUserIDEntryIDResponse
+func (c *ClientWithResponses) PostAddDataTableUserIDEntryIDWithBodyWithResponse(ctx context.Context, table Table, userID UserID, entryID EntryID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostAddDataTableUserIDEntryIDResponse, error) {
+	rsp, err := c.PostAddDataTableUserIDEntryIDWithBody(ctx, table, userID, entryID, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostAddDataTableUserIDEntryIDResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostAddDataTableUserIDEntryIDWithResponse(ctx context.Context, table Table, userID UserID, entryID EntryID, body PostAddDataTableUserIDEntryIDJSONRequestBody, reqEditors ...RequestEditorFn) (*PostAddDataTableUserIDEntryIDResponse, error) {
+	rsp, err := c.PostAddDataTableUserIDEntryID(ctx, table, userID, entryID, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostAddDataTableUserIDEntryIDResponse(rsp)
+}
+
+// GetAdminCacheStatsWithResponse request returning *GetAdminCacheStatsResponse
+func (c *ClientWithResponses) GetAdminCacheStatsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAdminCacheStatsResponse, error) {
+	rsp, err := c.GetAdminCacheStats(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAdminCacheStatsResponse(rsp)
+}
+
+// GetAdminReencryptionWithResponse request returning *GetAdminReencryptionResponse
+func (c *ClientWithResponses) GetAdminReencryptionWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAdminReencryptionResponse, error) {
+	rsp, err := c.GetAdminReencryption(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAdminReencryptionResponse(rsp)
+}
+
+// PostAdminReencryptionWithResponse request returning *PostAdminReencryptionResponse
+func (c *ClientWithResponses) PostAdminReencryptionWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*PostAdminReencryptionResponse, error) {
+	rsp, err := c.PostAdminReencryption(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostAdminReencryptionResponse(rsp)
+}
+
+// GetAdminStatsWithResponse request returning *GetAdminStatsResponse
+func (c *ClientWithResponses) GetAdminStatsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetAdminStatsResponse, error) {
+	rsp, err := c.GetAdminStats(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetAdminStatsResponse(rsp)
+}
+
+// PostBatchUserIDWithBodyWithResponse request with arbitrary body returning *PostBatchUserIDResponse
+func (c *ClientWithResponses) PostBatchUserIDWithBodyWithResponse(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostBatchUserIDResponse, error) {
+	rsp, err := c.PostBatchUserIDWithBody(ctx, userID, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostBatchUserIDResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostBatchUserIDWithResponse(ctx context.Context, userID UserID, body PostBatchUserIDJSONRequestBody, reqEditors ...RequestEditorFn) (*PostBatchUserIDResponse, error) {
+	rsp, err := c.PostBatchUserID(ctx, userID, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostBatchUserIDResponse(rsp)
+}
+
+// DeleteDeleteDataTableUserIDEntryIDWithResponse request returning *DeleteDeleteDataTableUserIDEntryIDResponse
+func (c *ClientWithResponses) DeleteDeleteDataTableUserIDEntryIDWithResponse(ctx context.Context, table Table, userID UserID, entryID EntryID, reqEditors ...RequestEditorFn) (*DeleteDeleteDataTableUserIDEntryIDResponse, error) {
+	rsp, err := c.DeleteDeleteDataTableUserIDEntryID(ctx, table, userID, entryID, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteDeleteDataTableUserIDEntryIDResponse(rsp)
+}
+
+// GetEventsUserIDWithResponse request returning *GetEventsUserIDResponse
+func (c *ClientWithResponses) GetEventsUserIDWithResponse(ctx context.Context, userID UserID, reqEditors ...RequestEditorFn) (*GetEventsUserIDResponse, error) {
+	rsp, err := c.GetEventsUserID(ctx, userID, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetEventsUserIDResponse(rsp)
+}
+
+// GetGetAllDataTableUserIDWithResponse request returning *GetGetAllDataTableUserIDResponse
+func (c *ClientWithResponses) GetGetAllDataTableUserIDWithResponse(ctx context.Context, table Table, userID UserID, lastSync string, reqEditors ...RequestEditorFn) (*GetGetAllDataTableUserIDResponse, error) {
+	rsp, err := c.GetGetAllDataTableUserID(ctx, table, userID, lastSync, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetGetAllDataTableUserIDResponse(rsp)
+}
+
+// GetGetDataTableUserIDEntryIDWithResponse request returning *GetGetDataTableUserIDEntryIDResponse
+func (c *ClientWithResponses) GetGetDataTableUserIDEntryIDWithResponse(ctx context.Context, table Table, userID UserID, entryID EntryID, reqEditors ...RequestEditorFn) (*GetGetDataTableUserIDEntryIDResponse, error) {
+	rsp, err := c.GetGetDataTableUserIDEntryID(ctx, table, userID, entryID, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetGetDataTableUserIDEntryIDResponse(rsp)
+}
+
+// GetGetFileUserIDEntryIDWithResponse request returning *GetGetFileUserIDEntryIDResponse
+func (c *ClientWithResponses) GetGetFileUserIDEntryIDWithResponse(ctx context.Context, userID UserID, entryID EntryID, reqEditors ...RequestEditorFn) (*GetGetFileUserIDEntryIDResponse, error) {
+	rsp, err := c.GetGetFileUserIDEntryID(ctx, userID, entryID, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetGetFileUserIDEntryIDResponse(rsp)
+}
+
+// GetGetPasswordUsernameWithResponse request returning *GetGetPasswordUsernameResponse
+func (c *ClientWithResponses) GetGetPasswordUsernameWithResponse(ctx context.Context, username string, reqEditors ...RequestEditorFn) (*GetGetPasswordUsernameResponse, error) {
+	rsp, err := c.GetGetPasswordUsername(ctx, username, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetGetPasswordUsernameResponse(rsp)
+}
+
+// GetGetUserIDUsernameWithResponse request returning *GetGetUserIDUsernameResponse
+func (c *ClientWithResponses) GetGetUserIDUsernameWithResponse(ctx context.Context, username string, reqEditors ...RequestEditorFn) (*GetGetUserIDUsernameResponse, error) {
+	rsp, err := c.GetGetUserIDUsername(ctx, username, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetGetUserIDUsernameResponse(rsp)
+}
+
+// PostLoginWithBodyWithResponse request with arbitrary body returning *PostLoginResponse
+func (c *ClientWithResponses) PostLoginWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostLoginResponse, error) {
+	rsp, err := c.PostLoginWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostLoginResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostLoginWithResponse(ctx context.Context, body PostLoginJSONRequestBody, reqEditors ...RequestEditorFn) (*PostLoginResponse, error) {
+	rsp, err := c.PostLogin(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostLoginResponse(rsp)
+}
+
+// PostLoginMfaWithBodyWithResponse request with arbitrary body returning *PostLoginMfaResponse
+func (c *ClientWithResponses) PostLoginMfaWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostLoginMfaResponse, error) {
+	rsp, err := c.PostLoginMfaWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostLoginMfaResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostLoginMfaWithResponse(ctx context.Context, body PostLoginMfaJSONRequestBody, reqEditors ...RequestEditorFn) (*PostLoginMfaResponse, error) {
+	rsp, err := c.PostLoginMfa(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostLoginMfaResponse(rsp)
+}
+
+// PostLogoutWithBodyWithResponse request with arbitrary body returning *PostLogoutResponse
+func (c *ClientWithResponses) PostLogoutWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostLogoutResponse, error) {
+	rsp, err := c.PostLogoutWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostLogoutResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostLogoutWithResponse(ctx context.Context, body PostLogoutJSONRequestBody, reqEditors ...RequestEditorFn) (*PostLogoutResponse, error) {
+	rsp, err := c.PostLogout(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostLogoutResponse(rsp)
+}
+
+// PostMfaUserIDEnrollWithBodyWithResponse request with arbitrary body returning *PostMfaUserIDEnrollResponse
+func (c *ClientWithResponses) PostMfaUserIDEnrollWithBodyWithResponse(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostMfaUserIDEnrollResponse, error) {
+	rsp, err := c.PostMfaUserIDEnrollWithBody(ctx, userID, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostMfaUserIDEnrollResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostMfaUserIDEnrollWithResponse(ctx context.Context, userID UserID, body PostMfaUserIDEnrollJSONRequestBody, reqEditors ...RequestEditorFn) (*PostMfaUserIDEnrollResponse, error) {
+	rsp, err := c.PostMfaUserIDEnroll(ctx, userID, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostMfaUserIDEnrollResponse(rsp)
+}
+
+// PostMfaUserIDVerifyWithBodyWithResponse request with arbitrary body returning *PostMfaUserIDVerifyResponse
+func (c *ClientWithResponses) PostMfaUserIDVerifyWithBodyWithResponse(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostMfaUserIDVerifyResponse, error) {
+	rsp, err := c.PostMfaUserIDVerifyWithBody(ctx, userID, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostMfaUserIDVerifyResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostMfaUserIDVerifyWithResponse(ctx context.Context, userID UserID, body PostMfaUserIDVerifyJSONRequestBody, reqEditors ...RequestEditorFn) (*PostMfaUserIDVerifyResponse, error) {
+	rsp, err := c.PostMfaUserIDVerify(ctx, userID, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostMfaUserIDVerifyResponse(rsp)
+}
+
+// PostRegisterWithBodyWithResponse request with arbitrary body returning *PostRegisterResponse
+func (c *ClientWithResponses) PostRegisterWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostRegisterResponse, error) {
+	rsp, err := c.PostRegisterWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostRegisterResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostRegisterWithResponse(ctx context.Context, body PostRegisterJSONRequestBody, reqEditors ...RequestEditorFn) (*PostRegisterResponse, error) {
+	rsp, err := c.PostRegister(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostRegisterResponse(rsp)
+}
+
+// PostSendFileUserIDWithBodyWithResponse request with arbitrary body returning *PostSendFileUserIDResponse
+func (c *ClientWithResponses) PostSendFileUserIDWithBodyWithResponse(ctx context.Context, userID UserID, fileName string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostSendFileUserIDResponse, error) {
+	rsp, err := c.PostSendFileUserIDWithBody(ctx, userID, fileName, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostSendFileUserIDResponse(rsp)
+}
+
+// DeleteSessionsUserIDWithResponse request returning *DeleteSessionsUserIDResponse
+func (c *ClientWithResponses) DeleteSessionsUserIDWithResponse(ctx context.Context, userID UserID, reqEditors ...RequestEditorFn) (*DeleteSessionsUserIDResponse, error) {
+	rsp, err := c.DeleteSessionsUserID(ctx, userID, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteSessionsUserIDResponse(rsp)
+}
+
+// GetSessionsUserIDWithResponse request returning *GetSessionsUserIDResponse
+func (c *ClientWithResponses) GetSessionsUserIDWithResponse(ctx context.Context, userID UserID, reqEditors ...RequestEditorFn) (*GetSessionsUserIDResponse, error) {
+	rsp, err := c.GetSessionsUserID(ctx, userID, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetSessionsUserIDResponse(rsp)
+}
+
+// DeleteSessionsUserIDSessionIDWithResponse request returning *DeleteSessionsUserIDSessionIDResponse
+func (c *ClientWithResponses) DeleteSessionsUserIDSessionIDWithResponse(ctx context.Context, userID UserID, sessionID string, reqEditors ...RequestEditorFn) (*DeleteSessionsUserIDSessionIDResponse, error) {
+	rsp, err := c.DeleteSessionsUserIDSessionID(ctx, userID, sessionID, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteSessionsUserIDSessionIDResponse(rsp)
+}
+
+// PostSrpLoginInitWithBodyWithResponse request with arbitrary body returning *PostSrpLoginInitResponse
+func (c *ClientWithResponses) PostSrpLoginInitWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostSrpLoginInitResponse, error) {
+	rsp, err := c.PostSrpLoginInitWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostSrpLoginInitResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostSrpLoginInitWithResponse(ctx context.Context, body PostSrpLoginInitJSONRequestBody, reqEditors ...RequestEditorFn) (*PostSrpLoginInitResponse, error) {
+	rsp, err := c.PostSrpLoginInit(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostSrpLoginInitResponse(rsp)
+}
+
+// PostSrpLoginVerifyWithBodyWithResponse request with arbitrary body returning *PostSrpLoginVerifyResponse
+func (c *ClientWithResponses) PostSrpLoginVerifyWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostSrpLoginVerifyResponse, error) {
+	rsp, err := c.PostSrpLoginVerifyWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostSrpLoginVerifyResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostSrpLoginVerifyWithResponse(ctx context.Context, body PostSrpLoginVerifyJSONRequestBody, reqEditors ...RequestEditorFn) (*PostSrpLoginVerifyResponse, error) {
+	rsp, err := c.PostSrpLoginVerify(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostSrpLoginVerifyResponse(rsp)
+}
+
+// PostSrpRegisterWithBodyWithResponse request with arbitrary body returning *PostSrpRegisterResponse
+func (c *ClientWithResponses) PostSrpRegisterWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostSrpRegisterResponse, error) {
+	rsp, err := c.PostSrpRegisterWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostSrpRegisterResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostSrpRegisterWithResponse(ctx context.Context, body PostSrpRegisterJSONRequestBody, reqEditors ...RequestEditorFn) (*PostSrpRegisterResponse, error) {
+	rsp, err := c.PostSrpRegister(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostSrpRegisterResponse(rsp)
+}
+
+// GetSyncUserIDWithResponse request returning *GetSyncUserIDResponse
+func (c *ClientWithResponses) GetSyncUserIDWithResponse(ctx context.Context, userID UserID, params *GetSyncUserIDParams, reqEditors ...RequestEditorFn) (*GetSyncUserIDResponse, error) {
+	rsp, err := c.GetSyncUserID(ctx, userID, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetSyncUserIDResponse(rsp)
+}
+
+// PostTokenRefreshWithBodyWithResponse request with arbitrary body returning *PostTokenRefreshResponse
+func (c *ClientWithResponses) PostTokenRefreshWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostTokenRefreshResponse, error) {
+	rsp, err := c.PostTokenRefreshWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTokenRefreshResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostTokenRefreshWithResponse(ctx context.Context, body PostTokenRefreshJSONRequestBody, reqEditors ...RequestEditorFn) (*PostTokenRefreshResponse, error) {
+	rsp, err := c.PostTokenRefresh(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostTokenRefreshResponse(rsp)
+}
+
+// PutUpdateDataTableUserIDEntryIDWithBodyWithResponse request with arbitrary body returning *PutUpdateDataTableUserIDEntryIDResponse
+func (c *ClientWithResponses) PutUpdateDataTableUserIDEntryIDWithBodyWithResponse(ctx context.Context, table Table, userID UserID, entryID EntryID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PutUpdateDataTableUserIDEntryIDResponse, error) {
+	rsp, err := c.PutUpdateDataTableUserIDEntryIDWithBody(ctx, table, userID, entryID, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutUpdateDataTableUserIDEntryIDResponse(rsp)
+}
+
+func (c *ClientWithResponses) PutUpdateDataTableUserIDEntryIDWithResponse(ctx context.Context, table Table, userID UserID, entryID EntryID, body PutUpdateDataTableUserIDEntryIDJSONRequestBody, reqEditors ...RequestEditorFn) (*PutUpdateDataTableUserIDEntryIDResponse, error) {
+	rsp, err := c.PutUpdateDataTableUserIDEntryID(ctx, table, userID, entryID, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePutUpdateDataTableUserIDEntryIDResponse(rsp)
+}
+
+// PostUploadsUserIDWithBodyWithResponse request with arbitrary body returning *PostUploadsUserIDResponse
+func (c *ClientWithResponses) PostUploadsUserIDWithBodyWithResponse(ctx context.Context, userID UserID, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostUploadsUserIDResponse, error) {
+	rsp, err := c.PostUploadsUserIDWithBody(ctx, userID, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostUploadsUserIDResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostUploadsUserIDWithResponse(ctx context.Context, userID UserID, body PostUploadsUserIDJSONRequestBody, reqEditors ...RequestEditorFn) (*PostUploadsUserIDResponse, error) {
+	rsp, err := c.PostUploadsUserID(ctx, userID, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostUploadsUserIDResponse(rsp)
+}
+
+// DeleteUploadsUserIDUploadIDWithResponse request returning *DeleteUploadsUserIDUploadIDResponse
+func (c *ClientWithResponses) DeleteUploadsUserIDUploadIDWithResponse(ctx context.Context, userID UserID, uploadID UploadID, reqEditors ...RequestEditorFn) (*DeleteUploadsUserIDUploadIDResponse, error) {
+	rsp, err := c.DeleteUploadsUserIDUploadID(ctx, userID, uploadID, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteUploadsUserIDUploadIDResponse(rsp)
+}
+
+// HeadUploadsUserIDUploadIDWithResponse request returning *HeadUploadsUserIDUploadIDResponse
+func (c *ClientWithResponses) HeadUploadsUserIDUploadIDWithResponse(ctx context.Context, userID UserID, uploadID UploadID, reqEditors ...RequestEditorFn) (*HeadUploadsUserIDUploadIDResponse, error) {
+	rsp, err := c.HeadUploadsUserIDUploadID(ctx, userID, uploadID, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHeadUploadsUserIDUploadIDResponse(rsp)
+}
+
+// PatchUploadsUserIDUploadIDWithBodyWithResponse request with arbitrary body returning *PatchUploadsUserIDUploadIDResponse
+func (c *ClientWithResponses) PatchUploadsUserIDUploadIDWithBodyWithResponse(ctx context.Context, userID UserID, uploadID UploadID, params *PatchUploadsUserIDUploadIDParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PatchUploadsUserIDUploadIDResponse, error) {
+	rsp, err := c.PatchUploadsUserIDUploadIDWithBody(ctx, userID, uploadID, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePatchUploadsUserIDUploadIDResponse(rsp)
+}
+
+// PostUploadsUserIDUploadIDFinishWithResponse request returning *PostUploadsUserIDUploadIDFinishResponse
+func (c *ClientWithResponses) PostUploadsUserIDUploadIDFinishWithResponse(ctx context.Context, userID UserID, uploadID UploadID, reqEditors ...RequestEditorFn) (*PostUploadsUserIDUploadIDFinishResponse, error) {
+	rsp, err := c.PostUploadsUserIDUploadIDFinish(ctx, userID, uploadID, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostUploadsUserIDUploadIDFinishResponse(rsp)
+}
+
+// ParsePostAddDataTableUserIDEntryIDResponse parses an HTTP response from a PostAddDataTableUserIDEntryIDWithResponse call
+func ParsePostAddDataTableUserIDEntryIDResponse(rsp *http.Response) (*PostAddDataTableUserIDEntryIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostAddDataTableUserIDEntryIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseGetAdminCacheStatsResponse parses an HTTP response from a GetAdminCacheStatsWithResponse call
+func ParseGetAdminCacheStatsResponse(rsp *http.Response) (*GetAdminCacheStatsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetAdminCacheStatsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest CacheStats
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetAdminReencryptionResponse parses an HTTP response from a GetAdminReencryptionWithResponse call
+func ParseGetAdminReencryptionResponse(rsp *http.Response) (*GetAdminReencryptionResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetAdminReencryptionResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ReencryptionProgress
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostAdminReencryptionResponse parses an HTTP response from a PostAdminReencryptionWithResponse call
+func ParsePostAdminReencryptionResponse(rsp *http.Response) (*PostAdminReencryptionResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostAdminReencryptionResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest ReencryptionProgress
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetAdminStatsResponse parses an HTTP response from a GetAdminStatsWithResponse call
+func ParseGetAdminStatsResponse(rsp *http.Response) (*GetAdminStatsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetAdminStatsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest BlobStats
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostBatchUserIDResponse parses an HTTP response from a PostBatchUserIDWithResponse call
+func ParsePostBatchUserIDResponse(rsp *http.Response) (*PostBatchUserIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostBatchUserIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && true:
+		var dest BatchResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSONDefault = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDeleteDeleteDataTableUserIDEntryIDResponse parses an HTTP response from a DeleteDeleteDataTableUserIDEntryIDWithResponse call
+func ParseDeleteDeleteDataTableUserIDEntryIDResponse(rsp *http.Response) (*DeleteDeleteDataTableUserIDEntryIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DeleteDeleteDataTableUserIDEntryIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseGetEventsUserIDResponse parses an HTTP response from a GetEventsUserIDWithResponse call
+func ParseGetEventsUserIDResponse(rsp *http.Response) (*GetEventsUserIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetEventsUserIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseGetGetAllDataTableUserIDResponse parses an HTTP response from a GetGetAllDataTableUserIDWithResponse call
+func ParseGetGetAllDataTableUserIDResponse(rsp *http.Response) (*GetGetAllDataTableUserIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetGetAllDataTableUserIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []Record
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetGetDataTableUserIDEntryIDResponse parses an HTTP response from a GetGetDataTableUserIDEntryIDWithResponse call
+func ParseGetGetDataTableUserIDEntryIDResponse(rsp *http.Response) (*GetGetDataTableUserIDEntryIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetGetDataTableUserIDEntryIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Record
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetGetFileUserIDEntryIDResponse parses an HTTP response from a GetGetFileUserIDEntryIDWithResponse call
+func ParseGetGetFileUserIDEntryIDResponse(rsp *http.Response) (*GetGetFileUserIDEntryIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetGetFileUserIDEntryIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseGetGetPasswordUsernameResponse parses an HTTP response from a GetGetPasswordUsernameWithResponse call
+func ParseGetGetPasswordUsernameResponse(rsp *http.Response) (*GetGetPasswordUsernameResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetGetPasswordUsernameResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseGetGetUserIDUsernameResponse parses an HTTP response from a GetGetUserIDUsernameWithResponse call
+func ParseGetGetUserIDUsernameResponse(rsp *http.Response) (*GetGetUserIDUsernameResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetGetUserIDUsernameResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest int
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostLoginResponse parses an HTTP response from a PostLoginWithResponse call
+func ParsePostLoginResponse(rsp *http.Response) (*PostLoginResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostLoginResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Login
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostLoginMfaResponse parses an HTTP response from a PostLoginMfaWithResponse call
+func ParsePostLoginMfaResponse(rsp *http.Response) (*PostLoginMfaResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostLoginMfaResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Login
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostLogoutResponse parses an HTTP response from a PostLogoutWithResponse call
+func ParsePostLogoutResponse(rsp *http.Response) (*PostLogoutResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostLogoutResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParsePostMfaUserIDEnrollResponse parses an HTTP response from a PostMfaUserIDEnrollWithResponse call
+func ParsePostMfaUserIDEnrollResponse(rsp *http.Response) (*PostMfaUserIDEnrollResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostMfaUserIDEnrollResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest MFAEnrollment
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostMfaUserIDVerifyResponse parses an HTTP response from a PostMfaUserIDVerifyWithResponse call
+func ParsePostMfaUserIDVerifyResponse(rsp *http.Response) (*PostMfaUserIDVerifyResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostMfaUserIDVerifyResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest RecoveryCodes
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostRegisterResponse parses an HTTP response from a PostRegisterWithResponse call
+func ParsePostRegisterResponse(rsp *http.Response) (*PostRegisterResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostRegisterResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParsePostSendFileUserIDResponse parses an HTTP response from a PostSendFileUserIDWithResponse call
+func ParsePostSendFileUserIDResponse(rsp *http.Response) (*PostSendFileUserIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostSendFileUserIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest FileContentResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDeleteSessionsUserIDResponse parses an HTTP response from a DeleteSessionsUserIDWithResponse call
+func ParseDeleteSessionsUserIDResponse(rsp *http.Response) (*DeleteSessionsUserIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DeleteSessionsUserIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseGetSessionsUserIDResponse parses an HTTP response from a GetSessionsUserIDWithResponse call
+func ParseGetSessionsUserIDResponse(rsp *http.Response) (*GetSessionsUserIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetSessionsUserIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []Session
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDeleteSessionsUserIDSessionIDResponse parses an HTTP response from a DeleteSessionsUserIDSessionIDWithResponse call
+func ParseDeleteSessionsUserIDSessionIDResponse(rsp *http.Response) (*DeleteSessionsUserIDSessionIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DeleteSessionsUserIDSessionIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParsePostSrpLoginInitResponse parses an HTTP response from a PostSrpLoginInitWithResponse call
+func ParsePostSrpLoginInitResponse(rsp *http.Response) (*PostSrpLoginInitResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostSrpLoginInitResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SRPChallenge
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostSrpLoginVerifyResponse parses an HTTP response from a PostSrpLoginVerifyWithResponse call
+func ParsePostSrpLoginVerifyResponse(rsp *http.Response) (*PostSrpLoginVerifyResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostSrpLoginVerifyResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Login
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostSrpRegisterResponse parses an HTTP response from a PostSrpRegisterWithResponse call
+func ParsePostSrpRegisterResponse(rsp *http.Response) (*PostSrpRegisterResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostSrpRegisterResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseGetSyncUserIDResponse parses an HTTP response from a GetSyncUserIDWithResponse call
+func ParseGetSyncUserIDResponse(rsp *http.Response) (*GetSyncUserIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetSyncUserIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest SyncResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostTokenRefreshResponse parses an HTTP response from a PostTokenRefreshWithResponse call
+func ParsePostTokenRefreshResponse(rsp *http.Response) (*PostTokenRefreshResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostTokenRefreshResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Login
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePutUpdateDataTableUserIDEntryIDResponse parses an HTTP response from a PutUpdateDataTableUserIDEntryIDWithResponse call
+func ParsePutUpdateDataTableUserIDEntryIDResponse(rsp *http.Response) (*PutUpdateDataTableUserIDEntryIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PutUpdateDataTableUserIDEntryIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Version
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Record
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostUploadsUserIDResponse parses an HTTP response from a PostUploadsUserIDWithResponse call
+func ParsePostUploadsUserIDResponse(rsp *http.Response) (*PostUploadsUserIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostUploadsUserIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest UploadSession
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDeleteUploadsUserIDUploadIDResponse parses an HTTP response from a DeleteUploadsUserIDUploadIDWithResponse call
+func ParseDeleteUploadsUserIDUploadIDResponse(rsp *http.Response) (*DeleteUploadsUserIDUploadIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DeleteUploadsUserIDUploadIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseHeadUploadsUserIDUploadIDResponse parses an HTTP response from a HeadUploadsUserIDUploadIDWithResponse call
+func ParseHeadUploadsUserIDUploadIDResponse(rsp *http.Response) (*HeadUploadsUserIDUploadIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &HeadUploadsUserIDUploadIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParsePatchUploadsUserIDUploadIDResponse parses an HTTP response from a PatchUploadsUserIDUploadIDWithResponse call
+func ParsePatchUploadsUserIDUploadIDResponse(rsp *http.Response) (*PatchUploadsUserIDUploadIDResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PatchUploadsUserIDUploadIDResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParsePostUploadsUserIDUploadIDFinishResponse parses an HTTP response from a PostUploadsUserIDUploadIDFinishWithResponse call
+func ParsePostUploadsUserIDUploadIDFinishResponse(rsp *http.Response) (*PostUploadsUserIDUploadIDFinishResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostUploadsUserIDUploadIDFinishResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest FileContentResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
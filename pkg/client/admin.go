@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/wurt83ow/gophkeeper-server/pkg/api"
+)
+
+// AdminCacheStats returns the counters of the server's storage cache. Only administrators may call it.
+func (c *Client) AdminCacheStats(ctx context.Context) (*api.CacheStats, error) {
+	resp, err := c.api.GetAdminCacheStats(ctx)
+
+	var stats api.CacheStats
+	if err := decodeResponse(resp, err, http.StatusOK, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// StartReencryption starts moving the stored data of the server to its current master key
+// and returns the initial progress. Only administrators may call it.
+func (c *Client) StartReencryption(ctx context.Context) (*api.ReencryptionProgress, error) {
+	resp, err := c.api.PostAdminReencryption(ctx)
+
+	var progress api.ReencryptionProgress
+	if err := decodeResponse(resp, err, http.StatusAccepted, &progress); err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// ReencryptionProgress returns the progress of the latest re-encryption run. Only administrators may call it.
+func (c *Client) ReencryptionProgress(ctx context.Context) (*api.ReencryptionProgress, error) {
+	resp, err := c.api.GetAdminReencryption(ctx)
+
+	var progress api.ReencryptionProgress
+	if err := decodeResponse(resp, err, http.StatusOK, &progress); err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
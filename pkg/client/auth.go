@@ -0,0 +1,224 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/wurt83ow/gophkeeper-server/pkg/api"
+)
+
+// Register registers a user with a password.
+func (c *Client) Register(ctx context.Context, username, password string) error {
+	resp, err := c.public.PostRegister(ctx, api.PostRegisterJSONRequestBody{
+		Username: username,
+		Password: password,
+	})
+	return checkResponse(resp, err, http.StatusOK)
+}
+
+// Login logs the user in with a password. If the user has two-factor authentication,
+// ErrMFARequired is returned with the challenge token to pass to LoginMFA.
+func (c *Client) Login(ctx context.Context, username, password string) (*api.LoginResponse, error) {
+	deviceID, deviceName := c.device()
+	resp, err := c.public.PostLogin(ctx, api.PostLoginJSONRequestBody{
+		Username:   username,
+		Password:   password,
+		DeviceID:   deviceID,
+		DeviceName: deviceName,
+	})
+	return c.completeLogin(resp, err)
+}
+
+// LoginMFA completes a login with a one-time code of the authenticator.
+func (c *Client) LoginMFA(ctx context.Context, challengeToken, code string) (*api.LoginResponse, error) {
+	resp, err := c.public.PostLoginMfa(ctx, api.PostLoginMfaJSONRequestBody{
+		ChallengeToken: challengeToken,
+		Code:           code,
+	})
+	return c.completeLogin(resp, err)
+}
+
+// LoginRecoveryCode completes a login with a recovery code instead of a one-time code.
+func (c *Client) LoginRecoveryCode(ctx context.Context, challengeToken, recoveryCode string) (*api.LoginResponse, error) {
+	resp, err := c.public.PostLoginMfa(ctx, api.PostLoginMfaJSONRequestBody{
+		ChallengeToken: challengeToken,
+		RecoveryCode:   recoveryCode,
+	})
+	return c.completeLogin(resp, err)
+}
+
+// SRPRegister registers a user with an SRP-6a salt and verifier.
+func (c *Client) SRPRegister(ctx context.Context, username, salt, verifier string) error {
+	resp, err := c.public.PostSrpRegister(ctx, api.PostSrpRegisterJSONRequestBody{
+		Username: username,
+		Salt:     salt,
+		Verifier: verifier,
+	})
+	return checkResponse(resp, err, http.StatusOK)
+}
+
+// SRPLoginInit starts an SRP-6a login with the public ephemeral value of the client.
+func (c *Client) SRPLoginInit(ctx context.Context, username, publicA string) (*api.SRPChallenge, error) {
+	resp, err := c.public.PostSrpLoginInit(ctx, api.PostSrpLoginInitJSONRequestBody{
+		Username: username,
+		PublicA:  publicA,
+	})
+
+	var challenge api.SRPChallenge
+	if err := decodeResponse(resp, err, http.StatusOK, &challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// SRPLoginVerify completes an SRP-6a login with the client proof. The caller should
+// check the server proof of the response before trusting the session.
+func (c *Client) SRPLoginVerify(ctx context.Context, challengeID, clientProof string) (*api.LoginResponse, error) {
+	deviceID, deviceName := c.device()
+	resp, err := c.public.PostSrpLoginVerify(ctx, api.PostSrpLoginVerifyJSONRequestBody{
+		ChallengeID: challengeID,
+		ClientProof: clientProof,
+		DeviceID:    deviceID,
+		DeviceName:  deviceName,
+	})
+	return c.completeLogin(resp, err)
+}
+
+// Refresh exchanges the refresh token for new tokens.
+// Requests refresh expired tokens by themselves, so it is rarely needed.
+func (c *Client) Refresh(ctx context.Context) error {
+	return c.refresh(ctx, c.accessToken())
+}
+
+// Logout ends the session and forgets its tokens.
+func (c *Client) Logout(ctx context.Context) error {
+	tokens := c.Tokens()
+	if tokens.AccessToken == "" {
+		return ErrNotLoggedIn
+	}
+
+	resp, err := c.api.PostLogout(ctx, api.PostLogoutJSONRequestBody{
+		RefreshToken: tokens.RefreshToken,
+	})
+	if err := checkResponse(resp, err, http.StatusOK); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.tokens = Tokens{DeviceID: c.tokens.DeviceID}
+	c.mu.Unlock()
+	return nil
+}
+
+// UserID looks up the ID of a user.
+func (c *Client) UserID(ctx context.Context, username string) (int, error) {
+	resp, err := c.public.GetGetUserIDUsername(ctx, username)
+
+	var userID int
+	if err := decodeResponse(resp, err, http.StatusOK, &userID); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// Sessions lists the device sessions of the user.
+func (c *Client) Sessions(ctx context.Context) ([]api.Session, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.api.GetSessionsUserID(ctx, userID)
+
+	var sessions []api.Session
+	if err := decodeResponse(resp, err, http.StatusOK, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RevokeSession ends a session of the user.
+func (c *Client) RevokeSession(ctx context.Context, sessionID string) error {
+	userID, err := c.userID()
+	if err != nil {
+		return err
+	}
+	resp, err := c.api.DeleteSessionsUserIDSessionID(ctx, userID, sessionID)
+	return checkResponse(resp, err, http.StatusOK)
+}
+
+// RevokeSessions ends all sessions of the user, including the current one.
+func (c *Client) RevokeSessions(ctx context.Context) error {
+	userID, err := c.userID()
+	if err != nil {
+		return err
+	}
+	resp, err := c.api.DeleteSessionsUserID(ctx, userID)
+	return checkResponse(resp, err, http.StatusOK)
+}
+
+// EnrollMFA starts enrolling a TOTP authenticator. The enrollment is confirmed with VerifyMFA.
+// Replacing the authenticator of a user with two-factor authentication takes a one-time
+// code of the current one; the code is empty for the first enrollment.
+func (c *Client) EnrollMFA(ctx context.Context, code string) (*api.MFAEnrollment, error) {
+	return c.enrollMFA(ctx, api.PostMfaUserIDEnrollJSONRequestBody{Code: code})
+}
+
+// EnrollMFARecoveryCode replaces the authenticator with a recovery code instead of a one-time code.
+func (c *Client) EnrollMFARecoveryCode(ctx context.Context, recoveryCode string) (*api.MFAEnrollment, error) {
+	return c.enrollMFA(ctx, api.PostMfaUserIDEnrollJSONRequestBody{RecoveryCode: recoveryCode})
+}
+
+// enrollMFA starts an enrollment with the code of the current authenticator in body.
+func (c *Client) enrollMFA(ctx context.Context, body api.PostMfaUserIDEnrollJSONRequestBody) (*api.MFAEnrollment, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.api.PostMfaUserIDEnroll(ctx, userID, body)
+
+	var enrollment api.MFAEnrollment
+	if err := decodeResponse(resp, err, http.StatusOK, &enrollment); err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// VerifyMFA confirms a TOTP enrollment with a code and returns the recovery codes.
+func (c *Client) VerifyMFA(ctx context.Context, code string) ([]string, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.api.PostMfaUserIDVerify(ctx, userID, api.PostMfaUserIDVerifyJSONRequestBody{
+		Code: code,
+	})
+
+	var codes api.RecoveryCodes
+	if err := decodeResponse(resp, err, http.StatusOK, &codes); err != nil {
+		return nil, err
+	}
+	return codes.RecoveryCodes, nil
+}
+
+// device returns the device the sessions are registered for.
+func (c *Client) device() (string, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.tokens.DeviceID, c.deviceName
+}
+
+// completeLogin stores the tokens of a login response.
+func (c *Client) completeLogin(resp *http.Response, err error) (*api.LoginResponse, error) {
+	var login api.LoginResponse
+	if err := decodeResponse(resp, err, http.StatusOK, &login); err != nil {
+		return nil, err
+	}
+
+	if login.MfaRequired != nil && *login.MfaRequired {
+		return &login, ErrMFARequired
+	}
+
+	c.setTokens(login)
+	return &login, nil
+}
@@ -0,0 +1,467 @@
+// Package client provides a Go client for the GophKeeper API.
+//
+// The client logs in once and then keeps the access token fresh by itself: a request
+// rejected with 401 Unauthorized is repeated after the refresh token is exchanged for
+// new tokens. Idempotent requests are retried with exponential backoff when the server
+// is unavailable, and JSON request bodies can be sent gzip-compressed.
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wurt83ow/gophkeeper-server/pkg/api"
+)
+
+const (
+	// defaultRetries is how many times an idempotent request is repeated.
+	defaultRetries = 3
+	// defaultBackoff is the delay before the first retry, doubled for each next one.
+	defaultBackoff = 100 * time.Millisecond
+	// maxErrorMessage is how much of an error response is kept in Error.
+	maxErrorMessage = 1 << 10
+)
+
+var (
+	// ErrNotLoggedIn is returned by the methods that need a session before a login.
+	ErrNotLoggedIn = errors.New("client is not logged in")
+	// ErrMFARequired is returned by Login when the login must be completed with LoginMFA.
+	ErrMFARequired = errors.New("two-factor authentication required")
+	// ErrUnauthorized matches errors of requests rejected with 401 Unauthorized.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden matches errors of requests rejected with 403 Forbidden.
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound matches errors of requests for resources that do not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict matches errors of requests rejected with 409 Conflict.
+	ErrConflict = errors.New("conflict")
+)
+
+// Error is a response of the server with an unexpected status code.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+// Error returns the status code and the message of the server.
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("gophkeeper: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("gophkeeper: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Is matches the error with the sentinel error of its status code.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	}
+	return false
+}
+
+// ConflictError is returned by UpdateItem when the record has changed since the base version.
+// Current is the server copy of the record the changes can be merged with.
+type ConflictError struct {
+	Current api.Record
+}
+
+// Error describes the conflict.
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("record %s has changed on the server, its version is %s", e.Current.Id, e.Current.Version)
+}
+
+// Is matches the error with ErrConflict.
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// Tokens are the credentials of a session. They can be saved and passed to
+// WithTokens to continue the session without a new login.
+type Tokens struct {
+	UserID       int
+	AccessToken  string
+	RefreshToken string
+	DeviceID     string
+}
+
+// Client is a client of the GophKeeper API. It is safe for concurrent use.
+type Client struct {
+	// api sends the requests that need the access token, public the others
+	api    *api.Client
+	public *api.Client
+
+	http       *http.Client
+	retries    int
+	backoff    time.Duration
+	gzip       bool
+	deviceName string
+	onTokens   func(Tokens)
+
+	mu     sync.Mutex
+	tokens Tokens
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client that sends the requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.http = httpClient
+	}
+}
+
+// WithRetries sets how many times an idempotent request is repeated and the delay
+// before the first retry. Zero retries disable retrying.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// WithGzip makes the client compress JSON request bodies.
+// Compressed responses are accepted and unpacked by the HTTP client in any case.
+func WithGzip() Option {
+	return func(c *Client) {
+		c.gzip = true
+	}
+}
+
+// WithDevice sets the device the sessions of the client are registered for.
+// Logins from the same device replace its previous session.
+func WithDevice(deviceID, deviceName string) Option {
+	return func(c *Client) {
+		c.tokens.DeviceID = deviceID
+		c.deviceName = deviceName
+	}
+}
+
+// WithTokens continues a session saved from a previous client.
+func WithTokens(tokens Tokens) Option {
+	return func(c *Client) {
+		c.tokens = tokens
+	}
+}
+
+// WithTokensHook sets a function called with the new tokens after each login and refresh.
+// Refresh tokens can be used only once, so a client that saves its session must save them again.
+func WithTokensHook(hook func(Tokens)) Option {
+	return func(c *Client) {
+		c.onTokens = hook
+	}
+}
+
+// New creates a new Client for the server at the given base URL.
+func New(server string, opts ...Option) (*Client, error) {
+	c := &Client{
+		http:    http.DefaultClient,
+		retries: defaultRetries,
+		backoff: defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	var err error
+	c.api, err = api.NewClient(server, api.WithHTTPClient(&doer{client: c, auth: true}))
+	if err != nil {
+		return nil, err
+	}
+	c.public, err = api.NewClient(server, api.WithHTTPClient(&doer{client: c}))
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Tokens returns the credentials of the current session.
+func (c *Client) Tokens() Tokens {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.tokens
+}
+
+// userID returns the ID of the logged in user.
+func (c *Client) userID() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.tokens.AccessToken == "" {
+		return 0, ErrNotLoggedIn
+	}
+	return c.tokens.UserID, nil
+}
+
+// accessToken returns the current access token.
+func (c *Client) accessToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.tokens.AccessToken
+}
+
+// setTokens stores the tokens of a login or refresh response.
+func (c *Client) setTokens(login api.LoginResponse) {
+	c.mu.Lock()
+	c.setTokensLocked(login)
+	tokens := c.tokens
+	c.mu.Unlock()
+
+	if c.onTokens != nil {
+		c.onTokens(tokens)
+	}
+}
+
+func (c *Client) setTokensLocked(login api.LoginResponse) {
+	if login.UserID != nil {
+		c.tokens.UserID = *login.UserID
+	}
+	if login.Token != nil {
+		c.tokens.AccessToken = *login.Token
+	}
+	if login.RefreshToken != nil {
+		c.tokens.RefreshToken = *login.RefreshToken
+	}
+	if login.DeviceID != nil {
+		c.tokens.DeviceID = *login.DeviceID
+	}
+}
+
+// refresh exchanges the refresh token for new tokens unless another request
+// has already replaced the stale access token.
+func (c *Client) refresh(ctx context.Context, stale string) error {
+	c.mu.Lock()
+	if c.tokens.AccessToken != stale {
+		c.mu.Unlock()
+		return nil
+	}
+	if c.tokens.RefreshToken == "" {
+		c.mu.Unlock()
+		return ErrNotLoggedIn
+	}
+
+	// The lock is held during the request, so concurrent requests refresh only once
+	resp, err := c.public.PostTokenRefresh(ctx, api.PostTokenRefreshJSONRequestBody{
+		RefreshToken: c.tokens.RefreshToken,
+	})
+	var login api.LoginResponse
+	if err := decodeResponse(resp, err, http.StatusOK, &login); err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	c.setTokensLocked(login)
+	tokens := c.tokens
+	c.mu.Unlock()
+
+	if c.onTokens != nil {
+		c.onTokens(tokens)
+	}
+	return nil
+}
+
+// doer sends the requests of the generated client. It adds the access token,
+// compresses request bodies, refreshes expired tokens and retries idempotent requests.
+type doer struct {
+	client *Client
+	auth   bool
+}
+
+// Do sends the request.
+func (d *doer) Do(req *http.Request) (*http.Response, error) {
+	c := d.client
+
+	if c.gzip {
+		if err := compressBody(req); err != nil {
+			return nil, err
+		}
+	}
+
+	refreshed := false
+	for attempt, retry := 0, 0; ; attempt++ {
+		r, err := attemptRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		token := ""
+		if d.auth {
+			token = c.accessToken()
+			r.Header.Set("Authorization", token)
+		}
+
+		resp, err := c.http.Do(r)
+
+		// An expired access token is replaced once, then the request is repeated
+		if d.auth && !refreshed && err == nil && resp.StatusCode == http.StatusUnauthorized && rewindable(req) {
+			refreshed = true
+			if c.refresh(req.Context(), token) == nil {
+				discard(resp)
+				continue
+			}
+		}
+
+		if retry >= c.retries || !retryable(req, resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			discard(resp)
+		}
+
+		if err := sleep(req.Context(), c.backoff<<retry); err != nil {
+			return nil, err
+		}
+		retry++
+	}
+}
+
+// attemptRequest returns a copy of the request with a fresh body for a repeated attempt.
+func attemptRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 {
+		return req.Clone(req.Context()), nil
+	}
+
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+// rewindable reports whether the request body can be sent again.
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// retryable reports whether a failed attempt of the request may be repeated.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+	default:
+		return false
+	}
+	if !rewindable(req) {
+		return false
+	}
+
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// sleep waits for the delay or until the context is done.
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// discard reads the rest of the response body, so the connection can be reused.
+func discard(resp *http.Response) {
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// compressBody replaces a JSON request body with its gzip-compressed copy.
+// File content is left as is, so that it is still streamed.
+func compressBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get("Content-Type") != "application/json" {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := io.Copy(zw, req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	data := buf.Bytes()
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Encoding", "gzip")
+	return nil
+}
+
+// checkResponse closes the response and returns an Error unless it has the wanted status.
+func checkResponse(resp *http.Response, err error, want int) error {
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return checkStatus(resp, want)
+}
+
+// decodeResponse decodes the JSON body of a response with the wanted status into v.
+func decodeResponse(resp *http.Response, err error, want int, v interface{}) error {
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp, want); err != nil {
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// checkStatus returns an Error with the message of the server unless the response has the wanted status.
+func checkStatus(resp *http.Response, want int) error {
+	if resp.StatusCode == want {
+		return nil
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorMessage))
+	return &Error{
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(message)),
+	}
+}
+
+// parseVersion returns the record version of a response ETag.
+func parseVersion(etag string) (int64, error) {
+	return strconv.ParseInt(strings.Trim(etag, `"`), 10, 64)
+}
@@ -0,0 +1,439 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wurt83ow/gophkeeper-server/internal/app"
+	authz "github.com/wurt83ow/gophkeeper-server/internal/authorization"
+	"github.com/wurt83ow/gophkeeper-server/internal/blobstore"
+	"github.com/wurt83ow/gophkeeper-server/internal/controllers"
+	"github.com/wurt83ow/gophkeeper-server/internal/cryptokeeper"
+	"github.com/wurt83ow/gophkeeper-server/internal/events"
+	"github.com/wurt83ow/gophkeeper-server/internal/keyring"
+	"github.com/wurt83ow/gophkeeper-server/internal/logger"
+	"github.com/wurt83ow/gophkeeper-server/internal/memkeeper"
+	"github.com/wurt83ow/gophkeeper-server/internal/storage"
+	"github.com/wurt83ow/gophkeeper-server/internal/uploads"
+	"github.com/wurt83ow/gophkeeper-server/pkg/api"
+	"go.uber.org/zap"
+)
+
+type testOptions struct {
+	dir    string
+	admins []string
+}
+
+func (o *testOptions) ParseFlags()             {}
+func (o *testOptions) RunAddr() string         { return "" }
+func (o *testOptions) FileStoragePath() string { return o.dir }
+func (o *testOptions) MaxUploadSize() int64    { return 1 << 20 }
+func (o *testOptions) AdminUsers() []string    { return o.admins }
+
+// newTestServer serves the real router over an in-memory keeper.
+func newTestServer(t *testing.T) *httptest.Server {
+	return newAdminTestServer(t, memkeeper.NewMemKeeper(), nil, nil)
+}
+
+// newAdminTestServer serves the real router over keeper, letting admins use the admin
+// endpoints, which start and report the re-encryption runs of reencryption.
+func newAdminTestServer(t *testing.T, keeper storage.Keeper, admins []string, reencryption controllers.Reencryption) *httptest.Server {
+	log, err := logger.NewLogger("error")
+	require.NoError(t, err)
+
+	memoryStorage := storage.NewMemoryStorage(keeper, log, storage.CacheOptions{Size: 100, TTL: time.Minute})
+	hasher := authz.NewArgon2idHasher(authz.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1})
+	jwtAuthz := authz.NewJWTAuthz("secret", time.Minute, time.Hour, hasher, log)
+
+	dir := t.TempDir()
+	baseController := controllers.NewBaseController(memoryStorage, &testOptions{dir: dir, admins: admins}, log, jwtAuthz,
+		uploads.NewStore(dir), blobstore.NewFileStore(filepath.Join(dir, "blobs")), events.NewBroadcaster(), reencryption)
+
+	router, err := app.NewRouter(baseController, jwtAuthz, memoryStorage, 1<<20, log)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newLoggedInClient registers a user and logs the client in.
+func newLoggedInClient(t *testing.T, srv *httptest.Server, opts ...Option) *Client {
+	c, err := New(srv.URL, opts...)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, c.Register(ctx, "user", "password"))
+	_, err = c.Login(ctx, "user", "password")
+	require.NoError(t, err)
+
+	return c
+}
+
+// recordingTransport answers the first failures requests with 503 Service Unavailable
+// and records the requests and responses that reached the server.
+type recordingTransport struct {
+	mu        sync.Mutex
+	failures  int
+	attempts  int
+	requests  []*http.Request
+	responses []*http.Response
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.attempts++
+	if rt.failures > 0 {
+		rt.failures--
+		rt.mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Header:     http.Header{},
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	}
+	rt.requests = append(rt.requests, req)
+	rt.mu.Unlock()
+
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err == nil {
+		rt.mu.Lock()
+		rt.responses = append(rt.responses, resp)
+		rt.mu.Unlock()
+	}
+	return resp, err
+}
+
+func (rt *recordingTransport) fail(n int) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	rt.failures = n
+	rt.attempts = 0
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestClient_Items(t *testing.T) {
+	srv := newTestServer(t)
+	c := newLoggedInClient(t, srv)
+	ctx := context.Background()
+
+	version, err := c.AddCredentials(ctx, "site", api.Credentials{Login: "alice", Password: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+
+	_, err = c.AddText(ctx, "note", api.Text{Data: "text", MetaInfo: strPtr("meta")})
+	require.NoError(t, err)
+
+	record, err := c.GetItem(ctx, TableCredentials, "site")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", *record.Login)
+	assert.Equal(t, "1", record.Version)
+
+	version, err = c.UpdateItem(ctx, TableCredentials, "site", api.ItemPatch{Password: strPtr("changed")}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+
+	// An update based on an outdated version gets the current server copy
+	_, err = c.UpdateItem(ctx, TableCredentials, "site", api.ItemPatch{Password: strPtr("stale")}, 1)
+	var conflict *ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Equal(t, "2", conflict.Current.Version)
+	assert.Equal(t, "changed", *conflict.Current.Password)
+
+	records, err := c.ListItems(ctx, TableTexts, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "text", *records[0].Data)
+
+	// Each record is synced once in its latest state
+	sync, err := c.Sync(ctx, "", 1)
+	require.NoError(t, err)
+	assert.Len(t, sync.Changes, 1)
+	assert.True(t, sync.HasMore)
+
+	sync, err = c.Sync(ctx, sync.Cursor, 0)
+	require.NoError(t, err)
+	assert.Len(t, sync.Changes, 1)
+	assert.False(t, sync.HasMore)
+
+	batch, err := c.Batch(ctx, []api.BatchOperation{
+		{Op: "add", Table: TableTexts, Id: "second", Data: map[string]string{"data": "more"}},
+		{Op: "update", Table: TableTexts, Id: "missing", Data: map[string]string{"data": "none"}},
+	}, true)
+	require.NoError(t, err)
+	assert.True(t, batch.Committed)
+	require.Len(t, batch.Results, 2)
+	assert.Equal(t, http.StatusOK, batch.Results[0].Status)
+	assert.Equal(t, http.StatusNotFound, batch.Results[1].Status)
+
+	require.NoError(t, c.DeleteItem(ctx, TableTexts, "note"))
+	_, err = c.GetItem(ctx, TableTexts, "note")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// An item must be of the kind the table stores
+	var item api.Item
+	require.NoError(t, item.FromText(api.Text{Data: "text"}))
+	_, err = c.AddItem(ctx, TableCards, "card", item)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+}
+
+func TestClient_Login(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	_, err = c.GetItem(ctx, TableTexts, "note")
+	assert.ErrorIs(t, err, ErrNotLoggedIn)
+
+	require.NoError(t, c.Register(ctx, "user", "password"))
+
+	_, err = c.Login(ctx, "user", "wrong")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	login, err := c.Login(ctx, "user", "password")
+	require.NoError(t, err)
+	assert.Equal(t, *login.UserID, c.Tokens().UserID)
+
+	userID, err := c.UserID(ctx, "user")
+	require.NoError(t, err)
+	assert.Equal(t, *login.UserID, userID)
+
+	sessions, err := c.Sessions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+
+	require.NoError(t, c.Logout(ctx))
+	assert.Empty(t, c.Tokens().AccessToken)
+
+	_, err = c.Sessions(ctx)
+	assert.ErrorIs(t, err, ErrNotLoggedIn)
+}
+
+func TestClient_Refresh(t *testing.T) {
+	srv := newTestServer(t)
+	ctx := context.Background()
+	tokens := newLoggedInClient(t, srv).Tokens()
+
+	// A saved session whose access token has expired is refreshed by the first request
+	var saved []Tokens
+	c, err := New(srv.URL, WithTokens(Tokens{
+		UserID:       tokens.UserID,
+		AccessToken:  "expired",
+		RefreshToken: tokens.RefreshToken,
+	}), WithTokensHook(func(tokens Tokens) {
+		saved = append(saved, tokens)
+	}))
+	require.NoError(t, err)
+
+	_, err = c.AddText(ctx, "note", api.Text{Data: "text"})
+	require.NoError(t, err)
+
+	require.Len(t, saved, 1)
+	assert.Equal(t, saved[0], c.Tokens())
+	assert.NotEqual(t, "expired", saved[0].AccessToken)
+	assert.NotEqual(t, tokens.RefreshToken, saved[0].RefreshToken)
+
+	// The used refresh token cannot be used again
+	c, err = New(srv.URL, WithTokens(Tokens{
+		UserID:       tokens.UserID,
+		AccessToken:  "expired",
+		RefreshToken: tokens.RefreshToken,
+	}))
+	require.NoError(t, err)
+
+	_, err = c.GetItem(ctx, TableTexts, "note")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestClient_Retries(t *testing.T) {
+	srv := newTestServer(t)
+	transport := &recordingTransport{}
+	c := newLoggedInClient(t, srv,
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithRetries(2, time.Millisecond))
+	ctx := context.Background()
+
+	_, err := c.AddText(ctx, "note", api.Text{Data: "text"})
+	require.NoError(t, err)
+
+	// Idempotent requests are repeated
+	transport.fail(2)
+	_, err = c.GetItem(ctx, TableTexts, "note")
+	require.NoError(t, err)
+	assert.Equal(t, 3, transport.attempts)
+
+	transport.fail(3)
+	_, err = c.GetItem(ctx, TableTexts, "note")
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, 3, transport.attempts)
+
+	// Others are not
+	transport.fail(1)
+	_, err = c.AddText(ctx, "other", api.Text{Data: "text"})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, 1, transport.attempts)
+
+	// The backoff stops with the context
+	c, err = New(srv.URL, WithTokens(c.Tokens()),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithRetries(5, time.Hour))
+	require.NoError(t, err)
+
+	transport.fail(1)
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = c.GetItem(ctx, TableTexts, "note")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+}
+
+func TestClient_Gzip(t *testing.T) {
+	srv := newTestServer(t)
+	transport := &recordingTransport{}
+	c := newLoggedInClient(t, srv, WithHTTPClient(&http.Client{Transport: transport}), WithGzip())
+	ctx := context.Background()
+
+	_, err := c.AddText(ctx, "note", api.Text{Data: strings.Repeat("text", 100)})
+	require.NoError(t, err)
+
+	records, err := c.ListItems(ctx, TableTexts, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	last := len(transport.requests) - 1
+	assert.Equal(t, "gzip", transport.requests[last-1].Header.Get("Content-Encoding"))
+	assert.True(t, transport.responses[last].Uncompressed)
+}
+
+func TestClient_Files(t *testing.T) {
+	srv := newTestServer(t)
+	c := newLoggedInClient(t, srv)
+	ctx := context.Background()
+
+	_, err := c.AddBinary(ctx, "small", api.Binary{Path: "small.txt"})
+	require.NoError(t, err)
+
+	result, err := c.SendFile(ctx, "small", strings.NewReader("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), result.Size)
+
+	content, err := c.GetFile(ctx, "small")
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	content.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	_, err = c.AddBinary(ctx, "large", api.Binary{Path: "large.txt"})
+	require.NoError(t, err)
+
+	upload, err := c.CreateUpload(ctx, "large", 11)
+	require.NoError(t, err)
+
+	offset, err := c.UploadChunk(ctx, upload.Id, 0, strings.NewReader("hello "))
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), offset)
+
+	// A repeated chunk is refused with the offset to continue from
+	offset, err = c.UploadChunk(ctx, upload.Id, 0, strings.NewReader("hello "))
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Equal(t, int64(6), offset)
+
+	offset, err = c.UploadOffset(ctx, upload.Id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), offset)
+
+	_, err = c.UploadChunk(ctx, upload.Id, offset, strings.NewReader("world"))
+	require.NoError(t, err)
+
+	result, err = c.FinishUpload(ctx, upload.Id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), result.Size)
+
+	_, err = c.GetFile(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_Events(t *testing.T) {
+	srv := newTestServer(t)
+	c := newLoggedInClient(t, srv)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received, err := c.Events(ctx)
+	require.NoError(t, err)
+
+	// The subscription is in place once Events has returned
+	_, err = c.AddText(context.Background(), "note", api.Text{Data: "text"})
+	require.NoError(t, err)
+
+	select {
+	case event := <-received:
+		assert.Equal(t, Event{Type: "item-changed", Table: TableTexts, ID: "note", Version: 1}, event)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not delivered")
+	}
+
+	// The channel is closed once the stream ends
+	cancel()
+	select {
+	case _, ok := <-received:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel was not closed")
+	}
+}
+
+func TestClient_Reencryption(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	keys, err := keyring.New(keyring.Key{ID: "2024", Secret: bytes.Repeat([]byte{7}, cryptokeeper.KeySize)})
+	require.NoError(t, err)
+	keeper, err := cryptokeeper.NewCryptoKeeper(memkeeper.NewMemKeeper(), keys)
+	require.NoError(t, err)
+	job := cryptokeeper.NewReencryption(keeper, 10, zap.NewNop())
+	go job.Run(ctx)
+
+	srv := newAdminTestServer(t, keeper, []string{"user"}, job)
+	c := newLoggedInClient(t, srv)
+
+	progress, err := c.StartReencryption(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2024", progress.KeyID)
+
+	// The progress reports the processed users out of all users
+	require.Eventually(t, func() bool {
+		progress, err = c.ReencryptionProgress(ctx)
+		return err == nil && !progress.Running
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(1), progress.Users)
+	assert.Equal(t, int64(1), progress.Total)
+
+	_, err = c.AdminCacheStats(ctx)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/wurt83ow/gophkeeper-server/internal/models"
+)
+
+// Event is a change made by another device of the user.
+type Event = models.Event
+
+// Events subscribes to the changes of the user's vault and delivers them on the returned channel.
+// The channel is closed when the stream ends: when ctx is done, the access token expires or the
+// session is revoked. Changes made in the meantime are not replayed, so after reconnecting the
+// client catches up with Changes.
+func (c *Client) Events(ctx context.Context) (<-chan models.Event, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.api.GetEventsUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resp, http.StatusOK); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	events := make(chan models.Event)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+
+		// Each event is a block of "event:" and "data:" lines ended by an empty line,
+		// lines that start with a colon are heartbeats
+		var data strings.Builder
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "data:"):
+				data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			case line == "" && data.Len() > 0:
+				var event models.Event
+				err := json.Unmarshal([]byte(data.String()), &event)
+				data.Reset()
+				if err != nil {
+					continue
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/wurt83ow/gophkeeper-server/pkg/api"
+)
+
+// SendFile uploads the content of a file record in one request.
+// Large files are better sent with CreateUpload, which can resume an interrupted upload.
+func (c *Client) SendFile(ctx context.Context, entryID string, content io.Reader) (*api.FileContentResponse, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.api.PostSendFileUserIDWithBody(ctx, userID, entryID, "application/octet-stream", content)
+
+	var result api.FileContentResponse
+	if err := decodeResponse(resp, err, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetFile downloads the content of a file record. The caller must close the returned reader.
+func (c *Client) GetFile(ctx context.Context, entryID string) (io.ReadCloser, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.api.GetGetFileUserIDEntryID(ctx, userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStatus(resp, http.StatusOK); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// CreateUpload starts a resumable upload of length bytes for a file record.
+func (c *Client) CreateUpload(ctx context.Context, entryID string, length int64) (*api.UploadSession, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.api.PostUploadsUserID(ctx, userID, api.PostUploadsUserIDJSONRequestBody{
+		EntryID: entryID,
+		Length:  length,
+	})
+
+	var session api.UploadSession
+	if err := decodeResponse(resp, err, http.StatusCreated, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// UploadOffset returns the number of bytes of a resumable upload stored so far.
+func (c *Client) UploadOffset(ctx context.Context, uploadID string) (int64, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.api.HeadUploadsUserIDUploadID(ctx, userID, uploadID)
+	if err := checkResponse(resp, err, http.StatusOK); err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(resp.Header.Get("Upload-Offset"), 10, 64)
+}
+
+// UploadChunk appends a chunk that starts at offset to a resumable upload and returns
+// the new offset. If the offset does not match, the error matches ErrConflict and
+// the returned offset is the one the upload continues from.
+func (c *Client) UploadChunk(ctx context.Context, uploadID string, offset int64, chunk io.Reader) (int64, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.api.PatchUploadsUserIDUploadIDWithBody(ctx, userID, uploadID,
+		&api.PatchUploadsUserIDUploadIDParams{UploadOffset: offset}, "application/octet-stream", chunk)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	statusErr := checkStatus(resp, http.StatusNoContent)
+	if header := resp.Header.Get("Upload-Offset"); header != "" {
+		offset, err = strconv.ParseInt(header, 10, 64)
+		if err != nil && statusErr == nil {
+			return 0, err
+		}
+	}
+	return offset, statusErr
+}
+
+// FinishUpload completes a resumable upload and stores its content.
+func (c *Client) FinishUpload(ctx context.Context, uploadID string) (*api.FileContentResponse, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.api.PostUploadsUserIDUploadIDFinish(ctx, userID, uploadID)
+
+	var result api.FileContentResponse
+	if err := decodeResponse(resp, err, http.StatusOK, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AbortUpload removes a resumable upload.
+func (c *Client) AbortUpload(ctx context.Context, uploadID string) error {
+	userID, err := c.userID()
+	if err != nil {
+		return err
+	}
+	resp, err := c.api.DeleteUploadsUserIDUploadID(ctx, userID, uploadID)
+	return checkResponse(resp, err, http.StatusNoContent)
+}
+
+// AdminStats returns the storage statistics of the blobs. Only administrators may call it.
+func (c *Client) AdminStats(ctx context.Context) (*api.BlobStats, error) {
+	resp, err := c.api.GetAdminStats(ctx)
+
+	var stats api.BlobStats
+	if err := decodeResponse(resp, err, http.StatusOK, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
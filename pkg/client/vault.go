@@ -0,0 +1,204 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/wurt83ow/gophkeeper-server/pkg/api"
+)
+
+// Vault tables, one for each kind of record.
+const (
+	TableCredentials = "UserCredentials"
+	TableCards       = "CreditCardData"
+	TableTexts       = "TextData"
+	TableFiles       = "FilesData"
+)
+
+// AddItem adds a vault record of the kind stored in the table and returns its version.
+func (c *Client) AddItem(ctx context.Context, table, entryID string, item api.Item) (int64, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.api.PostAddDataTableUserIDEntryID(ctx, table, userID, entryID, item)
+	if err := checkResponse(resp, err, http.StatusOK); err != nil {
+		return 0, err
+	}
+
+	return parseVersion(resp.Header.Get("ETag"))
+}
+
+// AddCredentials adds a login and password.
+func (c *Client) AddCredentials(ctx context.Context, entryID string, credentials api.Credentials) (int64, error) {
+	var item api.Item
+	if err := item.FromCredentials(credentials); err != nil {
+		return 0, err
+	}
+	return c.AddItem(ctx, TableCredentials, entryID, item)
+}
+
+// AddCard adds a bank card.
+func (c *Client) AddCard(ctx context.Context, entryID string, card api.Card) (int64, error) {
+	var item api.Item
+	if err := item.FromCard(card); err != nil {
+		return 0, err
+	}
+	return c.AddItem(ctx, TableCards, entryID, item)
+}
+
+// AddText adds a text.
+func (c *Client) AddText(ctx context.Context, entryID string, text api.Text) (int64, error) {
+	var item api.Item
+	if err := item.FromText(text); err != nil {
+		return 0, err
+	}
+	return c.AddItem(ctx, TableTexts, entryID, item)
+}
+
+// AddBinary adds the description of a file. Its content is uploaded with SendFile or CreateUpload.
+func (c *Client) AddBinary(ctx context.Context, entryID string, binary api.Binary) (int64, error) {
+	var item api.Item
+	if err := item.FromBinary(binary); err != nil {
+		return 0, err
+	}
+	return c.AddItem(ctx, TableFiles, entryID, item)
+}
+
+// UpdateItem changes the fields of a vault record and returns its new version.
+// A non-zero baseVersion makes the update fail with a ConflictError
+// if the record has changed since that version.
+func (c *Client) UpdateItem(ctx context.Context, table, entryID string, patch api.ItemPatch, baseVersion int64) (int64, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return 0, err
+	}
+
+	var editors []api.RequestEditorFn
+	if baseVersion != 0 {
+		editors = append(editors, func(ctx context.Context, req *http.Request) error {
+			req.Header.Set("If-Match", `"`+strconv.FormatInt(baseVersion, 10)+`"`)
+			return nil
+		})
+	}
+
+	resp, err := c.api.PutUpdateDataTableUserIDEntryID(ctx, table, userID, entryID, patch, editors...)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusConflict {
+		conflict := &ConflictError{}
+		if err := json.NewDecoder(resp.Body).Decode(&conflict.Current); err != nil {
+			return 0, err
+		}
+		return 0, conflict
+	}
+
+	var version api.Version
+	if err := decodeResponse(resp, nil, http.StatusOK, &version); err != nil {
+		return 0, err
+	}
+	return version.Version, nil
+}
+
+// DeleteItem marks a vault record as deleted.
+func (c *Client) DeleteItem(ctx context.Context, table, entryID string) error {
+	userID, err := c.userID()
+	if err != nil {
+		return err
+	}
+	resp, err := c.api.DeleteDeleteDataTableUserIDEntryID(ctx, table, userID, entryID)
+	return checkResponse(resp, err, http.StatusOK)
+}
+
+// GetItem returns a vault record that is not deleted.
+func (c *Client) GetItem(ctx context.Context, table, entryID string) (*api.Record, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.api.GetGetDataTableUserIDEntryID(ctx, table, userID, entryID)
+
+	var record api.Record
+	if err := decodeResponse(resp, err, http.StatusOK, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// ListItems returns the vault records of the table changed after since. A zero since
+// returns all records that are not deleted; any other time also returns deleted ones.
+func (c *Client) ListItems(ctx context.Context, table string, since time.Time) ([]api.Record, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.api.GetGetAllDataTableUserID(ctx, table, userID, since.UTC().Format(time.RFC3339))
+
+	var records []api.Record
+	if err := decodeResponse(resp, err, http.StatusOK, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Batch applies the operations in one transaction. Unless partial is set, the first
+// failing operation rolls back the whole batch; the response is then returned
+// together with an Error of its status.
+func (c *Client) Batch(ctx context.Context, ops []api.BatchOperation, partial bool) (*api.BatchResponse, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.api.PostBatchUserID(ctx, userID, api.PostBatchUserIDJSONRequestBody{
+		Operations: ops,
+		Partial:    partial,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Requests rejected as a whole have no results
+	if resp.Header.Get("Content-Type") != "application/json" {
+		return nil, checkStatus(resp, http.StatusOK)
+	}
+
+	var batch api.BatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &batch, &Error{StatusCode: resp.StatusCode, Message: "batch rolled back"}
+	}
+	return &batch, nil
+}
+
+// Sync returns the changes of all vault records after the cursor of the previous call.
+// An empty cursor returns all records; a non-positive limit uses the server default.
+func (c *Client) Sync(ctx context.Context, cursor string, limit int) (*api.SyncResponse, error) {
+	userID, err := c.userID()
+	if err != nil {
+		return nil, err
+	}
+
+	params := &api.GetSyncUserIDParams{}
+	if cursor != "" {
+		params.Cursor = &cursor
+	}
+	if limit > 0 {
+		params.Limit = &limit
+	}
+	resp, err := c.api.GetSyncUserID(ctx, userID, params)
+
+	var sync api.SyncResponse
+	if err := decodeResponse(resp, err, http.StatusOK, &sync); err != nil {
+		return nil, err
+	}
+	return &sync, nil
+}
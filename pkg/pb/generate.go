@@ -0,0 +1,5 @@
+// Package pb contains the messages and the client and server stubs of the gRPC API
+// described in api/proto/gophkeeper.proto.
+package pb
+
+//go:generate protoc -I ../../api/proto --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative gophkeeper.proto
@@ -0,0 +1,1119 @@
+// The gRPC API of GophKeeper. It serves the same users and vault records as
+// the HTTP API described in api/openapi.yaml.
+//
+// Regenerate the Go code with: go generate ./pkg/pb
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: gophkeeper.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Keeper_Register_FullMethodName          = "/gophkeeper.v1.Keeper/Register"
+	Keeper_Login_FullMethodName             = "/gophkeeper.v1.Keeper/Login"
+	Keeper_LoginMFA_FullMethodName          = "/gophkeeper.v1.Keeper/LoginMFA"
+	Keeper_RefreshToken_FullMethodName      = "/gophkeeper.v1.Keeper/RefreshToken"
+	Keeper_AddCredentials_FullMethodName    = "/gophkeeper.v1.Keeper/AddCredentials"
+	Keeper_GetCredentials_FullMethodName    = "/gophkeeper.v1.Keeper/GetCredentials"
+	Keeper_ListCredentials_FullMethodName   = "/gophkeeper.v1.Keeper/ListCredentials"
+	Keeper_UpdateCredentials_FullMethodName = "/gophkeeper.v1.Keeper/UpdateCredentials"
+	Keeper_DeleteCredentials_FullMethodName = "/gophkeeper.v1.Keeper/DeleteCredentials"
+	Keeper_AddCard_FullMethodName           = "/gophkeeper.v1.Keeper/AddCard"
+	Keeper_GetCard_FullMethodName           = "/gophkeeper.v1.Keeper/GetCard"
+	Keeper_ListCards_FullMethodName         = "/gophkeeper.v1.Keeper/ListCards"
+	Keeper_UpdateCard_FullMethodName        = "/gophkeeper.v1.Keeper/UpdateCard"
+	Keeper_DeleteCard_FullMethodName        = "/gophkeeper.v1.Keeper/DeleteCard"
+	Keeper_AddText_FullMethodName           = "/gophkeeper.v1.Keeper/AddText"
+	Keeper_GetText_FullMethodName           = "/gophkeeper.v1.Keeper/GetText"
+	Keeper_ListTexts_FullMethodName         = "/gophkeeper.v1.Keeper/ListTexts"
+	Keeper_UpdateText_FullMethodName        = "/gophkeeper.v1.Keeper/UpdateText"
+	Keeper_DeleteText_FullMethodName        = "/gophkeeper.v1.Keeper/DeleteText"
+	Keeper_AddBinary_FullMethodName         = "/gophkeeper.v1.Keeper/AddBinary"
+	Keeper_GetBinary_FullMethodName         = "/gophkeeper.v1.Keeper/GetBinary"
+	Keeper_ListBinaries_FullMethodName      = "/gophkeeper.v1.Keeper/ListBinaries"
+	Keeper_UpdateBinary_FullMethodName      = "/gophkeeper.v1.Keeper/UpdateBinary"
+	Keeper_DeleteBinary_FullMethodName      = "/gophkeeper.v1.Keeper/DeleteBinary"
+	Keeper_Sync_FullMethodName              = "/gophkeeper.v1.Keeper/Sync"
+	Keeper_UploadFile_FullMethodName        = "/gophkeeper.v1.Keeper/UploadFile"
+)
+
+// KeeperClient is the client API for Keeper service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type KeeperClient interface {
+	// Register registers a user with a password.
+	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	// Login logs a user in with a password. Users with two-factor authentication
+	// get a challenge token to complete the login with LoginMFA.
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	// LoginMFA completes a login with a one-time or recovery code.
+	LoginMFA(ctx context.Context, in *LoginMFARequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	// RefreshToken exchanges a refresh token for new tokens.
+	RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	AddCredentials(ctx context.Context, in *AddCredentialsRequest, opts ...grpc.CallOption) (*ItemVersion, error)
+	GetCredentials(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*CredentialsItem, error)
+	ListCredentials(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListCredentialsResponse, error)
+	UpdateCredentials(ctx context.Context, in *UpdateCredentialsRequest, opts ...grpc.CallOption) (*ItemVersion, error)
+	DeleteCredentials(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*DeleteItemResponse, error)
+	AddCard(ctx context.Context, in *AddCardRequest, opts ...grpc.CallOption) (*ItemVersion, error)
+	GetCard(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*CardItem, error)
+	ListCards(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListCardsResponse, error)
+	UpdateCard(ctx context.Context, in *UpdateCardRequest, opts ...grpc.CallOption) (*ItemVersion, error)
+	DeleteCard(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*DeleteItemResponse, error)
+	AddText(ctx context.Context, in *AddTextRequest, opts ...grpc.CallOption) (*ItemVersion, error)
+	GetText(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*TextItem, error)
+	ListTexts(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListTextsResponse, error)
+	UpdateText(ctx context.Context, in *UpdateTextRequest, opts ...grpc.CallOption) (*ItemVersion, error)
+	DeleteText(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*DeleteItemResponse, error)
+	AddBinary(ctx context.Context, in *AddBinaryRequest, opts ...grpc.CallOption) (*ItemVersion, error)
+	GetBinary(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*BinaryItem, error)
+	ListBinaries(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListBinariesResponse, error)
+	UpdateBinary(ctx context.Context, in *UpdateBinaryRequest, opts ...grpc.CallOption) (*ItemVersion, error)
+	DeleteBinary(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*DeleteItemResponse, error)
+	// Sync streams the changes of all records after the cursor, oldest first.
+	// The stream ends once the client has caught up.
+	Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (Keeper_SyncClient, error)
+	// UploadFile stores the content of a binary record. The first message names
+	// the record, the following ones carry the content.
+	UploadFile(ctx context.Context, opts ...grpc.CallOption) (Keeper_UploadFileClient, error)
+}
+
+type keeperClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKeeperClient(cc grpc.ClientConnInterface) KeeperClient {
+	return &keeperClient{cc}
+}
+
+func (c *keeperClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
+	out := new(RegisterResponse)
+	err := c.cc.Invoke(ctx, Keeper_Register_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, Keeper_Login_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) LoginMFA(ctx context.Context, in *LoginMFARequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, Keeper_LoginMFA_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, Keeper_RefreshToken_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) AddCredentials(ctx context.Context, in *AddCredentialsRequest, opts ...grpc.CallOption) (*ItemVersion, error) {
+	out := new(ItemVersion)
+	err := c.cc.Invoke(ctx, Keeper_AddCredentials_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) GetCredentials(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*CredentialsItem, error) {
+	out := new(CredentialsItem)
+	err := c.cc.Invoke(ctx, Keeper_GetCredentials_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) ListCredentials(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListCredentialsResponse, error) {
+	out := new(ListCredentialsResponse)
+	err := c.cc.Invoke(ctx, Keeper_ListCredentials_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) UpdateCredentials(ctx context.Context, in *UpdateCredentialsRequest, opts ...grpc.CallOption) (*ItemVersion, error) {
+	out := new(ItemVersion)
+	err := c.cc.Invoke(ctx, Keeper_UpdateCredentials_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) DeleteCredentials(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*DeleteItemResponse, error) {
+	out := new(DeleteItemResponse)
+	err := c.cc.Invoke(ctx, Keeper_DeleteCredentials_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) AddCard(ctx context.Context, in *AddCardRequest, opts ...grpc.CallOption) (*ItemVersion, error) {
+	out := new(ItemVersion)
+	err := c.cc.Invoke(ctx, Keeper_AddCard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) GetCard(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*CardItem, error) {
+	out := new(CardItem)
+	err := c.cc.Invoke(ctx, Keeper_GetCard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) ListCards(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListCardsResponse, error) {
+	out := new(ListCardsResponse)
+	err := c.cc.Invoke(ctx, Keeper_ListCards_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) UpdateCard(ctx context.Context, in *UpdateCardRequest, opts ...grpc.CallOption) (*ItemVersion, error) {
+	out := new(ItemVersion)
+	err := c.cc.Invoke(ctx, Keeper_UpdateCard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) DeleteCard(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*DeleteItemResponse, error) {
+	out := new(DeleteItemResponse)
+	err := c.cc.Invoke(ctx, Keeper_DeleteCard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) AddText(ctx context.Context, in *AddTextRequest, opts ...grpc.CallOption) (*ItemVersion, error) {
+	out := new(ItemVersion)
+	err := c.cc.Invoke(ctx, Keeper_AddText_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) GetText(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*TextItem, error) {
+	out := new(TextItem)
+	err := c.cc.Invoke(ctx, Keeper_GetText_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) ListTexts(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListTextsResponse, error) {
+	out := new(ListTextsResponse)
+	err := c.cc.Invoke(ctx, Keeper_ListTexts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) UpdateText(ctx context.Context, in *UpdateTextRequest, opts ...grpc.CallOption) (*ItemVersion, error) {
+	out := new(ItemVersion)
+	err := c.cc.Invoke(ctx, Keeper_UpdateText_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) DeleteText(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*DeleteItemResponse, error) {
+	out := new(DeleteItemResponse)
+	err := c.cc.Invoke(ctx, Keeper_DeleteText_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) AddBinary(ctx context.Context, in *AddBinaryRequest, opts ...grpc.CallOption) (*ItemVersion, error) {
+	out := new(ItemVersion)
+	err := c.cc.Invoke(ctx, Keeper_AddBinary_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) GetBinary(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*BinaryItem, error) {
+	out := new(BinaryItem)
+	err := c.cc.Invoke(ctx, Keeper_GetBinary_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) ListBinaries(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListBinariesResponse, error) {
+	out := new(ListBinariesResponse)
+	err := c.cc.Invoke(ctx, Keeper_ListBinaries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) UpdateBinary(ctx context.Context, in *UpdateBinaryRequest, opts ...grpc.CallOption) (*ItemVersion, error) {
+	out := new(ItemVersion)
+	err := c.cc.Invoke(ctx, Keeper_UpdateBinary_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) DeleteBinary(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*DeleteItemResponse, error) {
+	out := new(DeleteItemResponse)
+	err := c.cc.Invoke(ctx, Keeper_DeleteBinary_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperClient) Sync(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (Keeper_SyncClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Keeper_ServiceDesc.Streams[0], Keeper_Sync_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &keeperSyncClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Keeper_SyncClient interface {
+	Recv() (*Change, error)
+	grpc.ClientStream
+}
+
+type keeperSyncClient struct {
+	grpc.ClientStream
+}
+
+func (x *keeperSyncClient) Recv() (*Change, error) {
+	m := new(Change)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *keeperClient) UploadFile(ctx context.Context, opts ...grpc.CallOption) (Keeper_UploadFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Keeper_ServiceDesc.Streams[1], Keeper_UploadFile_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &keeperUploadFileClient{stream}
+	return x, nil
+}
+
+type Keeper_UploadFileClient interface {
+	Send(*UploadFileRequest) error
+	CloseAndRecv() (*UploadFileResponse, error)
+	grpc.ClientStream
+}
+
+type keeperUploadFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *keeperUploadFileClient) Send(m *UploadFileRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *keeperUploadFileClient) CloseAndRecv() (*UploadFileResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadFileResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// KeeperServer is the server API for Keeper service.
+// All implementations must embed UnimplementedKeeperServer
+// for forward compatibility
+type KeeperServer interface {
+	// Register registers a user with a password.
+	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
+	// Login logs a user in with a password. Users with two-factor authentication
+	// get a challenge token to complete the login with LoginMFA.
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	// LoginMFA completes a login with a one-time or recovery code.
+	LoginMFA(context.Context, *LoginMFARequest) (*LoginResponse, error)
+	// RefreshToken exchanges a refresh token for new tokens.
+	RefreshToken(context.Context, *RefreshTokenRequest) (*LoginResponse, error)
+	AddCredentials(context.Context, *AddCredentialsRequest) (*ItemVersion, error)
+	GetCredentials(context.Context, *GetItemRequest) (*CredentialsItem, error)
+	ListCredentials(context.Context, *ListItemsRequest) (*ListCredentialsResponse, error)
+	UpdateCredentials(context.Context, *UpdateCredentialsRequest) (*ItemVersion, error)
+	DeleteCredentials(context.Context, *DeleteItemRequest) (*DeleteItemResponse, error)
+	AddCard(context.Context, *AddCardRequest) (*ItemVersion, error)
+	GetCard(context.Context, *GetItemRequest) (*CardItem, error)
+	ListCards(context.Context, *ListItemsRequest) (*ListCardsResponse, error)
+	UpdateCard(context.Context, *UpdateCardRequest) (*ItemVersion, error)
+	DeleteCard(context.Context, *DeleteItemRequest) (*DeleteItemResponse, error)
+	AddText(context.Context, *AddTextRequest) (*ItemVersion, error)
+	GetText(context.Context, *GetItemRequest) (*TextItem, error)
+	ListTexts(context.Context, *ListItemsRequest) (*ListTextsResponse, error)
+	UpdateText(context.Context, *UpdateTextRequest) (*ItemVersion, error)
+	DeleteText(context.Context, *DeleteItemRequest) (*DeleteItemResponse, error)
+	AddBinary(context.Context, *AddBinaryRequest) (*ItemVersion, error)
+	GetBinary(context.Context, *GetItemRequest) (*BinaryItem, error)
+	ListBinaries(context.Context, *ListItemsRequest) (*ListBinariesResponse, error)
+	UpdateBinary(context.Context, *UpdateBinaryRequest) (*ItemVersion, error)
+	DeleteBinary(context.Context, *DeleteItemRequest) (*DeleteItemResponse, error)
+	// Sync streams the changes of all records after the cursor, oldest first.
+	// The stream ends once the client has caught up.
+	Sync(*SyncRequest, Keeper_SyncServer) error
+	// UploadFile stores the content of a binary record. The first message names
+	// the record, the following ones carry the content.
+	UploadFile(Keeper_UploadFileServer) error
+	mustEmbedUnimplementedKeeperServer()
+}
+
+// UnimplementedKeeperServer must be embedded to have forward compatible implementations.
+type UnimplementedKeeperServer struct {
+}
+
+func (UnimplementedKeeperServer) Register(context.Context, *RegisterRequest) (*RegisterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedKeeperServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedKeeperServer) LoginMFA(context.Context, *LoginMFARequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoginMFA not implemented")
+}
+func (UnimplementedKeeperServer) RefreshToken(context.Context, *RefreshTokenRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
+}
+func (UnimplementedKeeperServer) AddCredentials(context.Context, *AddCredentialsRequest) (*ItemVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCredentials not implemented")
+}
+func (UnimplementedKeeperServer) GetCredentials(context.Context, *GetItemRequest) (*CredentialsItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCredentials not implemented")
+}
+func (UnimplementedKeeperServer) ListCredentials(context.Context, *ListItemsRequest) (*ListCredentialsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCredentials not implemented")
+}
+func (UnimplementedKeeperServer) UpdateCredentials(context.Context, *UpdateCredentialsRequest) (*ItemVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCredentials not implemented")
+}
+func (UnimplementedKeeperServer) DeleteCredentials(context.Context, *DeleteItemRequest) (*DeleteItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCredentials not implemented")
+}
+func (UnimplementedKeeperServer) AddCard(context.Context, *AddCardRequest) (*ItemVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCard not implemented")
+}
+func (UnimplementedKeeperServer) GetCard(context.Context, *GetItemRequest) (*CardItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCard not implemented")
+}
+func (UnimplementedKeeperServer) ListCards(context.Context, *ListItemsRequest) (*ListCardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCards not implemented")
+}
+func (UnimplementedKeeperServer) UpdateCard(context.Context, *UpdateCardRequest) (*ItemVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCard not implemented")
+}
+func (UnimplementedKeeperServer) DeleteCard(context.Context, *DeleteItemRequest) (*DeleteItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCard not implemented")
+}
+func (UnimplementedKeeperServer) AddText(context.Context, *AddTextRequest) (*ItemVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddText not implemented")
+}
+func (UnimplementedKeeperServer) GetText(context.Context, *GetItemRequest) (*TextItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetText not implemented")
+}
+func (UnimplementedKeeperServer) ListTexts(context.Context, *ListItemsRequest) (*ListTextsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTexts not implemented")
+}
+func (UnimplementedKeeperServer) UpdateText(context.Context, *UpdateTextRequest) (*ItemVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateText not implemented")
+}
+func (UnimplementedKeeperServer) DeleteText(context.Context, *DeleteItemRequest) (*DeleteItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteText not implemented")
+}
+func (UnimplementedKeeperServer) AddBinary(context.Context, *AddBinaryRequest) (*ItemVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBinary not implemented")
+}
+func (UnimplementedKeeperServer) GetBinary(context.Context, *GetItemRequest) (*BinaryItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBinary not implemented")
+}
+func (UnimplementedKeeperServer) ListBinaries(context.Context, *ListItemsRequest) (*ListBinariesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBinaries not implemented")
+}
+func (UnimplementedKeeperServer) UpdateBinary(context.Context, *UpdateBinaryRequest) (*ItemVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBinary not implemented")
+}
+func (UnimplementedKeeperServer) DeleteBinary(context.Context, *DeleteItemRequest) (*DeleteItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteBinary not implemented")
+}
+func (UnimplementedKeeperServer) Sync(*SyncRequest, Keeper_SyncServer) error {
+	return status.Errorf(codes.Unimplemented, "method Sync not implemented")
+}
+func (UnimplementedKeeperServer) UploadFile(Keeper_UploadFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadFile not implemented")
+}
+func (UnimplementedKeeperServer) mustEmbedUnimplementedKeeperServer() {}
+
+// UnsafeKeeperServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KeeperServer will
+// result in compilation errors.
+type UnsafeKeeperServer interface {
+	mustEmbedUnimplementedKeeperServer()
+}
+
+func RegisterKeeperServer(s grpc.ServiceRegistrar, srv KeeperServer) {
+	s.RegisterService(&Keeper_ServiceDesc, srv)
+}
+
+func _Keeper_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).Register(ctx, req.(*RegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_LoginMFA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginMFARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).LoginMFA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_LoginMFA_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).LoginMFA(ctx, req.(*LoginMFARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).RefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_RefreshToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).RefreshToken(ctx, req.(*RefreshTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_AddCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCredentialsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).AddCredentials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_AddCredentials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).AddCredentials(ctx, req.(*AddCredentialsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_GetCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).GetCredentials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_GetCredentials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).GetCredentials(ctx, req.(*GetItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_ListCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).ListCredentials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_ListCredentials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).ListCredentials(ctx, req.(*ListItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_UpdateCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCredentialsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).UpdateCredentials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_UpdateCredentials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).UpdateCredentials(ctx, req.(*UpdateCredentialsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_DeleteCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).DeleteCredentials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_DeleteCredentials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).DeleteCredentials(ctx, req.(*DeleteItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_AddCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).AddCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_AddCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).AddCard(ctx, req.(*AddCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_GetCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).GetCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_GetCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).GetCard(ctx, req.(*GetItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_ListCards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).ListCards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_ListCards_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).ListCards(ctx, req.(*ListItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_UpdateCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).UpdateCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_UpdateCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).UpdateCard(ctx, req.(*UpdateCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_DeleteCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).DeleteCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_DeleteCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).DeleteCard(ctx, req.(*DeleteItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_AddText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).AddText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_AddText_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).AddText(ctx, req.(*AddTextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_GetText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).GetText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_GetText_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).GetText(ctx, req.(*GetItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_ListTexts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).ListTexts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_ListTexts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).ListTexts(ctx, req.(*ListItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_UpdateText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).UpdateText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_UpdateText_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).UpdateText(ctx, req.(*UpdateTextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_DeleteText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).DeleteText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_DeleteText_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).DeleteText(ctx, req.(*DeleteItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_AddBinary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddBinaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).AddBinary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_AddBinary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).AddBinary(ctx, req.(*AddBinaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_GetBinary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).GetBinary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_GetBinary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).GetBinary(ctx, req.(*GetItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_ListBinaries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).ListBinaries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_ListBinaries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).ListBinaries(ctx, req.(*ListItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_UpdateBinary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBinaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).UpdateBinary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_UpdateBinary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).UpdateBinary(ctx, req.(*UpdateBinaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_DeleteBinary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServer).DeleteBinary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keeper_DeleteBinary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServer).DeleteBinary(ctx, req.(*DeleteItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keeper_Sync_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SyncRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(KeeperServer).Sync(m, &keeperSyncServer{stream})
+}
+
+type Keeper_SyncServer interface {
+	Send(*Change) error
+	grpc.ServerStream
+}
+
+type keeperSyncServer struct {
+	grpc.ServerStream
+}
+
+func (x *keeperSyncServer) Send(m *Change) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Keeper_UploadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(KeeperServer).UploadFile(&keeperUploadFileServer{stream})
+}
+
+type Keeper_UploadFileServer interface {
+	SendAndClose(*UploadFileResponse) error
+	Recv() (*UploadFileRequest, error)
+	grpc.ServerStream
+}
+
+type keeperUploadFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *keeperUploadFileServer) SendAndClose(m *UploadFileResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *keeperUploadFileServer) Recv() (*UploadFileRequest, error) {
+	m := new(UploadFileRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Keeper_ServiceDesc is the grpc.ServiceDesc for Keeper service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Keeper_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gophkeeper.v1.Keeper",
+	HandlerType: (*KeeperServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _Keeper_Register_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _Keeper_Login_Handler,
+		},
+		{
+			MethodName: "LoginMFA",
+			Handler:    _Keeper_LoginMFA_Handler,
+		},
+		{
+			MethodName: "RefreshToken",
+			Handler:    _Keeper_RefreshToken_Handler,
+		},
+		{
+			MethodName: "AddCredentials",
+			Handler:    _Keeper_AddCredentials_Handler,
+		},
+		{
+			MethodName: "GetCredentials",
+			Handler:    _Keeper_GetCredentials_Handler,
+		},
+		{
+			MethodName: "ListCredentials",
+			Handler:    _Keeper_ListCredentials_Handler,
+		},
+		{
+			MethodName: "UpdateCredentials",
+			Handler:    _Keeper_UpdateCredentials_Handler,
+		},
+		{
+			MethodName: "DeleteCredentials",
+			Handler:    _Keeper_DeleteCredentials_Handler,
+		},
+		{
+			MethodName: "AddCard",
+			Handler:    _Keeper_AddCard_Handler,
+		},
+		{
+			MethodName: "GetCard",
+			Handler:    _Keeper_GetCard_Handler,
+		},
+		{
+			MethodName: "ListCards",
+			Handler:    _Keeper_ListCards_Handler,
+		},
+		{
+			MethodName: "UpdateCard",
+			Handler:    _Keeper_UpdateCard_Handler,
+		},
+		{
+			MethodName: "DeleteCard",
+			Handler:    _Keeper_DeleteCard_Handler,
+		},
+		{
+			MethodName: "AddText",
+			Handler:    _Keeper_AddText_Handler,
+		},
+		{
+			MethodName: "GetText",
+			Handler:    _Keeper_GetText_Handler,
+		},
+		{
+			MethodName: "ListTexts",
+			Handler:    _Keeper_ListTexts_Handler,
+		},
+		{
+			MethodName: "UpdateText",
+			Handler:    _Keeper_UpdateText_Handler,
+		},
+		{
+			MethodName: "DeleteText",
+			Handler:    _Keeper_DeleteText_Handler,
+		},
+		{
+			MethodName: "AddBinary",
+			Handler:    _Keeper_AddBinary_Handler,
+		},
+		{
+			MethodName: "GetBinary",
+			Handler:    _Keeper_GetBinary_Handler,
+		},
+		{
+			MethodName: "ListBinaries",
+			Handler:    _Keeper_ListBinaries_Handler,
+		},
+		{
+			MethodName: "UpdateBinary",
+			Handler:    _Keeper_UpdateBinary_Handler,
+		},
+		{
+			MethodName: "DeleteBinary",
+			Handler:    _Keeper_DeleteBinary_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Sync",
+			Handler:       _Keeper_Sync_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadFile",
+			Handler:       _Keeper_UploadFile_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "gophkeeper.proto",
+}